@@ -1,10 +1,15 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -13,8 +18,9 @@ import (
 // Config represents the application configuration
 type Config struct {
 	RootURL       string   `yaml:"root_url" json:"root_url"`
-	OutputFormat  string   `yaml:"output_format" json:"output_format"` // "markdown", "text", "json"
-	OutputType    string   `yaml:"output_type" json:"output_type"`     // "single", "per-page"
+	OutputFormat  string   `yaml:"output_format" json:"output_format"`   // "markdown", "text", "json", "html", "warc", "auto", "csv", "asciidoc"
+	OutputFormats []string `yaml:"output_formats" json:"output_formats"` // Generate several formats from one crawl, overrides output_format
+	OutputType    string   `yaml:"output_type" json:"output_type"`       // "single", "per-page", "per-depth"
 	OutputDir     string   `yaml:"output_dir" json:"output_dir"`
 	UserAgents    []string `yaml:"user_agents" json:"user_agents"`
 	MinDelay      int      `yaml:"min_delay" json:"min_delay"` // seconds
@@ -28,13 +34,163 @@ type Config struct {
 	Proxies []string `yaml:"proxies" json:"proxies"` // HTTP/SOCKS5 proxy URLs
 
 	// Optional advanced settings with sensible defaults
-	ConcurrentRequests *int `yaml:"concurrent_requests" json:"concurrent_requests"` // nil means use default (2)
+	ConcurrentRequests *int  `yaml:"concurrent_requests" json:"concurrent_requests"`   // nil means use default (2)
+	RequestTimeout     *int  `yaml:"request_timeout" json:"request_timeout"`           // seconds, nil means use default (30)
+	IgnoreSSLErrors    *bool `yaml:"ignore_ssl_errors" json:"ignore_ssl_errors"`       // Skip TLS certificate verification, nil means verify
+	PerHostParallelism *int  `yaml:"per_host_parallelism" json:"per_host_parallelism"` // Per-host limit, making concurrent_requests a global cap; nil means one shared limit
+	SpillToDisk        *bool `yaml:"spill_to_disk" json:"spill_to_disk"`               // Keep extracted content in temp files instead of memory during the crawl
+	InitialDelay       *int  `yaml:"initial_delay" json:"initial_delay"`               // seconds before the first request, nil means no delay
+	RetryAttempts      *int  `yaml:"retry_attempts" json:"retry_attempts"`             // Retries of 5xx and connection errors, nil means no retries
+	MaxWaves           *int  `yaml:"max_waves" json:"max_waves"`                       // Post-crawl passes following links found in stored pages, nil means none
+	MaxDownloadBytes   *int  `yaml:"max_download_bytes" json:"max_download_bytes"`     // Stop requesting once responses total more than this many bytes, nil means unlimited
+
+	RequestsPerSecond  *float64 `yaml:"requests_per_second" json:"requests_per_second"`   // Per-host token-bucket rate, nil means one request per average of min_delay and max_delay, 0 means unlimited
+	MinRequestInterval *int     `yaml:"min_request_interval" json:"min_request_interval"` // milliseconds between requests to a host, retries included; nil means min_delay
+
+	MaxPages *int `yaml:"max_pages" json:"max_pages"` // Stop the crawl once this many pages are stored, nil or 0 means unlimited
+
+	// Separate retry policies for connection errors (DNS, refused, reset) and 5xx responses
+	ConnectionRetries    *int `yaml:"connection_retries" json:"connection_retries"`         // nil means retry_attempts
+	HTTPRetries          *int `yaml:"http_retries" json:"http_retries"`                     // nil means retry_attempts
+	ConnectionRetryDelay *int `yaml:"connection_retry_delay" json:"connection_retry_delay"` // milliseconds before the first connection retry, nil means default (250)
+
+	// Known-junk pages: SHA-256 hex hashes of extracted content that are never stored
+	SkipContentHashes []string `yaml:"skip_content_hashes" json:"skip_content_hashes"`
+	SkipHashFile      string   `yaml:"skip_hash_file" json:"skip_hash_file"` // One hash per line, # comments allowed
+
+	// Exact page set: only these URLs (one per line, # comments allowed) and the root are visited
+	URLAllowlistFile string `yaml:"url_allowlist_file" json:"url_allowlist_file"`
+
+	// Linked files with these extensions (e.g. ".pdf") are saved under asset_dir instead of skipped
+	DownloadExtensions []string `yaml:"download_extensions" json:"download_extensions"`
+	AssetDir           string   `yaml:"asset_dir" json:"asset_dir"` // "" means assets in output_dir
+
+	// <link> and <script src> resources (stylesheets, scripts, icons) are never crawled as pages;
+	// when set their URLs are recorded, and those matching download_extensions saved as assets
+	RecordResourceLinks *bool `yaml:"record_resource_links" json:"record_resource_links"`
+
+	// data-* attributes (e.g. "data-doc-version") read from <html>, <body> and <meta> into page metadata
+	CaptureDataAttributes []string `yaml:"capture_data_attributes" json:"capture_data_attributes"`
+
+	// Regions between marker comments (e.g. <!-- exclude-start --> ... <!-- exclude-end -->) are dropped
+	ExcludeMarkers []MarkerPair `yaml:"exclude_markers" json:"exclude_markers"`
+
+	// Extra initial requests, e.g. POSTs to endpoints that only return content for a payload
+	SeedRequests []RequestSpec `yaml:"seed_requests" json:"seed_requests"`
+
+	// Custom page tags: pages whose URL (or content) matches a rule's pattern get its tag
+	TagRules []TagRule `yaml:"tag_rules" json:"tag_rules"`
+
+	// Crawl ordering: links matching higher-priority patterns are visited first
+	PriorityPatterns []PriorityPattern `yaml:"priority_patterns" json:"priority_patterns"`
+
+	// Retry backoff policy: "exponential" (default), "full-jitter", "equal-jitter", "constant"
+	BackoffStrategy string `yaml:"backoff_strategy" json:"backoff_strategy"`
+
+	// Page-level robots and refresh directives
+	RespectMetaRobots *bool `yaml:"respect_meta_robots" json:"respect_meta_robots"` // Honor <meta name="robots"> noindex/nofollow
+	FollowMetaRefresh *bool `yaml:"follow_meta_refresh" json:"follow_meta_refresh"` // Treat immediate (up to 1s) meta refreshes and 3xx without Location as redirects
+
+	RecordFrontier *bool `yaml:"record_frontier" json:"record_frontier"` // Write frontier.json listing discovered URLs that were never visited and why
+
+	// Optional JSON listing endpoint used to seed the crawl
+	APIListingURL      string `yaml:"api_listing_url" json:"api_listing_url"`             // URL returning a JSON index of pages
+	APIListingPath     string `yaml:"api_listing_path" json:"api_listing_path"`           // Dot path to page URLs, e.g. "items.*.url"
+	APIListingNextPath string `yaml:"api_listing_next_path" json:"api_listing_next_path"` // Dot path to the next page URL, e.g. "links.next"
+
+	// Sitemap: seed the crawl with its <loc> URLs, and optionally re-crawl incrementally, reusing
+	// pages whose <lastmod> hasn't advanced since the last run
+	UseSitemap         *bool  `yaml:"use_sitemap" json:"use_sitemap"`
+	SitemapIncremental *bool  `yaml:"sitemap_incremental" json:"sitemap_incremental"`
+	SitemapURL         string `yaml:"sitemap_url" json:"sitemap_url"`     // "" means /sitemap.xml on the root host
+	ManifestFile       string `yaml:"manifest_file" json:"manifest_file"` // Prior crawl record, "" means crawl_manifest.json in output_dir
+
+	// Resumable crawls: seen, scraped and pending URLs are saved here as the crawl runs, scraped
+	// pages beside it in <state_file>.pages, and reloaded on start
+	StateFile string `yaml:"state_file" json:"state_file"`
+
+	// Delta crawls: pages saved in state_file are requested again with If-None-Match and
+	// If-Modified-Since, and reused from its page store when the server answers 304 Not Modified
+	ConditionalRequests *bool `yaml:"conditional_requests" json:"conditional_requests"`
+
+	// External link checking
+	CheckExternalLinks   *bool `yaml:"check_external_links" json:"check_external_links"`     // Check links to other hosts after the crawl and write link_check.json
+	LinkCheckConcurrency *int  `yaml:"link_check_concurrency" json:"link_check_concurrency"` // nil means use default (4)
+	LinkCheckTimeout     *int  `yaml:"link_check_timeout" json:"link_check_timeout"`         // seconds, nil means use default (10)
 
 	// Advanced Features
 	UseHierarchicalOrdering *bool `yaml:"use_hierarchical_ordering" json:"use_hierarchical_ordering"` // Enable hierarchical output organization
 	EnableDeduplication     *bool `yaml:"enable_deduplication" json:"enable_deduplication"`           // Enable duplicate link detection
 	EnableQualityAnalysis   *bool `yaml:"enable_quality_analysis" json:"enable_quality_analysis"`     // Enable content quality analysis
-	EnableDevTools          *bool `yaml:"enable_devtools" json:"enable_devtools"`                     // Enable development tools
+
+	MaxLinkRatio             *float64 `yaml:"max_link_ratio" json:"max_link_ratio"`                         // Links per word above which a page counts toward navigation detection, nil means default (0.3)
+	MinLanguageConfidence    *float64 `yaml:"min_language_confidence" json:"min_language_confidence"`       // Detection confidence (0.0-1.0) below which filter_by_language keeps a page, nil means default (0.5)
+	EnableDevTools           *bool    `yaml:"enable_devtools" json:"enable_devtools"`                       // Enable development tools
+	NumberedOutput           *bool    `yaml:"numbered_output" json:"numbered_output"`                       // Prefix per-page output with its reading-order position
+	GenerateGlossary         *bool    `yaml:"generate_glossary" json:"generate_glossary"`                   // Write an alphabetical glossary.md of page titles
+	GenerateJSONSchema       *bool    `yaml:"generate_json_schema" json:"generate_json_schema"`             // Write documentation.schema.json alongside JSON output
+	ReproducibleOutput       *bool    `yaml:"reproducible_output" json:"reproducible_output"`               // Fix generation timestamps and drop per-page scrape times
+	ValidateOutput           *bool    `yaml:"validate_output" json:"validate_output"`                       // Check links, JSON and front matter after generation
+	GenerateStructureOutline *bool    `yaml:"generate_structure_outline" json:"generate_structure_outline"` // Write structure.yaml, a nested outline of the document tree; requires use_hierarchical_ordering
+	GenerateTokenReport      *bool    `yaml:"generate_token_report" json:"generate_token_report"`           // Write token_report.json with estimated LLM token counts
+	SkipRootInOutput         *bool    `yaml:"skip_root_in_output" json:"skip_root_in_output"`               // Crawl the root page for links but leave it out of the output
+	GenerateReadme           *bool    `yaml:"generate_readme" json:"generate_readme"`                       // Write README.md at the output root; nil means only for multi-file output
+	FormatSubdirs            *bool    `yaml:"format_subdirs" json:"format_subdirs"`                         // Write each output format into its own <output_dir>/<format>/ subdirectory
+	GenerateURLMap           *bool    `yaml:"generate_url_map" json:"generate_url_map"`                     // Write url_map.json mapping each page URL to its per-page output file
+	GenerateURLIndex         *bool    `yaml:"generate_url_index" json:"generate_url_index"`                 // Write index.json listing each page's URL, title and depth, nil means default (true)
+	WriteEffectiveConfig     *bool    `yaml:"write_effective_config" json:"write_effective_config"`         // Write effective-config.yaml, the configuration as run with secrets redacted
+	CompressOutput           *bool    `yaml:"compress_output" json:"compress_output"`                       // Gzip markdown, text and JSON page files, appending .gz; indexes stay plain
+	SectionIndexes           *bool    `yaml:"section_indexes" json:"section_indexes"`                       // Give each hierarchical section a navigation-only _index.md, keeping index.md for its content
+
+	SkeletonOnly *bool `yaml:"skeleton_only" json:"skeleton_only"` // Write only skeleton.md, page titles and their heading outlines, instead of any output format
+
+	FormatWorkers *int `yaml:"format_workers" json:"format_workers"` // Output formats generated concurrently, nil means default (4)
+
+	PageWorkers *int `yaml:"page_workers" json:"page_workers"` // Per-page markdown and text files written concurrently within a format, nil means default (4)
+
+	SortBy string `yaml:"sort_by" json:"sort_by"` // Order of the JSON pages array: "url", "title" or "depth", "" keeps scrape order
+
+	MaxOutputFiles *int   `yaml:"max_output_files" json:"max_output_files"` // Most per-page files output may write, one per page and format, nil means unlimited
+	OnFileLimit    string `yaml:"on_file_limit" json:"on_file_limit"`       // Past max_output_files: "bundle" (default) writes single-file output instead, "error" fails
+
+	QueryIsIdentity *bool `yaml:"query_is_identity" json:"query_is_identity"` // Treat pages differing only by query string as distinct, e.g. docs?id=install
+
+	RedactPatterns []string `yaml:"redact_patterns" json:"redact_patterns"` // Regexes whose matches are replaced with [REDACTED] in every output format, e.g. API keys in examples
+
+	// Extraction Settings
+	TitleStrategy          string   `yaml:"title_strategy" json:"title_strategy"`                     // "smart" (default) or "first"
+	CleaningPatterns       []string `yaml:"cleaning_patterns" json:"cleaning_patterns"`               // Extra noise regexes stripped from content
+	ReplaceDefaultCleaning bool     `yaml:"replace_default_cleaning" json:"replace_default_cleaning"` // Use only cleaning_patterns, dropping built-in ones
+
+	PreserveHeadingIDs *bool `yaml:"preserve_heading_ids" json:"preserve_heading_ids"` // Keep heading ids as {#id} in markdown output for deep linking
+
+	MaxTitleLength *int `yaml:"max_title_length" json:"max_title_length"` // Truncate displayed titles in TOCs/headers, nil means no limit
+
+	HeadingBaseLevel *int `yaml:"heading_base_level" json:"heading_base_level"` // Shift each page's markdown headings so the shallowest is this level (1-6), nil means as extracted
+
+	UseStructuredData  *bool `yaml:"use_structured_data" json:"use_structured_data"`     // Feed JSON-LD dates into last_modified and breadcrumbs into the page tree
+	UseNavigationTrail *bool `yaml:"use_navigation_trail" json:"use_navigation_trail"`   // Place hierarchical pages under the nearest page of their highlighted navigation trail
+	UseTableOfContents *bool `yaml:"use_table_of_contents" json:"use_table_of_contents"` // Order hierarchical pages as their parent page's table of contents lists them
+
+	// CSS selector of the root page's nested <ul>/<li> menu, such as a docs sidebar, whose nesting places hierarchical pages
+	NavigationMenuSelector string `yaml:"navigation_menu_selector" json:"navigation_menu_selector"`
+
+	// Regexes locating a "last updated" date in page content; the first capture group holds the date
+	LastModifiedPatterns []string `yaml:"last_modified_patterns" json:"last_modified_patterns"`
+
+	// Redundant parent detection (hierarchical output)
+	ExcludeRedundantParents *bool    `yaml:"exclude_redundant_parents" json:"exclude_redundant_parents"` // Omit content of parents already covered by their children
+	RedundancyThreshold     *float64 `yaml:"redundancy_threshold" json:"redundancy_threshold"`           // Containment ratio (0.0-1.0) marking a parent redundant, nil means default (0.8)
+
+	CollapseSingleChildChains *bool `yaml:"collapse_single_child_chains" json:"collapse_single_child_chains"` // Merge content-less pages with their only child in hierarchical output, e.g. one "A / B / C" entry
+
+	// Link filtering; replaces the built-in skipped paths and extensions when set
+	CrawlFilter CrawlFilterConfig `yaml:"crawl_filter" json:"crawl_filter"`
+
+	// Version and language switchers: clusters of links on a page differing only by a version or
+	// locale path segment. When set, only the switcher entry for this version or locale is followed.
+	SwitcherVersion string `yaml:"switcher_version" json:"switcher_version"` // e.g. "v2" or "latest"
+	SwitcherLocale  string `yaml:"switcher_locale" json:"switcher_locale"`   // e.g. "en" or "pt-br"
 
 	// Deduplication Settings
 	Deduplication DeduplicationConfig `yaml:"deduplication" json:"deduplication"`
@@ -46,13 +202,65 @@ type Config struct {
 	DevTools DevToolsConfig `yaml:"devtools" json:"devtools"`
 }
 
+// sha256HexPattern matches a hex-encoded SHA-256 digest
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// hierarchicalFormats are the output formats use_hierarchical_ordering can write
+var hierarchicalFormats = []string{"markdown", "text", "json"}
+
+// MarkerPair delimits a region of a page by the text of its start and end HTML comments
+type MarkerPair struct {
+	Start string `yaml:"start" json:"start"`
+	End   string `yaml:"end" json:"end"`
+}
+
+// RequestSpec describes a seed request; its response is extracted like any crawled page
+type RequestSpec struct {
+	URL     string            `yaml:"url" json:"url"`
+	Method  string            `yaml:"method" json:"method"` // "GET" (default), "POST", "PUT" or "PATCH"
+	Body    string            `yaml:"body" json:"body"`
+	Headers map[string]string `yaml:"headers" json:"headers"` // e.g. Content-Type for the body
+}
+
+// GetMethod returns the upper-cased request method or default (GET)
+func (r RequestSpec) GetMethod() string {
+	if r.Method == "" {
+		return "GET"
+	}
+	return strings.ToUpper(r.Method)
+}
+
+// TagRule tags pages whose URL, or content when Match is "content", matches Pattern
+type TagRule struct {
+	Pattern string `yaml:"pattern" json:"pattern"`
+	Tag     string `yaml:"tag" json:"tag"`
+	Match   string `yaml:"match" json:"match"` // "url" (default) or "content"
+}
+
+// PriorityPattern assigns a crawl priority to URLs matching a regex (higher runs first, unmatched is 0)
+type PriorityPattern struct {
+	Pattern  string `yaml:"pattern" json:"pattern"`
+	Priority int    `yaml:"priority" json:"priority"`
+}
+
+// CrawlFilterConfig selects which discovered links are followed by regexes on the full URL
+type CrawlFilterConfig struct {
+	IncludePatterns []string `yaml:"include_patterns" json:"include_patterns"` // Links must match at least one, when set
+	ExcludePatterns []string `yaml:"exclude_patterns" json:"exclude_patterns"` // Links matching any are skipped
+}
+
 // DeduplicationConfig configures duplicate link detection
 type DeduplicationConfig struct {
-	RemoveFragments     bool `yaml:"remove_fragments" json:"remove_fragments"`           // Remove URL fragments (#section)
-	RemoveQueryParams   bool `yaml:"remove_query_params" json:"remove_query_params"`     // Remove query parameters (?param=value)
-	IgnoreCase          bool `yaml:"ignore_case" json:"ignore_case"`                     // Ignore case in URLs
-	IgnoreWWW           bool `yaml:"ignore_www" json:"ignore_www"`                       // Ignore www prefix
-	IgnoreTrailingSlash bool `yaml:"ignore_trailing_slash" json:"ignore_trailing_slash"` // Ignore trailing slashes
+	RemoveFragments     bool     `yaml:"remove_fragments" json:"remove_fragments"`           // Remove URL fragments (#section)
+	RemoveQueryParams   bool     `yaml:"remove_query_params" json:"remove_query_params"`     // Remove query parameters (?param=value)
+	IgnoreCase          bool     `yaml:"ignore_case" json:"ignore_case"`                     // Ignore case in URLs
+	IgnoreWWW           bool     `yaml:"ignore_www" json:"ignore_www"`                       // Ignore www prefix
+	IgnoreTrailingSlash bool     `yaml:"ignore_trailing_slash" json:"ignore_trailing_slash"` // Ignore trailing slashes
+	AMPPatterns         []string `yaml:"amp_patterns" json:"amp_patterns"`                   // AMP path segments ("amp") or segment suffixes (".amp") to strip
+	KeepPathSegments    bool     `yaml:"keep_path_segments" json:"keep_path_segments"`       // Don't collapse "//", "/./" and "/../" in paths
+
+	DedupeByContent  bool `yaml:"dedupe_by_content" json:"dedupe_by_content"` // Skip pages whose content was already scraped at another URL
+	SimHashThreshold int  `yaml:"simhash_threshold" json:"simhash_threshold"` // Max SimHash Hamming distance (0-64) for near-duplicates, 0 means exact matches only
 }
 
 // QualityConfig configures content quality analysis
@@ -64,6 +272,8 @@ type QualityConfig struct {
 	SkipNavigation      bool     `yaml:"skip_navigation" json:"skip_navigation"`           // Skip navigation pages
 	BlacklistedPatterns []string `yaml:"blacklisted_patterns" json:"blacklisted_patterns"` // Patterns to avoid
 	FilterByLanguage    string   `yaml:"filter_by_language" json:"filter_by_language"`     // Filter by detected language
+
+	AllowUnknownLanguage bool `yaml:"allow_unknown_language" json:"allow_unknown_language"` // Keep pages whose language is detected as "unknown" under filter_by_language
 }
 
 // DevToolsConfig configures development tools
@@ -94,68 +304,331 @@ func LoadConfig(filename string, cfg *Config) error {
 	if err != nil {
 		return err
 	}
+	data = expandEnv(filename, data)
 
-	// Try YAML first, then JSON
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return json.Unmarshal(data, cfg)
+	// Try YAML first, then JSON; both reject unknown keys so typos are reported by name
+	err = yaml.UnmarshalStrict(data, cfg)
+	if err != nil && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid config %s: %v", filename, err)
 	}
 
 	return nil
 }
 
-// Validate validates the configuration
+// expandEnv replaces ${VAR} and $VAR references in a config file with environment variables,
+// so credentials and URLs can stay out of committed files. Unset variables expand to empty with
+// a warning, and $$ is a literal dollar sign.
+func expandEnv(filename string, data []byte) []byte {
+	return []byte(os.Expand(string(data), func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			log.Printf("Warning: %s references unset environment variable %s", filename, name)
+		}
+		return value
+	}))
+}
+
+// Validate validates the configuration, returning the first problem found
 func (c *Config) Validate() error {
-	if c.RootURL == "" {
-		return fmt.Errorf("root_url is required")
+	if errs := c.validationErrors(); len(errs) > 0 {
+		return errs[0]
 	}
+	return nil
+}
+
+// ValidateStrict validates the configuration like Validate but reports every problem found,
+// one per line, instead of stopping at the first
+func (c *Config) ValidateStrict() error {
+	return errors.Join(c.validationErrors()...)
+}
+
+// validationErrors returns every configuration problem in the order Validate checks them
+func (c *Config) validationErrors() []error {
+	var errs []error
 
-	parsedURL, err := url.Parse(c.RootURL)
-	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
-		return fmt.Errorf("invalid root_url")
+	if c.RootURL == "" {
+		errs = append(errs, fmt.Errorf("root_url is required"))
+	} else if parsedURL, err := url.Parse(c.RootURL); err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+		errs = append(errs, fmt.Errorf("invalid root_url"))
 	}
 
 	if c.MinDelay < 0 {
-		return fmt.Errorf("min_delay cannot be negative")
+		errs = append(errs, fmt.Errorf("min_delay cannot be negative"))
 	}
 
 	if c.MaxDelay < c.MinDelay {
-		return fmt.Errorf("max_delay must be greater than or equal to min_delay")
+		errs = append(errs, fmt.Errorf("max_delay must be greater than or equal to min_delay"))
 	}
 
 	if c.MaxDepth < 0 {
-		return fmt.Errorf("max_depth cannot be negative")
+		errs = append(errs, fmt.Errorf("max_depth cannot be negative"))
 	}
 
 	// Validate output format
-	validFormats := []string{"markdown", "text", "json"}
+	validFormats := []string{"markdown", "text", "json", "html", "warc", "auto", "csv", "asciidoc"}
 	if !contains(validFormats, c.OutputFormat) {
-		return fmt.Errorf("invalid output_format")
+		errs = append(errs, fmt.Errorf("invalid output_format"))
+	}
+
+	for _, format := range c.OutputFormats {
+		if !contains(validFormats, format) {
+			errs = append(errs, fmt.Errorf("invalid output_formats entry: %s", format))
+		}
 	}
 
 	// Validate output type
-	validTypes := []string{"single", "per-page"}
+	validTypes := []string{"single", "per-page", "per-depth"}
 	if !contains(validTypes, c.OutputType) {
-		return fmt.Errorf("invalid output_type")
+		errs = append(errs, fmt.Errorf("invalid output_type"))
+	}
+
+	// Hierarchical output only writes the formats its tree generator supports
+	if c.GetUseHierarchicalOrdering() {
+		for _, format := range c.GetOutputFormats() {
+			if contains(validFormats, format) && !contains(hierarchicalFormats, format) {
+				errs = append(errs, fmt.Errorf("output format %s is not supported with use_hierarchical_ordering", format))
+			}
+		}
+	}
+
+	// structure.yaml outlines the document tree, which only hierarchical output builds
+	if c.GetGenerateStructureOutline() && !c.GetUseHierarchicalOrdering() {
+		errs = append(errs, fmt.Errorf("generate_structure_outline requires use_hierarchical_ordering"))
 	}
 
 	// Validate proxies (basic format check only)
 	for _, proxy := range c.Proxies {
 		if proxy == "" {
-			return fmt.Errorf("invalid proxy URL")
+			errs = append(errs, fmt.Errorf("invalid proxy URL"))
 		}
 		// Only reject URLs that look obviously wrong (contain specific invalid patterns)
 		if strings.Contains(proxy, "not-a-valid") {
-			return fmt.Errorf("invalid proxy URL")
+			errs = append(errs, fmt.Errorf("invalid proxy URL"))
 		}
 		// Don't validate URLs with :// patterns here, let proxy setup handle them
 	}
 
 	// Validate optional settings if they are set
 	if c.ConcurrentRequests != nil && *c.ConcurrentRequests <= 0 {
-		return fmt.Errorf("concurrent_requests must be greater than 0")
+		errs = append(errs, fmt.Errorf("concurrent_requests must be greater than 0"))
 	}
 
-	return nil
+	if c.RequestTimeout != nil && *c.RequestTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("request_timeout must be greater than 0"))
+	}
+
+	if c.PerHostParallelism != nil && *c.PerHostParallelism <= 0 {
+		errs = append(errs, fmt.Errorf("per_host_parallelism must be greater than 0"))
+	}
+
+	if c.BackoffStrategy != "" && !contains([]string{"exponential", "full-jitter", "equal-jitter", "constant"}, c.BackoffStrategy) {
+		errs = append(errs, fmt.Errorf("invalid backoff_strategy"))
+	}
+
+	if c.RetryAttempts != nil && *c.RetryAttempts < 0 {
+		errs = append(errs, fmt.Errorf("retry_attempts cannot be negative"))
+	}
+
+	if c.ConnectionRetries != nil && *c.ConnectionRetries < 0 {
+		errs = append(errs, fmt.Errorf("connection_retries cannot be negative"))
+	}
+
+	if c.HTTPRetries != nil && *c.HTTPRetries < 0 {
+		errs = append(errs, fmt.Errorf("http_retries cannot be negative"))
+	}
+
+	if c.ConnectionRetryDelay != nil && *c.ConnectionRetryDelay < 0 {
+		errs = append(errs, fmt.Errorf("connection_retry_delay cannot be negative"))
+	}
+
+	if c.MaxDownloadBytes != nil && *c.MaxDownloadBytes <= 0 {
+		errs = append(errs, fmt.Errorf("max_download_bytes must be greater than 0"))
+	}
+
+	if c.RequestsPerSecond != nil && *c.RequestsPerSecond < 0 {
+		errs = append(errs, fmt.Errorf("requests_per_second cannot be negative"))
+	}
+
+	if c.MinRequestInterval != nil && *c.MinRequestInterval < 0 {
+		errs = append(errs, fmt.Errorf("min_request_interval cannot be negative"))
+	}
+
+	if c.MaxPages != nil && *c.MaxPages < 0 {
+		errs = append(errs, fmt.Errorf("max_pages cannot be negative"))
+	}
+
+	if c.MaxWaves != nil && *c.MaxWaves < 0 {
+		errs = append(errs, fmt.Errorf("max_waves cannot be negative"))
+	}
+
+	if c.InitialDelay != nil && *c.InitialDelay < 0 {
+		errs = append(errs, fmt.Errorf("initial_delay cannot be negative"))
+	}
+
+	if c.APIListingURL != "" {
+		if listingURL, err := url.Parse(c.APIListingURL); err != nil || listingURL.Scheme == "" || listingURL.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid api_listing_url"))
+		}
+	}
+
+	if c.SitemapURL != "" {
+		if sitemapURL, err := url.Parse(c.SitemapURL); err != nil || sitemapURL.Scheme == "" || sitemapURL.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid sitemap_url"))
+		}
+	}
+
+	if c.LinkCheckConcurrency != nil && *c.LinkCheckConcurrency <= 0 {
+		errs = append(errs, fmt.Errorf("link_check_concurrency must be greater than 0"))
+	}
+
+	if c.LinkCheckTimeout != nil && *c.LinkCheckTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("link_check_timeout must be greater than 0"))
+	}
+
+	if c.RedundancyThreshold != nil && (*c.RedundancyThreshold <= 0 || *c.RedundancyThreshold > 1) {
+		errs = append(errs, fmt.Errorf("redundancy_threshold must be between 0 and 1"))
+	}
+
+	if c.MaxLinkRatio != nil && (*c.MaxLinkRatio <= 0 || *c.MaxLinkRatio > 1) {
+		errs = append(errs, fmt.Errorf("max_link_ratio must be between 0 and 1"))
+	}
+
+	if c.MinLanguageConfidence != nil && (*c.MinLanguageConfidence <= 0 || *c.MinLanguageConfidence > 1) {
+		errs = append(errs, fmt.Errorf("min_language_confidence must be between 0 and 1"))
+	}
+
+	if c.MaxTitleLength != nil && *c.MaxTitleLength <= 0 {
+		errs = append(errs, fmt.Errorf("max_title_length must be greater than 0"))
+	}
+
+	if c.FormatWorkers != nil && *c.FormatWorkers <= 0 {
+		errs = append(errs, fmt.Errorf("format_workers must be greater than 0"))
+	}
+
+	if c.PageWorkers != nil && *c.PageWorkers <= 0 {
+		errs = append(errs, fmt.Errorf("page_workers must be greater than 0"))
+	}
+
+	if c.HeadingBaseLevel != nil && (*c.HeadingBaseLevel < 1 || *c.HeadingBaseLevel > 6) {
+		errs = append(errs, fmt.Errorf("heading_base_level must be between 1 and 6"))
+	}
+
+	if c.SortBy != "" && !contains([]string{"url", "title", "depth"}, c.SortBy) {
+		errs = append(errs, fmt.Errorf("invalid sort_by"))
+	}
+
+	if c.MaxOutputFiles != nil && *c.MaxOutputFiles <= 0 {
+		errs = append(errs, fmt.Errorf("max_output_files must be greater than 0"))
+	}
+
+	if c.OnFileLimit != "" && !contains([]string{"bundle", "error"}, c.OnFileLimit) {
+		errs = append(errs, fmt.Errorf("invalid on_file_limit"))
+	}
+
+	if c.TitleStrategy != "" && !contains([]string{"smart", "first"}, c.TitleStrategy) {
+		errs = append(errs, fmt.Errorf("invalid title_strategy"))
+	}
+
+	for _, pattern := range c.CleaningPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid cleaning pattern: %s", pattern))
+		}
+	}
+
+	for _, pattern := range c.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid redact pattern: %s", pattern))
+		}
+	}
+
+	for _, hash := range c.SkipContentHashes {
+		if !sha256HexPattern.MatchString(strings.TrimSpace(hash)) {
+			errs = append(errs, fmt.Errorf("invalid skip content hash: %s", hash))
+		}
+	}
+
+	for _, seed := range c.SeedRequests {
+		if seedURL, err := url.Parse(seed.URL); err != nil || seedURL.Scheme == "" || seedURL.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid seed request url: %s", seed.URL))
+		}
+		if !contains([]string{"GET", "POST", "PUT", "PATCH"}, seed.GetMethod()) {
+			errs = append(errs, fmt.Errorf("invalid seed request method: %s", seed.Method))
+		}
+	}
+
+	for _, rule := range c.TagRules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid tag rule pattern: %s", rule.Pattern))
+		}
+		if strings.TrimSpace(rule.Tag) == "" {
+			errs = append(errs, fmt.Errorf("tag rule for %s requires a tag", rule.Pattern))
+		}
+		if rule.Match != "" && !contains([]string{"url", "content"}, rule.Match) {
+			errs = append(errs, fmt.Errorf("invalid tag rule match: %s", rule.Match))
+		}
+	}
+
+	for _, pattern := range c.PriorityPatterns {
+		if _, err := regexp.Compile(pattern.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid priority pattern: %s", pattern.Pattern))
+		}
+	}
+
+	for _, marker := range c.ExcludeMarkers {
+		if strings.TrimSpace(marker.Start) == "" || strings.TrimSpace(marker.End) == "" {
+			errs = append(errs, fmt.Errorf("exclude_markers entries require start and end"))
+		}
+	}
+
+	if c.Deduplication.SimHashThreshold < 0 || c.Deduplication.SimHashThreshold > 64 {
+		errs = append(errs, fmt.Errorf("simhash_threshold must be between 0 and 64"))
+	}
+
+	for _, pattern := range c.Deduplication.AMPPatterns {
+		if strings.Trim(pattern, ".") == "" || strings.Contains(pattern, "/") {
+			errs = append(errs, fmt.Errorf("invalid amp pattern: %s", pattern))
+		}
+	}
+
+	for _, ext := range c.DownloadExtensions {
+		if strings.Trim(ext, ". ") == "" || strings.Contains(ext, "/") {
+			errs = append(errs, fmt.Errorf("invalid download extension: %s", ext))
+		}
+	}
+
+	for _, name := range c.CaptureDataAttributes {
+		if !strings.HasPrefix(name, "data-") || len(name) == len("data-") {
+			errs = append(errs, fmt.Errorf("invalid data attribute: %s", name))
+		}
+	}
+
+	for _, pattern := range c.CrawlFilter.IncludePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid include pattern: %s", pattern))
+		}
+	}
+
+	for _, pattern := range c.CrawlFilter.ExcludePatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid exclude pattern: %s", pattern))
+		}
+	}
+
+	for _, pattern := range c.LastModifiedPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid last_modified pattern: %s", pattern))
+		}
+	}
+
+	return errs
 }
 
 // GetRandomProxy returns a random proxy from the list, or empty string if none
@@ -180,6 +653,335 @@ func (c *Config) GetConcurrentRequests() int {
 	return *c.ConcurrentRequests
 }
 
+// GetRequestTimeout returns the request timeout in seconds or default (30)
+func (c *Config) GetRequestTimeout() int {
+	if c.RequestTimeout == nil {
+		return 30
+	}
+	return *c.RequestTimeout
+}
+
+// GetFollowMetaRefresh returns the meta refresh setting or default (false)
+func (c *Config) GetFollowMetaRefresh() bool {
+	if c.FollowMetaRefresh == nil {
+		return false
+	}
+	return *c.FollowMetaRefresh
+}
+
+// GetRecordFrontier returns the frontier recording setting or default (false)
+func (c *Config) GetRecordFrontier() bool {
+	if c.RecordFrontier == nil {
+		return false
+	}
+	return *c.RecordFrontier
+}
+
+// GetRespectMetaRobots returns the meta robots setting or default (false)
+func (c *Config) GetRespectMetaRobots() bool {
+	if c.RespectMetaRobots == nil {
+		return false
+	}
+	return *c.RespectMetaRobots
+}
+
+// GetUseSitemap returns the sitemap seeding setting or default (false)
+func (c *Config) GetUseSitemap() bool {
+	if c.UseSitemap == nil {
+		return false
+	}
+	return *c.UseSitemap
+}
+
+// GetSitemapIncremental returns the incremental re-crawl setting or default (false)
+func (c *Config) GetSitemapIncremental() bool {
+	if c.SitemapIncremental == nil {
+		return false
+	}
+	return *c.SitemapIncremental
+}
+
+// GetSitemapURL returns sitemap_url or the root host's /sitemap.xml
+func (c *Config) GetSitemapURL() string {
+	if c.SitemapURL != "" {
+		return c.SitemapURL
+	}
+	rootURL, err := url.Parse(c.RootURL)
+	if err != nil {
+		return ""
+	}
+	return rootURL.Scheme + "://" + rootURL.Host + "/sitemap.xml"
+}
+
+// GetAssetDir returns asset_dir or assets in the output directory
+func (c *Config) GetAssetDir() string {
+	if c.AssetDir != "" {
+		return c.AssetDir
+	}
+	return filepath.Join(c.OutputDir, "assets")
+}
+
+// GetConditionalRequests returns the conditional requests setting or default (false)
+func (c *Config) GetConditionalRequests() bool {
+	if c.ConditionalRequests == nil {
+		return false
+	}
+	return *c.ConditionalRequests
+}
+
+// GetRecordResourceLinks returns the resource link recording setting or default (false)
+func (c *Config) GetRecordResourceLinks() bool {
+	if c.RecordResourceLinks == nil {
+		return false
+	}
+	return *c.RecordResourceLinks
+}
+
+// GetManifestFile returns manifest_file or crawl_manifest.json in the output directory
+func (c *Config) GetManifestFile() string {
+	if c.ManifestFile != "" {
+		return c.ManifestFile
+	}
+	return filepath.Join(c.OutputDir, "crawl_manifest.json")
+}
+
+// GetSpillToDisk returns the spill to disk setting or default (false)
+func (c *Config) GetSpillToDisk() bool {
+	if c.SpillToDisk == nil {
+		return false
+	}
+	return *c.SpillToDisk
+}
+
+// GetMaxDownloadBytes returns the crawl's download budget in bytes or default (0, unlimited)
+func (c *Config) GetMaxDownloadBytes() int {
+	if c.MaxDownloadBytes == nil {
+		return 0
+	}
+	return *c.MaxDownloadBytes
+}
+
+// GetRequestsPerSecond returns the per-host request rate or default (one request per average of
+// min_delay and max_delay, unlimited when both are 0)
+func (c *Config) GetRequestsPerSecond() float64 {
+	if c.RequestsPerSecond == nil {
+		if average := float64(c.MinDelay+c.MaxDelay) / 2; average > 0 {
+			return 1 / average
+		}
+		return 0
+	}
+	return *c.RequestsPerSecond
+}
+
+// GetMinRequestInterval returns the minimum milliseconds between requests to a host or default
+// (min_delay)
+func (c *Config) GetMinRequestInterval() int {
+	if c.MinRequestInterval == nil {
+		return c.MinDelay * 1000
+	}
+	return *c.MinRequestInterval
+}
+
+// GetMaxPages returns the crawl's page limit or default (0, unlimited)
+func (c *Config) GetMaxPages() int {
+	if c.MaxPages == nil {
+		return 0
+	}
+	return *c.MaxPages
+}
+
+// GetIgnoreSSLErrors returns the TLS verification skip setting or default (false)
+func (c *Config) GetIgnoreSSLErrors() bool {
+	if c.IgnoreSSLErrors == nil {
+		return false
+	}
+	return *c.IgnoreSSLErrors
+}
+
+// GetRetryAttempts returns the number of retries for transient errors or default (0)
+func (c *Config) GetRetryAttempts() int {
+	if c.RetryAttempts == nil {
+		return 0
+	}
+	return *c.RetryAttempts
+}
+
+// GetConnectionRetries returns the number of retries for connection errors or default (retry_attempts)
+func (c *Config) GetConnectionRetries() int {
+	if c.ConnectionRetries == nil {
+		return c.GetRetryAttempts()
+	}
+	return *c.ConnectionRetries
+}
+
+// GetHTTPRetries returns the number of retries for 5xx responses or default (retry_attempts)
+func (c *Config) GetHTTPRetries() int {
+	if c.HTTPRetries == nil {
+		return c.GetRetryAttempts()
+	}
+	return *c.HTTPRetries
+}
+
+// GetConnectionRetryDelay returns the base connection retry delay in milliseconds or default (250)
+func (c *Config) GetConnectionRetryDelay() int {
+	if c.ConnectionRetryDelay == nil {
+		return 250
+	}
+	return *c.ConnectionRetryDelay
+}
+
+// GetInitialDelay returns the delay before the first request in seconds or default (0)
+func (c *Config) GetInitialDelay() int {
+	if c.InitialDelay == nil {
+		return 0
+	}
+	return *c.InitialDelay
+}
+
+// GetOutputFormats returns the formats to generate: output_formats when set, otherwise output_format
+func (c *Config) GetOutputFormats() []string {
+	if len(c.OutputFormats) > 0 {
+		return c.OutputFormats
+	}
+	return []string{c.OutputFormat}
+}
+
+// GetReproducibleOutput returns the reproducible output setting or default (false)
+func (c *Config) GetReproducibleOutput() bool {
+	if c.ReproducibleOutput == nil {
+		return false
+	}
+	return *c.ReproducibleOutput
+}
+
+// GetUseStructuredData returns the JSON-LD usage setting or default (false)
+func (c *Config) GetUseStructuredData() bool {
+	if c.UseStructuredData == nil {
+		return false
+	}
+	return *c.UseStructuredData
+}
+
+// GetUseNavigationTrail returns the navigation trail tree placement setting or default (false)
+func (c *Config) GetUseNavigationTrail() bool {
+	if c.UseNavigationTrail == nil {
+		return false
+	}
+	return *c.UseNavigationTrail
+}
+
+// GetUseTableOfContents returns the table of contents child ordering setting or default (false)
+func (c *Config) GetUseTableOfContents() bool {
+	if c.UseTableOfContents == nil {
+		return false
+	}
+	return *c.UseTableOfContents
+}
+
+// GetPreserveHeadingIDs returns the heading id preservation setting or default (false)
+func (c *Config) GetPreserveHeadingIDs() bool {
+	if c.PreserveHeadingIDs == nil {
+		return false
+	}
+	return *c.PreserveHeadingIDs
+}
+
+// GetMaxTitleLength returns the maximum displayed title length, or 0 for no limit
+func (c *Config) GetMaxTitleLength() int {
+	if c.MaxTitleLength == nil {
+		return 0
+	}
+	return *c.MaxTitleLength
+}
+
+// GetHeadingBaseLevel returns the level page headings are shifted to start at, or 0 to leave them as extracted
+func (c *Config) GetHeadingBaseLevel() int {
+	if c.HeadingBaseLevel == nil {
+		return 0
+	}
+	return *c.HeadingBaseLevel
+}
+
+// GetExcludeRedundantParents returns the redundant parent exclusion setting or default (false)
+func (c *Config) GetExcludeRedundantParents() bool {
+	if c.ExcludeRedundantParents == nil {
+		return false
+	}
+	return *c.ExcludeRedundantParents
+}
+
+// GetRedundancyThreshold returns the redundant parent containment threshold or default (0.8)
+func (c *Config) GetRedundancyThreshold() float64 {
+	if c.RedundancyThreshold == nil {
+		return 0.8
+	}
+	return *c.RedundancyThreshold
+}
+
+// GetCollapseSingleChildChains returns the chain collapsing setting or default (false)
+func (c *Config) GetCollapseSingleChildChains() bool {
+	if c.CollapseSingleChildChains == nil {
+		return false
+	}
+	return *c.CollapseSingleChildChains
+}
+
+// GetMaxLinkRatio returns the navigation page link-to-word ratio threshold or default (0.3)
+func (c *Config) GetMaxLinkRatio() float64 {
+	if c.MaxLinkRatio == nil {
+		return 0.3
+	}
+	return *c.MaxLinkRatio
+}
+
+// GetMinLanguageConfidence returns the language detection confidence required for filtering or default (0.5)
+func (c *Config) GetMinLanguageConfidence() float64 {
+	if c.MinLanguageConfidence == nil {
+		return 0.5
+	}
+	return *c.MinLanguageConfidence
+}
+
+// GetMaxWaves returns the number of reconciliation waves or default (0)
+func (c *Config) GetMaxWaves() int {
+	if c.MaxWaves == nil {
+		return 0
+	}
+	return *c.MaxWaves
+}
+
+// GetBackoffStrategy returns the retry backoff strategy or default ("exponential")
+func (c *Config) GetBackoffStrategy() string {
+	if c.BackoffStrategy == "" {
+		return "exponential"
+	}
+	return c.BackoffStrategy
+}
+
+// GetCheckExternalLinks returns the external link checking setting or default (false)
+func (c *Config) GetCheckExternalLinks() bool {
+	if c.CheckExternalLinks == nil {
+		return false
+	}
+	return *c.CheckExternalLinks
+}
+
+// GetLinkCheckConcurrency returns the link checker concurrency or default (4)
+func (c *Config) GetLinkCheckConcurrency() int {
+	if c.LinkCheckConcurrency == nil {
+		return 4
+	}
+	return *c.LinkCheckConcurrency
+}
+
+// GetLinkCheckTimeout returns the link checker timeout in seconds or default (10)
+func (c *Config) GetLinkCheckTimeout() int {
+	if c.LinkCheckTimeout == nil {
+		return 10
+	}
+	return *c.LinkCheckTimeout
+}
+
 // GetUseHierarchicalOrdering returns the hierarchical ordering setting or default (false)
 func (c *Config) GetUseHierarchicalOrdering() bool {
 	if c.UseHierarchicalOrdering == nil {
@@ -212,6 +1014,175 @@ func (c *Config) GetEnableDevTools() bool {
 	return *c.EnableDevTools
 }
 
+// GetNumberedOutput returns the numbered output setting or default (false)
+func (c *Config) GetNumberedOutput() bool {
+	if c.NumberedOutput == nil {
+		return false
+	}
+	return *c.NumberedOutput
+}
+
+// GetGenerateGlossary returns the glossary generation setting or default (false)
+func (c *Config) GetGenerateGlossary() bool {
+	if c.GenerateGlossary == nil {
+		return false
+	}
+	return *c.GenerateGlossary
+}
+
+// GetGenerateJSONSchema returns the JSON schema generation setting or default (false)
+func (c *Config) GetGenerateJSONSchema() bool {
+	if c.GenerateJSONSchema == nil {
+		return false
+	}
+	return *c.GenerateJSONSchema
+}
+
+// GetValidateOutput returns the output validation setting or default (false)
+func (c *Config) GetValidateOutput() bool {
+	if c.ValidateOutput == nil {
+		return false
+	}
+	return *c.ValidateOutput
+}
+
+// GetGenerateStructureOutline returns the structure outline setting or default (false)
+func (c *Config) GetGenerateStructureOutline() bool {
+	if c.GenerateStructureOutline == nil {
+		return false
+	}
+	return *c.GenerateStructureOutline
+}
+
+// GetFormatWorkers returns how many output formats are generated at once or default (4)
+func (c *Config) GetFormatWorkers() int {
+	if c.FormatWorkers == nil {
+		return 4
+	}
+	return *c.FormatWorkers
+}
+
+// GetPageWorkers returns how many per-page output files are written at once or default (4)
+func (c *Config) GetPageWorkers() int {
+	if c.PageWorkers == nil {
+		return 4
+	}
+	return *c.PageWorkers
+}
+
+// GetMaxOutputFiles returns the per-page output file limit or default (0, unlimited)
+func (c *Config) GetMaxOutputFiles() int {
+	if c.MaxOutputFiles == nil {
+		return 0
+	}
+	return *c.MaxOutputFiles
+}
+
+// GetOnFileLimit returns what happens past max_output_files or default ("bundle")
+func (c *Config) GetOnFileLimit() string {
+	if c.OnFileLimit == "" {
+		return "bundle"
+	}
+	return c.OnFileLimit
+}
+
+// GetQueryIsIdentity returns the query-as-identity setting or default (false)
+func (c *Config) GetQueryIsIdentity() bool {
+	if c.QueryIsIdentity == nil {
+		return false
+	}
+	return *c.QueryIsIdentity
+}
+
+// GetSkeletonOnly returns the skeleton-only setting or default (false)
+func (c *Config) GetSkeletonOnly() bool {
+	if c.SkeletonOnly == nil {
+		return false
+	}
+	return *c.SkeletonOnly
+}
+
+// GetGenerateURLIndex returns the index.json setting or default (true)
+func (c *Config) GetGenerateURLIndex() bool {
+	if c.GenerateURLIndex == nil {
+		return true
+	}
+	return *c.GenerateURLIndex
+}
+
+// GetGenerateURLMap returns the URL map setting or default (false)
+func (c *Config) GetGenerateURLMap() bool {
+	if c.GenerateURLMap == nil {
+		return false
+	}
+	return *c.GenerateURLMap
+}
+
+// GetWriteEffectiveConfig returns the effective config setting or default (false)
+func (c *Config) GetWriteEffectiveConfig() bool {
+	if c.WriteEffectiveConfig == nil {
+		return false
+	}
+	return *c.WriteEffectiveConfig
+}
+
+// GetCompressOutput returns the compress output setting or default (false)
+func (c *Config) GetCompressOutput() bool {
+	if c.CompressOutput == nil {
+		return false
+	}
+	return *c.CompressOutput
+}
+
+// GetSectionIndexes returns the section indexes setting or default (false)
+func (c *Config) GetSectionIndexes() bool {
+	if c.SectionIndexes == nil {
+		return false
+	}
+	return *c.SectionIndexes
+}
+
+// GetGenerateTokenReport returns the token report setting or default (false)
+func (c *Config) GetGenerateTokenReport() bool {
+	if c.GenerateTokenReport == nil {
+		return false
+	}
+	return *c.GenerateTokenReport
+}
+
+// GetSkipRootInOutput returns the skip root setting or default (false)
+func (c *Config) GetSkipRootInOutput() bool {
+	if c.SkipRootInOutput == nil {
+		return false
+	}
+	return *c.SkipRootInOutput
+}
+
+// GetGenerateReadme returns the README setting, defaulting to true for per-page, per-depth
+// and hierarchical output, where a README helps navigate the many files
+func (c *Config) GetGenerateReadme() bool {
+	if c.GenerateReadme == nil {
+		return c.OutputType != "single" || c.GetUseHierarchicalOrdering()
+	}
+	return *c.GenerateReadme
+}
+
+// GetFormatSubdirs returns the per-format subdirectory setting or default (false)
+func (c *Config) GetFormatSubdirs() bool {
+	if c.FormatSubdirs == nil {
+		return false
+	}
+	return *c.FormatSubdirs
+}
+
+// GetTitleStrategy returns the title selection strategy or default ("smart")
+func (c *Config) GetTitleStrategy() string {
+	if c.TitleStrategy == "" {
+		return "smart"
+	}
+	return c.TitleStrategy
+}
+
 // SetDefaults sets default values for optional configuration fields
 func (c *Config) SetDefaults() {
 	// Set default values for deduplication
@@ -222,6 +1193,10 @@ func (c *Config) SetDefaults() {
 			IgnoreCase:          true,
 			IgnoreWWW:           true,
 			IgnoreTrailingSlash: true,
+			AMPPatterns:         c.Deduplication.AMPPatterns,
+			KeepPathSegments:    c.Deduplication.KeepPathSegments,
+			DedupeByContent:     c.Deduplication.DedupeByContent,
+			SimHashThreshold:    c.Deduplication.SimHashThreshold,
 		}
 	}
 
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EffectiveConfigFile is the file in output_dir that write_effective_config writes
+const EffectiveConfigFile = "effective-config.yaml"
+
+// redactedValue replaces secrets in the effective configuration
+const redactedValue = "REDACTED"
+
+// sensitiveNamePattern matches header and query parameter names whose values are credentials
+var sensitiveNamePattern = regexp.MustCompile(`(?i)auth|token|secret|passw|cookie|api[-_]?key|session|signature`)
+
+// effectiveConfigHeader introduces the written effective configuration
+const effectiveConfigHeader = `# Effective configuration of this run: defaults applied, secrets replaced with REDACTED.
+# Options left null are unlimited or off. Load it with the same config flag to rerun.
+`
+
+// nilOnlyOptions are pointer options whose nil value means unlimited or off, which no value
+// passing validation expresses; Effective leaves them nil
+var nilOnlyOptions = map[string]bool{
+	"PerHostParallelism": true,
+	"MaxDownloadBytes":   true,
+	"MaxOutputFiles":     true,
+	"MaxTitleLength":     true,
+	"HeadingBaseLevel":   true,
+}
+
+// Effective returns a copy of the configuration with defaults applied, each unset option
+// holding the value its getter returns, and secrets redacted: URL passwords, credential query
+// parameters and credential headers of seed requests
+func (c *Config) Effective() (*Config, error) {
+	applied := *c
+	applied.SetDefaults()
+	applied.applyGetterValues()
+
+	// Round-trip through YAML for a deep copy, so redacting leaves c untouched
+	data, err := yaml.Marshal(&applied)
+	if err != nil {
+		return nil, err
+	}
+	var effective Config
+	if err := yaml.Unmarshal(data, &effective); err != nil {
+		return nil, err
+	}
+
+	effective.redactSecrets()
+	return &effective, nil
+}
+
+// applyGetterValues sets each nil pointer option to the value of its GetX method, so the
+// effective configuration shows the setting that ran rather than null
+func (c *Config) applyGetterValues() {
+	fields := reflect.ValueOf(c).Elem()
+	methods := reflect.ValueOf(c)
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		name := fields.Type().Field(i).Name
+		if field.Kind() != reflect.Ptr || !field.IsNil() || nilOnlyOptions[name] {
+			continue
+		}
+
+		getter := methods.MethodByName("Get" + name)
+		if !getter.IsValid() || getter.Type().NumIn() != 0 || getter.Type().NumOut() != 1 ||
+			getter.Type().Out(0) != field.Type().Elem() {
+			continue
+		}
+		value := reflect.New(field.Type().Elem())
+		value.Elem().Set(getter.Call(nil)[0])
+		field.Set(value)
+	}
+}
+
+// SaveEffective writes the effective configuration to filename as YAML that LoadConfig reads back
+// to the same settings
+func (c *Config) SaveEffective(filename string) error {
+	effective, err := c.Effective()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(effective)
+	if err != nil {
+		return err
+	}
+
+	// LoadConfig expands environment variables, so literal dollars (e.g. in regexes) are escaped
+	escaped := strings.ReplaceAll(string(data), "$", "$$")
+	return os.WriteFile(filename, []byte(effectiveConfigHeader+escaped), 0644)
+}
+
+// redactSecrets replaces credentials in the configuration's URLs and seed request headers
+func (c *Config) redactSecrets() {
+	c.RootURL = redactURL(c.RootURL)
+	c.SitemapURL = redactURL(c.SitemapURL)
+	c.APIListingURL = redactURL(c.APIListingURL)
+	for i, proxy := range c.Proxies {
+		c.Proxies[i] = redactURL(proxy)
+	}
+	for i := range c.SeedRequests {
+		c.SeedRequests[i].URL = redactURL(c.SeedRequests[i].URL)
+		for name := range c.SeedRequests[i].Headers {
+			if sensitiveNamePattern.MatchString(name) {
+				c.SeedRequests[i].Headers[name] = redactedValue
+			}
+		}
+	}
+}
+
+// redactURL replaces the password and credential query parameters of a URL, returning other
+// URLs unchanged
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || raw == "" {
+		return raw
+	}
+
+	redacted := false
+	if _, hasPassword := parsed.User.Password(); hasPassword {
+		parsed.User = url.UserPassword(parsed.User.Username(), redactedValue)
+		redacted = true
+	}
+
+	query := parsed.Query()
+	for name := range query {
+		if sensitiveNamePattern.MatchString(name) {
+			query.Set(name, redactedValue)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return raw
+	}
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String()
+}
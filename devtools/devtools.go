@@ -3,13 +3,16 @@ package devtools
 import (
 	"fmt"
 	"log"
+	"math"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"docscraper/config"
+	"docscraper/scraper"
 )
 
 // DevTools provides development and debugging utilities
@@ -64,10 +67,10 @@ func (dt *DevTools) ValidateConfiguration() error {
 	return nil
 }
 
-// StartDryRun performs a dry run without actually scraping
-func (dt *DevTools) StartDryRun() error {
+// StartDryRun walks the site without scraping it and returns the URLs a real run would scrape
+func (dt *DevTools) StartDryRun() ([]string, error) {
 	if !dt.dryRunMode {
-		return fmt.Errorf("dry run mode not enabled")
+		return nil, fmt.Errorf("dry run mode not enabled")
 	}
 
 	dt.logger.Println("=== DRY RUN MODE ===")
@@ -76,16 +79,25 @@ func (dt *DevTools) StartDryRun() error {
 	dt.logger.Printf("Output Format: %s", dt.config.OutputFormat)
 	dt.logger.Printf("Output Directory: %s", dt.config.OutputDir)
 
-	// Simulate URL discovery
-	dt.logger.Println("Simulating URL discovery...")
-	estimatedURLs := dt.estimateURLCount()
-	dt.logger.Printf("Estimated URLs to scrape: %d", estimatedURLs)
+	dt.logger.Println("Discovering URLs...")
+	s, err := scraper.New(dt.config)
+	if err != nil {
+		return nil, err
+	}
+	urls, err := s.Discover()
+	if err != nil {
+		return nil, err
+	}
+	dt.logger.Printf("URLs to scrape: %d", len(urls))
+	for _, u := range urls {
+		dt.logger.Printf("  %s", u)
+	}
 
 	// Estimate time and resources
-	estimatedTime := time.Duration(estimatedURLs) * time.Second * 2 // 2 seconds per URL
+	estimatedTime := time.Duration(len(urls)) * time.Second * 2 // 2 seconds per URL
 	dt.logger.Printf("Estimated scraping time: %v", estimatedTime)
 
-	return nil
+	return urls, nil
 }
 
 // Debug logs debug information
@@ -111,26 +123,44 @@ func (dt *DevTools) StopProfiling() *PerformanceReport {
 		dt.logger.Printf("  Total Duration: %v", report.TotalDuration)
 		dt.logger.Printf("  Pages Scraped: %d", report.PagesScraped)
 		dt.logger.Printf("  Average Time per Page: %v", report.AverageTimePerPage)
+		dt.logger.Printf("  Time per Page: min %v, p50 %v, p90 %v, p99 %v, max %v",
+			report.MinTimePerPage, report.P50TimePerPage, report.P90TimePerPage, report.P99TimePerPage, report.MaxTimePerPage)
 		dt.logger.Printf("  Total Data Downloaded: %d bytes", report.TotalDataDownloaded)
 		dt.logger.Printf("  Errors Encountered: %d", report.ErrorsEncountered)
+		for _, timing := range report.SlowestExtractions {
+			dt.logger.Printf("  Slow Extraction: %v %s", timing.Duration, timing.URL)
+		}
 	}
 
 	return report
 }
 
-// UpdateProgress updates the progress tracker
-func (dt *DevTools) UpdateProgress(current, total int, currentURL string) {
-	dt.progressBar.Update(current, total, currentURL)
+// Profiler returns the profiler StartProfiling and StopProfiling drive, for
+// EnhancedScraper.SetProfiler to feed the crawl's responses into
+func (dt *DevTools) Profiler() *PerformanceProfiler {
+	return dt.profiler
+}
+
+// RecordError records a failed request, such as a retried or not-modified response, which
+// counts toward errors but not toward pages scraped or page timings
+func (pp *PerformanceProfiler) RecordError() {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.errorsEncountered++
 }
 
-// estimateURLCount estimates the number of URLs that would be scraped
-func (dt *DevTools) estimateURLCount() int {
-	// Simple estimation based on depth and common site structures
-	baseCount := 1
-	for i := 0; i < dt.config.MaxDepth; i++ {
-		baseCount += (i + 1) * 5 // Assume 5 links per page on average
+// RecordPageExtraction records a page's extraction time with the profiler
+func (dt *DevTools) RecordPageExtraction(url string, duration time.Duration) {
+	dt.profiler.RecordPageExtraction(url, duration)
+	if duration > SlowExtractionThreshold {
+		dt.Debug("Slow extraction (%v): %s", duration, url)
 	}
-	return baseCount
+}
+
+// UpdateProgress updates the progress tracker
+func (dt *DevTools) UpdateProgress(current, total int, currentURL string) {
+	dt.progressBar.Update(current, total, currentURL)
 }
 
 // ConfigValidator validates configuration settings
@@ -188,7 +218,7 @@ func (cv *ConfigValidator) ValidateConfig(cfg *config.Config) []ValidationIssue
 	}
 
 	// Validate output format
-	validFormats := []string{"markdown", "text", "json"}
+	validFormats := []string{"markdown", "text", "json", "html", "warc", "auto", "csv", "asciidoc"}
 	if !contains(validFormats, cfg.OutputFormat) {
 		issues = append(issues, ValidationIssue{
 			Type:     "invalid_output_format",
@@ -197,8 +227,18 @@ func (cv *ConfigValidator) ValidateConfig(cfg *config.Config) []ValidationIssue
 		})
 	}
 
+	for _, format := range cfg.OutputFormats {
+		if !contains(validFormats, format) {
+			issues = append(issues, ValidationIssue{
+				Type:     "invalid_output_formats",
+				Severity: "critical",
+				Message:  fmt.Sprintf("Invalid entry '%s' in output formats. Valid formats: %s", format, strings.Join(validFormats, ", ")),
+			})
+		}
+	}
+
 	// Validate output type
-	validTypes := []string{"single", "per-page"}
+	validTypes := []string{"single", "per-page", "per-depth"}
 	if !contains(validTypes, cfg.OutputType) {
 		issues = append(issues, ValidationIssue{
 			Type:     "invalid_output_type",
@@ -207,6 +247,19 @@ func (cv *ConfigValidator) ValidateConfig(cfg *config.Config) []ValidationIssue
 		})
 	}
 
+	if cfg.GetUseHierarchicalOrdering() {
+		hierarchicalFormats := []string{"markdown", "text", "json"}
+		for _, format := range cfg.GetOutputFormats() {
+			if contains(validFormats, format) && !contains(hierarchicalFormats, format) {
+				issues = append(issues, ValidationIssue{
+					Type:     "unsupported_hierarchical_format",
+					Severity: "critical",
+					Message:  fmt.Sprintf("Output format '%s' is not supported with hierarchical ordering. Supported formats: %s", format, strings.Join(hierarchicalFormats, ", ")),
+				})
+			}
+		}
+	}
+
 	// Validate delays
 	if cfg.MinDelay < 0 {
 		issues = append(issues, ValidationIssue{
@@ -233,6 +286,22 @@ func (cv *ConfigValidator) ValidateConfig(cfg *config.Config) []ValidationIssue
 		})
 	}
 
+	if cfg.MaxPages != nil && *cfg.MaxPages < 0 {
+		issues = append(issues, ValidationIssue{
+			Type:     "invalid_max_pages",
+			Severity: "warning",
+			Message:  "Maximum pages should not be negative",
+		})
+	}
+
+	if cfg.GetConditionalRequests() && cfg.StateFile == "" {
+		issues = append(issues, ValidationIssue{
+			Type:     "conditional_requests_without_state",
+			Severity: "warning",
+			Message:  "conditional_requests has no effect without a state_file to keep validators in",
+		})
+	}
+
 	if cfg.MaxDepth > 10 {
 		issues = append(issues, ValidationIssue{
 			Type:     "high_max_depth",
@@ -358,6 +427,17 @@ type PerformanceProfiler struct {
 	errorsEncountered   int
 	pageTimings         []time.Duration
 	mutex               sync.RWMutex
+
+	extractionTimings []PageTiming
+}
+
+// SlowExtractionThreshold is the extraction time above which a page is reported as slow
+const SlowExtractionThreshold = 100 * time.Millisecond
+
+// PageTiming is the time spent on a single page
+type PageTiming struct {
+	URL      string        `json:"url"`
+	Duration time.Duration `json:"duration"`
 }
 
 // NewPerformanceProfiler creates a new performance profiler
@@ -377,6 +457,7 @@ func (pp *PerformanceProfiler) Start() {
 	pp.totalDataDownloaded = 0
 	pp.errorsEncountered = 0
 	pp.pageTimings = pp.pageTimings[:0]
+	pp.extractionTimings = pp.extractionTimings[:0]
 }
 
 // RecordPageScrape records metrics for a scraped page
@@ -393,6 +474,14 @@ func (pp *PerformanceProfiler) RecordPageScrape(duration time.Duration, dataSize
 	}
 }
 
+// RecordPageExtraction records how long extracting and analysing a page's content took
+func (pp *PerformanceProfiler) RecordPageExtraction(url string, duration time.Duration) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	pp.extractionTimings = append(pp.extractionTimings, PageTiming{URL: url, Duration: duration})
+}
+
 // Stop ends profiling and returns a performance report
 func (pp *PerformanceProfiler) Stop() *PerformanceReport {
 	pp.mutex.Lock()
@@ -409,6 +498,9 @@ func (pp *PerformanceProfiler) Stop() *PerformanceReport {
 		averageTimePerPage = total / time.Duration(len(pp.pageTimings))
 	}
 
+	sorted := append([]time.Duration{}, pp.pageTimings...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
 	return &PerformanceReport{
 		TotalDuration:       pp.endTime.Sub(pp.startTime),
 		PagesScraped:        pp.pagesScraped,
@@ -416,7 +508,42 @@ func (pp *PerformanceProfiler) Stop() *PerformanceReport {
 		TotalDataDownloaded: pp.totalDataDownloaded,
 		ErrorsEncountered:   pp.errorsEncountered,
 		PageTimings:         append([]time.Duration{}, pp.pageTimings...),
+		ExtractionTimings:   append([]PageTiming{}, pp.extractionTimings...),
+		SlowestExtractions:  slowestExtractions(pp.extractionTimings),
+		MinTimePerPage:      percentile(sorted, 0),
+		MaxTimePerPage:      percentile(sorted, 100),
+		P50TimePerPage:      percentile(sorted, 50),
+		P90TimePerPage:      percentile(sorted, 90),
+		P99TimePerPage:      percentile(sorted, 99),
+	}
+}
+
+// percentile returns the p-th percentile (0-100) of ascending durations, interpolating linearly
+// between the two nearest ranks; zero when there are none
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
 	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + time.Duration(math.Round(fraction*float64(sorted[lower+1]-sorted[lower])))
+}
+
+// slowestExtractions returns the timings over SlowExtractionThreshold, slowest first
+func slowestExtractions(timings []PageTiming) []PageTiming {
+	var slow []PageTiming
+	for _, timing := range timings {
+		if timing.Duration > SlowExtractionThreshold {
+			slow = append(slow, timing)
+		}
+	}
+	sort.SliceStable(slow, func(i, j int) bool { return slow[i].Duration > slow[j].Duration })
+	return slow
 }
 
 // PerformanceReport contains performance metrics
@@ -427,6 +554,13 @@ type PerformanceReport struct {
 	TotalDataDownloaded int64           `json:"total_data_downloaded"`
 	ErrorsEncountered   int             `json:"errors_encountered"`
 	PageTimings         []time.Duration `json:"page_timings"`
+	ExtractionTimings   []PageTiming    `json:"extraction_timings,omitempty"`  // Per-page extraction and analysis times
+	SlowestExtractions  []PageTiming    `json:"slowest_extractions,omitempty"` // Extractions over SlowExtractionThreshold, slowest first
+	MinTimePerPage      time.Duration   `json:"min_time_per_page"`
+	MaxTimePerPage      time.Duration   `json:"max_time_per_page"`
+	P50TimePerPage      time.Duration   `json:"p50_time_per_page"` // Median of PageTimings
+	P90TimePerPage      time.Duration   `json:"p90_time_per_page"`
+	P99TimePerPage      time.Duration   `json:"p99_time_per_page"`
 }
 
 // SaveReport saves the performance report to a file
@@ -442,6 +576,8 @@ func (pr *PerformanceReport) SaveReport(filename string) error {
 	fmt.Fprintf(file, "Total Duration: %v\n", pr.TotalDuration)
 	fmt.Fprintf(file, "Pages Scraped: %d\n", pr.PagesScraped)
 	fmt.Fprintf(file, "Average Time per Page: %v\n", pr.AverageTimePerPage)
+	fmt.Fprintf(file, "Time per Page: min %v, p50 %v, p90 %v, p99 %v, max %v\n",
+		pr.MinTimePerPage, pr.P50TimePerPage, pr.P90TimePerPage, pr.P99TimePerPage, pr.MaxTimePerPage)
 	fmt.Fprintf(file, "Total Data Downloaded: %d bytes (%.2f MB)\n",
 		pr.TotalDataDownloaded, float64(pr.TotalDataDownloaded)/1024/1024)
 	fmt.Fprintf(file, "Errors Encountered: %d\n", pr.ErrorsEncountered)
@@ -453,5 +589,12 @@ func (pr *PerformanceReport) SaveReport(filename string) error {
 		}
 	}
 
+	if len(pr.SlowestExtractions) > 0 {
+		fmt.Fprintf(file, "\nSlow Extractions (over %v):\n", SlowExtractionThreshold)
+		for _, timing := range pr.SlowestExtractions {
+			fmt.Fprintf(file, "%v: %s\n", timing.Duration, timing.URL)
+		}
+	}
+
 	return nil
 }
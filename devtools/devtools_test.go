@@ -1,8 +1,14 @@
 package devtools
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -152,6 +158,24 @@ func TestPerformanceProfiler(t *testing.T) {
 	}
 }
 
+func TestPerformanceProfiler_RecordError(t *testing.T) {
+	profiler := NewPerformanceProfiler()
+	profiler.Start()
+
+	profiler.RecordPageScrape(100*time.Millisecond, 1024, false)
+	profiler.RecordError()
+	profiler.RecordError()
+
+	report := profiler.Stop()
+	if report.PagesScraped != 1 || len(report.PageTimings) != 1 {
+		t.Errorf("Expected errors kept out of pages and timings, got %d pages and %d timings",
+			report.PagesScraped, len(report.PageTimings))
+	}
+	if report.ErrorsEncountered != 2 {
+		t.Errorf("Expected 2 errors, got %d", report.ErrorsEncountered)
+	}
+}
+
 func TestDevToolsValidation(t *testing.T) {
 	// Create temporary directory for test
 	tempDir, err := os.MkdirTemp("", "devtools_test")
@@ -179,19 +203,74 @@ func TestDevToolsValidation(t *testing.T) {
 }
 
 func TestDevToolsDryRun(t *testing.T) {
+	// / links to /a and /b; /a links on to /a/deep, past max_depth. The external link,
+	// stylesheet and repeated links are never scraped.
+	pages := map[string]string{
+		"/":       `<a href="/a">A</a> <a href="/b">B</a> <a href="/b">B again</a> <a href="https://elsewhere.example/">Out</a> <a href="/style.css">CSS</a>`,
+		"/a":      `<a href="/a/one">One</a> <a href="/">Home</a>`,
+		"/b":      `<a href="/b/two">Two</a>`,
+		"/a/one":  `<a href="/a/deep">Deep</a>`,
+		"/b/two":  `<p>Leaf</p>`,
+		"/a/deep": `<p>Too deep</p>`,
+	}
+	heads := make(map[string]int)
+	var mutex sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodHead {
+			mutex.Lock()
+			heads[r.URL.Path]++
+			mutex.Unlock()
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<html><head><title>%s</title></head><body>%s</body></html>", r.URL.Path, body)
+	}))
+	defer server.Close()
+
 	cfg := &config.Config{
-		RootURL:      "https://example.com",
+		RootURL:      server.URL + "/",
 		OutputFormat: "markdown",
 		OutputType:   "single",
-		OutputDir:    "/tmp/test",
+		OutputDir:    t.TempDir(),
+		LogFile:      filepath.Join(t.TempDir(), "scraper.log"),
 		MaxDepth:     3,
 	}
 
 	devtools := NewDevTools(cfg, false, true)
 
-	err := devtools.StartDryRun()
+	urls, err := devtools.StartDryRun()
 	if err != nil {
-		t.Errorf("Dry run failed: %v", err)
+		t.Fatalf("Dry run failed: %v", err)
+	}
+
+	expected := []string{
+		server.URL + "/",
+		server.URL + "/a",
+		server.URL + "/a/one",
+		server.URL + "/b",
+		server.URL + "/b/two",
+	}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected discovered URLs %v, got %v", expected, urls)
+	}
+
+	// Pages at max_depth are only checked, never downloaded
+	mutex.Lock()
+	defer mutex.Unlock()
+	if heads["/a/one"] != 1 || heads["/b/two"] != 1 {
+		t.Errorf("Expected one HEAD request per page at max depth, got %v", heads)
+	}
+}
+
+func TestDevToolsDryRunDisabled(t *testing.T) {
+	devtools := NewDevTools(&config.Config{RootURL: "https://example.com"}, false, false)
+
+	if _, err := devtools.StartDryRun(); err == nil {
+		t.Error("Expected an error when dry run mode is not enabled")
 	}
 }
 
@@ -270,3 +349,99 @@ func TestDevToolsProfiling(t *testing.T) {
 		t.Errorf("Expected 1 page scraped, got %d", report.PagesScraped)
 	}
 }
+
+func TestPerformanceProfiler_Percentiles(t *testing.T) {
+	profiler := NewPerformanceProfiler()
+	profiler.Start()
+
+	// 10ms to 100ms in steps of 10ms, recorded out of order
+	for _, ms := range []int{70, 10, 100, 40, 20, 90, 30, 60, 50, 80} {
+		profiler.RecordPageScrape(time.Duration(ms)*time.Millisecond, 0, false)
+	}
+	report := profiler.Stop()
+
+	tests := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"min", report.MinTimePerPage, 10 * time.Millisecond},
+		{"p50", report.P50TimePerPage, 55 * time.Millisecond},
+		{"p90", report.P90TimePerPage, 91 * time.Millisecond},
+		{"p99", report.P99TimePerPage, 99100 * time.Microsecond},
+		{"max", report.MaxTimePerPage, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// Percentiles work on a sorted copy; PageTimings keeps the recording order
+	if report.PageTimings[0] != 70*time.Millisecond {
+		t.Errorf("Expected PageTimings in recording order, got %v first", report.PageTimings[0])
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.txt")
+	if err := report.SaveReport(filename); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Time per Page: min 10ms, p50 55ms, p90 91ms, p99 99.1ms, max 100ms"; !strings.Contains(string(content), want) {
+		t.Errorf("Expected %q in the saved report, got:\n%s", want, content)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of no timings = %v, want 0", got)
+	}
+	single := []time.Duration{42 * time.Millisecond}
+	for _, p := range []float64{0, 50, 99, 100} {
+		if got := percentile(single, p); got != 42*time.Millisecond {
+			t.Errorf("percentile(%v) of a single timing = %v, want 42ms", p, got)
+		}
+	}
+}
+
+func TestPerformanceProfiler_SlowestExtractions(t *testing.T) {
+	profiler := NewPerformanceProfiler()
+	profiler.Start()
+
+	profiler.RecordPageExtraction("https://example.com/fast", 5*time.Millisecond)
+	profiler.RecordPageExtraction("https://example.com/slow", 150*time.Millisecond)
+	profiler.RecordPageExtraction("https://example.com/threshold", SlowExtractionThreshold)
+	profiler.RecordPageExtraction("https://example.com/slowest", 400*time.Millisecond)
+
+	report := profiler.Stop()
+
+	if len(report.ExtractionTimings) != 4 {
+		t.Errorf("Expected 4 extraction timings, got %d", len(report.ExtractionTimings))
+	}
+
+	// Only pages over the threshold are flagged, slowest first
+	want := []string{"https://example.com/slowest", "https://example.com/slow"}
+	if len(report.SlowestExtractions) != len(want) {
+		t.Fatalf("Expected %d slow extractions, got %v", len(want), report.SlowestExtractions)
+	}
+	for i, url := range want {
+		if report.SlowestExtractions[i].URL != url {
+			t.Errorf("SlowestExtractions[%d] = %s, want %s", i, report.SlowestExtractions[i].URL, url)
+		}
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.txt")
+	if err := report.SaveReport(filename); err != nil {
+		t.Fatalf("Failed to save report: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Slow Extractions") || !strings.Contains(string(data), "400ms: https://example.com/slowest") {
+		t.Errorf("Expected the saved report to list slow extractions, got:\n%s", data)
+	}
+}
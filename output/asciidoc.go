@@ -0,0 +1,308 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"docscraper/config"
+)
+
+var (
+	// asciiDocImagePattern matches a markdown image, ![alt](src)
+	asciiDocImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+	// asciiDocLinkPattern matches a markdown link, [text](target)
+	asciiDocLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+	// asciiDocBoldPattern matches markdown strong emphasis, **text**
+	asciiDocBoldPattern = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+
+	// asciiDocListPattern matches a markdown list item, capturing its indent and marker
+	asciiDocListPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+`)
+)
+
+// generateAsciiDocOutput generates AsciiDoc output, laid out like the markdown output
+func (g *Generator) generateAsciiDocOutput() error {
+	switch g.config.OutputType {
+	case "single":
+		return g.generateSingleAsciiDoc()
+	case "per-depth":
+		return g.generatePerDepthAsciiDoc()
+	default:
+		return g.generatePerPageAsciiDoc()
+	}
+}
+
+// generateSingleAsciiDoc creates documentation.adoc with every page as a level 1 section
+func (g *Generator) generateSingleAsciiDoc() error {
+	file, err := os.Create(filepath.Join(g.config.OutputDir, "documentation.adoc"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "= Documentation Scrape Results\n:toc:\n:toclevels: 2\n\n")
+	g.writeAsciiDocSummary(file)
+
+	for i, page := range g.pages {
+		fmt.Fprintf(file, "[[%s]]\n", asciiDocID(g.createAnchor(identityTitle(g.config, page.Title, page.URL)), i))
+		fmt.Fprintf(file, "== %s\n\n", displayTitle(g.config, page.Title))
+		writeAsciiDocPageMeta(file, g.config, page.URL, page.Tags, page.Metadata, page.Timestamp)
+		if err := writeAsciiDocContent(file, page, 3); err != nil {
+			return err
+		}
+
+		if i < len(g.pages)-1 {
+			fmt.Fprintf(file, "'''\n\n")
+		}
+	}
+
+	return nil
+}
+
+// generatePerPageAsciiDoc creates a page_NNN.adoc document per page and an index.adoc
+func (g *Generator) generatePerPageAsciiDoc() error {
+	for i, page := range g.pages {
+		filename := fmt.Sprintf("page_%03d.adoc", i+1)
+		if err := g.writeAsciiDocPage(filepath.Join(g.config.OutputDir, filename), page); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(filepath.Join(g.config.OutputDir, "index.adoc"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "= Documentation Index\n\n")
+	g.writeAsciiDocSummary(file)
+	fmt.Fprintf(file, "== Pages\n\n")
+	for i, page := range g.pages {
+		fmt.Fprintf(file, ". xref:page_%03d.adoc[%s]\n", i+1, asciiDocLinkText(displayTitle(g.config, page.Title)))
+	}
+
+	return nil
+}
+
+// generatePerDepthAsciiDoc creates per-page AsciiDoc documents grouped into depth-N subdirectories
+func (g *Generator) generatePerDepthAsciiDoc() error {
+	groups, depths := g.pagesByDepth()
+
+	for _, depth := range depths {
+		dir := filepath.Join(g.config.OutputDir, depthDirName(depth))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		for _, i := range groups[depth] {
+			filename := fmt.Sprintf("page_%03d.adoc", i+1)
+			if err := g.writeAsciiDocPage(filepath.Join(dir, filename), g.pages[i]); err != nil {
+				return err
+			}
+		}
+
+		if err := g.writeAsciiDocDepthIndex(dir, depth, groups[depth]); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(filepath.Join(g.config.OutputDir, "index.adoc"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "= Documentation Index\n\n")
+	g.writeAsciiDocSummary(file)
+	fmt.Fprintf(file, "== Depths\n\n")
+	for _, depth := range depths {
+		fmt.Fprintf(file, "* xref:%s/index.adoc[Depth %d] (%d pages)\n", depthDirName(depth), depth, len(groups[depth]))
+	}
+
+	return nil
+}
+
+// writeAsciiDocDepthIndex writes the index.adoc listing the pages of a single depth directory
+func (g *Generator) writeAsciiDocDepthIndex(dir string, depth int, indexes []int) error {
+	file, err := os.Create(filepath.Join(dir, "index.adoc"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "= Depth %d\n\n", depth)
+	fmt.Fprintf(file, "*Total Pages:* %d\n\n", len(indexes))
+	fmt.Fprintf(file, "== Pages\n\n")
+	for _, i := range indexes {
+		fmt.Fprintf(file, ". xref:page_%03d.adoc[%s]\n", i+1, asciiDocLinkText(displayTitle(g.config, g.pages[i].Title)))
+	}
+
+	return nil
+}
+
+// writeAsciiDocPage writes a single page as a standalone AsciiDoc document
+func (g *Generator) writeAsciiDocPage(filename string, page PageData) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	g.recordPageFile(page, filename)
+
+	fmt.Fprintf(file, "= %s\n\n", displayTitle(g.config, page.Title))
+	writeAsciiDocPageMeta(file, g.config, page.URL, page.Tags, page.Metadata, page.Timestamp)
+	fmt.Fprintf(file, "'''\n\n")
+	return writeAsciiDocContent(file, page, 2)
+}
+
+// writeAsciiDocSummary writes the source URL, generation time and page count of an index
+func (g *Generator) writeAsciiDocSummary(w io.Writer) {
+	fmt.Fprintf(w, "*Scraped from:* %s +\n", g.config.RootURL)
+	fmt.Fprintf(w, "*Generated:* %s +\n", generatedAt(g.config))
+	fmt.Fprintf(w, "*Total Pages:* %d\n\n", len(g.pages))
+}
+
+// writeAsciiDocPageMeta writes the same page details as writeMarkdownPageMeta, as AsciiDoc
+func writeAsciiDocPageMeta(w io.Writer, cfg *config.Config, url string, tags []string, metadata map[string]string, scraped time.Time) {
+	lines := []string{fmt.Sprintf("*URL:* %s", url)}
+	if len(tags) > 0 {
+		lines = append(lines, fmt.Sprintf("*Tags:* %s", strings.Join(tags, ", ")))
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("*%s:* %s", key, metadata[key]))
+	}
+	if !cfg.GetReproducibleOutput() {
+		lines = append(lines, fmt.Sprintf("*Scraped:* %s", scraped.Format(time.RFC3339)))
+	}
+	fmt.Fprintf(w, "%s\n\n", strings.Join(lines, " +\n"))
+}
+
+// writeAsciiDocContent writes a page's markdown content as AsciiDoc, its shallowest heading at
+// section level base
+func writeAsciiDocContent(w io.Writer, page PageData, base int) error {
+	content, err := pageContent(page)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, markdownToAsciiDoc(content, base)+"\n\n")
+	return err
+}
+
+// asciiDocID returns anchor as a valid AsciiDoc id, which must start with a letter or "_",
+// falling back to page-N for titles without usable characters
+func asciiDocID(anchor string, index int) string {
+	if anchor == "" {
+		return fmt.Sprintf("page-%d", index+1)
+	}
+	if c := anchor[0]; c < 'a' || c > 'z' {
+		return "_" + anchor
+	}
+	return anchor
+}
+
+// asciiDocLinkText escapes the closing bracket that would end a macro's link text early
+func asciiDocLinkText(text string) string {
+	return strings.ReplaceAll(text, "]", `\]`)
+}
+
+// markdownToAsciiDoc converts extracted markdown to AsciiDoc: headings shifted so the shallowest
+// is at level base become "="-prefixed sections, fenced code becomes [source] listing blocks,
+// and links, images, bold text, lists and rules use AsciiDoc syntax
+func markdownToAsciiDoc(content string, base int) string {
+	lines := strings.Split(normalizeHeadingLevels(content, base), "\n")
+
+	headings := make(map[int]int)
+	headingLines(lines, func(i, level int) { headings[i] = level })
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			out = append(out, asciiDocSourceBlock(strings.TrimSpace(trimmed[3:]), code)...)
+			continue
+		}
+
+		if level, ok := headings[i]; ok {
+			title, id := splitHeadingID(strings.TrimSpace(line[level:]))
+			if id != "" {
+				out = append(out, "[#"+id+"]")
+			}
+			out = append(out, strings.Repeat("=", level)+" "+asciiDocInline(title))
+			continue
+		}
+
+		if trimmed == "---" || trimmed == "***" || trimmed == "___" {
+			out = append(out, "'''")
+			continue
+		}
+
+		if match := asciiDocListPattern.FindStringSubmatch(line); match != nil {
+			depth := len(strings.ReplaceAll(match[1], "\t", "  "))/2 + 1
+			marker := "*"
+			if match[2][0] >= '0' && match[2][0] <= '9' {
+				marker = "."
+			}
+			out = append(out, strings.Repeat(marker, depth)+" "+asciiDocInline(line[len(match[0]):]))
+			continue
+		}
+		out = append(out, asciiDocInline(line))
+	}
+	return strings.Join(out, "\n")
+}
+
+// asciiDocSourceBlock renders a fenced code block as a listing block, with a [source,lang]
+// style when the fence names a language; the delimiter grows past any line of the code
+func asciiDocSourceBlock(language string, code []string) []string {
+	delimiter := "----"
+	for _, line := range code {
+		for strings.TrimSpace(line) == delimiter {
+			delimiter += "-"
+		}
+	}
+
+	style := "[source]"
+	if language != "" {
+		style = "[source," + language + "]"
+	}
+	block := append([]string{style, delimiter}, code...)
+	return append(block, delimiter)
+}
+
+// asciiDocInline converts a line's images, links and bold text to AsciiDoc
+func asciiDocInline(line string) string {
+	line = asciiDocImagePattern.ReplaceAllStringFunc(line, func(image string) string {
+		match := asciiDocImagePattern.FindStringSubmatch(image)
+		return "image:" + match[2] + "[" + asciiDocLinkText(match[1]) + "]"
+	})
+	line = asciiDocLinkPattern.ReplaceAllStringFunc(line, func(link string) string {
+		match := asciiDocLinkPattern.FindStringSubmatch(link)
+		target, text := match[2], asciiDocLinkText(match[1])
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "mailto:") {
+			return target + "[" + text + "]"
+		}
+		if strings.HasPrefix(target, "#") {
+			return "<<" + target[1:] + "," + text + ">>"
+		}
+		return "link:" + target + "[" + text + "]"
+	})
+	return asciiDocBoldPattern.ReplaceAllString(line, "*$1*")
+}
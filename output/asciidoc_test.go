@@ -0,0 +1,124 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// asciiDocTestPages returns pages whose content has nested headings and a code sample
+func asciiDocTestPages() []PageData {
+	return []PageData{
+		{
+			Title:     "Install",
+			URL:       "https://example.com/install",
+			Content:   "# Install\n\nRun the [installer](https://example.com/get) with **care**.\n\n## Linux\n\n```bash\n./install.sh --prefix /usr\n```\n\n- one\n  - nested",
+			Timestamp: time.Now(),
+			Depth:     1,
+		},
+		{Title: "Usage", URL: "https://example.com/usage", Content: "See [install](/install).", Timestamp: time.Now(), Depth: 2},
+	}
+}
+
+func TestGenerator_Generate_AsciiDocSingle(t *testing.T) {
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    t.TempDir(),
+		OutputFormat: "asciidoc",
+		OutputType:   "single",
+	}
+	if err := New(cfg, asciiDocTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "documentation.adoc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := string(data)
+
+	// Pages are level 1 sections under the document title, their content headings nested below
+	for _, want := range []string{
+		"= Documentation Scrape Results\n",
+		"[[install]]\n== Install\n",
+		"\n=== Install\n",
+		"\n==== Linux\n",
+		"[source,bash]\n----\n./install.sh --prefix /usr\n----\n",
+		"https://example.com/get[installer] with *care*",
+		"* one\n** nested",
+		"link:/install[install]",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "```") || strings.Contains(output, "\n# ") {
+		t.Errorf("Expected no markdown fences or headings, got:\n%s", output)
+	}
+}
+
+func TestGenerator_Generate_AsciiDocPerPage(t *testing.T) {
+	for _, outputType := range []string{"per-page", "per-depth"} {
+		t.Run(outputType, func(t *testing.T) {
+			cfg := &config.Config{
+				RootURL:      "https://example.com",
+				OutputDir:    t.TempDir(),
+				OutputFormat: "asciidoc",
+				OutputType:   outputType,
+			}
+			if err := New(cfg, asciiDocTestPages()).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			page := filepath.Join(cfg.OutputDir, "page_001.adoc")
+			if outputType == "per-depth" {
+				page = filepath.Join(cfg.OutputDir, depthDirName(1), "page_001.adoc")
+			}
+			data, err := os.ReadFile(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(data), "= Install\n") || !strings.Contains(string(data), "\n== Install\n") || !strings.Contains(string(data), "\n=== Linux\n") {
+				t.Errorf("Expected the page title as document title and content sections below it, got:\n%s", data)
+			}
+
+			index, err := os.ReadFile(filepath.Join(cfg.OutputDir, "index.adoc"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(index), "xref:") {
+				t.Errorf("Expected index.adoc to link pages with xref, got:\n%s", index)
+			}
+		})
+	}
+}
+
+func TestMarkdownToAsciiDoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		base    int
+		want    string
+	}{
+		{"heading id", "## Setup {#setup}", 2, "[#setup]\n== Setup"},
+		{"untyped fence", "```\ncode\n```", 2, "[source]\n----\ncode\n----"},
+		{"delimiter in code", "~~~yaml\n----\n~~~", 2, "[source,yaml]\n-----\n----\n-----"},
+		{"fenced heading untouched", "```\n# comment\n```", 2, "[source]\n----\n# comment\n----"},
+		{"ordered list", "1. first\n2. second", 2, ". first\n. second"},
+		{"image", "![Logo](/logo.png)", 2, "image:/logo.png[Logo]"},
+		{"anchor link", "[Setup](#setup)", 2, "<<setup,Setup>>"},
+		{"rule", "---", 2, "'''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownToAsciiDoc(tt.content, tt.base); got != tt.want {
+				t.Errorf("markdownToAsciiDoc(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
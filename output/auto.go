@@ -0,0 +1,153 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source kinds used by the "auto" output format
+const (
+	sourceHTML     = "html"
+	sourceMarkdown = "markdown"
+	sourceJSON     = "json"
+	sourceText     = "text"
+)
+
+// sourceKind classifies a page by the content type it was served with
+func sourceKind(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case mediaType == "text/markdown" || mediaType == "text/x-markdown":
+		return sourceMarkdown
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return sourceJSON
+	case mediaType == "text/plain":
+		return sourceText
+	default:
+		return sourceHTML
+	}
+}
+
+// autoFilename returns the per-page file written for a page by the "auto" format
+func autoFilename(page PageData, index int) string {
+	if sourceKind(page.ContentType) == sourceText {
+		return fmt.Sprintf("page_%03d.txt", index+1)
+	}
+	return fmt.Sprintf("page_%03d.md", index+1)
+}
+
+// generateAutoOutput writes each page in the format best matching its source: markdown is kept
+// verbatim, HTML is rendered as markdown, JSON specs become structured markdown and plain text stays text
+func (g *Generator) generateAutoOutput() error {
+	for i, page := range g.pages {
+		content, err := pageContent(page)
+		if err != nil {
+			return err
+		}
+		page.Content = content
+
+		filename := filepath.Join(g.config.OutputDir, autoFilename(page, i))
+
+		switch sourceKind(page.ContentType) {
+		case sourceMarkdown:
+			err = writeOutputFile(g.config, filename, []byte(strings.TrimRight(page.Content, "\n")+"\n"))
+		case sourceJSON:
+			err = writeOutputFile(g.config, filename, []byte(renderJSONSpec(page)))
+		case sourceText:
+			err = writeOutputFile(g.config, filename, []byte(fmt.Sprintf("TITLE: %s\nURL: %s\n\n%s\n", page.Title, page.URL, page.Content)))
+		default:
+			err = g.writeMarkdownPage(filename, page)
+		}
+		if err != nil {
+			return err
+		}
+		g.recordPageFile(page, outputName(g.config, filename))
+	}
+
+	// Create index linking the mixed file types
+	file, err := os.Create(filepath.Join(g.config.OutputDir, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# Documentation Index\n\n")
+	fmt.Fprintf(file, "**Scraped from:** %s  \n", g.config.RootURL)
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(g.config))
+	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(g.pages))
+	fmt.Fprintf(file, "## Pages\n\n")
+
+	for i, page := range g.pages {
+		fmt.Fprintf(file, "%d. [%s](%s) (%s)\n", i+1, displayTitle(g.config, page.Title), outputName(g.config, autoFilename(page, i)), sourceKind(page.ContentType))
+	}
+
+	return nil
+}
+
+// renderJSONSpec renders a JSON page as markdown, summarizing OpenAPI/Swagger endpoints when present
+func renderJSONSpec(page PageData) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n", page.Title)
+	fmt.Fprintf(&builder, "**URL:** %s\n\n", page.URL)
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(page.Content), &spec); err != nil || (spec["openapi"] == nil && spec["swagger"] == nil) {
+		fmt.Fprintf(&builder, "```json\n%s\n```\n", prettyJSON(page.Content))
+		return builder.String()
+	}
+
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		if version, ok := info["version"].(string); ok {
+			fmt.Fprintf(&builder, "**Version:** %s\n\n", version)
+		}
+		if description, ok := info["description"].(string); ok && description != "" {
+			fmt.Fprintf(&builder, "%s\n\n", description)
+		}
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	fmt.Fprintf(&builder, "## Endpoints\n\n")
+
+	pathNames := make([]string, 0, len(paths))
+	for path := range paths {
+		pathNames = append(pathNames, path)
+	}
+	sort.Strings(pathNames)
+
+	for _, path := range pathNames {
+		operations, _ := paths[path].(map[string]interface{})
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			fmt.Fprintf(&builder, "### %s %s\n\n", strings.ToUpper(method), path)
+			if operation, ok := operations[method].(map[string]interface{}); ok {
+				if summary, ok := operation["summary"].(string); ok && summary != "" {
+					fmt.Fprintf(&builder, "%s\n\n", summary)
+				}
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+// prettyJSON indents valid JSON and returns anything else unchanged
+func prettyJSON(content string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return content
+	}
+	formatted, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return content
+	}
+	return string(formatted)
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestSourceKind(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"text/html; charset=utf-8", sourceHTML},
+		{"", sourceHTML},
+		{"text/markdown", sourceMarkdown},
+		{"text/x-markdown; charset=utf-8", sourceMarkdown},
+		{"application/json", sourceJSON},
+		{"application/vnd.oai.openapi+json", sourceJSON},
+		{"text/plain", sourceText},
+	}
+
+	for _, tt := range tests {
+		if got := sourceKind(tt.contentType); got != tt.want {
+			t.Errorf("sourceKind(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestGenerator_Generate_Auto(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "auto",
+		OutputType:   "per-page",
+	}
+
+	spec := `{"openapi": "3.0.0", "info": {"title": "Pets API", "version": "1.2.0"},
+		"paths": {"/pets": {"get": {"summary": "List pets"}, "post": {"summary": "Create a pet"}}}}`
+
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", ContentType: "text/html; charset=utf-8", Timestamp: time.Now()},
+		{Title: "Readme", URL: "https://example.com/README.md", Content: "# Readme\n\nSome *markdown*.", ContentType: "text/markdown", Timestamp: time.Now()},
+		{Title: "Pets API", URL: "https://example.com/openapi.json", Content: spec, ContentType: "application/json", Timestamp: time.Now()},
+		{Title: "data.json", URL: "https://example.com/data.json", Content: `{"a":1}`, ContentType: "application/json", Timestamp: time.Now()},
+		{Title: "notes.txt", URL: "https://example.com/notes.txt", Content: "Plain notes", ContentType: "text/plain", Timestamp: time.Now()},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+
+	if html := files["page_001.md"]; !strings.Contains(html, "# Home") || !strings.Contains(html, "Home content") {
+		t.Errorf("HTML page should be rendered as markdown, got:\n%s", html)
+	}
+	if md := files["page_002.md"]; md != "# Readme\n\nSome *markdown*.\n" {
+		t.Errorf("Markdown page should be kept verbatim, got:\n%q", md)
+	}
+
+	api := files["page_003.md"]
+	for _, want := range []string{"# Pets API", "**Version:** 1.2.0", "### GET /pets", "List pets", "### POST /pets"} {
+		if !strings.Contains(api, want) {
+			t.Errorf("OpenAPI page missing %q, got:\n%s", want, api)
+		}
+	}
+	if !strings.Contains(files["page_004.md"], "```json\n{\n  \"a\": 1\n}\n```") {
+		t.Errorf("Generic JSON page should be rendered as a fenced block, got:\n%s", files["page_004.md"])
+	}
+
+	if _, ok := files["page_005.md"]; ok {
+		t.Error("Plain text page should not be written as markdown")
+	}
+	if !strings.Contains(files["page_005.txt"], "Plain notes") {
+		t.Errorf("Plain text page should be written as text, got:\n%s", files["page_005.txt"])
+	}
+
+	index := files["index.md"]
+	for _, want := range []string{
+		"[Home](page_001.md) (html)",
+		"[Readme](page_002.md) (markdown)",
+		"[Pets API](page_003.md) (json)",
+		"[notes.txt](page_005.txt) (text)",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("Index missing %q, got:\n%s", want, index)
+		}
+	}
+}
+
+func TestGenerator_Generate_AutoGlossaryLinks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	generate := true
+	cfg := &config.Config{
+		RootURL:          "https://example.com",
+		OutputDir:        tmpDir,
+		OutputFormat:     "auto",
+		OutputType:       "per-page",
+		GenerateGlossary: &generate,
+	}
+
+	pages := []PageData{
+		{Title: "Notes", URL: "https://example.com/notes.txt", Content: "Plain notes", ContentType: "text/plain", Timestamp: time.Now()},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	glossary, err := os.ReadFile(filepath.Join(tmpDir, "glossary.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(glossary), "(page_001.txt)") {
+		t.Errorf("Glossary should link to the auto output file, got:\n%s", glossary)
+	}
+}
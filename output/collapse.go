@@ -0,0 +1,43 @@
+package output
+
+import "docscraper/scraper"
+
+// collapseSingleChildChains merges each page without meaningful content that has a single child
+// into that child, titled "Parent / Child", so URL chains like /a -> /a/b -> /a/b/c become one
+// entry; levels and tree statistics are recalculated afterwards
+func collapseSingleChildChains(tree *DocumentTree) error {
+	var collapse func(node *DocumentNode) error
+	collapse = func(node *DocumentNode) error {
+		for i, child := range node.Children {
+			for len(child.Children) == 1 {
+				content, err := child.loadContent()
+				if err != nil {
+					return err
+				}
+				if scraper.HasMeaningfulContent(content) {
+					break
+				}
+				only := child.Children[0]
+				only.Title = scraper.JoinChainTitle(child.Title, only.Title)
+				only.Parent = node
+				delete(tree.NodeMap, child.URL)
+				child = only
+			}
+			node.Children[i] = child
+			child.Level = node.Level + 1
+			if err := collapse(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if tree.Root != nil {
+		if err := collapse(tree.Root); err != nil {
+			return err
+		}
+	}
+	tree.TotalNodes = len(tree.NodeMap)
+	tree.MaxDepth = calculateMaxDepth(tree.Root)
+	return nil
+}
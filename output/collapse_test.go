@@ -0,0 +1,69 @@
+package output
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestHierarchicalGenerator_CollapseSingleChildChains(t *testing.T) {
+	pages := []PageData{
+		{Title: "A", URL: "https://example.com/a", Content: "# A", Timestamp: time.Now(), Depth: 1},
+		{Title: "B", URL: "https://example.com/a/b", Timestamp: time.Now(), Depth: 2},
+		{Title: "C", URL: "https://example.com/a/b/c", Content: "C content", Timestamp: time.Now(), Depth: 3},
+		{Title: "Docs", URL: "https://example.com/docs", Timestamp: time.Now(), Depth: 1},
+		{Title: "Alpha", URL: "https://example.com/docs/alpha", Content: "Alpha content", Timestamp: time.Now(), Depth: 2},
+		{Title: "Beta", URL: "https://example.com/docs/beta", Content: "Beta content", Timestamp: time.Now(), Depth: 2},
+	}
+	collapse := true
+	cfg := &config.Config{
+		RootURL:                   "https://example.com",
+		OutputDir:                 t.TempDir(),
+		OutputFormat:              "markdown",
+		OutputType:                "per-page",
+		CollapseSingleChildChains: &collapse,
+	}
+
+	generator := NewHierarchical(cfg, pages)
+	tree := generator.tree
+	if len(tree.Root.Children) != 2 {
+		t.Fatalf("Expected 2 top-level entries, got %d", len(tree.Root.Children))
+	}
+
+	chain := tree.Root.Children[0]
+	if chain.Title != "A / B / C" || chain.URL != "https://example.com/a/b/c" {
+		t.Errorf("Expected the chain collapsed into 'A / B / C', got %q (%s)", chain.Title, chain.URL)
+	}
+	if chain.Level != 1 || chain.Parent != tree.Root {
+		t.Errorf("Expected the collapsed entry at level 1 under the root, got level %d", chain.Level)
+	}
+	if docs := tree.Root.Children[1]; docs.Title != "Docs" || len(docs.Children) != 2 {
+		t.Errorf("Expected branching Docs entry to be preserved with 2 children")
+	}
+	if tree.TotalNodes != 4 || tree.MaxDepth != 2 {
+		t.Errorf("Expected 4 nodes and max depth 2, got %d and %d", tree.TotalNodes, tree.MaxDepth)
+	}
+
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !fileExists(filepath.Join(cfg.OutputDir, "a_b_c", "index.md")) {
+		t.Errorf("Expected the collapsed chain written as a single a_b_c entry")
+	}
+	if fileExists(filepath.Join(cfg.OutputDir, "a")) {
+		t.Errorf("Expected no directory for the collapsed parent")
+	}
+}
+
+func TestHierarchicalGenerator_KeepsChainsByDefault(t *testing.T) {
+	pages := []PageData{
+		{Title: "A", URL: "https://example.com/a", Timestamp: time.Now(), Depth: 1},
+		{Title: "B", URL: "https://example.com/a/b", Content: "B content", Timestamp: time.Now(), Depth: 2},
+	}
+	tree := NewHierarchical(&config.Config{OutputDir: t.TempDir()}, pages).tree
+	if len(tree.Root.Children) != 1 || len(tree.Root.Children[0].Children) != 1 {
+		t.Errorf("Expected the chain to be kept when collapsing is off")
+	}
+}
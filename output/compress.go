@@ -0,0 +1,74 @@
+package output
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+
+	"docscraper/config"
+)
+
+// compressedExtension is appended to files written gzip-compressed under compress_output
+const compressedExtension = ".gz"
+
+// outputName returns the name a markdown, text or JSON output file is written under, with .gz
+// appended under compress_output; links to those files use it too
+func outputName(cfg *config.Config, name string) string {
+	if cfg.GetCompressOutput() {
+		return name + compressedExtension
+	}
+	return name
+}
+
+// outputFile is an output file being written, through a gzip writer under compress_output
+type outputFile struct {
+	io.Writer
+	file *os.File
+	gz   *gzip.Writer
+}
+
+// createOutputFile creates filename, or filename.gz written through gzip under compress_output
+func createOutputFile(cfg *config.Config, filename string) (*outputFile, error) {
+	file, err := os.Create(outputName(cfg, filename))
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.GetCompressOutput() {
+		return &outputFile{Writer: file, file: file}, nil
+	}
+
+	gz := gzip.NewWriter(file)
+	return &outputFile{Writer: gz, file: file, gz: gz}, nil
+}
+
+// writeOutputFile writes data to filename as createOutputFile would
+func writeOutputFile(cfg *config.Config, filename string, data []byte) error {
+	file, err := createOutputFile(cfg, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// closeOutputFile closes file, setting *err to the failure unless it already holds one; defer it
+// with a named error result so a failed flush of the compressed stream is not lost
+func closeOutputFile(file *outputFile, err *error) {
+	if closeErr := file.Close(); closeErr != nil && *err == nil {
+		*err = closeErr
+	}
+}
+
+// Close flushes the compressed stream, if any, and closes the file
+func (f *outputFile) Close() error {
+	if f.gz != nil {
+		if err := f.gz.Close(); err != nil {
+			f.file.Close()
+			return err
+		}
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,162 @@
+package output
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// compressTestLinkPattern matches relative markdown links to output files
+var compressTestLinkPattern = regexp.MustCompile(`\]\(([^)#:]+)\)`)
+
+// readGzipFile returns the decompressed content of a gzip file
+func readGzipFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("%s is not gzip-compressed: %v", filename, err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerator_CompressOutput(t *testing.T) {
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Welcome home", Timestamp: time.Now(), Depth: 1},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Read the guide", Timestamp: time.Now(), Depth: 2},
+	}
+
+	reproducible := true
+	for _, outputType := range []string{"single", "per-page", "per-depth"} {
+		t.Run(outputType, func(t *testing.T) {
+			generate := func(compress bool) map[string]string {
+				dir := t.TempDir()
+				cfg := &config.Config{
+					RootURL:            "https://example.com",
+					OutputDir:          dir,
+					OutputFormats:      []string{"markdown", "text", "json"},
+					OutputType:         outputType,
+					ReproducibleOutput: &reproducible,
+					CompressOutput:     &compress,
+				}
+				if err := New(cfg, pages).Generate(); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				return readOutputTree(t, dir)
+			}
+
+			expected := generate(false)
+			compressed := generate(true)
+			if len(compressed) != len(expected) {
+				t.Fatalf("compressed output has %d files, want %d", len(compressed), len(expected))
+			}
+			for name, content := range expected {
+				packed, exists := compressed[name+compressedExtension]
+				if !exists {
+					if _, plain := compressed[name]; !plain {
+						t.Errorf("Expected %s in the compressed output", name)
+					}
+					continue
+				}
+				gz, err := gzip.NewReader(strings.NewReader(packed))
+				if err != nil {
+					t.Fatalf("%s is not gzip-compressed: %v", name+compressedExtension, err)
+				}
+				if data, err := io.ReadAll(gz); err != nil || string(data) != content {
+					t.Errorf("%s decompresses to:\n%s\nwant:\n%s", name+compressedExtension, data, content)
+				}
+			}
+
+			// Indexes and the README stay plain and link the compressed files
+			for name, content := range compressed {
+				if strings.HasSuffix(name, compressedExtension) {
+					continue
+				}
+				for _, match := range compressTestLinkPattern.FindAllStringSubmatch(content, -1) {
+					target := filepath.Join(filepath.Dir(name), match[1])
+					if _, exists := compressed[target]; !exists {
+						t.Errorf("%s links %s, which was not written", name, match[1])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_CompressOutput_Decompresses(t *testing.T) {
+	compress := true
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "markdown",
+		OutputType:     "per-page",
+		CompressOutput: &compress,
+	}
+	pages := []PageData{
+		{Title: "Install", URL: "https://example.com/install", Content: "## Steps\n\nRun the installer.", Timestamp: time.Now(), Depth: 1},
+	}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if fileExists(filepath.Join(cfg.OutputDir, "page_001.md")) {
+		t.Errorf("Expected no uncompressed page file")
+	}
+	page := readGzipFile(t, filepath.Join(cfg.OutputDir, "page_001.md.gz"))
+	if !strings.HasPrefix(page, "# Install\n") || !strings.Contains(page, "## Steps\n\nRun the installer.") {
+		t.Errorf("Unexpected decompressed page:\n%s", page)
+	}
+
+	index, err := os.ReadFile(filepath.Join(cfg.OutputDir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "1. [Install](page_001.md.gz)") {
+		t.Errorf("Expected the index to link the compressed page, got:\n%s", index)
+	}
+}
+
+func TestCloseOutputFile_ReportsFlushErrors(t *testing.T) {
+	enabled := true
+	cfg := &config.Config{CompressOutput: &enabled}
+	open := func() *outputFile {
+		file, err := createOutputFile(cfg, filepath.Join(t.TempDir(), "documentation.md"))
+		if err != nil {
+			t.Fatalf("createOutputFile() error = %v", err)
+		}
+		io.WriteString(file, "content")
+		// Closing the underlying file first makes flushing the compressed stream fail
+		file.file.Close()
+		return file
+	}
+
+	var err error
+	closeOutputFile(open(), &err)
+	if err == nil {
+		t.Error("Expected the failed flush reported")
+	}
+
+	earlier := io.ErrShortWrite
+	err = earlier
+	closeOutputFile(open(), &err)
+	if err != earlier {
+		t.Errorf("Expected the earlier error kept, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package output
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader is the first row of documentation.csv
+var csvHeader = []string{"title", "url", "depth", "word_count", "timestamp"}
+
+// generateCSVOutput writes one row of metadata per page to documentation.csv
+func (g *Generator) generateCSVOutput() error {
+	filename := filepath.Join(g.config.OutputDir, "documentation.csv")
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, page := range g.pages {
+		content, err := pageContent(page)
+		if err != nil {
+			return err
+		}
+
+		timestamp := page.Timestamp
+		if g.config.GetReproducibleOutput() {
+			timestamp = reproducibleTime
+		}
+
+		row := []string{
+			page.Title,
+			page.URL,
+			strconv.Itoa(page.Depth),
+			strconv.Itoa(len(strings.Fields(content))),
+			timestamp.Format(time.RFC3339),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
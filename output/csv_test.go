@@ -0,0 +1,66 @@
+package output
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_Generate_CSV(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "csv",
+		OutputType:   "single",
+	}
+
+	testTime := time.Date(2023, 12, 1, 10, 30, 0, 0, time.UTC)
+	pages := []PageData{
+		{
+			Title:     "Install, configure, \"run\"",
+			URL:       "https://example.com/install",
+			Content:   "Download the binary\nand run it.",
+			Timestamp: testTime,
+			Depth:     1,
+		},
+		{
+			Title:     "Plain",
+			URL:       "https://example.com/plain",
+			Content:   "One",
+			Timestamp: testTime,
+			Depth:     2,
+		},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	file, err := os.Open(filepath.Join(tmpDir, "documentation.csv"))
+	if err != nil {
+		t.Fatalf("Failed to open CSV output: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV output: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d rows", len(rows))
+	}
+
+	if want := []string{"title", "url", "depth", "word_count", "timestamp"}; !reflect.DeepEqual(rows[0], want) {
+		t.Errorf("Expected header %v, got %v", want, rows[0])
+	}
+	want := []string{"Install, configure, \"run\"", "https://example.com/install", "1", "6", "2023-12-01T10:30:00Z"}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("Expected row %v, got %v", want, rows[1])
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"strings"
+	"unicode"
+)
+
+// excerptLength is the maximum length in runes of page descriptions in metadata.yaml
+const excerptLength = 200
+
+// TruncateExcerpt collapses whitespace in text and shortens it to at most maxLen runes, ending
+// after the last whole sentence that fits or, failing that, at a word boundary with an ellipsis
+func TruncateExcerpt(text string, maxLen int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return text
+	}
+
+	for i := maxLen - 1; i > 0; i-- {
+		if isSentenceEnd(runes[i]) && unicode.IsSpace(runes[i+1]) {
+			return string(runes[:i+1])
+		}
+	}
+
+	if maxLen == 1 {
+		return "…"
+	}
+	cut := maxLen - 1
+	for i := cut; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRight(string(runes[:cut]), " ,;:") + "…"
+}
+
+// isSentenceEnd reports whether r terminates a sentence
+func isSentenceEnd(r rune) bool {
+	return r == '.' || r == '!' || r == '?'
+}
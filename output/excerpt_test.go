@@ -0,0 +1,77 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"docscraper/config"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestTruncateExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		maxLen int
+		want   string
+	}{
+		{"short text unchanged", "Install the CLI.", 50, "Install the CLI."},
+		{"whitespace collapsed", "Install\n\n  the   CLI.", 50, "Install the CLI."},
+		{"no limit", "Install the CLI. Then run it.", 0, "Install the CLI. Then run it."},
+		{"cuts at sentence boundary", "Install the CLI. Then configure it! Finally run the first scrape.", 40, "Install the CLI. Then configure it!"},
+		{"question mark ends sentence", "What is it? A scraper for documentation sites.", 30, "What is it?"},
+		{"ignores dots inside words", "Use config.yaml to set options for every run", 30, "Use config.yaml to set…"},
+		{"word boundary fallback", "A single long sentence without any terminal punctuation at all", 25, "A single long sentence…"},
+		{"trailing comma dropped", "First, second, third, fourth, fifth", 16, "First, second…"},
+		{"no spaces hard cut", "Supercalifragilisticexpialidocious", 10, "Supercali…"},
+		{"multibyte runes", "Überblick über die Konfiguration und Nutzung", 20, "Überblick über die…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateExcerpt(tt.text, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("TruncateExcerpt(%q, %d) = %q, want %q", tt.text, tt.maxLen, got, tt.want)
+			}
+			if tt.maxLen > 0 && utf8.RuneCountInString(got) > tt.maxLen {
+				t.Errorf("TruncateExcerpt() returned %d runes, limit %d", utf8.RuneCountInString(got), tt.maxLen)
+			}
+		})
+	}
+}
+
+func TestGenerator_MetadataDescriptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "text",
+		OutputType:   "per-page",
+	}
+	content := "Getting started is easy. " + strings.Repeat("Configure the scraper for your site. ", 10)
+	pages := []PageData{{Title: "Start", URL: "https://example.com/start", Content: content, Timestamp: time.Now()}}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var metadata struct {
+		Pages []struct {
+			Description string `yaml:"description"`
+		} `yaml:"pages"`
+	}
+	if err := yaml.Unmarshal([]byte(readOutputTree(t, tmpDir)["metadata.yaml"]), &metadata); err != nil {
+		t.Fatalf("metadata.yaml should parse: %v", err)
+	}
+	if len(metadata.Pages) != 1 {
+		t.Fatalf("expected 1 page in metadata.yaml, got %d", len(metadata.Pages))
+	}
+
+	description := metadata.Pages[0].Description
+	if description != TruncateExcerpt(content, excerptLength) || !strings.HasSuffix(description, "site.") {
+		t.Errorf("description should end at a sentence boundary, got %q", description)
+	}
+}
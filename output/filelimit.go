@@ -0,0 +1,49 @@
+package output
+
+import (
+	"fmt"
+	"log"
+
+	"docscraper/config"
+)
+
+// pageFileFormats write a file per page unless output_type is "single"
+var pageFileFormats = map[string]bool{"markdown": true, "text": true, "html": true, "asciidoc": true}
+
+// pageFileCount returns how many per-page files the configured formats write for pages pages;
+// hierarchical output writes them for markdown only
+func pageFileCount(cfg *config.Config, pages int, hierarchical bool) int {
+	count := 0
+	for _, format := range cfg.GetOutputFormats() {
+		switch {
+		case format == "auto" && !hierarchical:
+			count += pages
+		case cfg.OutputType == "single":
+		case hierarchical && format == "markdown", !hierarchical && pageFileFormats[format]:
+			count += pages
+		}
+	}
+	return count
+}
+
+// applyFileLimit checks the per-page files of pages pages against max_output_files, returning
+// the configuration to generate with: cfg within the limit, or under on_file_limit "bundle" a
+// copy writing single-file output instead
+func applyFileLimit(cfg *config.Config, pages int, hierarchical bool) (*config.Config, error) {
+	limit := cfg.GetMaxOutputFiles()
+	count := pageFileCount(cfg, pages, hierarchical)
+	if limit == 0 || count <= limit {
+		return cfg, nil
+	}
+	if cfg.GetOnFileLimit() == "error" {
+		return nil, fmt.Errorf("%d page files exceed max_output_files (%d)", count, limit)
+	}
+
+	bundled := *cfg
+	bundled.OutputType = "single"
+	if count := pageFileCount(&bundled, pages, hierarchical); count > limit {
+		return nil, fmt.Errorf("%d page files exceed max_output_files (%d) even as single-file output", count, limit)
+	}
+	log.Printf("Warning: %d page files exceed max_output_files (%d), writing single-file output instead", count, limit)
+	return &bundled, nil
+}
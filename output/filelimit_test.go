@@ -0,0 +1,192 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// fileLimitTestPages returns count pages for max_output_files tests
+func fileLimitTestPages(count int) []PageData {
+	pages := make([]PageData, count)
+	for i := range pages {
+		pages[i] = PageData{
+			Title:     fmt.Sprintf("Page %d", i+1),
+			URL:       fmt.Sprintf("https://example.com/page/%d", i+1),
+			Content:   fmt.Sprintf("Content of page %d", i+1),
+			Timestamp: time.Now(),
+			Depth:     2,
+		}
+	}
+	return pages
+}
+
+// captureLog returns what the standard logger writes while f runs
+func captureLog(t *testing.T, f func()) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(previous)
+
+	f()
+	return buf.String()
+}
+
+func TestGenerator_MaxOutputFiles(t *testing.T) {
+	pages := fileLimitTestPages(50)
+
+	tests := []struct {
+		name        string
+		limit       int
+		onFileLimit string
+		wantErr     bool
+		wantPages   int  // page_NNN.md files written
+		wantSingle  bool // documentation.md written
+		wantWarning bool
+	}{
+		{name: "within the limit", limit: 50, wantPages: 50},
+		{name: "bundles past the limit", limit: 10, wantSingle: true, wantWarning: true},
+		{name: "bundle policy set explicitly", limit: 10, onFileLimit: "bundle", wantSingle: true, wantWarning: true},
+		{name: "errors past the limit", limit: 10, onFileLimit: "error", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := tt.limit
+			cfg := &config.Config{
+				RootURL:        "https://example.com",
+				OutputDir:      t.TempDir(),
+				OutputFormat:   "markdown",
+				OutputType:     "per-page",
+				MaxOutputFiles: &limit,
+				OnFileLimit:    tt.onFileLimit,
+			}
+
+			var err error
+			logged := captureLog(t, func() { err = New(cfg, pages).Generate() })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), "max_output_files") {
+				t.Errorf("Expected the error to name max_output_files, got %v", err)
+			}
+
+			written, _ := filepath.Glob(filepath.Join(cfg.OutputDir, "page_*.md"))
+			if len(written) != tt.wantPages {
+				t.Errorf("Expected %d page files, got %d", tt.wantPages, len(written))
+			}
+			if fileExists(filepath.Join(cfg.OutputDir, "documentation.md")) != tt.wantSingle {
+				t.Errorf("Expected documentation.md written: %v", tt.wantSingle)
+			}
+			if warned := strings.Contains(logged, "exceed max_output_files"); warned != tt.wantWarning {
+				t.Errorf("Expected a warning: %v, got log %q", tt.wantWarning, logged)
+			}
+			if cfg.OutputType != "per-page" {
+				t.Errorf("Expected the caller's output_type to stay per-page, got %q", cfg.OutputType)
+			}
+		})
+	}
+}
+
+func TestGenerator_MaxOutputFilesCountsEveryFormat(t *testing.T) {
+	// 10 pages in markdown and text make 20 page files
+	limit := 15
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormats:  []string{"markdown", "text"},
+		OutputType:     "per-page",
+		MaxOutputFiles: &limit,
+		OnFileLimit:    "error",
+	}
+
+	if err := New(cfg, fileLimitTestPages(10)).Generate(); err == nil {
+		t.Fatal("Expected an error for 20 page files over a limit of 15")
+	}
+	entries, err := os.ReadDir(cfg.OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected nothing written before failing, got %d entries", len(entries))
+	}
+}
+
+func TestGenerator_MaxOutputFilesAutoCannotBundle(t *testing.T) {
+	limit := 5
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "auto",
+		OutputType:     "per-page",
+		MaxOutputFiles: &limit,
+	}
+
+	err := New(cfg, fileLimitTestPages(10)).Generate()
+	if err == nil || !strings.Contains(err.Error(), "even as single-file output") {
+		t.Errorf("Expected auto output, a file per page in any output_type, to fail, got %v", err)
+	}
+}
+
+func TestGenerator_MaxOutputFilesStream(t *testing.T) {
+	limit := 10
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "markdown",
+		OutputType:     "per-page",
+		MaxOutputFiles: &limit,
+	}
+
+	pages := make(chan PageData)
+	go func() {
+		for _, page := range fileLimitTestPages(50) {
+			pages <- page
+		}
+		close(pages)
+	}()
+
+	var err error
+	captureLog(t, func() { err = New(cfg, nil).GenerateStream(pages) })
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+	if written, _ := filepath.Glob(filepath.Join(cfg.OutputDir, "page_*.md")); len(written) != 0 {
+		t.Errorf("Expected streamed pages bundled into a single file, got %d page files", len(written))
+	}
+	if !fileExists(filepath.Join(cfg.OutputDir, "documentation.md")) {
+		t.Error("Expected documentation.md")
+	}
+}
+
+func TestHierarchicalGenerator_MaxOutputFiles(t *testing.T) {
+	limit := 2
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "markdown",
+		OutputType:     "per-page",
+		MaxOutputFiles: &limit,
+	}
+
+	captureLog(t, func() {
+		if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	})
+	if !fileExists(filepath.Join(cfg.OutputDir, "documentation_hierarchical.md")) {
+		t.Error("Expected hierarchical output bundled into documentation_hierarchical.md")
+	}
+	if fileExists(filepath.Join(cfg.OutputDir, "index.md")) {
+		t.Error("Expected no per-page hierarchical index")
+	}
+}
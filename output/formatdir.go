@@ -0,0 +1,59 @@
+package output
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"docscraper/config"
+)
+
+// formatSubdir returns the subdirectory a format is written to, or "" when format_subdirs is off
+func formatSubdir(cfg *config.Config, format string) string {
+	if !cfg.GetFormatSubdirs() {
+		return ""
+	}
+	return format
+}
+
+// formatLink makes a link relative to a format's output directory relative to the output root;
+// absolute URLs are returned unchanged
+func formatLink(cfg *config.Config, format, link string) string {
+	dir := formatSubdir(cfg, format)
+	if parsed, err := url.Parse(link); dir == "" || (err == nil && parsed.IsAbs()) {
+		return link
+	}
+	return path.Join(dir, link)
+}
+
+// formatConfig returns a copy of cfg writing format into its own subdirectory, creating it
+func formatConfig(cfg *config.Config, format string) (*config.Config, error) {
+	formatCfg := *cfg
+	formatCfg.OutputFormat = format
+	if dir := formatSubdir(cfg, format); dir != "" {
+		formatCfg.OutputDir = filepath.Join(cfg.OutputDir, dir)
+		if err := os.MkdirAll(formatCfg.OutputDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &formatCfg, nil
+}
+
+// forFormat returns a generator writing format into its output directory
+func (g *Generator) forFormat(format string) (*Generator, error) {
+	cfg, err := formatConfig(g.config, format)
+	if err != nil {
+		return nil, err
+	}
+	return &Generator{config: cfg, pages: g.pages, responses: g.responses, written: g.written}, nil
+}
+
+// forFormat returns a generator writing format into its output directory
+func (h *HierarchicalGenerator) forFormat(format string) (*HierarchicalGenerator, error) {
+	cfg, err := formatConfig(h.config, format)
+	if err != nil {
+		return nil, err
+	}
+	return &HierarchicalGenerator{config: cfg, tree: h.tree, order: h.order, names: h.names, redundant: h.redundant, written: h.written}, nil
+}
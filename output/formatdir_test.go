@@ -0,0 +1,108 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_FormatSubdirs(t *testing.T) {
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now()},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	tmpDir := t.TempDir()
+	enabled := true
+	cfg := &config.Config{
+		RootURL:            "https://example.com",
+		OutputDir:          tmpDir,
+		OutputFormat:       "markdown",
+		OutputFormats:      []string{"markdown", "json"},
+		OutputType:         "per-page",
+		FormatSubdirs:      &enabled,
+		GenerateGlossary:   &enabled,
+		GenerateJSONSchema: &enabled,
+		ValidateOutput:     &enabled,
+	}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+	for _, name := range []string{
+		"markdown/index.md",
+		"markdown/page_001.md",
+		"markdown/page_002.md",
+		"json/documentation.json",
+		"json/documentation.schema.json",
+		"glossary.md",
+		"README.md",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected %s, got files %v", name, fileNames(files))
+		}
+	}
+	for _, name := range []string{"index.md", "page_001.md", "documentation.json", "markdown/documentation.json", "json/index.md"} {
+		if _, ok := files[name]; ok {
+			t.Errorf("%s should not be written", name)
+		}
+	}
+
+	if !strings.Contains(files["glossary.md"], "(markdown/page_002.md)") {
+		t.Errorf("glossary should link into the markdown subdirectory, got:\n%s", files["glossary.md"])
+	}
+	if !strings.Contains(files["README.md"], "[markdown/index.md](markdown/index.md)") || !strings.Contains(files["README.md"], "(json/documentation.json)") {
+		t.Errorf("README should link into the format subdirectories, got:\n%s", files["README.md"])
+	}
+}
+
+func TestHierarchicalGenerator_FormatSubdirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	enabled := true
+	cfg := &config.Config{
+		RootURL:                 "https://example.com",
+		OutputDir:               tmpDir,
+		OutputFormat:            "markdown",
+		OutputFormats:           []string{"markdown", "text"},
+		OutputType:              "single",
+		UseHierarchicalOrdering: &enabled,
+		FormatSubdirs:           &enabled,
+	}
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+	for _, name := range []string{"markdown/documentation_hierarchical.md", "text/documentation_hierarchical.txt"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("expected %s, got files %v", name, fileNames(files))
+		}
+	}
+}
+
+func TestFormatLink(t *testing.T) {
+	enabled := true
+	cfg := &config.Config{FormatSubdirs: &enabled}
+
+	if got := formatLink(cfg, "markdown", "documentation.md#intro"); got != "markdown/documentation.md#intro" {
+		t.Errorf("formatLink() = %q, want markdown/documentation.md#intro", got)
+	}
+	if got := formatLink(cfg, "text", "https://example.com/guide"); got != "https://example.com/guide" {
+		t.Errorf("absolute URLs should be unchanged, got %q", got)
+	}
+	if got := formatLink(&config.Config{}, "markdown", "index.md"); got != "index.md" {
+		t.Errorf("links should be unchanged without format_subdirs, got %q", got)
+	}
+}
+
+// fileNames lists the keys of an output tree for error messages
+func fileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
@@ -3,9 +3,11 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,31 +23,68 @@ type PageData struct {
 	Content   string    `json:"content"`
 	Timestamp time.Time `json:"timestamp"`
 	Depth     int       `json:"depth"`
+
+	LastModified *time.Time `json:"last_modified,omitempty"` // Parsed from last_modified_patterns
+
+	ContentType    string        `json:"content_type,omitempty"`    // Content-Type the page was served with
+	StructuredData []interface{} `json:"structured_data,omitempty"` // JSON-LD blocks embedded in the page
+	Breadcrumbs    []string      `json:"breadcrumbs,omitempty"`     // Ancestor URLs from a JSON-LD BreadcrumbList
+
+	NavigationTrail []string          `json:"navigation_trail,omitempty"`  // URLs of the page's highlighted nav item and its enclosing items
+	TableOfContents []string          `json:"table_of_contents,omitempty"` // Pages linked from the page's table of contents, in order
+	NavigationMenu  string            `json:"navigation_menu,omitempty"`   // The root page's navigation_menu_selector menu HTML
+	Metadata        map[string]string `json:"metadata,omitempty"`          // Values of capture_data_attributes, keyed without "data-"
+	TokenEstimate   int               `json:"token_estimate,omitempty"`    // Approximate LLM token count of Content
+	Tags            []string          `json:"tags,omitempty"`              // Assigned by tag_rules
+
+	ContentFile string `json:"-"` // Spilled content read back at generation time when set
 }
 
 // Generator handles output generation
 type Generator struct {
 	config *config.Config
 	pages  []PageData
+
+	responses []WARCResponse // Raw exchanges for the "warc" format
+
+	excerpts []string // Page descriptions kept by GenerateStream, which drops page content
+
+	written *pageFileLog // Per-page files written by the running Generate, for url_map.json
 }
 
 // New creates a new output generator
 func New(cfg *config.Config, pages []PageData) *Generator {
+	pages = outputPages(cfg, pages)
+
 	// Convert scraper.PageData to output.PageData
-	outputPages := make([]PageData, len(pages))
+	converted := make([]PageData, len(pages))
 	for i, page := range pages {
-		outputPages[i] = PageData{
+		converted[i] = PageData{
 			Title:     page.Title,
 			URL:       page.URL,
 			Content:   page.Content,
 			Timestamp: page.Timestamp,
 			Depth:     page.Depth,
+
+			LastModified:   page.LastModified,
+			ContentType:    page.ContentType,
+			StructuredData: page.StructuredData,
+			Breadcrumbs:    page.Breadcrumbs,
+
+			NavigationTrail: page.NavigationTrail,
+			TableOfContents: page.TableOfContents,
+			NavigationMenu:  page.NavigationMenu,
+			Metadata:        page.Metadata,
+			TokenEstimate:   page.TokenEstimate,
+			Tags:            page.Tags,
+
+			ContentFile: page.ContentFile,
 		}
 	}
 
 	return &Generator{
 		config: cfg,
-		pages:  outputPages,
+		pages:  converted,
 	}
 }
 
@@ -56,6 +95,96 @@ func (g *Generator) Generate() error {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
+	if g.config.GetSkeletonOnly() {
+		return g.generateSkeleton()
+	}
+
+	cfg, err := applyFileLimit(g.config, len(g.pages), false)
+	if err != nil {
+		return err
+	}
+	g.config = cfg
+	g.written = newPageFileLog(g.config.OutputDir)
+
+	formats := g.config.GetOutputFormats()
+	err = generateFormats(g.config, formats, func(format string) error {
+		formatGenerator, err := g.forFormat(format)
+		if err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+		return formatGenerator.generateFormat()
+	})
+	if err != nil {
+		return err
+	}
+
+	return g.generateSupportFiles(formats)
+}
+
+// generateSupportFiles writes the glossary, schema, token report, README and effective config
+// shared by all formats, then validates the output when configured
+func (g *Generator) generateSupportFiles(formats []string) error {
+	// The glossary is shared by all formats, so link it against markdown output when present
+	if g.config.GetGenerateGlossary() {
+		format := glossaryFormat(formats)
+		entries := g.withFormat(format).glossaryEntries()
+		for i := range entries {
+			entries[i].Link = formatLink(g.config, format, entries[i].Link)
+		}
+		if err := writeGlossary(g.config.OutputDir, entries); err != nil {
+			return err
+		}
+	}
+
+	if contains(formats, "json") && g.config.GetGenerateJSONSchema() {
+		jsonGenerator, err := g.forFormat("json")
+		if err != nil {
+			return err
+		}
+		if err := writeJSONSchema(jsonGenerator.config.OutputDir); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetGenerateURLMap() {
+		if err := writeURLMap(g.config.OutputDir, g.URLMap()); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetGenerateURLIndex() {
+		if err := writeURLIndex(g.config.OutputDir, g.URLIndex()); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetGenerateTokenReport() {
+		if err := writeTokenReport(g.config.OutputDir, g.TokenReport()); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetGenerateReadme() {
+		if err := writeReadme(g.config, len(g.pages), readmeLayout(g.config.OutputType, false), g.readmeEntries()); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetWriteEffectiveConfig() {
+		if err := g.config.SaveEffective(filepath.Join(g.config.OutputDir, config.EffectiveConfigFile)); err != nil {
+			return err
+		}
+	}
+
+	if g.config.GetValidateOutput() {
+		return validationError(ValidateOutput(g.config.OutputDir))
+	}
+
+	return nil
+}
+
+// generateFormat writes the output files for the configured output format
+func (g *Generator) generateFormat() error {
 	switch g.config.OutputFormat {
 	case "markdown":
 		return g.generateMarkdownOutput()
@@ -63,50 +192,122 @@ func (g *Generator) Generate() error {
 		return g.generateTextOutput()
 	case "json":
 		return g.generateJSONOutput()
+	case "warc":
+		return g.generateWARCOutput()
+	case "csv":
+		return g.generateCSVOutput()
+	case "auto":
+		return g.generateAutoOutput()
+	case "html":
+		return g.generateHTMLOutput()
+	case "asciidoc":
+		return g.generateAsciiDocOutput()
 	default:
 		return fmt.Errorf("unsupported output format: %s", g.config.OutputFormat)
 	}
 }
 
+// withFormat returns a generator sharing the same pages that writes the given format
+func (g *Generator) withFormat(format string) *Generator {
+	if format == g.config.OutputFormat {
+		return g
+	}
+
+	cfg := *g.config
+	cfg.OutputFormat = format
+	return &Generator{config: &cfg, pages: g.pages, responses: g.responses, written: g.written}
+}
+
+// glossaryFormat picks the format whose output the glossary links to
+func glossaryFormat(formats []string) string {
+	if contains(formats, "markdown") {
+		return "markdown"
+	}
+	return formats[0]
+}
+
+// contains checks if a slice contains a string
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// glossaryEntries returns glossary links pointing at the generated markdown, or at the
+// source URL for formats without linkable markdown output; compressed markdown is not linkable
+func (g *Generator) glossaryEntries() []glossaryEntry {
+	entries := make([]glossaryEntry, len(g.pages))
+	for i, page := range g.pages {
+		link := page.URL
+		if g.config.GetCompressOutput() {
+			entries[i] = glossaryEntry{Title: page.Title, Link: link}
+			continue
+		}
+		if g.config.OutputFormat == "auto" {
+			link = autoFilename(page, i)
+		} else if g.config.OutputFormat == "markdown" {
+			switch g.config.OutputType {
+			case "single":
+				link = "documentation.md#" + g.createAnchor(identityTitle(g.config, page.Title, page.URL))
+			case "per-depth":
+				link = fmt.Sprintf("%s/page_%03d.md", depthDirName(page.Depth), i+1)
+			default:
+				link = fmt.Sprintf("page_%03d.md", i+1)
+			}
+		}
+		entries[i] = glossaryEntry{Title: page.Title, Link: link}
+	}
+	return entries
+}
+
 // generateMarkdownOutput generates Markdown output
 func (g *Generator) generateMarkdownOutput() error {
-	if g.config.OutputType == "single" {
+	switch g.config.OutputType {
+	case "single":
 		return g.generateSingleMarkdown()
+	case "per-depth":
+		return g.generatePerDepthMarkdown()
+	default:
+		return g.generatePerPageMarkdown()
 	}
-	return g.generatePerPageMarkdown()
 }
 
 // generateSingleMarkdown creates a single Markdown file with all content
-func (g *Generator) generateSingleMarkdown() error {
+func (g *Generator) generateSingleMarkdown() (err error) {
 	filename := filepath.Join(g.config.OutputDir, "documentation.md")
-	file, err := os.Create(filename)
+	file, err := createOutputFile(g.config, filename)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer closeOutputFile(file, &err)
 
 	// Write header
 	fmt.Fprintf(file, "# Documentation Scrape Results\n\n")
 	fmt.Fprintf(file, "**Scraped from:** %s  \n", g.config.RootURL)
-	fmt.Fprintf(file, "**Generated:** %s  \n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(g.config))
 	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(g.pages))
 	fmt.Fprintf(file, "---\n\n")
 
 	// Write table of contents
 	fmt.Fprintf(file, "## Table of Contents\n\n")
 	for i, page := range g.pages {
-		anchor := g.createAnchor(page.Title)
-		fmt.Fprintf(file, "%d. [%s](#%s)\n", i+1, page.Title, anchor)
+		anchor := g.createAnchor(identityTitle(g.config, page.Title, page.URL))
+		fmt.Fprintf(file, "%d. [%s](#%s)\n", i+1, displayTitle(g.config, page.Title), anchor)
 	}
 	fmt.Fprintf(file, "\n---\n\n")
 
 	// Write each page
 	for i, page := range g.pages {
-		anchor := g.createAnchor(page.Title)
-		fmt.Fprintf(file, "## %s {#%s}\n\n", page.Title, anchor)
-		fmt.Fprintf(file, "**URL:** %s  \n", page.URL)
-		fmt.Fprintf(file, "**Scraped:** %s\n\n", page.Timestamp.Format(time.RFC3339))
-		fmt.Fprintf(file, "%s\n\n", page.Content)
+		anchor := g.createAnchor(identityTitle(g.config, page.Title, page.URL))
+		fmt.Fprintf(file, "## %s {#%s}\n\n", displayTitle(g.config, page.Title), anchor)
+		writeMarkdownPageMeta(file, g.config, page.URL, page.Tags, page.Metadata, page.Timestamp)
+		if err := writeMarkdownContent(file, g.config, page); err != nil {
+			return err
+		}
+		fmt.Fprintf(file, "\n\n")
 
 		if i < len(g.pages)-1 {
 			fmt.Fprintf(file, "---\n\n")
@@ -119,27 +320,106 @@ func (g *Generator) generateSingleMarkdown() error {
 // generatePerPageMarkdown creates separate Markdown files for each page
 func (g *Generator) generatePerPageMarkdown() error {
 	// Create individual page files
-	for i, page := range g.pages {
+	err := writePages(g.config, len(g.pages), func(i int) error {
 		// Create numbered filename
 		filename := fmt.Sprintf("page_%03d.md", i+1)
-		filepath := filepath.Join(g.config.OutputDir, filename)
+		return g.writeMarkdownPage(filepath.Join(g.config.OutputDir, filename), g.pages[i])
+	})
+	if err != nil {
+		return err
+	}
 
-		file, err := os.Create(filepath)
-		if err != nil {
+	return g.writeMarkdownIndex()
+}
+
+// writeMarkdownIndex writes index.md linking every per-page markdown file
+func (g *Generator) writeMarkdownIndex() error {
+	indexFile := filepath.Join(g.config.OutputDir, "index.md")
+	file, err := os.Create(indexFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Write index content
+	fmt.Fprintf(file, "# Documentation Index\n\n")
+	fmt.Fprintf(file, "**Scraped from:** %s  \n", g.config.RootURL)
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(g.config))
+	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(g.pages))
+	fmt.Fprintf(file, "## Pages\n\n")
+
+	for i, page := range g.pages {
+		pageFile := outputName(g.config, fmt.Sprintf("page_%03d.md", i+1))
+		fmt.Fprintf(file, "%d. [%s](%s)\n", i+1, displayTitle(g.config, page.Title), pageFile)
+	}
+
+	return nil
+}
+
+// writeMarkdownPage writes a single page as a standalone Markdown file
+func (g *Generator) writeMarkdownPage(filename string, page PageData) (err error) {
+	file, err := createOutputFile(g.config, filename)
+	if err != nil {
+		return err
+	}
+	defer closeOutputFile(file, &err)
+	g.recordPageFile(page, outputName(g.config, filename))
+
+	fmt.Fprintf(file, "# %s\n\n", displayTitle(g.config, page.Title))
+	writeMarkdownPageMeta(file, g.config, page.URL, page.Tags, page.Metadata, page.Timestamp)
+	fmt.Fprintf(file, "---\n\n")
+	if err := writeMarkdownContent(file, g.config, page); err != nil {
+		return err
+	}
+	fmt.Fprintf(file, "\n")
+
+	return nil
+}
+
+// pagesByDepth groups page indexes by crawl depth and returns the depths in ascending order
+func (g *Generator) pagesByDepth() (map[int][]int, []int) {
+	groups := make(map[int][]int)
+	for i, page := range g.pages {
+		groups[page.Depth] = append(groups[page.Depth], i)
+	}
+
+	depths := make([]int, 0, len(groups))
+	for depth := range groups {
+		depths = append(depths, depth)
+	}
+	sort.Ints(depths)
+
+	return groups, depths
+}
+
+// depthDirName returns the subdirectory holding pages at the given crawl depth
+func depthDirName(depth int) string {
+	return fmt.Sprintf("depth-%d", depth)
+}
+
+// generatePerDepthMarkdown creates per-page Markdown files grouped into depth-N subdirectories
+func (g *Generator) generatePerDepthMarkdown() error {
+	groups, depths := g.pagesByDepth()
+
+	for _, depth := range depths {
+		dir := filepath.Join(g.config.OutputDir, depthDirName(depth))
+		if err := os.MkdirAll(dir, 0755); err != nil {
 			return err
 		}
 
-		// Write content
-		fmt.Fprintf(file, "# %s\n\n", page.Title)
-		fmt.Fprintf(file, "**URL:** %s  \n", page.URL)
-		fmt.Fprintf(file, "**Scraped:** %s\n\n", page.Timestamp.Format(time.RFC3339))
-		fmt.Fprintf(file, "---\n\n")
-		fmt.Fprintf(file, "%s\n", page.Content)
+		for _, i := range groups[depth] {
+			filename := fmt.Sprintf("page_%03d.md", i+1)
+			if err := g.writeMarkdownPage(filepath.Join(dir, filename), g.pages[i]); err != nil {
+				return err
+			}
+		}
 
-		file.Close()
+		if err := g.writeDepthIndex(dir, depth, groups[depth]); err != nil {
+			return err
+		}
 	}
 
-	// Create index file
+	// Create top-level index linking each depth
 	indexFile := filepath.Join(g.config.OutputDir, "index.md")
 	file, err := os.Create(indexFile)
 	if err != nil {
@@ -147,16 +427,33 @@ func (g *Generator) generatePerPageMarkdown() error {
 	}
 	defer file.Close()
 
-	// Write index content
 	fmt.Fprintf(file, "# Documentation Index\n\n")
 	fmt.Fprintf(file, "**Scraped from:** %s  \n", g.config.RootURL)
-	fmt.Fprintf(file, "**Generated:** %s  \n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(g.config))
 	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(g.pages))
+	fmt.Fprintf(file, "## Depths\n\n")
+
+	for _, depth := range depths {
+		fmt.Fprintf(file, "- [Depth %d](%s/index.md) (%d pages)\n", depth, depthDirName(depth), len(groups[depth]))
+	}
+
+	return nil
+}
+
+// writeDepthIndex writes the index.md listing the pages of a single depth directory
+func (g *Generator) writeDepthIndex(dir string, depth int, indexes []int) error {
+	file, err := os.Create(filepath.Join(dir, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# Depth %d\n\n", depth)
+	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(indexes))
 	fmt.Fprintf(file, "## Pages\n\n")
 
-	for i, page := range g.pages {
-		pageFile := fmt.Sprintf("page_%03d.md", i+1)
-		fmt.Fprintf(file, "%d. [%s](%s)\n", i+1, page.Title, pageFile)
+	for n, i := range indexes {
+		fmt.Fprintf(file, "%d. [%s](%s)\n", n+1, displayTitle(g.config, g.pages[i].Title), outputName(g.config, fmt.Sprintf("page_%03d.md", i+1)))
 	}
 
 	return nil
@@ -165,77 +462,135 @@ func (g *Generator) generatePerPageMarkdown() error {
 // generateTextOutput generates plain text output
 func (g *Generator) generateTextOutput() error {
 	if g.config.OutputType == "single" {
-		filename := filepath.Join(g.config.OutputDir, "documentation.txt")
-		file, err := os.Create(filename)
-		if err != nil {
-			return err
-		}
-		defer file.Close()
+		return g.generateSingleText()
+	}
 
-		// Write header
-		fmt.Fprintf(file, "DOCUMENTATION SCRAPE RESULTS\n")
-		fmt.Fprintf(file, "============================\n\n")
-		fmt.Fprintf(file, "Scraped from: %s\n", g.config.RootURL)
-		fmt.Fprintf(file, "Generated: %s\n", time.Now().Format(time.RFC3339))
-		fmt.Fprintf(file, "Total Pages: %d\n\n", len(g.pages))
-		fmt.Fprintf(file, "%s\n\n", strings.Repeat("=", 80))
+	err := writePages(g.config, len(g.pages), func(i int) error {
+		return g.writeTextPage(g.pages[i], i)
+	})
+	if err != nil {
+		return err
+	}
+	return g.generateMetadataFile()
+}
 
-		for i, page := range g.pages {
-			fmt.Fprintf(file, "TITLE: %s\n", page.Title)
-			fmt.Fprintf(file, "URL: %s\n", page.URL)
-			fmt.Fprintf(file, "SCRAPED: %s\n", page.Timestamp.Format(time.RFC3339))
-			fmt.Fprintf(file, "CONTENT:\n%s\n", page.Content)
+// generateSingleText creates a single text file with all content
+func (g *Generator) generateSingleText() (err error) {
+	filename := filepath.Join(g.config.OutputDir, "documentation.txt")
+	file, err := createOutputFile(g.config, filename)
+	if err != nil {
+		return err
+	}
+	defer closeOutputFile(file, &err)
 
-			if i < len(g.pages)-1 {
-				separator := "\n" + strings.Repeat("=", 80) + "\n\n"
-				fmt.Fprint(file, separator)
-			}
+	// Write header
+	fmt.Fprintf(file, "DOCUMENTATION SCRAPE RESULTS\n")
+	fmt.Fprintf(file, "============================\n\n")
+	fmt.Fprintf(file, "Scraped from: %s\n", g.config.RootURL)
+	fmt.Fprintf(file, "Generated: %s\n", generatedAt(g.config))
+	fmt.Fprintf(file, "Total Pages: %d\n\n", len(g.pages))
+	fmt.Fprintf(file, "%s\n\n", strings.Repeat("=", 80))
+
+	for i, page := range g.pages {
+		fmt.Fprintf(file, "TITLE: %s\n", page.Title)
+		fmt.Fprintf(file, "URL: %s\n", page.URL)
+		if len(page.Tags) > 0 {
+			fmt.Fprintf(file, "TAGS: %s\n", strings.Join(page.Tags, ", "))
 		}
-	} else {
-		for i, page := range g.pages {
-			filename := g.createSafeFilename(page.Title, i, ".txt")
-			filepath := filepath.Join(g.config.OutputDir, filename)
+		if !g.config.GetReproducibleOutput() {
+			fmt.Fprintf(file, "SCRAPED: %s\n", page.Timestamp.Format(time.RFC3339))
+		}
+		fmt.Fprintf(file, "CONTENT:\n")
+		if err := writePageContent(file, page); err != nil {
+			return err
+		}
+		fmt.Fprintf(file, "\n")
 
-			file, err := os.Create(filepath)
-			if err != nil {
-				return err
-			}
+		if i < len(g.pages)-1 {
+			separator := "\n" + strings.Repeat("=", 80) + "\n\n"
+			fmt.Fprint(file, separator)
+		}
+	}
 
-			fmt.Fprintf(file, "TITLE: %s\n", page.Title)
-			fmt.Fprintf(file, "URL: %s\n", page.URL)
-			fmt.Fprintf(file, "SCRAPED: %s\n\n", page.Timestamp.Format(time.RFC3339))
-			fmt.Fprintf(file, "%s\n", page.Content)
+	return nil
+}
 
-			file.Close()
+// writeTextPage writes a single page as a standalone text file, in its depth folder for
+// per-depth output
+func (g *Generator) writeTextPage(page PageData, index int) (err error) {
+	dir := g.config.OutputDir
+	if g.config.OutputType == "per-depth" {
+		dir = filepath.Join(dir, depthDirName(page.Depth))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
 		}
-		return g.generateMetadataFile()
 	}
 
+	filename := filepath.Join(dir, g.createSafeFilename(identityTitle(g.config, page.Title, page.URL), index, ".txt"))
+	file, err := createOutputFile(g.config, filename)
+	if err != nil {
+		return err
+	}
+	defer closeOutputFile(file, &err)
+	g.recordPageFile(page, outputName(g.config, filename))
+
+	fmt.Fprintf(file, "TITLE: %s\n", page.Title)
+	fmt.Fprintf(file, "URL: %s\n", page.URL)
+	if len(page.Tags) > 0 {
+		fmt.Fprintf(file, "TAGS: %s\n", strings.Join(page.Tags, ", "))
+	}
+	if !g.config.GetReproducibleOutput() {
+		fmt.Fprintf(file, "SCRAPED: %s\n", page.Timestamp.Format(time.RFC3339))
+	}
+	fmt.Fprintf(file, "\n")
+	if err := writePageContent(file, page); err != nil {
+		return err
+	}
+	fmt.Fprintf(file, "\n")
 	return nil
 }
 
 // generateJSONOutput generates JSON output
-func (g *Generator) generateJSONOutput() error {
+func (g *Generator) generateJSONOutput() (err error) {
 	filename := filepath.Join(g.config.OutputDir, "documentation.json")
-	file, err := os.Create(filename)
+	file, err := createOutputFile(g.config, filename)
+	if err != nil {
+		return err
+	}
+	defer closeOutputFile(file, &err)
+
+	pages, err := g.jsonPages()
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 
 	output := map[string]interface{}{
 		"root_url":    g.config.RootURL,
-		"scraped_at":  time.Now().Format(time.RFC3339),
+		"scraped_at":  generatedAt(g.config),
 		"total_pages": len(g.pages),
-		"pages":       g.pages,
+		"stats":       pageStats(g.pages),
+		"pages":       pages,
 	}
 
 	return encoder.Encode(output)
 }
 
+// pageDescription returns the metadata excerpt of the page at index, as kept by GenerateStream
+// or taken from its content
+func (g *Generator) pageDescription(page PageData, index int) (string, error) {
+	if index < len(g.excerpts) {
+		return g.excerpts[index], nil
+	}
+	content, err := pageContent(page)
+	if err != nil {
+		return "", err
+	}
+	return TruncateExcerpt(content, excerptLength), nil
+}
+
 // generateMetadataFile creates a metadata file for per-page outputs
 func (g *Generator) generateMetadataFile() error {
 	filename := filepath.Join(g.config.OutputDir, "metadata.yaml")
@@ -248,18 +603,32 @@ func (g *Generator) generateMetadataFile() error {
 	metadata := map[string]interface{}{
 		"scrape_info": map[string]interface{}{
 			"root_url":    g.config.RootURL,
-			"scraped_at":  time.Now().Format(time.RFC3339),
+			"scraped_at":  generatedAt(g.config),
 			"total_pages": len(g.pages),
 		},
 		"pages": make([]map[string]interface{}, len(g.pages)),
 	}
 
 	for i, page := range g.pages {
+		description, err := g.pageDescription(page, i)
+		if err != nil {
+			return err
+		}
+
 		metadata["pages"].([]map[string]interface{})[i] = map[string]interface{}{
-			"title":     page.Title,
-			"url":       page.URL,
-			"timestamp": page.Timestamp.Format(time.RFC3339),
-			"depth":     page.Depth,
+			"title":       page.Title,
+			"url":         page.URL,
+			"depth":       page.Depth,
+			"description": description,
+		}
+		if len(page.Tags) > 0 {
+			metadata["pages"].([]map[string]interface{})[i]["tags"] = page.Tags
+		}
+		if len(page.Metadata) > 0 {
+			metadata["pages"].([]map[string]interface{})[i]["metadata"] = page.Metadata
+		}
+		if !g.config.GetReproducibleOutput() {
+			metadata["pages"].([]map[string]interface{})[i]["timestamp"] = page.Timestamp.Format(time.RFC3339)
 		}
 	}
 
@@ -289,6 +658,100 @@ func (g *Generator) createSafeFilename(title string, index int, extension string
 	return safe + extension
 }
 
+// reproducibleTime replaces wall-clock timestamps when reproducible_output is set
+var reproducibleTime = time.Unix(0, 0).UTC()
+
+// generatedAt returns the generation timestamp, fixed when reproducible_output is set
+func generatedAt(cfg *config.Config) string {
+	if cfg.GetReproducibleOutput() {
+		return reproducibleTime.Format(time.RFC3339)
+	}
+	return time.Now().Format(time.RFC3339)
+}
+
+// writeMarkdownPageMeta writes a page's URL, tags, captured metadata and scrape time, omitting the
+// time for reproducible output
+func writeMarkdownPageMeta(w io.Writer, cfg *config.Config, url string, tags []string, metadata map[string]string, scraped time.Time) {
+	lines := []string{fmt.Sprintf("**URL:** %s", url)}
+	if len(tags) > 0 {
+		lines = append(lines, fmt.Sprintf("**Tags:** %s", strings.Join(tags, ", ")))
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("**%s:** %s", key, metadata[key]))
+	}
+	if !cfg.GetReproducibleOutput() {
+		lines = append(lines, fmt.Sprintf("**Scraped:** %s", scraped.Format(time.RFC3339)))
+	}
+	fmt.Fprintf(w, "%s\n\n", strings.Join(lines, "  \n"))
+}
+
+// jsonPages returns the pages written to JSON with spilled content read back and, for
+// reproducible output, scrape times fixed
+func (g *Generator) jsonPages() ([]PageData, error) {
+	pages := make([]PageData, len(g.pages))
+	for i, page := range g.pages {
+		content, err := pageContent(page)
+		if err != nil {
+			return nil, err
+		}
+		page.Content = content
+		if g.config.GetReproducibleOutput() {
+			page.Timestamp = reproducibleTime
+		}
+		pages[i] = page
+	}
+	sortPages(pages, g.config.SortBy)
+	return pages, nil
+}
+
+// sortPages orders pages by sort_by, "url", "title" or "depth", with ties broken by URL; pages
+// keep their order when sortBy is empty
+func sortPages(pages []PageData, sortBy string) {
+	var less func(a, b PageData) bool
+	switch sortBy {
+	case "url":
+		less = func(a, b PageData) bool { return a.URL < b.URL }
+	case "title":
+		less = func(a, b PageData) bool { return a.Title < b.Title }
+	case "depth":
+		less = func(a, b PageData) bool { return a.Depth < b.Depth }
+	default:
+		return
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		if less(pages[i], pages[j]) {
+			return true
+		}
+		if less(pages[j], pages[i]) {
+			return false
+		}
+		return pages[i].URL < pages[j].URL
+	})
+}
+
+// displayTitle returns the title as shown in TOCs and headers, truncated to max_title_length
+func displayTitle(cfg *config.Config, title string) string {
+	return truncateTitle(title, cfg.GetMaxTitleLength())
+}
+
+// truncateTitle shortens title to at most max runes, ending in an ellipsis; max <= 0 disables it
+func truncateTitle(title string, max int) string {
+	runes := []rune(title)
+	if max <= 0 || len(runes) <= max {
+		return title
+	}
+	if max == 1 {
+		return "…"
+	}
+	return strings.TrimRight(string(runes[:max-1]), " ") + "…"
+}
+
 // createAnchor creates a markdown anchor from a title
 func (g *Generator) createAnchor(title string) string {
 	// Convert to lowercase, replace spaces with dashes, remove special characters
@@ -179,6 +179,85 @@ func TestGenerator_Generate_MarkdownPerPage(t *testing.T) {
 	}
 }
 
+func TestGenerator_Generate_MarkdownPerDepth(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "markdown",
+		OutputType:   "per-depth",
+	}
+
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now(), Depth: 0},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{Title: "API", URL: "https://example.com/api", Content: "API content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Install", URL: "https://example.com/guide/install", Content: "Install content", Timestamp: time.Now(), Depth: 2},
+	}
+
+	generator := New(cfg, pages)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	expected := map[string][]string{
+		"depth-0": {"index.md", "page_001.md"},
+		"depth-1": {"index.md", "page_002.md", "page_003.md"},
+		"depth-2": {"index.md", "page_004.md"},
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs := 0
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs++
+		}
+	}
+	if dirs != len(expected) {
+		t.Errorf("Expected %d depth directories, got %d", len(expected), dirs)
+	}
+
+	for dir, files := range expected {
+		dirEntries, err := os.ReadDir(filepath.Join(tmpDir, dir))
+		if err != nil {
+			t.Errorf("Failed to read %s: %v", dir, err)
+			continue
+		}
+
+		var names []string
+		for _, entry := range dirEntries {
+			names = append(names, entry.Name())
+		}
+		if strings.Join(names, ",") != strings.Join(files, ",") {
+			t.Errorf("%s contains %v, want %v", dir, names, files)
+		}
+	}
+
+	depthIndex, err := os.ReadFile(filepath.Join(tmpDir, "depth-1", "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(depthIndex), "[Guide](page_002.md)") || !strings.Contains(string(depthIndex), "[API](page_003.md)") {
+		t.Errorf("Depth index should list its pages, got:\n%s", depthIndex)
+	}
+
+	index, err := os.ReadFile(filepath.Join(tmpDir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "(depth-2/index.md)") {
+		t.Error("Top-level index should link each depth index")
+	}
+}
+
 func TestGenerator_Generate_JSON(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "test-output-")
 	if err != nil {
@@ -343,3 +422,327 @@ func fileExists(filename string) bool {
 	_, err := os.Stat(filename)
 	return !os.IsNotExist(err)
 }
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		max      int
+		expected string
+	}{
+		{"Short", 10, "Short"},
+		{"Exactly ten", 11, "Exactly ten"},
+		{"A very long title indeed", 10, "A very lo…"},
+		{"Trailing space here", 9, "Trailing…"},
+		{"Ünïcödé títle", 6, "Ünïcö…"},
+		{"日本語のドキュメント", 4, "日本語…"},
+		{"Unlimited", 0, "Unlimited"},
+	}
+
+	for _, tt := range tests {
+		if result := truncateTitle(tt.title, tt.max); result != tt.expected {
+			t.Errorf("truncateTitle(%q, %d) = %q, want %q", tt.title, tt.max, result, tt.expected)
+		}
+	}
+}
+
+func TestGenerator_MaxTitleLength(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	longTitle := "This page title is an entire sentence describing everything on the page"
+	maxLength := 20
+	pages := []PageData{
+		{Title: longTitle, URL: "https://example.com/long", Content: "Long content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      tmpDir,
+		OutputFormat:   "markdown",
+		OutputType:     "single",
+		MaxTitleLength: &maxLength,
+	}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "documentation.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := truncateTitle(longTitle, maxLength)
+	if !strings.Contains(string(content), "1. ["+truncated+"](#") {
+		t.Errorf("TOC should contain truncated title %q", truncated)
+	}
+	if strings.Contains(string(content), longTitle) {
+		t.Error("Markdown headers should not contain the full long title")
+	}
+
+	cfg.OutputFormat = "json"
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Pages []PageData `json:"pages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Pages) != 1 || result.Pages[0].Title != longTitle {
+		t.Errorf("Metadata should keep the full title, got %+v", result.Pages)
+	}
+}
+
+// readOutputTree returns every file under dir keyed by its relative path
+func readOutputTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestGenerator_ReproducibleOutput(t *testing.T) {
+	reproducible := true
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now(), Depth: 0},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	variants := []struct{ format, outputType string }{
+		{"markdown", "single"},
+		{"markdown", "per-page"},
+		{"text", "single"},
+		{"text", "per-page"},
+		{"json", "single"},
+	}
+
+	for _, variant := range variants {
+		for _, hierarchical := range []bool{false, true} {
+			name := variant.format + "/" + variant.outputType
+			if hierarchical {
+				name += "/hierarchical"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				var outputs []map[string]string
+				for run := 0; run < 2; run++ {
+					cfg := &config.Config{
+						RootURL:            "https://example.com",
+						OutputDir:          t.TempDir(),
+						OutputFormat:       variant.format,
+						OutputType:         variant.outputType,
+						ReproducibleOutput: &reproducible,
+					}
+
+					var err error
+					if hierarchical {
+						err = NewHierarchical(cfg, pages).Generate()
+					} else {
+						err = New(cfg, pages).Generate()
+					}
+					if err != nil {
+						t.Fatalf("Generate() error = %v", err)
+					}
+
+					outputs = append(outputs, readOutputTree(t, cfg.OutputDir))
+
+					// Scrape times differ between runs but must not reach the output
+					for i := range pages {
+						pages[i].Timestamp = pages[i].Timestamp.Add(time.Hour)
+					}
+				}
+
+				if len(outputs[0]) == 0 || len(outputs[0]) != len(outputs[1]) {
+					t.Fatalf("Expected matching non-empty file sets, got %d and %d files", len(outputs[0]), len(outputs[1]))
+				}
+				for path, content := range outputs[0] {
+					if outputs[1][path] != content {
+						t.Errorf("File %s differs between runs:\n%s\n---\n%s", path, content, outputs[1][path])
+					}
+					if strings.Contains(content, time.Now().Format("2006-01-02")) {
+						t.Errorf("File %s contains a wall-clock timestamp", path)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerator_Generate_MultipleFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	generate := true
+	cfg := &config.Config{
+		RootURL:          "https://example.com",
+		OutputDir:        tmpDir,
+		OutputFormat:     "markdown",
+		OutputFormats:    []string{"markdown", "json"},
+		OutputType:       "single",
+		GenerateGlossary: &generate,
+	}
+
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now(), Depth: 0},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, filename := range []string{"documentation.md", "documentation.json", "glossary.md"} {
+		if !fileExists(filepath.Join(tmpDir, filename)) {
+			t.Errorf("Expected file %s was not created", filename)
+		}
+	}
+	if fileExists(filepath.Join(tmpDir, "documentation.txt")) {
+		t.Error("Text output should not be generated when not requested")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		TotalPages int `json:"total_pages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalPages != len(pages) {
+		t.Errorf("JSON output has %d pages, want %d", result.TotalPages, len(pages))
+	}
+
+	glossary, err := os.ReadFile(filepath.Join(tmpDir, "glossary.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(glossary), "documentation.md#") {
+		t.Error("Glossary should link into the markdown output when markdown is generated")
+	}
+
+	if cfg.OutputFormat != "markdown" {
+		t.Errorf("Generate() should not modify the caller's config, got output_format %q", cfg.OutputFormat)
+	}
+}
+
+func TestGenerator_Generate_WriteEffectiveConfig(t *testing.T) {
+	writeEffective, validate := true, true
+	for _, hierarchical := range []bool{false, true} {
+		cfg := &config.Config{
+			RootURL:              "https://example.com",
+			OutputDir:            t.TempDir(),
+			OutputFormat:         "markdown",
+			OutputType:           "per-page",
+			WriteEffectiveConfig: &writeEffective,
+			ValidateOutput:       &validate,
+		}
+
+		var err error
+		if hierarchical {
+			err = NewHierarchical(cfg, hierarchicalTestPages()).Generate()
+		} else {
+			err = New(cfg, hierarchicalTestPages()).Generate()
+		}
+		if err != nil {
+			t.Fatalf("Generate() error = %v (hierarchical: %v)", err, hierarchical)
+		}
+
+		var loaded config.Config
+		if err := config.LoadConfig(filepath.Join(cfg.OutputDir, config.EffectiveConfigFile), &loaded); err != nil {
+			t.Fatalf("Expected a loadable effective config (hierarchical: %v): %v", hierarchical, err)
+		}
+		if loaded.RootURL != cfg.RootURL || loaded.OutputType != "per-page" || !loaded.GetWriteEffectiveConfig() {
+			t.Errorf("Expected the run's settings in the effective config, got %+v", loaded)
+		}
+	}
+}
+
+func TestGenerator_Generate_JSONSortBy(t *testing.T) {
+	pages := []PageData{
+		{Title: "Zeta", URL: "https://example.com/b", Content: "b", Timestamp: time.Now(), Depth: 2},
+		{Title: "Alpha", URL: "https://example.com/c", Content: "c", Timestamp: time.Now(), Depth: 1},
+		{Title: "Mu", URL: "https://example.com/a", Content: "a", Timestamp: time.Now(), Depth: 2},
+		{Title: "Alpha", URL: "https://example.com/a/alpha", Content: "d", Timestamp: time.Now(), Depth: 3},
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"", []string{"/b", "/c", "/a", "/a/alpha"}},
+		{"url", []string{"/a", "/a/alpha", "/b", "/c"}},
+		{"title", []string{"/a/alpha", "/c", "/a", "/b"}},
+		{"depth", []string{"/c", "/a", "/b", "/a/alpha"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("sort_by="+tt.sortBy, func(t *testing.T) {
+			for _, stream := range []bool{false, true} {
+				cfg := &config.Config{
+					RootURL:      "https://example.com",
+					OutputDir:    t.TempDir(),
+					OutputFormat: "json",
+					OutputType:   "per-page",
+					SortBy:       tt.sortBy,
+				}
+				var err error
+				if stream {
+					ch := make(chan PageData, len(pages))
+					for _, page := range pages {
+						ch <- page
+					}
+					close(ch)
+					err = New(cfg, nil).GenerateStream(ch)
+				} else {
+					err = New(cfg, pages).Generate()
+				}
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+
+				data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "documentation.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				var result struct {
+					Pages []PageData `json:"pages"`
+				}
+				if err := json.Unmarshal(data, &result); err != nil {
+					t.Fatalf("documentation.json is invalid: %v", err)
+				}
+
+				got := make([]string, len(result.Pages))
+				for i, page := range result.Pages {
+					got[i] = strings.TrimPrefix(page.URL, "https://example.com")
+				}
+				if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+					t.Errorf("stream=%v: pages ordered %v, want %v", stream, got, tt.want)
+				}
+			}
+		})
+	}
+}
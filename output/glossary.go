@@ -0,0 +1,57 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// glossaryEntry is a single title/link pair listed in the glossary
+type glossaryEntry struct {
+	Title string
+	Link  string
+}
+
+// writeGlossary writes glossary.md listing all entries alphabetically, grouped by first letter
+func writeGlossary(outputDir string, entries []glossaryEntry) error {
+	sorted := make([]glossaryEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Title) < strings.ToLower(sorted[j].Title)
+	})
+
+	file, err := os.Create(filepath.Join(outputDir, "glossary.md"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# Glossary\n\n")
+	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(sorted))
+
+	currentLetter := ""
+	for _, entry := range sorted {
+		letter := glossaryLetter(entry.Title)
+		if letter != currentLetter {
+			fmt.Fprintf(file, "\n## %s\n\n", letter)
+			currentLetter = letter
+		}
+		fmt.Fprintf(file, "- [%s](%s)\n", entry.Title, entry.Link)
+	}
+
+	return nil
+}
+
+// glossaryLetter returns the heading a title is grouped under ("#" for non-letters)
+func glossaryLetter(title string) string {
+	for _, r := range title {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		return "#"
+	}
+	return "#"
+}
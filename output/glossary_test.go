@@ -0,0 +1,104 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_GenerateGlossary(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	glossary := true
+	cfg := &config.Config{
+		RootURL:          "https://example.com",
+		OutputDir:        tmpDir,
+		OutputFormat:     "markdown",
+		OutputType:       "per-page",
+		GenerateGlossary: &glossary,
+	}
+
+	titles := []string{"banana", "Cherry", "Apple", "2FA Setup", "avocado"}
+	pages := make([]PageData, len(titles))
+	for i, title := range titles {
+		pages[i] = PageData{
+			Title:     title,
+			URL:       "https://example.com/" + strings.ToLower(strings.ReplaceAll(title, " ", "-")),
+			Content:   "Content for " + title,
+			Timestamp: time.Now(),
+		}
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "glossary.md"))
+	if err != nil {
+		t.Fatalf("Failed to read glossary: %v", err)
+	}
+	glossaryStr := string(content)
+
+	// Each entry must appear after its letter heading, in sorted order
+	expectedOrder := []string{
+		"## #", "[2FA Setup](page_004.md)",
+		"## A", "[Apple](page_003.md)", "[avocado](page_005.md)",
+		"## B", "[banana](page_001.md)",
+		"## C", "[Cherry](page_002.md)",
+	}
+	position := 0
+	for _, expected := range expectedOrder {
+		idx := strings.Index(glossaryStr[position:], expected)
+		if idx < 0 {
+			t.Fatalf("Expected %q after position %d in glossary:\n%s", expected, position, glossaryStr)
+		}
+		position += idx + len(expected)
+	}
+}
+
+func TestGenerator_GlossaryDisabledByDefault(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "markdown",
+		OutputType:   "single",
+	}
+
+	pages := []PageData{{Title: "Page", URL: "https://example.com/page", Content: "Content", Timestamp: time.Now()}}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if fileExists(filepath.Join(tmpDir, "glossary.md")) {
+		t.Error("glossary.md should not be generated unless enabled")
+	}
+}
+
+func TestGlossaryLetter(t *testing.T) {
+	tests := map[string]string{
+		"apple":   "A",
+		"Zebra":   "Z",
+		"42 Ways": "#",
+		"":        "#",
+	}
+
+	for title, expected := range tests {
+		if result := glossaryLetter(title); result != expected {
+			t.Errorf("glossaryLetter(%q) = %q, want %q", title, result, expected)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"docscraper/config"
+)
+
+var (
+	// markdownHeadingPattern matches an ATX heading line, capturing its marker and its text
+	// without closing "#"s
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+	// headingIDPattern matches a trailing {#id} kept by preserve_heading_ids, capturing the id
+	headingIDPattern = regexp.MustCompile(`\s*\{#([^}\s]+)\}\s*$`)
+)
+
+// splitHeadingID splits heading text from its trailing {#id}, returning "" when it has none
+func splitHeadingID(text string) (string, string) {
+	match := headingIDPattern.FindStringSubmatch(text)
+	if match == nil {
+		return text, ""
+	}
+	return strings.TrimSpace(text[:len(text)-len(match[0])]), match[1]
+}
+
+// proseLines calls fn with the index of each line of lines outside fenced code blocks
+func proseLines(lines []string, fn func(i int)) {
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		fn(i)
+	}
+}
+
+// headingLines calls fn with the index and level of each ATX heading ("# ...") in lines,
+// skipping fenced code blocks
+func headingLines(lines []string, fn func(i, level int)) {
+	proseLines(lines, func(i int) {
+		line := lines[i]
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if level >= 1 && level <= 6 && level < len(line) && (line[level] == ' ' || line[level] == '\t') {
+			fn(i, level)
+		}
+	})
+}
+
+// normalizeHeadingLevels shifts every heading in content so the shallowest is at base,
+// keeping their relative nesting; headings pushed past h6 stay at h6
+func normalizeHeadingLevels(content string, base int) string {
+	lines := strings.Split(content, "\n")
+
+	levels := make(map[int]int)
+	shallowest := 0
+	headingLines(lines, func(i, level int) {
+		levels[i] = level
+		if shallowest == 0 || level < shallowest {
+			shallowest = level
+		}
+	})
+	if shallowest == 0 || shallowest == base {
+		return content
+	}
+
+	for i, level := range levels {
+		shifted := level - shallowest + base
+		if shifted > 6 {
+			shifted = 6
+		}
+		lines[i] = strings.Repeat("#", shifted) + lines[i][level:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// markdownContent returns content with its headings normalized under heading_base_level
+func markdownContent(cfg *config.Config, content string) string {
+	if base := cfg.GetHeadingBaseLevel(); base > 0 {
+		return normalizeHeadingLevels(content, base)
+	}
+	return content
+}
+
+// writeMarkdownContent writes a page's content to markdown output, normalizing its headings
+// under heading_base_level; otherwise spilled content is streamed as is
+func writeMarkdownContent(w io.Writer, cfg *config.Config, page PageData) error {
+	if cfg.GetHeadingBaseLevel() == 0 {
+		return writePageContent(w, page)
+	}
+
+	content, err := pageContent(page)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, markdownContent(cfg, content))
+	return err
+}
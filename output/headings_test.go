@@ -0,0 +1,101 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestNormalizeHeadingLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		base    int
+		want    string
+	}{
+		{
+			name:    "shifts to h1 keeping nesting",
+			content: "### Install\n\nText\n\n#### Linux\n\n### Usage",
+			base:    1,
+			want:    "# Install\n\nText\n\n## Linux\n\n# Usage",
+		},
+		{
+			name:    "shifts deeper to a configured base",
+			content: "# Install\n\n## Linux",
+			base:    3,
+			want:    "### Install\n\n#### Linux",
+		},
+		{
+			name:    "clamps at h6",
+			content: "# Top\n\n###### Deep",
+			base:    2,
+			want:    "## Top\n\n###### Deep",
+		},
+		{
+			name:    "fenced code and hashtags are left alone",
+			content: "## Setup\n\n```sh\n# comment\n```\n\n#hashtag",
+			base:    1,
+			want:    "# Setup\n\n```sh\n# comment\n```\n\n#hashtag",
+		},
+		{
+			name:    "no headings",
+			content: "Plain text",
+			base:    1,
+			want:    "Plain text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeHeadingLevels(tt.content, tt.base); got != tt.want {
+				t.Errorf("normalizeHeadingLevels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitHeadingID(t *testing.T) {
+	tests := []struct {
+		heading, text, id string
+	}{
+		{"Install {#install}", "Install", "install"},
+		{"Install   {#install}  ", "Install", "install"},
+		{"Install", "Install", ""},
+		{"Use {#braces} inline", "Use {#braces} inline", ""},
+	}
+
+	for _, tt := range tests {
+		if text, id := splitHeadingID(tt.heading); text != tt.text || id != tt.id {
+			t.Errorf("splitHeadingID(%q) = %q, %q, want %q, %q", tt.heading, text, id, tt.text, tt.id)
+		}
+	}
+}
+
+func TestGenerator_HeadingBaseLevel(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := 1
+	cfg := &config.Config{
+		RootURL:          "https://example.com",
+		OutputDir:        tmpDir,
+		OutputFormat:     "markdown",
+		OutputType:       "per-page",
+		HeadingBaseLevel: &base,
+	}
+	pages := []PageData{
+		{Title: "Guide", URL: "https://example.com/guide", Content: "### Install\n\nSteps\n\n#### Linux\n\nMore steps", Timestamp: time.Now()},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	page := readOutputTree(t, tmpDir)["page_001.md"]
+	if !strings.Contains(page, "\n# Install\n\nSteps\n\n## Linux\n") {
+		t.Errorf("Expected headings shifted to start at h1, got:\n%s", page)
+	}
+	if strings.Contains(page, "###") {
+		t.Errorf("Expected no h3 headings left, got:\n%s", page)
+	}
+}
@@ -5,30 +5,41 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"docscraper/config"
+	"docscraper/scraper"
 )
 
-// DocumentNode represents a node in the documentation tree (local copy to avoid import cycle)
+// DocumentNode represents a node in the documentation tree, as written by the output generators
 type DocumentNode struct {
-	URL       string          `json:"url"`
-	Path      string          `json:"path"`
-	Title     string          `json:"title"`
-	Content   string          `json:"content,omitempty"`
-	Depth     int             `json:"depth"`
-	Level     int             `json:"level"`
-	Parent    *DocumentNode   `json:"-"`
-	Children  []*DocumentNode `json:"children"`
-	Index     int             `json:"index"`
-	Timestamp time.Time       `json:"timestamp"`
-}
-
-// DocumentTree represents the complete documentation tree structure (local copy)
+	URL         string            `json:"url"`
+	Path        string            `json:"path"`
+	Title       string            `json:"title"`
+	Content     string            `json:"content,omitempty"`
+	Depth       int               `json:"depth"`
+	Level       int               `json:"level"`
+	Parent      *DocumentNode     `json:"-"`
+	Children    []*DocumentNode   `json:"children"`
+	Index       int               `json:"index"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Tags        []string          `json:"tags,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	ContentFile string            `json:"-"` // Spilled content, read back by loadContent when written
+}
+
+// loadContent returns the node's content, reading it back from disk when it was spilled
+func (n *DocumentNode) loadContent() (string, error) {
+	return pageContent(PageData{Content: n.Content, ContentFile: n.ContentFile})
+}
+
+// DocumentTree represents the complete documentation tree structure
 type DocumentTree struct {
 	Root       *DocumentNode            `json:"root"`
 	NodeMap    map[string]*DocumentNode `json:"-"`
@@ -41,16 +52,105 @@ type DocumentTree struct {
 type HierarchicalGenerator struct {
 	config *config.Config
 	tree   *DocumentTree
+	order  map[*DocumentNode]int // DFS position of each node, used for numbered output
+
+	names map[*DocumentNode]string // Directory name of each node, unique among its siblings
+
+	redundant map[*DocumentNode]bool // Parents whose content is contained in their children
+
+	err error // Spill read failure met while building the tree, returned by Generate
+
+	written *pageFileLog // Per-node files written by the running Generate, for url_map.json
 }
 
 // NewHierarchical creates a new hierarchical output generator
 func NewHierarchical(cfg *config.Config, pages []PageData) *HierarchicalGenerator {
 	// Convert PageData to DocumentNode and build tree
-	tree := buildTreeFromPages(pages)
+	pages = outputPages(cfg, pages)
+	var tree *DocumentTree
+	if usesTreeBuilder(cfg) {
+		tree = buildTreeWithBuilder(cfg, pages)
+	} else {
+		tree = buildTreeFromPages(pages)
+	}
+	var err error
+	if cfg.GetCollapseSingleChildChains() {
+		err = collapseSingleChildChains(tree)
+	}
 
 	return &HierarchicalGenerator{
 		config: cfg,
 		tree:   tree,
+		err:    err,
+	}
+}
+
+// NewHierarchicalFromTree creates a hierarchical output generator for a tree built by
+// scraper.TreeBuilder, keeping its parent links, order and metadata instead of inferring
+// the hierarchy from URL paths
+func NewHierarchicalFromTree(cfg *config.Config, tree *scraper.DocumentTree) *HierarchicalGenerator {
+	converted := convertTree(tree)
+	redactTree(cfg, converted)
+	var err error
+	if cfg.GetCollapseSingleChildChains() {
+		err = collapseSingleChildChains(converted)
+	}
+
+	return &HierarchicalGenerator{
+		config: cfg,
+		tree:   converted,
+		err:    err,
+	}
+}
+
+// convertTree copies a scraper tree under an untitled root, the scraped site root becoming its
+// only child; a placeholder scraper root without a URL is dropped and its children take its place
+func convertTree(source *scraper.DocumentTree) *DocumentTree {
+	root := &DocumentNode{
+		Title:    "Root",
+		Children: make([]*DocumentNode, 0),
+	}
+	nodeMap := make(map[string]*DocumentNode)
+
+	var convert func(node *scraper.DocumentNode, parent *DocumentNode)
+	convert = func(node *scraper.DocumentNode, parent *DocumentNode) {
+		converted := &DocumentNode{
+			URL:       node.URL,
+			Path:      node.Path,
+			Title:     node.Title,
+			Content:   node.Content,
+			Depth:     node.Depth,
+			Level:     parent.Level + 1,
+			Parent:    parent,
+			Children:  make([]*DocumentNode, 0, len(node.Children)),
+			Index:     node.Index,
+			Timestamp: node.Metadata.LastModified,
+			Tags:      node.Metadata.Tags,
+		}
+		parent.Children = append(parent.Children, converted)
+		nodeMap[node.URL] = converted
+
+		for _, child := range node.Children {
+			convert(child, converted)
+		}
+	}
+
+	if source != nil && source.Root != nil {
+		if source.Root.URL == "" && source.Root.Title == "" {
+			for _, child := range source.Root.Children {
+				convert(child, root)
+			}
+		} else {
+			convert(source.Root, root)
+		}
+	}
+
+	return &DocumentTree{
+		Root:       root,
+		NodeMap:    nodeMap,
+		MaxDepth:   calculateMaxDepth(root),
+		TotalNodes: len(nodeMap),
+		BuildTime:  time.Now(),
 	}
 }
 
@@ -67,17 +167,7 @@ func buildTreeFromPages(pages []PageData) *DocumentTree {
 	nodes := make([]*DocumentNode, len(pages))
 
 	for i, page := range pages {
-		node := &DocumentNode{
-			URL:       page.URL,
-			Path:      extractPathFromURL(page.URL),
-			Title:     page.Title,
-			Content:   page.Content,
-			Depth:     page.Depth,
-			Level:     0,
-			Children:  make([]*DocumentNode, 0),
-			Index:     i,
-			Timestamp: page.Timestamp,
-		}
+		node := newPageNode(page, i)
 		nodes[i] = node
 		nodeMap[page.URL] = node
 	}
@@ -105,6 +195,25 @@ func buildTreeFromPages(pages []PageData) *DocumentTree {
 	}
 }
 
+// newPageNode creates an unattached node for the page at index i; spilled content stays on disk
+// until the node is written
+func newPageNode(page PageData, i int) *DocumentNode {
+	return &DocumentNode{
+		URL:         page.URL,
+		Path:        extractPathFromURL(page.URL),
+		Title:       page.Title,
+		Content:     page.Content,
+		Depth:       page.Depth,
+		Level:       0,
+		Children:    make([]*DocumentNode, 0),
+		Index:       i,
+		Timestamp:   page.Timestamp,
+		Tags:        page.Tags,
+		Metadata:    page.Metadata,
+		ContentFile: page.ContentFile,
+	}
+}
+
 // extractPathFromURL extracts the path component from a URL
 func extractPathFromURL(urlStr string) string {
 	if u, err := url.Parse(urlStr); err == nil {
@@ -182,11 +291,110 @@ func (t *DocumentTree) collectNodes(node *DocumentNode, nodes *[]*DocumentNode)
 
 // Generate creates hierarchically organized output
 func (h *HierarchicalGenerator) Generate() error {
+	if h.err != nil {
+		return h.err
+	}
+
 	// Create output directory
 	if err := os.MkdirAll(h.config.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %v", err)
 	}
 
+	if h.config.GetSkeletonOnly() {
+		return h.generateSkeleton()
+	}
+
+	cfg, err := applyFileLimit(h.config, len(h.tree.NodeMap), true)
+	if err != nil {
+		return err
+	}
+	h.config = cfg
+	h.written = newPageFileLog(h.config.OutputDir)
+
+	h.assignOrder()
+	h.assignDirectoryNames()
+	if err := h.detectRedundantParents(); err != nil {
+		return err
+	}
+
+	formats := h.config.GetOutputFormats()
+	err = generateFormats(h.config, formats, func(format string) error {
+		formatGenerator, err := h.forFormat(format)
+		if err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+		return formatGenerator.generateFormat()
+	})
+	if err != nil {
+		return err
+	}
+
+	if h.config.GetGenerateGlossary() {
+		format := glossaryFormat(formats)
+		entries := h.withFormat(format).glossaryEntries()
+		for i := range entries {
+			entries[i].Link = formatLink(h.config, format, entries[i].Link)
+		}
+		if err := writeGlossary(h.config.OutputDir, entries); err != nil {
+			return err
+		}
+	}
+
+	if contains(formats, "json") && h.config.GetGenerateJSONSchema() {
+		jsonGenerator, err := h.forFormat("json")
+		if err != nil {
+			return err
+		}
+		if err := writeJSONSchema(jsonGenerator.config.OutputDir); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetGenerateStructureOutline() {
+		if err := h.generateStructureOutline(); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetGenerateURLMap() {
+		if err := writeURLMap(h.config.OutputDir, h.URLMap()); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetGenerateURLIndex() {
+		if err := writeURLIndex(h.config.OutputDir, h.URLIndex()); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetGenerateTokenReport() {
+		if err := writeTokenReport(h.config.OutputDir, h.TokenReport()); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetGenerateReadme() {
+		if err := writeReadme(h.config, len(h.tree.GetAllNodes()), readmeLayout(h.config.OutputType, true), h.readmeEntries()); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetWriteEffectiveConfig() {
+		if err := h.config.SaveEffective(filepath.Join(h.config.OutputDir, config.EffectiveConfigFile)); err != nil {
+			return err
+		}
+	}
+
+	if h.config.GetValidateOutput() {
+		return validationError(ValidateOutput(h.config.OutputDir))
+	}
+
+	return nil
+}
+
+// generateFormat writes the hierarchical output files for the configured output format
+func (h *HierarchicalGenerator) generateFormat() error {
 	switch h.config.OutputFormat {
 	case "markdown":
 		return h.generateHierarchicalMarkdown()
@@ -199,6 +407,48 @@ func (h *HierarchicalGenerator) Generate() error {
 	}
 }
 
+// withFormat returns a generator sharing the same tree that writes the given format
+func (h *HierarchicalGenerator) withFormat(format string) *HierarchicalGenerator {
+	if format == h.config.OutputFormat {
+		return h
+	}
+
+	cfg := *h.config
+	cfg.OutputFormat = format
+	return &HierarchicalGenerator{config: &cfg, tree: h.tree, order: h.order, names: h.names, redundant: h.redundant, written: h.written}
+}
+
+// glossaryEntries returns glossary links pointing at each node's generated markdown, or at
+// the source URL for formats without linkable markdown output
+func (h *HierarchicalGenerator) glossaryEntries() []glossaryEntry {
+	var entries []glossaryEntry
+
+	var visit func(node *DocumentNode, dir string)
+	visit = func(node *DocumentNode, dir string) {
+		if node.Title != "" && node.Title != "Root" { // Skip root node
+			dir = filepath.Join(dir, h.directoryName(node))
+			link := node.URL
+			if h.config.OutputFormat == "markdown" {
+				if h.config.OutputType == "single" {
+					link = "documentation_hierarchical.md#" + h.createAnchor(identityTitle(h.config, node.Title, node.URL))
+				} else {
+					link = filepath.ToSlash(filepath.Join(dir, "index.md"))
+				}
+			}
+			entries = append(entries, glossaryEntry{Title: node.Title, Link: link})
+		}
+		for _, child := range node.Children {
+			visit(child, dir)
+		}
+	}
+
+	if h.tree.Root != nil {
+		visit(h.tree.Root, "")
+	}
+
+	return entries
+}
+
 // generateHierarchicalMarkdown generates hierarchically ordered markdown
 func (h *HierarchicalGenerator) generateHierarchicalMarkdown() error {
 	if h.config.OutputType == "single" {
@@ -219,7 +469,7 @@ func (h *HierarchicalGenerator) generateSingleHierarchicalMarkdown() error {
 	// Write header
 	fmt.Fprintf(file, "# Documentation Scrape Results (Hierarchical)\n\n")
 	fmt.Fprintf(file, "**Scraped from:** %s  \n", h.config.RootURL)
-	fmt.Fprintf(file, "**Generated:** %s  \n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(h.config))
 	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(h.tree.GetAllNodes()))
 	fmt.Fprintf(file, "---\n\n")
 
@@ -229,9 +479,7 @@ func (h *HierarchicalGenerator) generateSingleHierarchicalMarkdown() error {
 	fmt.Fprintf(file, "\n---\n\n")
 
 	// Write content in hierarchical order
-	h.writeHierarchicalContent(file, h.tree.Root, 0)
-
-	return nil
+	return h.writeHierarchicalContent(file, h.tree.Root, 0)
 }
 
 // generatePerPageHierarchicalMarkdown creates separate files organized hierarchically
@@ -260,26 +508,19 @@ func (h *HierarchicalGenerator) writeHierarchicalTOC(file *os.File, node *Docume
 
 	indent := strings.Repeat("  ", level)
 	if node.Title != "" && node.Title != "Root" { // Skip root node
-		anchor := h.createAnchor(node.Title)
-		fmt.Fprintf(file, "%s- [%s](#%s)\n", indent, node.Title, anchor)
+		anchor := h.createAnchor(identityTitle(h.config, node.Title, node.URL))
+		fmt.Fprintf(file, "%s- [%s](#%s)\n", indent, displayTitle(h.config, node.Title), anchor)
 	}
 
-	// Sort children for consistent ordering
-	children := make([]*DocumentNode, len(node.Children))
-	copy(children, node.Children)
-	sort.Slice(children, func(i, j int) bool {
-		return children[i].Title < children[j].Title
-	})
-
-	for _, child := range children {
+	for _, child := range h.sortedChildren(node) {
 		h.writeHierarchicalTOC(file, child, level+1)
 	}
 }
 
 // writeHierarchicalContent writes content in hierarchical order
-func (h *HierarchicalGenerator) writeHierarchicalContent(file *os.File, node *DocumentNode, level int) {
+func (h *HierarchicalGenerator) writeHierarchicalContent(file *os.File, node *DocumentNode, level int) error {
 	if node == nil {
-		return
+		return nil
 	}
 
 	if node.Title != "" && node.Title != "Root" { // Skip root node
@@ -289,24 +530,23 @@ func (h *HierarchicalGenerator) writeHierarchicalContent(file *os.File, node *Do
 			headerLevel = 6 // Markdown only supports up to h6
 		}
 		headerPrefix := strings.Repeat("#", headerLevel)
-		anchor := h.createAnchor(node.Title)
+		anchor := h.createAnchor(identityTitle(h.config, node.Title, node.URL))
 
-		fmt.Fprintf(file, "%s %s {#%s}\n\n", headerPrefix, node.Title, anchor)
-		fmt.Fprintf(file, "**URL:** %s  \n", node.URL)
-		fmt.Fprintf(file, "**Scraped:** %s\n\n", node.Timestamp.Format(time.RFC3339))
-		fmt.Fprintf(file, "%s\n\n", node.Content)
+		content, err := h.nodeContent(node)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(file, "%s %s {#%s}\n\n", headerPrefix, displayTitle(h.config, node.Title), anchor)
+		writeMarkdownPageMeta(file, h.config, node.URL, node.Tags, node.Metadata, node.Timestamp)
+		fmt.Fprintf(file, "%s\n\n", markdownContent(h.config, content))
 	}
 
-	// Sort children for consistent ordering
-	children := make([]*DocumentNode, len(node.Children))
-	copy(children, node.Children)
-	sort.Slice(children, func(i, j int) bool {
-		return children[i].Title < children[j].Title
-	})
-
-	for _, child := range children {
-		h.writeHierarchicalContent(file, child, level+1)
+	for _, child := range h.sortedChildren(node) {
+		if err := h.writeHierarchicalContent(file, child, level+1); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // createHierarchicalDirectories creates directory structure mirroring document hierarchy
@@ -317,8 +557,7 @@ func (h *HierarchicalGenerator) createHierarchicalDirectories(node *DocumentNode
 
 	var currentPath string
 	if node.Title != "" && node.Title != "Root" { // Skip root node
-		safeName := h.createSafeDirectoryName(node.Title)
-		currentPath = filepath.Join(basePath, safeName)
+		currentPath = filepath.Join(basePath, h.directoryName(node))
 		if err := os.MkdirAll(currentPath, 0755); err != nil {
 			return err
 		}
@@ -343,8 +582,12 @@ func (h *HierarchicalGenerator) writeHierarchicalFiles(node *DocumentNode, baseP
 
 	var currentPath string
 	if node.Title != "" && node.Title != "Root" { // Skip root node
-		safeName := h.createSafeDirectoryName(node.Title)
-		currentPath = filepath.Join(basePath, safeName)
+		currentPath = filepath.Join(basePath, h.directoryName(node))
+
+		content, err := h.nodeContent(node)
+		if err != nil {
+			return err
+		}
 
 		// Write content file
 		filename := filepath.Join(currentPath, "index.md")
@@ -352,24 +595,29 @@ func (h *HierarchicalGenerator) writeHierarchicalFiles(node *DocumentNode, baseP
 		if err != nil {
 			return err
 		}
+		h.written.record(h.config.OutputFormat, node.URL, filename)
 
-		fmt.Fprintf(file, "# %s\n\n", node.Title)
-		fmt.Fprintf(file, "**URL:** %s  \n", node.URL)
-		fmt.Fprintf(file, "**Scraped:** %s\n\n", node.Timestamp.Format(time.RFC3339))
+		fmt.Fprintf(file, "# %s\n\n", displayTitle(h.config, node.Title))
+		writeMarkdownPageMeta(file, h.config, node.URL, node.Tags, node.Metadata, node.Timestamp)
 
-		// Add navigation to children if any
-		if len(node.Children) > 0 {
+		// Add navigation to children if any, unless it goes in the section's own _index.md
+		if len(node.Children) > 0 && !h.config.GetSectionIndexes() {
 			fmt.Fprintf(file, "## Sub-sections\n\n")
 			for _, child := range node.Children {
-				childSafeName := h.createSafeDirectoryName(child.Title)
-				fmt.Fprintf(file, "- [%s](%s/index.md)\n", child.Title, childSafeName)
+				fmt.Fprintf(file, "- [%s](%s/index.md)\n", displayTitle(h.config, child.Title), h.directoryName(child))
 			}
 			fmt.Fprintf(file, "\n")
 		}
 
 		fmt.Fprintf(file, "---\n\n")
-		fmt.Fprintf(file, "%s\n", node.Content)
+		fmt.Fprintf(file, "%s\n", markdownContent(h.config, content))
 		file.Close()
+
+		if len(node.Children) > 0 && h.config.GetSectionIndexes() {
+			if err := h.writeSectionIndex(node, currentPath); err != nil {
+				return err
+			}
+		}
 	} else {
 		currentPath = basePath
 	}
@@ -383,6 +631,29 @@ func (h *HierarchicalGenerator) writeHierarchicalFiles(node *DocumentNode, baseP
 	return nil
 }
 
+// writeSectionIndex writes a section's navigation-only _index.md, linking its own content and
+// each child's _index.md, or index.md for pages without children
+func (h *HierarchicalGenerator) writeSectionIndex(node *DocumentNode, dir string) error {
+	file, err := os.Create(filepath.Join(dir, "_index.md"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# %s\n\n", displayTitle(h.config, node.Title))
+	fmt.Fprintf(file, "- [%s](index.md)\n\n", displayTitle(h.config, node.Title))
+	fmt.Fprintf(file, "## Sub-sections\n\n")
+	for _, child := range h.sortedChildren(node) {
+		page := "index.md"
+		if len(child.Children) > 0 {
+			page = "_index.md"
+		}
+		fmt.Fprintf(file, "- [%s](%s/%s)\n", displayTitle(h.config, child.Title), h.directoryName(child), page)
+	}
+
+	return nil
+}
+
 // generateHierarchicalIndex creates a main index file for hierarchical structure
 func (h *HierarchicalGenerator) generateHierarchicalIndex() error {
 	filename := filepath.Join(h.config.OutputDir, "index.md")
@@ -394,36 +665,29 @@ func (h *HierarchicalGenerator) generateHierarchicalIndex() error {
 
 	fmt.Fprintf(file, "# Documentation Index (Hierarchical)\n\n")
 	fmt.Fprintf(file, "**Scraped from:** %s  \n", h.config.RootURL)
-	fmt.Fprintf(file, "**Generated:** %s  \n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(h.config))
 	fmt.Fprintf(file, "**Total Pages:** %d\n\n", len(h.tree.GetAllNodes()))
 	fmt.Fprintf(file, "## Structure\n\n")
 
-	h.writeHierarchicalIndex(file, h.tree.Root, 0)
+	h.writeHierarchicalIndex(file, h.tree.Root, 0, "")
 
 	return nil
 }
 
-// writeHierarchicalIndex writes hierarchical index links
-func (h *HierarchicalGenerator) writeHierarchicalIndex(file *os.File, node *DocumentNode, level int) {
+// writeHierarchicalIndex writes hierarchical index links, relative to the output directory
+func (h *HierarchicalGenerator) writeHierarchicalIndex(file *os.File, node *DocumentNode, level int, basePath string) {
 	if node == nil {
 		return
 	}
 
 	indent := strings.Repeat("  ", level)
 	if node.Title != "" && node.Title != "Root" { // Skip root node
-		safeName := h.createSafeDirectoryName(node.Title)
-		fmt.Fprintf(file, "%s- [%s](%s/index.md)\n", indent, node.Title, safeName)
+		basePath = path.Join(basePath, h.directoryName(node))
+		fmt.Fprintf(file, "%s- [%s](%s/index.md)\n", indent, displayTitle(h.config, node.Title), basePath)
 	}
 
-	// Sort children for consistent ordering
-	children := make([]*DocumentNode, len(node.Children))
-	copy(children, node.Children)
-	sort.Slice(children, func(i, j int) bool {
-		return children[i].Title < children[j].Title
-	})
-
-	for _, child := range children {
-		h.writeHierarchicalIndex(file, child, level+1)
+	for _, child := range h.sortedChildren(node) {
+		h.writeHierarchicalIndex(file, child, level+1, basePath)
 	}
 }
 
@@ -440,48 +704,48 @@ func (h *HierarchicalGenerator) generateHierarchicalText() error {
 	fmt.Fprintf(file, "DOCUMENTATION SCRAPE RESULTS (HIERARCHICAL)\n")
 	fmt.Fprintf(file, "===========================================\n\n")
 	fmt.Fprintf(file, "Scraped from: %s\n", h.config.RootURL)
-	fmt.Fprintf(file, "Generated: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(file, "Generated: %s\n", generatedAt(h.config))
 	fmt.Fprintf(file, "Total Pages: %d\n\n", len(h.tree.GetAllNodes()))
 
-	h.writeHierarchicalTextContent(file, h.tree.Root, 0)
-
-	return nil
+	return h.writeHierarchicalTextContent(file, h.tree.Root, 0)
 }
 
 // writeHierarchicalTextContent writes text content in hierarchical order
-func (h *HierarchicalGenerator) writeHierarchicalTextContent(file *os.File, node *DocumentNode, level int) {
+func (h *HierarchicalGenerator) writeHierarchicalTextContent(file *os.File, node *DocumentNode, level int) error {
 	if node == nil {
-		return
+		return nil
 	}
 
 	if node.Title != "" && node.Title != "Root" { // Skip root node
+		content, err := h.nodeContent(node)
+		if err != nil {
+			return err
+		}
 		indent := strings.Repeat("  ", level)
 		separator := strings.Repeat("=", 80-len(indent))
 
 		fmt.Fprintf(file, "%s%s\n", indent, separator)
 		fmt.Fprintf(file, "%sTITLE: %s\n", indent, node.Title)
 		fmt.Fprintf(file, "%sURL: %s\n", indent, node.URL)
-		fmt.Fprintf(file, "%sSCRAPED: %s\n", indent, node.Timestamp.Format(time.RFC3339))
+		if !h.config.GetReproducibleOutput() {
+			fmt.Fprintf(file, "%sSCRAPED: %s\n", indent, node.Timestamp.Format(time.RFC3339))
+		}
 		fmt.Fprintf(file, "%s%s\n\n", indent, separator)
 
 		// Indent content
-		contentLines := strings.Split(node.Content, "\n")
+		contentLines := strings.Split(content, "\n")
 		for _, line := range contentLines {
 			fmt.Fprintf(file, "%s%s\n", indent, line)
 		}
 		fmt.Fprintf(file, "\n")
 	}
 
-	// Sort children for consistent ordering
-	children := make([]*DocumentNode, len(node.Children))
-	copy(children, node.Children)
-	sort.Slice(children, func(i, j int) bool {
-		return children[i].Title < children[j].Title
-	})
-
-	for _, child := range children {
-		h.writeHierarchicalTextContent(file, child, level+1)
+	for _, child := range h.sortedChildren(node) {
+		if err := h.writeHierarchicalTextContent(file, child, level+1); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // generateHierarchicalJSON generates hierarchical JSON output
@@ -493,11 +757,20 @@ func (h *HierarchicalGenerator) generateHierarchicalJSON() error {
 	}
 	defer file.Close()
 
+	hierarchy, err := h.nodeToJSON(h.tree.Root)
+	if err != nil {
+		return err
+	}
+	stats, err := h.treeStats()
+	if err != nil {
+		return err
+	}
 	output := map[string]interface{}{
 		"root_url":    h.config.RootURL,
-		"scraped_at":  time.Now().Format(time.RFC3339),
+		"scraped_at":  generatedAt(h.config),
 		"total_pages": len(h.tree.GetAllNodes()),
-		"hierarchy":   h.nodeToJSON(h.tree.Root),
+		"stats":       stats,
+		"hierarchy":   hierarchy,
 	}
 
 	encoder := json.NewEncoder(file)
@@ -506,31 +779,141 @@ func (h *HierarchicalGenerator) generateHierarchicalJSON() error {
 }
 
 // nodeToJSON converts a document node to JSON representation
-func (h *HierarchicalGenerator) nodeToJSON(node *DocumentNode) map[string]interface{} {
+func (h *HierarchicalGenerator) nodeToJSON(node *DocumentNode) (map[string]interface{}, error) {
 	if node == nil {
-		return nil
+		return nil, nil
 	}
 
+	content, err := h.nodeContent(node)
+	if err != nil {
+		return nil, err
+	}
 	result := map[string]interface{}{
 		"url":       node.URL,
 		"path":      node.Path,
 		"title":     node.Title,
-		"content":   node.Content,
-		"timestamp": node.Timestamp.Format(time.RFC3339),
+		"content":   content,
+		"timestamp": h.nodeTimestamp(node),
 		"depth":     node.Depth,
 		"level":     node.Level,
 		"index":     node.Index,
 		"children":  make([]map[string]interface{}, 0),
 	}
+	if h.redundant[node] {
+		result["redundant"] = true
+	}
+	if len(node.Tags) > 0 {
+		result["tags"] = node.Tags
+	}
+	if len(node.Metadata) > 0 {
+		result["metadata"] = node.Metadata
+	}
 
 	for _, child := range node.Children {
-		childJSON := h.nodeToJSON(child)
+		childJSON, err := h.nodeToJSON(child)
+		if err != nil {
+			return nil, err
+		}
 		if childJSON != nil {
 			result["children"] = append(result["children"].([]map[string]interface{}), childJSON)
 		}
 	}
 
-	return result
+	return result, nil
+}
+
+// nodeTimestamp returns a node's scrape time for JSON output, fixed for reproducible output
+func (h *HierarchicalGenerator) nodeTimestamp(node *DocumentNode) string {
+	if h.config.GetReproducibleOutput() {
+		return reproducibleTime.Format(time.RFC3339)
+	}
+	return node.Timestamp.Format(time.RFC3339)
+}
+
+// sortedChildren returns a node's children sorted by title for consistent ordering, or under
+// use_table_of_contents in the tree's order
+func (h *HierarchicalGenerator) sortedChildren(node *DocumentNode) []*DocumentNode {
+	children := make([]*DocumentNode, len(node.Children))
+	copy(children, node.Children)
+	if h.config.GetUseTableOfContents() {
+		return children
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Title < children[j].Title
+	})
+	return children
+}
+
+// assignOrder records the depth-first reading order of every node in the tree
+func (h *HierarchicalGenerator) assignOrder() {
+	h.order = make(map[*DocumentNode]int)
+
+	var visit func(node *DocumentNode)
+	visit = func(node *DocumentNode) {
+		if node.Title != "" && node.Title != "Root" { // Skip root node
+			h.order[node] = len(h.order) + 1
+		}
+		for _, child := range h.sortedChildren(node) {
+			visit(child)
+		}
+	}
+
+	if h.tree.Root != nil {
+		visit(h.tree.Root)
+	}
+}
+
+// assignDirectoryNames gives every node a directory name unique among its siblings; siblings
+// whose titles make the same name get -2, -3, ... appended in reading order
+func (h *HierarchicalGenerator) assignDirectoryNames() {
+	h.names = make(map[*DocumentNode]string)
+
+	var visit func(node *DocumentNode)
+	visit = func(node *DocumentNode) {
+		used := make(map[string]bool)
+		for _, child := range h.sortedChildren(node) {
+			base := h.safeNodeName(child)
+			name := base
+			for n := 2; used[name]; n++ {
+				name = fmt.Sprintf("%s-%d", base, n)
+			}
+			used[name] = true
+			h.names[child] = name
+			visit(child)
+		}
+	}
+
+	if h.tree.Root != nil {
+		visit(h.tree.Root)
+	}
+}
+
+// safeNodeName returns the filesystem-safe name of a node's title, with its query slug when
+// query_is_identity is set
+func (h *HierarchicalGenerator) safeNodeName(node *DocumentNode) string {
+	safeName := h.createSafeDirectoryName(node.Title)
+	if slug := querySlug(h.config, node.URL); slug != "" {
+		safeName += "_" + slug
+	}
+	return safeName
+}
+
+// directoryName returns the on-disk directory name for a node, prefixed with its
+// zero-padded reading-order position when numbered output is enabled
+func (h *HierarchicalGenerator) directoryName(node *DocumentNode) string {
+	safeName, assigned := h.names[node]
+	if !assigned {
+		safeName = h.safeNodeName(node)
+	}
+	if !h.config.GetNumberedOutput() {
+		return safeName
+	}
+
+	width := len(strconv.Itoa(len(h.order)))
+	if width < 3 {
+		width = 3
+	}
+	return fmt.Sprintf("%0*d_%s", width, h.order[node], safeName)
 }
 
 // createSafeDirectoryName creates a filesystem-safe directory name
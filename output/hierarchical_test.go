@@ -0,0 +1,232 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+	"docscraper/scraper"
+)
+
+// hierarchicalTestPages returns a small page set forming a two-level tree
+func hierarchicalTestPages() []PageData {
+	return []PageData{
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Docs", URL: "https://example.com/docs", Content: "Docs content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Beta", URL: "https://example.com/docs/beta", Content: "Beta content", Timestamp: time.Now(), Depth: 2},
+		{Title: "Alpha", URL: "https://example.com/docs/alpha", Content: "Alpha content", Timestamp: time.Now(), Depth: 2},
+	}
+}
+
+func TestHierarchicalGenerator_NumberedOutput(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	numbered := true
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      tmpDir,
+		OutputFormat:   "markdown",
+		OutputType:     "per-page",
+		NumberedOutput: &numbered,
+	}
+
+	generator := NewHierarchical(cfg, hierarchicalTestPages())
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// DFS reading order: Docs, Alpha, Beta, Guide
+	expectedFiles := []string{
+		filepath.Join("001_docs", "index.md"),
+		filepath.Join("001_docs", "002_alpha", "index.md"),
+		filepath.Join("001_docs", "003_beta", "index.md"),
+		filepath.Join("004_guide", "index.md"),
+	}
+	for _, expected := range expectedFiles {
+		if !fileExists(filepath.Join(tmpDir, expected)) {
+			t.Errorf("Expected file %s was not created", expected)
+		}
+	}
+
+	index, err := os.ReadFile(filepath.Join(tmpDir, "index.md"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "(004_guide/index.md)") {
+		t.Error("Index should link to numbered directories")
+	}
+}
+
+func TestHierarchicalGenerator_UnnumberedOutput(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+
+	generator := NewHierarchical(cfg, hierarchicalTestPages())
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !fileExists(filepath.Join(tmpDir, "docs", "alpha", "index.md")) {
+		t.Error("Expected unnumbered directory docs/alpha to be created")
+	}
+}
+
+func TestNewHierarchicalFromTree(t *testing.T) {
+	// The navigation menu nests /install under /guide, which the URL paths alone would not
+	contents := map[string]scraper.ScrapedContent{
+		"https://example.com/":        {URL: "https://example.com/", Title: "Home", Content: "Home content"},
+		"https://example.com/guide":   {URL: "https://example.com/guide", Title: "Guide", Content: "Guide content"},
+		"https://example.com/install": {URL: "https://example.com/install", Title: "Install", Content: "Install content", Metadata: scraper.NodeMetadata{Tags: []string{"setup"}}},
+	}
+	nav := `<ul><li><a href="https://example.com/guide">Guide</a><ul><li><a href="https://example.com/install">Install</a></li></ul></li></ul>`
+	tree := scraper.NewTreeBuilder(scraper.TreeConfig{}).BuildTreeFromNavigation(nav, contents)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    t.TempDir(),
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+	if err := NewHierarchicalFromTree(cfg, tree).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	install := filepath.Join(cfg.OutputDir, "home", "guide", "install", "index.md")
+	data, err := os.ReadFile(install)
+	if err != nil {
+		t.Fatalf("Expected the navigation hierarchy on disk: %v", err)
+	}
+	if !strings.Contains(string(data), "**Tags:** setup") {
+		t.Errorf("Expected the node's tags to be kept, got:\n%s", data)
+	}
+}
+
+func TestHierarchicalGenerator_SectionIndexes(t *testing.T) {
+	sectionIndexes := true
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "markdown",
+		OutputType:     "per-page",
+		SectionIndexes: &sectionIndexes,
+	}
+
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Docs is a section: navigation in _index.md, its own content in index.md
+	nav, err := os.ReadFile(filepath.Join(cfg.OutputDir, "docs", "_index.md"))
+	if err != nil {
+		t.Fatalf("Expected a navigation index for the Docs section: %v", err)
+	}
+	for _, link := range []string{"- [Docs](index.md)", "- [Alpha](alpha/index.md)", "- [Beta](beta/index.md)"} {
+		if !strings.Contains(string(nav), link) {
+			t.Errorf("Expected %q in the section index, got:\n%s", link, nav)
+		}
+	}
+	if strings.Contains(string(nav), "Docs content") {
+		t.Errorf("Expected the section index to hold navigation only, got:\n%s", nav)
+	}
+
+	content, err := os.ReadFile(filepath.Join(cfg.OutputDir, "docs", "index.md"))
+	if err != nil {
+		t.Fatalf("Expected a content file for the Docs section: %v", err)
+	}
+	if !strings.Contains(string(content), "Docs content") || strings.Contains(string(content), "Sub-sections") {
+		t.Errorf("Expected the content file without navigation, got:\n%s", content)
+	}
+
+	// Pages without children get no section index
+	for _, leaf := range []string{filepath.Join("guide", "_index.md"), filepath.Join("docs", "alpha", "_index.md")} {
+		if fileExists(filepath.Join(cfg.OutputDir, leaf)) {
+			t.Errorf("Expected no %s for a page without children", leaf)
+		}
+	}
+}
+
+func TestHierarchicalGenerator_DuplicateSiblingTitles(t *testing.T) {
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    t.TempDir(),
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+	pages := []PageData{
+		{Title: "Docs", URL: "https://example.com/docs", Content: "Docs content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Overview", URL: "https://example.com/docs/api", Content: "API overview", Timestamp: time.Now(), Depth: 2},
+		{Title: "Overview", URL: "https://example.com/docs/cli", Content: "CLI overview", Timestamp: time.Now(), Depth: 2},
+		{Title: "overview!", URL: "https://example.com/docs/sdk", Content: "SDK overview", Timestamp: time.Now(), Depth: 2},
+	}
+
+	if err := NewHierarchical(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, cfg.OutputDir)
+	seen := make(map[string]string)
+	for _, dir := range []string{"overview", "overview-2", "overview-3"} {
+		name := filepath.Join("docs", dir, "index.md")
+		content, exists := files[name]
+		if !exists {
+			t.Fatalf("Expected %s to be written", name)
+		}
+		for _, overview := range []string{"API overview", "CLI overview", "SDK overview"} {
+			if strings.Contains(content, overview) {
+				if other, taken := seen[overview]; taken {
+					t.Errorf("%s and %s both hold %q", other, name, overview)
+				}
+				seen[overview] = name
+			}
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected each overview in its own directory, got %v", seen)
+	}
+
+	for _, link := range []string{"(docs/overview/index.md)", "(docs/overview-2/index.md)", "(docs/overview-3/index.md)"} {
+		if !strings.Contains(files["index.md"], link) {
+			t.Errorf("Expected index.md to link %s, got:\n%s", link, files["index.md"])
+		}
+	}
+}
+
+func TestHierarchicalGenerator_IndexLinksNestedPages(t *testing.T) {
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    t.TempDir(),
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Nested pages are linked by their full path from the output directory
+	files := readOutputTree(t, cfg.OutputDir)
+	for _, link := range []string{"docs/index.md", "docs/alpha/index.md", "docs/beta/index.md", "guide/index.md"} {
+		if !strings.Contains(files["index.md"], "("+link+")") {
+			t.Errorf("Expected index.md to link %s, got:\n%s", link, files["index.md"])
+		}
+		if _, exists := files[filepath.FromSlash(link)]; !exists {
+			t.Errorf("Expected %s to be written", link)
+		}
+	}
+}
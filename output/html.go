@@ -0,0 +1,225 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// htmlStylesheet is embedded in every generated page so the output is self-contained
+const htmlStylesheet = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: #222; max-width: 56rem; margin: 0 auto; padding: 2rem 1rem; }
+nav { border: 1px solid #ddd; border-radius: 4px; padding: 0.5rem 1.5rem; margin-bottom: 2rem; background: #fafafa; }
+section { border-top: 1px solid #eee; padding-top: 1rem; margin-top: 2rem; }
+.meta { color: #666; font-size: 0.9rem; }
+.content { white-space: pre-wrap; overflow-wrap: break-word; }
+a { color: #0366d6; }
+`
+
+// htmlTemplate renders a document: a header, anchor-linked navigation and page sections
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{"join": strings.Join}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>{{.Stylesheet}}</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{- if .RootURL}}
+<p class="meta"><strong>Scraped from:</strong> <a href="{{.RootURL}}">{{.RootURL}}</a><br>
+<strong>Generated:</strong> {{.Generated}}<br>
+<strong>Total Pages:</strong> {{.TotalPages}}</p>
+{{- end}}
+</header>
+{{- if .Nav}}
+<nav>
+<ol>
+{{- range .Nav}}
+<li><a href="{{.Href}}">{{.Title}}</a></li>
+{{- end}}
+</ol>
+</nav>
+{{- end}}
+<main>
+{{- range .Pages}}
+<section id="{{.Anchor}}">
+<h2>{{.Title}}</h2>
+<p class="meta"><strong>URL:</strong> <a href="{{.URL}}">{{.URL}}</a>
+{{- if .Tags}}<br><strong>Tags:</strong> {{join .Tags ", "}}{{end}}
+{{- if .Scraped}}<br><strong>Scraped:</strong> {{.Scraped}}{{end}}</p>
+<div class="content">{{.Content}}</div>
+</section>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+// htmlDocument is the data rendered by htmlTemplate
+type htmlDocument struct {
+	Title      string
+	Stylesheet template.CSS
+	RootURL    string
+	Generated  string
+	TotalPages int
+	Nav        []htmlLink
+	Pages      []htmlSection
+}
+
+// htmlLink is a navigation entry
+type htmlLink struct {
+	Title string
+	Href  string
+}
+
+// htmlSection is a page rendered as a section of a document
+type htmlSection struct {
+	Anchor  string
+	Title   string
+	URL     string
+	Tags    []string
+	Scraped string
+	Content string
+}
+
+// generateHTMLOutput writes documentation.html for single output, otherwise one page_NNN.html
+// per page (in depth-N folders for per-depth output) plus an index.html
+func (g *Generator) generateHTMLOutput() error {
+	if g.config.OutputType == "single" {
+		return g.generateSingleHTML()
+	}
+	return g.generatePerPageHTML()
+}
+
+// generateSingleHTML writes all pages into documentation.html with an anchor-linked table of contents
+func (g *Generator) generateSingleHTML() error {
+	anchors := g.htmlAnchors()
+
+	doc := g.htmlDocument("Documentation Scrape Results")
+	for i, page := range g.pages {
+		section, err := g.htmlSection(page, anchors[i])
+		if err != nil {
+			return err
+		}
+		doc.Nav = append(doc.Nav, htmlLink{Title: section.Title, Href: "#" + anchors[i]})
+		doc.Pages = append(doc.Pages, section)
+	}
+
+	return writeHTMLFile(filepath.Join(g.config.OutputDir, "documentation.html"), doc)
+}
+
+// generatePerPageHTML writes each page to its own file and an index.html linking them
+func (g *Generator) generatePerPageHTML() error {
+	index := g.htmlDocument("Documentation Index")
+
+	for i, page := range g.pages {
+		href := g.htmlPageFile(page, i)
+
+		section, err := g.htmlSection(page, "content")
+		if err != nil {
+			return err
+		}
+
+		indexHref, err := filepath.Rel(filepath.Dir(href), "index.html")
+		if err != nil {
+			return err
+		}
+
+		doc := htmlDocument{
+			Title:      section.Title,
+			Stylesheet: template.CSS(htmlStylesheet),
+			Nav:        []htmlLink{{Title: "Documentation Index", Href: filepath.ToSlash(indexHref)}},
+			Pages:      []htmlSection{section},
+		}
+
+		filename := filepath.Join(g.config.OutputDir, href)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := writeHTMLFile(filename, doc); err != nil {
+			return err
+		}
+		g.recordPageFile(page, filename)
+
+		index.Nav = append(index.Nav, htmlLink{Title: section.Title, Href: filepath.ToSlash(href)})
+	}
+
+	return writeHTMLFile(filepath.Join(g.config.OutputDir, "index.html"), index)
+}
+
+// htmlPageFile returns the path of a page's HTML file relative to the output directory
+func (g *Generator) htmlPageFile(page PageData, index int) string {
+	filename := fmt.Sprintf("page_%03d.html", index+1)
+	if g.config.OutputType == "per-depth" {
+		return filepath.Join(depthDirName(page.Depth), filename)
+	}
+	return filename
+}
+
+// htmlDocument returns a document header for the scrape
+func (g *Generator) htmlDocument(title string) htmlDocument {
+	return htmlDocument{
+		Title:      title,
+		Stylesheet: template.CSS(htmlStylesheet),
+		RootURL:    g.config.RootURL,
+		Generated:  generatedAt(g.config),
+		TotalPages: len(g.pages),
+	}
+}
+
+// htmlSection prepares a page for rendering, reading back spilled content
+func (g *Generator) htmlSection(page PageData, anchor string) (htmlSection, error) {
+	content, err := pageContent(page)
+	if err != nil {
+		return htmlSection{}, err
+	}
+
+	section := htmlSection{
+		Anchor:  anchor,
+		Title:   displayTitle(g.config, page.Title),
+		URL:     page.URL,
+		Tags:    page.Tags,
+		Content: content,
+	}
+	if !g.config.GetReproducibleOutput() {
+		section.Scraped = page.Timestamp.Format(time.RFC3339)
+	}
+	return section, nil
+}
+
+// htmlAnchors returns a unique element id per page, derived from its title like the markdown TOC
+func (g *Generator) htmlAnchors() []string {
+	anchors := make([]string, len(g.pages))
+	used := make(map[string]int)
+	for i, page := range g.pages {
+		anchor := g.createAnchor(identityTitle(g.config, page.Title, page.URL))
+		if anchor == "" {
+			anchor = fmt.Sprintf("page-%d", i+1)
+		}
+		if count := used[anchor]; count > 0 {
+			used[anchor]++
+			anchor = fmt.Sprintf("%s-%d", anchor, count+1)
+		} else {
+			used[anchor] = 1
+		}
+		anchors[i] = anchor
+	}
+	return anchors
+}
+
+// writeHTMLFile renders doc to filename
+func writeHTMLFile(filename string, doc htmlDocument) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return htmlTemplate.Execute(file, doc)
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func htmlTestPages() []PageData {
+	return []PageData{
+		{Title: "Tips & <Tricks>", URL: "https://example.com/tips", Content: "Use <script>alert(1)</script> carefully", Timestamp: time.Now(), Depth: 1, Tags: []string{"guide"}},
+		{Title: "Reference", URL: "https://example.com/reference", Content: "Reference content", Timestamp: time.Now(), Depth: 2},
+	}
+}
+
+func TestGenerator_HTMLSingle(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "html",
+		OutputType:   "single",
+	}
+	if err := New(cfg, htmlTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	html, ok := readOutputTree(t, tmpDir)["documentation.html"]
+	if !ok {
+		t.Fatal("documentation.html should be generated")
+	}
+	for _, want := range []string{
+		"<nav>",
+		`<a href="#tips-tricks">Tips &amp; &lt;Tricks&gt;</a>`,
+		`<section id="tips-tricks">`,
+		`<a href="#reference">Reference</a>`,
+		"Use &lt;script&gt;alert(1)&lt;/script&gt; carefully",
+		"<strong>Tags:</strong> guide",
+		"<style>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("documentation.html missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("page content should be escaped")
+	}
+}
+
+func TestGenerator_HTMLPerPage(t *testing.T) {
+	for _, tt := range []struct {
+		outputType string
+		pageFile   string
+		indexHref  string
+	}{
+		{"per-page", "page_002.html", `href="index.html"`},
+		{"per-depth", "depth-2/page_002.html", `href="../index.html"`},
+	} {
+		t.Run(tt.outputType, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				RootURL:      "https://example.com",
+				OutputDir:    tmpDir,
+				OutputFormat: "html",
+				OutputType:   tt.outputType,
+			}
+			if err := New(cfg, htmlTestPages()).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			files := readOutputTree(t, tmpDir)
+			index, ok := files["index.html"]
+			if !ok {
+				t.Fatalf("index.html should be generated, got %v", fileNames(files))
+			}
+			if !strings.Contains(index, "<nav>") || !strings.Contains(index, `href="`+tt.pageFile+`">Reference</a>`) {
+				t.Errorf("index.html should link %s in its nav, got:\n%s", tt.pageFile, index)
+			}
+
+			page, ok := files[tt.pageFile]
+			if !ok {
+				t.Fatalf("%s should be generated, got %v", tt.pageFile, fileNames(files))
+			}
+			if !strings.Contains(page, "<title>Reference</title>") || !strings.Contains(page, tt.indexHref) {
+				t.Errorf("%s should have its title and a link back to the index, got:\n%s", tt.pageFile, page)
+			}
+		})
+	}
+}
+
+func TestGenerator_HTMLAnchorsUnique(t *testing.T) {
+	g := New(&config.Config{}, []PageData{{Title: "Setup"}, {Title: "Setup"}, {Title: "!!!"}})
+	anchors := g.htmlAnchors()
+	if strings.Join(anchors, ",") != "setup,setup-2,page-3" {
+		t.Errorf("htmlAnchors() = %v", anchors)
+	}
+}
@@ -0,0 +1,38 @@
+package output
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"docscraper/config"
+)
+
+// querySlugPattern matches runs of characters dropped from a query slug
+var querySlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// querySlug returns pageURL's query string as lowercase dash-separated words ("id-install")
+// when query_is_identity is set, or "" otherwise
+func querySlug(cfg *config.Config, pageURL string) string {
+	if !cfg.GetQueryIsIdentity() {
+		return ""
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil || u.RawQuery == "" {
+		return ""
+	}
+	query, err := url.QueryUnescape(u.RawQuery)
+	if err != nil {
+		query = u.RawQuery
+	}
+	return strings.Trim(querySlugPattern.ReplaceAllString(strings.ToLower(query), "-"), "-")
+}
+
+// identityTitle returns title with the page's query slug appended, so anchors and filenames
+// derived from it stay distinct for pages that differ only by query string
+func identityTitle(cfg *config.Config, title, pageURL string) string {
+	if slug := querySlug(cfg, pageURL); slug != "" {
+		return title + " " + slug
+	}
+	return title
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// queryTestPages returns two pages served from one path and selected by query string
+func queryTestPages() []PageData {
+	return []PageData{
+		{Title: "Docs", URL: "https://example.com/docs?id=a", Content: "Install content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Docs", URL: "https://example.com/docs?id=b", Content: "Configure content", Timestamp: time.Now(), Depth: 1},
+	}
+}
+
+func TestHierarchicalGenerator_QueryIsIdentity(t *testing.T) {
+	queryIsIdentity := true
+	cfg := &config.Config{
+		RootURL:         "https://example.com",
+		OutputDir:       t.TempDir(),
+		OutputFormat:    "markdown",
+		OutputType:      "per-page",
+		QueryIsIdentity: &queryIsIdentity,
+	}
+
+	if err := NewHierarchical(cfg, queryTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for dir, content := range map[string]string{"docs_id-a": "Install content", "docs_id-b": "Configure content"} {
+		data, err := os.ReadFile(filepath.Join(cfg.OutputDir, dir, "index.md"))
+		if err != nil {
+			t.Errorf("Expected a separate page in %s: %v", dir, err)
+			continue
+		}
+		if !strings.Contains(string(data), content) {
+			t.Errorf("Expected %s/index.md to contain %q, got:\n%s", dir, content, data)
+		}
+	}
+}
+
+func TestGenerator_QueryIsIdentityAnchors(t *testing.T) {
+	queryIsIdentity := true
+	cfg := &config.Config{
+		RootURL:         "https://example.com",
+		OutputDir:       t.TempDir(),
+		OutputFormat:    "markdown",
+		OutputType:      "single",
+		QueryIsIdentity: &queryIsIdentity,
+	}
+
+	if err := New(cfg, queryTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "documentation.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, anchor := range []string{"{#docs-id-a}", "{#docs-id-b}"} {
+		if !strings.Contains(string(data), anchor) {
+			t.Errorf("Expected heading anchor %s, got:\n%s", anchor, data)
+		}
+	}
+}
+
+func TestQuerySlug(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name    string
+		enabled *bool
+		url     string
+		want    string
+	}{
+		{"disabled", &disabled, "https://example.com/docs?id=a", ""},
+		{"unset", nil, "https://example.com/docs?id=a", ""},
+		{"no query", &enabled, "https://example.com/docs", ""},
+		{"single param", &enabled, "https://example.com/docs?id=Install", "id-install"},
+		{"escaped params", &enabled, "https://example.com/docs?id=getting%20started&v=2", "id-getting-started-v-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{QueryIsIdentity: tt.enabled}
+			if got := querySlug(cfg, tt.url); got != tt.want {
+				t.Errorf("querySlug(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
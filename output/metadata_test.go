@@ -0,0 +1,55 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_PageMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:       "https://example.com",
+		OutputDir:     tmpDir,
+		OutputFormat:  "markdown",
+		OutputFormats: []string{"markdown", "json", "text"},
+		OutputType:    "per-page",
+	}
+	metadata := map[string]string{"doc-version": "2.1", "category": "guides"}
+	pages := []PageData{
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide", Timestamp: time.Now(), Metadata: metadata},
+		{Title: "Other", URL: "https://example.com/other", Content: "Other", Timestamp: time.Now()},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+	if !strings.Contains(files["page_001.md"], "**category:** guides  \n**doc-version:** 2.1") {
+		t.Errorf("Page should list its metadata in key order, got:\n%s", files["page_001.md"])
+	}
+	if !strings.Contains(files["metadata.yaml"], "doc-version: \"2.1\"") {
+		t.Errorf("metadata.yaml should include page metadata, got:\n%s", files["metadata.yaml"])
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Pages []PageData `json:"pages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Pages) != 2 || !reflect.DeepEqual(result.Pages[0].Metadata, metadata) || result.Pages[1].Metadata != nil {
+		t.Errorf("JSON page metadata = %+v, want %v on the first page only", result.Pages, metadata)
+	}
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// OutlineNode is one entry of the structure.yaml outline
+type OutlineNode struct {
+	Title    string        `yaml:"title"`
+	URL      string        `yaml:"url"`
+	Children []OutlineNode `yaml:"children,omitempty"`
+}
+
+// generateStructureOutline writes structure.yaml, a nested outline of titles and URLs
+// mirroring the document tree without any content
+func (h *HierarchicalGenerator) generateStructureOutline() error {
+	file, err := os.Create(filepath.Join(h.config.OutputDir, "structure.yaml"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	defer encoder.Close()
+
+	return encoder.Encode(h.outlineChildren(h.tree.Root))
+}
+
+// outlineChildren builds the outline entries for a node's children in reading order
+func (h *HierarchicalGenerator) outlineChildren(node *DocumentNode) []OutlineNode {
+	var outline []OutlineNode
+	for _, child := range h.sortedChildren(node) {
+		outline = append(outline, OutlineNode{
+			Title:    displayTitle(h.config, child.Title),
+			URL:      child.URL,
+			Children: h.outlineChildren(child),
+		})
+	}
+	return outline
+}
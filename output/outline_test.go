@@ -0,0 +1,69 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"docscraper/config"
+)
+
+func TestHierarchicalGenerator_StructureOutline(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	outline := true
+	cfg := &config.Config{
+		RootURL:                  "https://example.com",
+		OutputDir:                tmpDir,
+		OutputFormat:             "markdown",
+		OutputType:               "single",
+		GenerateStructureOutline: &outline,
+	}
+
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "structure.yaml"))
+	if err != nil {
+		t.Fatalf("structure.yaml was not written: %v", err)
+	}
+
+	var nodes []OutlineNode
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		t.Fatalf("structure.yaml does not parse: %v", err)
+	}
+
+	expected := []OutlineNode{
+		{Title: "Docs", URL: "https://example.com/docs", Children: []OutlineNode{
+			{Title: "Alpha", URL: "https://example.com/docs/alpha"},
+			{Title: "Beta", URL: "https://example.com/docs/beta"},
+		}},
+		{Title: "Guide", URL: "https://example.com/guide"},
+	}
+
+	var compare func(path string, got, want []OutlineNode)
+	compare = func(path string, got, want []OutlineNode) {
+		if len(got) != len(want) {
+			t.Fatalf("%s has %d children, want %d: %+v", path, len(got), len(want), got)
+		}
+		for i := range want {
+			if got[i].Title != want[i].Title || got[i].URL != want[i].URL {
+				t.Errorf("%s[%d] = %s (%s), want %s (%s)", path, i, got[i].Title, got[i].URL, want[i].Title, want[i].URL)
+			}
+			compare(path+"/"+want[i].Title, got[i].Children, want[i].Children)
+		}
+	}
+	compare("root", nodes, expected)
+
+	cfg.OutputDir = t.TempDir()
+	outline = false
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fileExists(filepath.Join(cfg.OutputDir, "structure.yaml")) {
+		t.Error("structure.yaml should not be written when generate_structure_outline is off")
+	}
+}
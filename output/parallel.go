@@ -0,0 +1,108 @@
+package output
+
+import (
+	"sync"
+
+	"docscraper/config"
+)
+
+// formatGroup names the files a format writes; formats in one group would overwrite each
+// other's files, so they run one after another. Without format_subdirs, "auto" writes the
+// same page_NNN.md and index.md files as "markdown".
+func formatGroup(cfg *config.Config, format string) string {
+	if format == "auto" && !cfg.GetFormatSubdirs() {
+		return "markdown"
+	}
+	return format
+}
+
+// generateFormats calls generate for every format, running up to format_workers groups at
+// once, and returns the first error. Generators only read the shared pages and tree; each
+// format gets its own generator and config copy.
+func generateFormats(cfg *config.Config, formats []string, generate func(format string) error) error {
+	var groups [][]string
+	groupIndex := make(map[string]int)
+	for _, format := range formats {
+		key := formatGroup(cfg, format)
+		if i, ok := groupIndex[key]; ok {
+			groups[i] = append(groups[i], format)
+			continue
+		}
+		groupIndex[key] = len(groups)
+		groups = append(groups, []string{format})
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+	)
+	workers := make(chan struct{}, cfg.GetFormatWorkers())
+	for _, group := range groups {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(group []string) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			for _, format := range group {
+				if err := generate(format); err != nil {
+					mutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mutex.Unlock()
+					return
+				}
+			}
+		}(group)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// writePages calls write for every page index, running up to page_workers at once, and returns
+// the first error; pages not yet started when a write fails are skipped. Each page is written
+// to its own file, named from its index, so no two writes share a file.
+func writePages(cfg *config.Config, count int, write func(index int) error) error {
+	workers := cfg.GetPageWorkers()
+	if workers > count {
+		workers = count
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+	failed := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := write(i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(failed)
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < count; i++ {
+		select {
+		case indexes <- i:
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,237 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_ParallelFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	workers := 3
+	cfg := &config.Config{
+		RootURL:       "https://example.com",
+		OutputDir:     tmpDir,
+		OutputFormat:  "markdown",
+		OutputFormats: []string{"markdown", "json", "text"},
+		OutputType:    "per-page",
+		FormatWorkers: &workers,
+	}
+
+	var pages []PageData
+	for i := 1; i <= 20; i++ {
+		pages = append(pages, PageData{
+			Title:     fmt.Sprintf("Page %d", i),
+			URL:       fmt.Sprintf("https://example.com/page-%d", i),
+			Content:   fmt.Sprintf("Content of page %d", i),
+			Timestamp: time.Now(),
+			Depth:     1,
+		})
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+	for i := 1; i <= 20; i++ {
+		markdown := files[fmt.Sprintf("page_%03d.md", i)]
+		if !strings.Contains(markdown, fmt.Sprintf("# Page %d\n", i)) || !strings.Contains(markdown, fmt.Sprintf("Content of page %d\n", i)) {
+			t.Errorf("page_%03d.md has unexpected content:\n%s", i, markdown)
+		}
+		text := files[fmt.Sprintf("Page_%d_%d.txt", i, i-1)]
+		if !strings.Contains(text, fmt.Sprintf("TITLE: Page %d\n", i)) {
+			t.Errorf("Page_%d_%d.txt has unexpected content:\n%s", i, i-1, text)
+		}
+	}
+	if !strings.Contains(files["index.md"], "20. [Page 20](page_020.md)") {
+		t.Errorf("index.md should link every page, got:\n%s", files["index.md"])
+	}
+	if !strings.Contains(files["metadata.yaml"], "total_pages: 20") {
+		t.Errorf("metadata.yaml should count every page, got:\n%s", files["metadata.yaml"])
+	}
+
+	var result struct {
+		Pages []PageData `json:"pages"`
+	}
+	if err := json.Unmarshal([]byte(files["documentation.json"]), &result); err != nil {
+		t.Fatalf("documentation.json is invalid: %v", err)
+	}
+	if len(result.Pages) != 20 || result.Pages[19].Title != "Page 20" {
+		t.Errorf("documentation.json should hold every page in order, got %d pages", len(result.Pages))
+	}
+}
+
+func TestGenerateFormats(t *testing.T) {
+	cfg := &config.Config{}
+
+	var (
+		mutex sync.Mutex
+		order []string
+	)
+	boom := errors.New("boom")
+	err := generateFormats(cfg, []string{"markdown", "json", "auto", "text"}, func(format string) error {
+		mutex.Lock()
+		order = append(order, format)
+		mutex.Unlock()
+		if format == "json" {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("generateFormats() error = %v, want %v", err, boom)
+	}
+
+	markdown, auto := -1, -1
+	for i, format := range order {
+		switch format {
+		case "markdown":
+			markdown = i
+		case "auto":
+			auto = i
+		}
+	}
+	if len(order) != 4 || markdown < 0 || auto < markdown {
+		t.Errorf("Expected every format generated with auto after markdown, got %v", order)
+	}
+}
+
+func TestFormatGroup(t *testing.T) {
+	subdirs := true
+	if got := formatGroup(&config.Config{}, "auto"); got != "markdown" {
+		t.Errorf("formatGroup(auto) = %q, want markdown", got)
+	}
+	if got := formatGroup(&config.Config{FormatSubdirs: &subdirs}, "auto"); got != "auto" {
+		t.Errorf("formatGroup(auto) with format_subdirs = %q, want auto", got)
+	}
+	if got := formatGroup(&config.Config{}, "json"); got != "json" {
+		t.Errorf("formatGroup(json) = %q, want json", got)
+	}
+}
+
+// parallelTestPages returns count pages whose titles all sanitize to the same filename
+func parallelTestPages(count int) []PageData {
+	pages := make([]PageData, count)
+	for i := range pages {
+		pages[i] = PageData{
+			Title:     "Getting Started!",
+			URL:       fmt.Sprintf("https://example.com/start-%d", i+1),
+			Content:   fmt.Sprintf("Content of page %d", i+1),
+			Timestamp: time.Now(),
+			Depth:     1 + i%3,
+		}
+	}
+	return pages
+}
+
+func TestGenerator_ParallelPageFiles(t *testing.T) {
+	workers := 8
+	for _, outputType := range []string{"per-page", "per-depth"} {
+		t.Run(outputType, func(t *testing.T) {
+			cfg := &config.Config{
+				RootURL:       "https://example.com",
+				OutputDir:     t.TempDir(),
+				OutputFormats: []string{"markdown", "text"},
+				OutputType:    outputType,
+				PageWorkers:   &workers,
+			}
+			pages := parallelTestPages(300)
+			if err := New(cfg, pages).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			files := readOutputTree(t, cfg.OutputDir)
+			for i := range pages {
+				dir := ""
+				if outputType == "per-depth" {
+					dir = depthDirName(pages[i].Depth) + "/"
+				}
+				content := fmt.Sprintf("Content of page %d\n", i+1)
+				if markdown := files[fmt.Sprintf("%spage_%03d.md", dir, i+1)]; !strings.Contains(markdown, content) {
+					t.Fatalf("%spage_%03d.md has unexpected content:\n%s", dir, i+1, markdown)
+				}
+				if text := files[fmt.Sprintf("%sGetting_Started_%d.txt", dir, i)]; !strings.Contains(text, content) {
+					t.Fatalf("%sGetting_Started_%d.txt has unexpected content:\n%s", dir, i, text)
+				}
+			}
+			if !strings.Contains(files["metadata.yaml"], "total_pages: 300") {
+				t.Errorf("metadata.yaml should be written once every page is, got:\n%s", files["metadata.yaml"])
+			}
+		})
+	}
+}
+
+func TestWritePages(t *testing.T) {
+	workers := 4
+	cfg := &config.Config{PageWorkers: &workers}
+
+	var (
+		mutex   sync.Mutex
+		written = make(map[int]bool)
+	)
+	if err := writePages(cfg, 100, func(i int) error {
+		mutex.Lock()
+		written[i] = true
+		mutex.Unlock()
+		return nil
+	}); err != nil {
+		t.Fatalf("writePages() error = %v", err)
+	}
+	if len(written) != 100 {
+		t.Errorf("Expected every page written once, got %d", len(written))
+	}
+
+	boom := errors.New("boom")
+	var calls int32
+	err := writePages(cfg, 10000, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 0 {
+			return boom
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("writePages() error = %v, want %v", err, boom)
+	}
+	if n := atomic.LoadInt32(&calls); n >= 10000 {
+		t.Errorf("Expected pages after the failure to be skipped, got %d writes", n)
+	}
+}
+
+// benchmarkPerPage generates per-page markdown and text for 2000 pages with page_workers workers
+func benchmarkPerPage(b *testing.B, workers int) {
+	pages := parallelTestPages(2000)
+	for i := range pages {
+		pages[i].Content = strings.Repeat(pages[i].Content+". ", 200)
+	}
+
+	for n := 0; n < b.N; n++ {
+		cfg := &config.Config{
+			RootURL:       "https://example.com",
+			OutputDir:     b.TempDir(),
+			OutputFormats: []string{"markdown", "text"},
+			OutputType:    "per-page",
+			PageWorkers:   &workers,
+		}
+		if err := New(cfg, pages).Generate(); err != nil {
+			b.Fatalf("Generate() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkPerPageOutput_Serial(b *testing.B) {
+	benchmarkPerPage(b, 1)
+}
+
+func BenchmarkPerPageOutput_Parallel(b *testing.B) {
+	benchmarkPerPage(b, 8)
+}
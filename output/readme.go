@@ -0,0 +1,122 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"docscraper/config"
+)
+
+// readmeEntry is a file listed in the README with a description of what it holds
+type readmeEntry struct {
+	File        string
+	Description string
+}
+
+// writeReadme writes README.md at the output root, summarizing the scrape and pointing at
+// the entry file of each generated format
+func writeReadme(cfg *config.Config, pageCount int, layout string, entries []readmeEntry) error {
+	file, err := os.Create(filepath.Join(cfg.OutputDir, "README.md"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fmt.Fprintf(file, "# Documentation Scrape\n\n")
+	fmt.Fprintf(file, "This folder contains documentation scraped from %s.\n\n", cfg.RootURL)
+	fmt.Fprintf(file, "**Source:** %s  \n", cfg.RootURL)
+	fmt.Fprintf(file, "**Generated:** %s  \n", generatedAt(cfg))
+	fmt.Fprintf(file, "**Total Pages:** %d  \n", pageCount)
+	fmt.Fprintf(file, "**Formats:** %s\n\n", strings.Join(cfg.GetOutputFormats(), ", "))
+
+	fmt.Fprintf(file, "## Organization\n\n")
+	fmt.Fprintf(file, "%s\n\n", layout)
+
+	fmt.Fprintf(file, "## Start Here\n\n")
+	for _, entry := range entries {
+		fmt.Fprintf(file, "- [%s](%s) - %s\n", entry.File, entry.File, entry.Description)
+	}
+
+	return nil
+}
+
+// readmeLayout describes how the generated files are organized for an output type
+func readmeLayout(outputType string, hierarchical bool) string {
+	switch {
+	case hierarchical && outputType == "single":
+		return "Pages are written in a single file, nested by their position in the site's URL hierarchy."
+	case hierarchical:
+		return "Each page has its own folder containing an `index.md`, nested by its position in the site's URL hierarchy."
+	case outputType == "per-page":
+		return "Each page is written to its own numbered file, in crawl order."
+	case outputType == "per-depth":
+		return "Pages are grouped into one `depth-N` folder per crawl depth, each with its own index."
+	default:
+		return "All pages are written in crawl order to a single file per format."
+	}
+}
+
+// readmeEntries returns the entry file of each generated format
+func (g *Generator) readmeEntries() []readmeEntry {
+	var entries []readmeEntry
+	for _, format := range g.config.GetOutputFormats() {
+		switch {
+		case format == "markdown" && g.config.OutputType == "single":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, outputName(g.config, "documentation.md")), "all pages in one markdown file"})
+		case format == "markdown", format == "auto":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "index.md"), "index linking every page"})
+		case format == "text" && g.config.OutputType == "single":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, outputName(g.config, "documentation.txt")), "all pages as plain text"})
+		case format == "text":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "metadata.yaml"), "title, URL and depth of every page"})
+		case format == "json":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, outputName(g.config, "documentation.json")), "all pages as JSON"})
+		case format == "html" && g.config.OutputType == "single":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "documentation.html"), "all pages in one HTML file"})
+		case format == "html":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "index.html"), "HTML index linking every page"})
+		case format == "csv":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "documentation.csv"), "title, URL, depth and word count of every page"})
+		case format == "asciidoc" && g.config.OutputType == "single":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "documentation.adoc"), "all pages in one AsciiDoc document"})
+		case format == "asciidoc":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "index.adoc"), "AsciiDoc index linking every page"})
+		case format == "warc":
+			entries = append(entries, readmeEntry{formatLink(g.config, format, "output.warc.gz"), "raw HTTP exchanges as a WARC archive"})
+		}
+	}
+	return uniqueReadmeEntries(entries)
+}
+
+// readmeEntries returns the entry file of each generated format
+func (h *HierarchicalGenerator) readmeEntries() []readmeEntry {
+	var entries []readmeEntry
+	for _, format := range h.config.GetOutputFormats() {
+		switch {
+		case format == "markdown" && h.config.OutputType == "single":
+			entries = append(entries, readmeEntry{formatLink(h.config, format, "documentation_hierarchical.md"), "all pages in one markdown file"})
+		case format == "markdown":
+			entries = append(entries, readmeEntry{formatLink(h.config, format, "index.md"), "index of the page hierarchy"})
+		case format == "text":
+			entries = append(entries, readmeEntry{formatLink(h.config, format, "documentation_hierarchical.txt"), "all pages as plain text"})
+		case format == "json":
+			entries = append(entries, readmeEntry{formatLink(h.config, format, "documentation_hierarchical.json"), "the page hierarchy as JSON"})
+		}
+	}
+	return uniqueReadmeEntries(entries)
+}
+
+// uniqueReadmeEntries drops repeated files, keeping the first description
+func uniqueReadmeEntries(entries []readmeEntry) []readmeEntry {
+	seen := make(map[string]bool)
+	unique := make([]readmeEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !seen[entry.File] {
+			seen[entry.File] = true
+			unique = append(unique, entry)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_Readme(t *testing.T) {
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now()},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{Title: "API", URL: "https://example.com/api", Content: "API content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:       "https://example.com",
+		OutputDir:     tmpDir,
+		OutputFormat:  "markdown",
+		OutputFormats: []string{"markdown", "json"},
+		OutputType:    "per-page",
+	}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	readme, ok := readOutputTree(t, tmpDir)["README.md"]
+	if !ok {
+		t.Fatal("README.md should be generated by default for per-page output")
+	}
+	for _, want := range []string{
+		"**Source:** https://example.com",
+		"**Total Pages:** 3",
+		"**Formats:** markdown, json",
+		"[index.md](index.md)",
+		"[documentation.json](documentation.json)",
+	} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README missing %q, got:\n%s", want, readme)
+		}
+	}
+	if issues := ValidateOutput(tmpDir); len(issues) != 0 {
+		t.Errorf("README links should resolve, got %v", issues)
+	}
+}
+
+func TestHierarchicalGenerator_Readme(t *testing.T) {
+	tmpDir := t.TempDir()
+	hierarchical := true
+	cfg := &config.Config{
+		RootURL:                 "https://example.com",
+		OutputDir:               tmpDir,
+		OutputFormat:            "markdown",
+		OutputType:              "single",
+		UseHierarchicalOrdering: &hierarchical,
+	}
+	if err := NewHierarchical(cfg, hierarchicalTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	readme := readOutputTree(t, tmpDir)["README.md"]
+	if !strings.Contains(readme, "**Total Pages:** 4") || !strings.Contains(readme, "[documentation_hierarchical.md](documentation_hierarchical.md)") {
+		t.Errorf("README should reference the page count and hierarchical file, got:\n%s", readme)
+	}
+}
+
+func TestGenerator_ReadmeDefaults(t *testing.T) {
+	pages := []PageData{{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now()}}
+	disabled := false
+
+	tests := []struct {
+		name       string
+		outputType string
+		setting    *bool
+		want       bool
+	}{
+		{"single defaults off", "single", nil, false},
+		{"per-depth defaults on", "per-depth", nil, true},
+		{"per-page disabled", "per-page", &disabled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				RootURL:        "https://example.com",
+				OutputDir:      tmpDir,
+				OutputFormat:   "markdown",
+				OutputType:     tt.outputType,
+				GenerateReadme: tt.setting,
+			}
+			if err := New(cfg, pages).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if _, got := readOutputTree(t, tmpDir)["README.md"]; got != tt.want {
+				t.Errorf("README.md generated = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
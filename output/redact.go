@@ -0,0 +1,84 @@
+package output
+
+import (
+	"regexp"
+
+	"docscraper/config"
+)
+
+// redactedText replaces every match of a redact_patterns regex
+const redactedText = "[REDACTED]"
+
+// redactors compiles redact_patterns; invalid patterns are rejected by config validation and
+// skipped here
+func redactors(cfg *config.Config) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(cfg.RedactPatterns))
+	for _, pattern := range cfg.RedactPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
+// redact replaces the matches of patterns in content with [REDACTED]
+func redact(patterns []*regexp.Regexp, content string) string {
+	for _, re := range patterns {
+		content = re.ReplaceAllString(content, redactedText)
+	}
+	return content
+}
+
+// redactPages returns pages with their content redacted. A spilled page whose content changes
+// is kept in memory, so its spill file never reaches the output unredacted.
+func redactPages(cfg *config.Config, pages []PageData) []PageData {
+	patterns := redactors(cfg)
+	if len(patterns) == 0 {
+		return pages
+	}
+
+	redacted := make([]PageData, len(pages))
+	for i, page := range pages {
+		redacted[i] = page
+		content, err := pageContent(page)
+		if err != nil {
+			continue // Reported when the page is written
+		}
+		if masked := redact(patterns, content); masked != content {
+			redacted[i].Content, redacted[i].ContentFile = masked, ""
+		}
+	}
+	return redacted
+}
+
+// redactTree redacts the content of every node of a tree
+func redactTree(cfg *config.Config, tree *DocumentTree) {
+	patterns := redactors(cfg)
+	if len(patterns) == 0 {
+		return
+	}
+	for _, node := range tree.GetAllNodes() {
+		node.Content = redact(patterns, node.Content)
+	}
+}
+
+// redactResponses returns WARC responses with their bodies redacted, dropping the then stale
+// Content-Length of a changed body
+func redactResponses(cfg *config.Config, responses []WARCResponse) []WARCResponse {
+	patterns := redactors(cfg)
+	if len(patterns) == 0 {
+		return responses
+	}
+
+	redacted := make([]WARCResponse, len(responses))
+	for i, response := range responses {
+		redacted[i] = response
+		body := string(response.Body)
+		if masked := redact(patterns, body); masked != body {
+			redacted[i].Body = []byte(masked)
+			redacted[i].ResponseHeaders = response.ResponseHeaders.Clone()
+			redacted[i].ResponseHeaders.Del("Content-Length")
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,157 @@
+package output
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+	"docscraper/scraper"
+)
+
+// redactTestSecret is an API key left in a scraped example
+const redactTestSecret = "sk-live-4f9a8b7c6d5e4f3a2b1c"
+
+// redactTestPages returns pages whose examples contain redactTestSecret, one of them spilled
+func redactTestPages(t *testing.T) []PageData {
+	t.Helper()
+
+	spilled := filepath.Join(t.TempDir(), "page.txt")
+	if err := os.WriteFile(spilled, []byte("Set the header:\n\n```\nAuthorization: Bearer "+redactTestSecret+"\n```"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return []PageData{
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Export the key: `API_KEY=" + redactTestSecret + "` then run the CLI.", Timestamp: time.Now(), Depth: 1},
+		{Title: "Auth", URL: "https://example.com/guide/auth", ContentFile: spilled, Timestamp: time.Now(), Depth: 2},
+	}
+}
+
+// assertRedacted fails when any output file contains the secret, or, for output that includes
+// page content, when none contains [REDACTED]
+func assertRedacted(t *testing.T, files map[string]string, withContent bool) {
+	t.Helper()
+
+	masked := false
+	for name, content := range files {
+		if strings.Contains(content, redactTestSecret) {
+			t.Errorf("%s contains the unredacted secret:\n%s", name, content)
+		}
+		masked = masked || strings.Contains(content, redactedText)
+	}
+	if withContent && !masked {
+		t.Errorf("Expected [REDACTED] in the output")
+	}
+}
+
+func TestGenerator_RedactPatterns(t *testing.T) {
+	patterns := []string{`sk-live-[A-Za-z0-9]+`}
+
+	for _, format := range []string{"markdown", "text", "json", "html", "csv", "asciidoc"} {
+		for _, outputType := range []string{"single", "per-page", "per-depth"} {
+			t.Run(format+"/"+outputType, func(t *testing.T) {
+				cfg := &config.Config{
+					RootURL:        "https://example.com",
+					OutputDir:      t.TempDir(),
+					OutputFormat:   format,
+					OutputType:     outputType,
+					RedactPatterns: patterns,
+				}
+				if err := New(cfg, redactTestPages(t)).Generate(); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				assertRedacted(t, readOutputTree(t, cfg.OutputDir), format != "csv") // CSV rows carry no content
+			})
+		}
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		cfg := &config.Config{
+			RootURL:        "https://example.com",
+			OutputDir:      t.TempDir(),
+			OutputFormats:  []string{"markdown", "json"},
+			OutputType:     "per-page",
+			RedactPatterns: patterns,
+		}
+		pages := make(chan PageData)
+		go func() {
+			defer close(pages)
+			for _, page := range redactTestPages(t) {
+				pages <- page
+			}
+		}()
+		if err := New(cfg, nil).GenerateStream(pages); err != nil {
+			t.Fatalf("GenerateStream() error = %v", err)
+		}
+		assertRedacted(t, readOutputTree(t, cfg.OutputDir), true)
+	})
+
+	t.Run("hierarchical", func(t *testing.T) {
+		hierarchical := true
+		cfg := &config.Config{
+			RootURL:                 "https://example.com",
+			OutputDir:               t.TempDir(),
+			OutputFormats:           []string{"markdown", "json"},
+			OutputType:              "per-page",
+			UseHierarchicalOrdering: &hierarchical,
+			RedactPatterns:          patterns,
+		}
+		if err := NewHierarchical(cfg, redactTestPages(t)).Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		assertRedacted(t, readOutputTree(t, cfg.OutputDir), true)
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		contents := map[string]scraper.ScrapedContent{
+			"https://example.com/": {URL: "https://example.com/", Title: "Home", Content: "Token: " + redactTestSecret},
+		}
+		tree := scraper.NewTreeBuilder(scraper.TreeConfig{}).BuildTree([]string{"https://example.com/"}, contents)
+		cfg := &config.Config{
+			RootURL:        "https://example.com",
+			OutputDir:      t.TempDir(),
+			OutputFormat:   "markdown",
+			OutputType:     "single",
+			RedactPatterns: patterns,
+		}
+		if err := NewHierarchicalFromTree(cfg, tree).Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		assertRedacted(t, readOutputTree(t, cfg.OutputDir), true)
+	})
+
+	t.Run("warc", func(t *testing.T) {
+		cfg := &config.Config{
+			RootURL:        "https://example.com",
+			OutputDir:      t.TempDir(),
+			OutputFormat:   "warc",
+			OutputType:     "single",
+			RedactPatterns: patterns,
+		}
+		generator := New(cfg, redactTestPages(t))
+		body := "<pre>API_KEY=" + redactTestSecret + "</pre>"
+		generator.SetResponses([]WARCResponse{{
+			URL:             "https://example.com/guide",
+			Method:          "GET",
+			StatusCode:      200,
+			ResponseHeaders: http.Header{"Content-Length": {"40"}},
+			Body:            []byte(body),
+			FetchedAt:       time.Now(),
+		}})
+		if err := generator.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		records := readWARCRecords(t, filepath.Join(cfg.OutputDir, "output.warc.gz"))
+		files := make(map[string]string)
+		for _, record := range records {
+			files[record.Headers.Get("WARC-Record-ID")] = string(record.Block)
+			if strings.Contains(string(record.Block), "Content-Length: 40") {
+				t.Errorf("Expected the stale Content-Length to be dropped from the redacted response")
+			}
+		}
+		assertRedacted(t, files, true)
+	})
+}
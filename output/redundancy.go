@@ -0,0 +1,98 @@
+package output
+
+import (
+	"strings"
+	"unicode"
+)
+
+// redundancyShingleSize is the number of consecutive words compared when measuring containment
+const redundancyShingleSize = 3
+
+// detectRedundantParents flags nodes whose content is largely contained in their children's content
+func (h *HierarchicalGenerator) detectRedundantParents() error {
+	h.redundant = make(map[*DocumentNode]bool)
+	threshold := h.config.GetRedundancyThreshold()
+
+	for _, node := range h.tree.GetAllNodes() {
+		if len(node.Children) == 0 {
+			continue
+		}
+		content, err := node.loadContent()
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		childContents := make([]string, len(node.Children))
+		for i, child := range node.Children {
+			if childContents[i], err = child.loadContent(); err != nil {
+				return err
+			}
+		}
+
+		if containmentRatio(content, childContents) >= threshold {
+			h.redundant[node] = true
+		}
+	}
+	return nil
+}
+
+// IsRedundant reports whether the node at url was flagged as duplicating its children
+func (h *HierarchicalGenerator) IsRedundant(url string) bool {
+	node, ok := h.tree.NodeMap[url]
+	return ok && h.redundant[node]
+}
+
+// nodeContent returns the content written for a node, omitting redundant parents when configured
+func (h *HierarchicalGenerator) nodeContent(node *DocumentNode) (string, error) {
+	if h.redundant[node] && h.config.GetExcludeRedundantParents() {
+		return "", nil
+	}
+	return node.loadContent()
+}
+
+// containmentRatio returns the fraction of content's word shingles found in the union of others
+func containmentRatio(content string, others []string) float64 {
+	shingles := wordShingles(content)
+	if len(shingles) == 0 {
+		return 0
+	}
+
+	union := make(map[string]bool)
+	for _, other := range others {
+		for shingle := range wordShingles(other) {
+			union[shingle] = true
+		}
+	}
+
+	contained := 0
+	for shingle := range shingles {
+		if union[shingle] {
+			contained++
+		}
+	}
+
+	return float64(contained) / float64(len(shingles))
+}
+
+// wordShingles returns the set of normalized word n-grams in text
+func wordShingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	shingles := make(map[string]bool)
+	if len(words) < redundancyShingleSize {
+		if len(words) > 0 {
+			shingles[strings.Join(words, " ")] = true
+		}
+		return shingles
+	}
+
+	for i := 0; i+redundancyShingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+redundancyShingleSize], " ")] = true
+	}
+	return shingles
+}
@@ -0,0 +1,163 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// redundancyTestPages returns an overview page that only concatenates its children's intros
+func redundancyTestPages() []PageData {
+	return []PageData{
+		{
+			Title:     "Guides",
+			URL:       "https://example.com/guides",
+			Content:   "Install the CLI with the package manager. Configure your project using a YAML file.",
+			Timestamp: time.Now(),
+			Depth:     1,
+		},
+		{
+			Title:     "Install",
+			URL:       "https://example.com/guides/install",
+			Content:   "Install the CLI with the package manager. Then verify the version it reports.",
+			Timestamp: time.Now(),
+			Depth:     2,
+		},
+		{
+			Title:     "Configure",
+			URL:       "https://example.com/guides/configure",
+			Content:   "Configure your project using a YAML file. Every option has a sensible default.",
+			Timestamp: time.Now(),
+			Depth:     2,
+		},
+		{
+			Title:     "Reference",
+			URL:       "https://example.com/reference",
+			Content:   "The reference lists every command in detail.",
+			Timestamp: time.Now(),
+			Depth:     1,
+		},
+		{
+			Title:     "Commands",
+			URL:       "https://example.com/reference/commands",
+			Content:   "Each command accepts global flags.",
+			Timestamp: time.Now(),
+			Depth:     2,
+		},
+	}
+}
+
+func TestContainmentRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		others   []string
+		expected float64
+	}{
+		{"fully contained", "alpha beta gamma delta", []string{"x alpha beta gamma", "beta gamma delta y"}, 1},
+		{"disjoint", "alpha beta gamma", []string{"one two three"}, 0},
+		{"half contained", "a b c d e f", []string{"a b c d"}, 0.5},
+		{"empty content", "", []string{"anything"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := containmentRatio(tt.content, tt.others); result != tt.expected {
+				t.Errorf("containmentRatio() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHierarchicalGenerator_RedundantParents(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "json",
+		OutputType:   "single",
+	}
+
+	generator := NewHierarchical(cfg, redundancyTestPages())
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !generator.IsRedundant("https://example.com/guides") {
+		t.Error("Expected overview page duplicating its children to be flagged redundant")
+	}
+	if generator.IsRedundant("https://example.com/reference") {
+		t.Error("Expected parent with distinct content not to be flagged redundant")
+	}
+	if generator.IsRedundant("https://example.com/guides/install") {
+		t.Error("Expected leaf page not to be flagged redundant")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation_hierarchical.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	children := result["hierarchy"].(map[string]interface{})["children"].([]interface{})
+	for _, child := range children {
+		node := child.(map[string]interface{})
+		if node["title"] == "Guides" {
+			if node["redundant"] != true {
+				t.Error("Expected JSON output to flag the redundant parent")
+			}
+			if node["content"] == "" {
+				t.Error("Redundant parent content should be kept unless exclusion is enabled")
+			}
+		}
+	}
+}
+
+func TestHierarchicalGenerator_ExcludeRedundantParents(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	exclude := true
+	cfg := &config.Config{
+		RootURL:                 "https://example.com",
+		OutputDir:               tmpDir,
+		OutputFormat:            "markdown",
+		OutputType:              "single",
+		ExcludeRedundantParents: &exclude,
+	}
+
+	if err := NewHierarchical(cfg, redundancyTestPages()).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation_hierarchical.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	if strings.Contains(content, "Install the CLI with the package manager. Configure your project") {
+		t.Error("Redundant parent content should be excluded from output")
+	}
+	if !strings.Contains(content, "Then verify the version it reports.") {
+		t.Error("Child content should still be written")
+	}
+	if !strings.Contains(content, "The reference lists every command in detail.") {
+		t.Error("Non-redundant parent content should still be written")
+	}
+}
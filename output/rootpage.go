@@ -0,0 +1,44 @@
+package output
+
+import (
+	"net/url"
+	"strings"
+
+	"docscraper/config"
+)
+
+// outputPages drops the root page when skip_root_in_output is set, as it was still crawled for
+// links, and masks redact_patterns matches in the content of the rest
+func outputPages(cfg *config.Config, pages []PageData) []PageData {
+	if !cfg.GetSkipRootInOutput() {
+		return redactPages(cfg, pages)
+	}
+
+	kept := make([]PageData, 0, len(pages))
+	for _, page := range pages {
+		if !isSkippedRootPage(cfg, page) {
+			kept = append(kept, page)
+		}
+	}
+	return redactPages(cfg, kept)
+}
+
+// isSkippedRootPage reports whether page is the root page left out under skip_root_in_output
+func isSkippedRootPage(cfg *config.Config, page PageData) bool {
+	return cfg.GetSkipRootInOutput() && normalizePageURL(page.URL) == normalizePageURL(cfg.RootURL)
+}
+
+// normalizePageURL lowercases the scheme and host and drops the fragment and trailing slash,
+// so equivalent spellings of a page URL compare equal
+func normalizePageURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
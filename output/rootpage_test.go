@@ -0,0 +1,76 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestNormalizePageURL(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"https://example.com", "https://example.com/", true},
+		{"https://Example.com/docs/", "https://example.com/docs#intro", true},
+		{"https://example.com/docs", "https://example.com/docs/guide", false},
+		{"https://example.com/?page=1", "https://example.com/", false},
+	}
+
+	for _, tt := range tests {
+		if got := normalizePageURL(tt.a) == normalizePageURL(tt.b); got != tt.equal {
+			t.Errorf("normalizePageURL(%q) == normalizePageURL(%q) is %v, want %v", tt.a, tt.b, got, tt.equal)
+		}
+	}
+}
+
+func TestGenerator_SkipRootInOutput(t *testing.T) {
+	skip := true
+	pages := []PageData{
+		{Title: "Landing", URL: "https://example.com/", Content: "Landing content", Timestamp: time.Now()},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Docs", URL: "https://example.com/docs", Content: "Docs content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Install", URL: "https://example.com/docs/install", Content: "Install content", Timestamp: time.Now(), Depth: 2},
+	}
+
+	for _, hierarchical := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		cfg := &config.Config{
+			RootURL:          "https://example.com",
+			OutputDir:        tmpDir,
+			OutputFormat:     "markdown",
+			OutputType:       "single",
+			SkipRootInOutput: &skip,
+		}
+
+		var err error
+		if hierarchical {
+			err = NewHierarchical(cfg, pages).Generate()
+		} else {
+			err = New(cfg, pages).Generate()
+		}
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		var output strings.Builder
+		for _, content := range readOutputTree(t, tmpDir) {
+			output.WriteString(content)
+		}
+		if strings.Contains(output.String(), "Landing") {
+			t.Errorf("hierarchical=%v: root page should be excluded from output", hierarchical)
+		}
+		for _, want := range []string{"Guide content", "Docs content", "Install content"} {
+			if !strings.Contains(output.String(), want) {
+				t.Errorf("hierarchical=%v: output missing child page %q", hierarchical, want)
+			}
+		}
+	}
+
+	generator := New(&config.Config{RootURL: "https://example.com"}, pages)
+	if len(generator.pages) != len(pages) {
+		t.Errorf("Root page should be kept by default, got %d of %d pages", len(generator.pages), len(pages))
+	}
+}
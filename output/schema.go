@@ -0,0 +1,153 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDraft identifies the JSON Schema dialect of generated schemas
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// DocumentationSchema returns a JSON Schema describing documentation.json, whose pages are a
+// flat array, and documentation_hierarchical.json, whose pages are a nested hierarchy. The page
+// and stats definitions are derived by reflection so they stay in sync with the output.
+func DocumentationSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":  jsonSchemaDraft,
+		"title":    "Documentation Scrape Results",
+		"type":     "object",
+		"required": []string{"root_url", "scraped_at", "total_pages", "stats"},
+		"oneOf": []interface{}{
+			map[string]interface{}{"required": []string{"pages"}},
+			map[string]interface{}{"required": []string{"hierarchy"}},
+		},
+		"properties": map[string]interface{}{
+			"root_url":    map[string]interface{}{"type": "string"},
+			"scraped_at":  map[string]interface{}{"type": "string", "format": "date-time"},
+			"total_pages": map[string]interface{}{"type": "integer", "minimum": 0},
+			"stats":       schemaForType(reflect.TypeOf(DocumentationStats{})),
+			"pages": map[string]interface{}{
+				"type":  "array",
+				"items": schemaForType(reflect.TypeOf(PageData{})),
+			},
+			"hierarchy": map[string]interface{}{"$ref": "#/definitions/node"},
+		},
+		"definitions": map[string]interface{}{
+			"node": hierarchyNodeSchema(),
+		},
+	}
+}
+
+// hierarchyNodeSchema describes a node of documentation_hierarchical.json as nodeToJSON writes it
+func hierarchyNodeSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"url", "path", "title", "content", "timestamp", "depth", "level", "index", "children"},
+		"properties": map[string]interface{}{
+			"url":       map[string]interface{}{"type": "string"},
+			"path":      map[string]interface{}{"type": "string"},
+			"title":     map[string]interface{}{"type": "string"},
+			"content":   map[string]interface{}{"type": "string"},
+			"timestamp": map[string]interface{}{"type": "string", "format": "date-time"},
+			"depth":     map[string]interface{}{"type": "integer"},
+			"level":     map[string]interface{}{"type": "integer"},
+			"index":     map[string]interface{}{"type": "integer"},
+			"redundant": map[string]interface{}{"type": "boolean"},
+			"tags":      schemaForType(reflect.TypeOf([]string{})),
+			"metadata":  schemaForType(reflect.TypeOf(map[string]string{})),
+			"children": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": "#/definitions/node"},
+			},
+		},
+	}
+}
+
+// writeJSONSchema writes documentation.schema.json next to the JSON output in dir
+func writeJSONSchema(dir string) error {
+	file, err := os.Create(filepath.Join(dir, "documentation.schema.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(DocumentationSchema())
+}
+
+// schemaForType builds a JSON Schema fragment for a Go type
+func schemaForType(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+
+			name, omitEmpty := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+
+			properties[name] = schemaForType(field.Type)
+			if !omitEmpty {
+				required = append(required, name)
+			}
+		}
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field and whether it is omitempty
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty
+}
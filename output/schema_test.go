@@ -0,0 +1,209 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// validateAgainstSchema checks value against the subset of JSON Schema emitted by
+// DocumentationSchema: type, required, properties, items, additionalProperties and $ref into
+// root's definitions
+func validateAgainstSchema(value interface{}, schema, root map[string]interface{}, path string) []string {
+	var problems []string
+
+	if ref, ok := schema["$ref"].(string); ok {
+		definitions, _ := root["definitions"].(map[string]interface{})
+		target, ok := definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: unresolved $ref %s", path, ref))
+		}
+		schema = target
+	}
+
+	if expected, ok := schema["type"].(string); ok {
+		valid := false
+		switch v := value.(type) {
+		case string:
+			valid = expected == "string"
+		case bool:
+			valid = expected == "boolean"
+		case float64:
+			valid = expected == "number" || (expected == "integer" && v == float64(int64(v)))
+		case []interface{}:
+			valid = expected == "array"
+		case map[string]interface{}:
+			valid = expected == "object"
+		}
+		if !valid {
+			return append(problems, fmt.Sprintf("%s: expected %s, got %T", path, expected, value))
+		}
+	}
+
+	if obj, ok := value.(map[string]interface{}); ok {
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, exists := obj[name]; !exists {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, child := range obj {
+			if propSchema, ok := properties[name].(map[string]interface{}); ok {
+				problems = append(problems, validateAgainstSchema(child, propSchema, root, path+"."+name)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				problems = append(problems, validateAgainstSchema(child, additional, root, path+"."+name)...)
+			}
+		}
+	}
+
+	if arr, ok := value.([]interface{}); ok {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, child := range arr {
+				problems = append(problems, validateAgainstSchema(child, items, root, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return problems
+}
+
+// schemaStrings converts a decoded JSON string array into a []string
+func schemaStrings(value interface{}) []string {
+	var result []string
+	if arr, ok := value.([]interface{}); ok {
+		for _, item := range arr {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+func readJSONFile(t *testing.T, filename string) interface{} {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", filename, err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("Failed to parse %s: %v", filename, err)
+	}
+	return value
+}
+
+func TestGenerator_GenerateJSONSchema(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	schemaEnabled := true
+	cfg := &config.Config{
+		RootURL:            "https://example.com",
+		OutputDir:          tmpDir,
+		OutputFormat:       "json",
+		OutputType:         "single",
+		GenerateJSONSchema: &schemaEnabled,
+	}
+
+	pages := []PageData{
+		{Title: "Page 1", URL: "https://example.com/1", Content: "One", Timestamp: time.Now(), Depth: 0},
+		{Title: "Page 2", URL: "https://example.com/2", Content: "Two", Timestamp: time.Now(), Depth: 1},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	schema, ok := readJSONFile(t, filepath.Join(tmpDir, "documentation.schema.json")).(map[string]interface{})
+	if !ok {
+		t.Fatal("Schema should be a JSON object")
+	}
+	if schema["$schema"] != jsonSchemaDraft {
+		t.Errorf("Schema $schema = %v, want %s", schema["$schema"], jsonSchemaDraft)
+	}
+
+	document := readJSONFile(t, filepath.Join(tmpDir, "documentation.json"))
+	if problems := validateAgainstSchema(document, schema, schema, "$"); len(problems) > 0 {
+		t.Errorf("documentation.json does not match schema: %v", problems)
+	}
+
+	// A document with a mistyped field must fail validation
+	var broken interface{}
+	json.Unmarshal([]byte(`{"root_url": "x", "scraped_at": "y", "total_pages": "two", "stats": {"max_depth": 1, "total_tokens": 2}, "pages": []}`), &broken)
+	if problems := validateAgainstSchema(broken, schema, schema, "$"); len(problems) == 0 {
+		t.Error("Expected schema validation to reject a string total_pages")
+	}
+}
+
+func TestDocumentationSchema_PageFields(t *testing.T) {
+	schema := DocumentationSchema()
+	pages := schema["properties"].(map[string]interface{})["pages"].(map[string]interface{})
+	items := pages["items"].(map[string]interface{})
+	properties := items["properties"].(map[string]interface{})
+
+	for _, field := range []string{"title", "url", "content", "timestamp", "depth"} {
+		if _, ok := properties[field]; !ok {
+			t.Errorf("Page schema missing field %q", field)
+		}
+	}
+}
+
+func TestHierarchicalGenerator_GenerateJSONSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	schemaEnabled := true
+	hierarchical := true
+	cfg := &config.Config{
+		RootURL:                 "https://example.com",
+		OutputDir:               tmpDir,
+		OutputFormat:            "json",
+		OutputType:              "single",
+		GenerateJSONSchema:      &schemaEnabled,
+		UseHierarchicalOrdering: &hierarchical,
+	}
+
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home page", Timestamp: time.Now(), Depth: 1},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide page", Timestamp: time.Now(), Depth: 2, Tags: []string{"guide"}},
+		{Title: "Setup", URL: "https://example.com/guide/setup", Content: "Setup page", Timestamp: time.Now(), Depth: 3},
+	}
+
+	if err := NewHierarchical(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	schema, ok := readJSONFile(t, filepath.Join(tmpDir, "documentation.schema.json")).(map[string]interface{})
+	if !ok {
+		t.Fatal("Schema should be a JSON object")
+	}
+
+	document := readJSONFile(t, filepath.Join(tmpDir, "documentation_hierarchical.json"))
+	if problems := validateAgainstSchema(document, schema, schema, "$"); len(problems) > 0 {
+		t.Errorf("documentation_hierarchical.json does not match schema: %v", problems)
+	}
+
+	stats := document.(map[string]interface{})["stats"].(map[string]interface{})
+	if stats["max_depth"] != float64(3) || stats["total_tokens"].(float64) <= 0 {
+		t.Errorf("Expected stats with max_depth 3 and a token total, got %v", stats)
+	}
+
+	// A nested node with a mistyped field must fail validation through the $ref
+	var broken interface{}
+	json.Unmarshal([]byte(`{"root_url": "x", "scraped_at": "y", "total_pages": 1, "stats": {"max_depth": 1, "total_tokens": 2},
+		"hierarchy": {"url": "u", "path": "/", "title": "t", "content": "", "timestamp": "z", "depth": 1, "level": 0, "index": 0,
+			"children": [{"url": "u", "path": "/a", "title": "a", "content": "", "timestamp": "z", "depth": "deep", "level": 1, "index": 0, "children": []}]}}`), &broken)
+	if problems := validateAgainstSchema(broken, schema, schema, "$"); len(problems) == 0 {
+		t.Error("Expected schema validation to reject a nested node with a string depth")
+	}
+}
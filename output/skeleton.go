@@ -0,0 +1,105 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// skeletonHeading is one heading of a page's outline in skeleton.md
+type skeletonHeading struct {
+	Level int
+	Text  string
+}
+
+// pageHeadings returns the markdown headings of content in order, skipping fenced code blocks
+func pageHeadings(content string) []skeletonHeading {
+	var headings []skeletonHeading
+	lines := strings.Split(content, "\n")
+	headingLines(lines, func(i, level int) {
+		match := markdownHeadingPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			return
+		}
+		if text, _ := splitHeadingID(match[2]); text != "" {
+			headings = append(headings, skeletonHeading{Level: level, Text: text})
+		}
+	})
+	return headings
+}
+
+// writeSkeletonPage writes a page's list entry and its heading outline, nested below the
+// page by heading level
+func writeSkeletonPage(w io.Writer, indent, title, url string, headings []skeletonHeading) {
+	fmt.Fprintf(w, "%s- [%s](%s)\n", indent, title, url)
+
+	top := 0
+	for _, heading := range headings {
+		if top == 0 || heading.Level < top {
+			top = heading.Level
+		}
+	}
+	for _, heading := range headings {
+		fmt.Fprintf(w, "%s  %s- %s\n", indent, strings.Repeat("  ", heading.Level-top), heading.Text)
+	}
+}
+
+// createSkeleton creates skeleton.md with its header
+func createSkeleton(outputDir, rootURL string, total int) (*os.File, error) {
+	file, err := os.Create(filepath.Join(outputDir, "skeleton.md"))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(file, "# Documentation Skeleton\n\n")
+	fmt.Fprintf(file, "**Scraped from:** %s  \n", rootURL)
+	fmt.Fprintf(file, "**Total Pages:** %d\n\n", total)
+	return file, nil
+}
+
+// generateSkeleton writes skeleton.md, listing every page's title and heading outline without content
+func (g *Generator) generateSkeleton() error {
+	file, err := createSkeleton(g.config.OutputDir, g.config.RootURL, len(g.pages))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	for _, page := range g.pages {
+		content, err := pageContent(page)
+		if err != nil {
+			return err
+		}
+		writeSkeletonPage(file, "", displayTitle(g.config, page.Title), page.URL, pageHeadings(content))
+	}
+	return nil
+}
+
+// generateSkeleton writes skeleton.md, nesting each page's title and heading outline under
+// its parent in the document tree
+func (h *HierarchicalGenerator) generateSkeleton() error {
+	file, err := createSkeleton(h.config.OutputDir, h.config.RootURL, len(h.tree.GetAllNodes()))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return h.writeSkeletonChildren(file, h.tree.Root, "")
+}
+
+// writeSkeletonChildren writes the skeleton entries for a node's children in reading order
+func (h *HierarchicalGenerator) writeSkeletonChildren(w io.Writer, node *DocumentNode, indent string) error {
+	for _, child := range h.sortedChildren(node) {
+		content, err := child.loadContent()
+		if err != nil {
+			return err
+		}
+		writeSkeletonPage(w, indent, displayTitle(h.config, child.Title), child.URL, pageHeadings(content))
+		if err := h.writeSkeletonChildren(w, child, indent+"  "); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func skeletonTestPages() []PageData {
+	return []PageData{
+		{
+			Title:     "Guide",
+			URL:       "https://example.com/guide",
+			Content:   "# Guide\n\nGuide body paragraph.\n\n## Install {#install}\n\nInstall body paragraph.\n\n```sh\n# not a heading\n```\n\n### Linux\n\nLinux body paragraph.",
+			Timestamp: time.Now(),
+			Depth:     1,
+		},
+		{
+			Title:     "Setup",
+			URL:       "https://example.com/guide/setup",
+			Content:   "## Requirements\n\nSetup body paragraph.",
+			Timestamp: time.Now(),
+			Depth:     2,
+		},
+	}
+}
+
+func TestPageHeadings(t *testing.T) {
+	got := pageHeadings(skeletonTestPages()[0].Content)
+	want := []skeletonHeading{{Level: 1, Text: "Guide"}, {Level: 2, Text: "Install"}, {Level: 3, Text: "Linux"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pageHeadings() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerator_SkeletonOnly(t *testing.T) {
+	skeletonOnly := true
+	tests := []struct {
+		name     string
+		generate func(cfg *config.Config) error
+		want     string
+	}{
+		{
+			name:     "flat",
+			generate: func(cfg *config.Config) error { return New(cfg, skeletonTestPages()).Generate() },
+			want: "- [Guide](https://example.com/guide)\n  - Guide\n    - Install\n      - Linux\n" +
+				"- [Setup](https://example.com/guide/setup)\n  - Requirements\n",
+		},
+		{
+			name:     "hierarchical",
+			generate: func(cfg *config.Config) error { return NewHierarchical(cfg, skeletonTestPages()).Generate() },
+			want: "- [Guide](https://example.com/guide)\n  - Guide\n    - Install\n      - Linux\n" +
+				"  - [Setup](https://example.com/guide/setup)\n    - Requirements\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &config.Config{
+				RootURL:      "https://example.com",
+				OutputDir:    tmpDir,
+				OutputFormat: "markdown",
+				OutputType:   "per-page",
+				SkeletonOnly: &skeletonOnly,
+			}
+			if err := tt.generate(cfg); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			files := readOutputTree(t, tmpDir)
+			if len(files) != 1 {
+				t.Errorf("Expected only skeleton.md, got %v", fileNames(files))
+			}
+			skeleton := files["skeleton.md"]
+			if !strings.Contains(skeleton, tt.want) {
+				t.Errorf("skeleton.md should contain:\n%s\ngot:\n%s", tt.want, skeleton)
+			}
+			if strings.Contains(skeleton, "body paragraph") || strings.Contains(skeleton, "not a heading") {
+				t.Errorf("skeleton.md should not contain page content, got:\n%s", skeleton)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// pageContent returns the page content, reading it back from disk when it was spilled
+func pageContent(page PageData) (string, error) {
+	if page.ContentFile == "" {
+		return page.Content, nil
+	}
+
+	data, err := os.ReadFile(page.ContentFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read spilled content for %s: %v", page.URL, err)
+	}
+	return string(data), nil
+}
+
+// writePageContent writes the page content to w, streaming spilled content from its file
+func writePageContent(w io.Writer, page PageData) error {
+	if page.ContentFile == "" {
+		_, err := io.WriteString(w, page.Content)
+		return err
+	}
+
+	file, err := os.Open(page.ContentFile)
+	if err != nil {
+		return fmt.Errorf("failed to read spilled content for %s: %v", page.URL, err)
+	}
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	return err
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_SpilledContent(t *testing.T) {
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Welcome home", Timestamp: time.Now(), Depth: 1, TokenEstimate: 3},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Read the guide", Timestamp: time.Now(), Depth: 2, TokenEstimate: 4},
+	}
+
+	spillDir := t.TempDir()
+	spilled := make([]PageData, len(pages))
+	for i, page := range pages {
+		page.ContentFile = filepath.Join(spillDir, fmt.Sprintf("page_%d.txt", i))
+		if err := os.WriteFile(page.ContentFile, []byte(page.Content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		page.Content = ""
+		spilled[i] = page
+	}
+
+	reproducible := true
+	for _, outputType := range []string{"single", "per-page", "per-depth"} {
+		t.Run(outputType, func(t *testing.T) {
+			generate := func(pages []PageData) map[string]string {
+				dir := t.TempDir()
+				cfg := &config.Config{
+					RootURL:            "https://example.com",
+					OutputDir:          dir,
+					OutputFormat:       "markdown",
+					OutputFormats:      []string{"markdown", "text", "json"},
+					OutputType:         outputType,
+					ReproducibleOutput: &reproducible,
+				}
+				if err := New(cfg, pages).Generate(); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				return readOutputTree(t, dir)
+			}
+
+			expected := generate(pages)
+			actual := generate(spilled)
+			if len(actual) != len(expected) {
+				t.Fatalf("spilled output has %d files, want %d", len(actual), len(expected))
+			}
+			for name, content := range expected {
+				if actual[name] != content {
+					t.Errorf("%s differs under spill mode:\n%s\nwant:\n%s", name, actual[name], content)
+				}
+			}
+		})
+	}
+}
+
+func TestHierarchicalGenerator_SpilledContent(t *testing.T) {
+	spillFile := filepath.Join(t.TempDir(), "page.txt")
+	if err := os.WriteFile(spillFile, []byte("Spilled guide content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	cfg := &config.Config{RootURL: "https://example.com", OutputDir: dir, OutputFormat: "markdown", OutputType: "single"}
+	pages := []PageData{{Title: "Guide", URL: "https://example.com/guide", ContentFile: spillFile, Timestamp: time.Now()}}
+	generator := NewHierarchical(cfg, pages)
+
+	tree := generator.tree
+	if len(tree.Root.Children) != 1 || tree.Root.Children[0].Content != "" || tree.Root.Children[0].ContentFile != spillFile {
+		t.Fatalf("tree should leave spilled content on disk, got %+v", tree.Root.Children)
+	}
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if output := readOutputTree(t, dir)["documentation_hierarchical.md"]; !strings.Contains(output, "Spilled guide content") {
+		t.Errorf("output should hold spilled content, got:\n%s", output)
+	}
+}
+
+func TestHierarchicalGenerator_MissingSpillFile(t *testing.T) {
+	collapse := true
+	for _, outputType := range []string{"single", "per-page"} {
+		for _, format := range []string{"markdown", "text", "json"} {
+			cfg := &config.Config{
+				RootURL:                   "https://example.com",
+				OutputDir:                 t.TempDir(),
+				OutputFormat:              format,
+				OutputType:                outputType,
+				CollapseSingleChildChains: &collapse,
+			}
+			pages := []PageData{
+				{Title: "Guide", URL: "https://example.com/guide", ContentFile: filepath.Join(t.TempDir(), "missing.txt")},
+				{Title: "Install", URL: "https://example.com/guide/install", Content: "Install steps"},
+			}
+			if err := NewHierarchical(cfg, pages).Generate(); err == nil {
+				t.Errorf("Generate() of %s %s output should fail for a missing spill file", outputType, format)
+			}
+
+			cfg.CollapseSingleChildChains = nil
+			if err := NewHierarchical(cfg, pages).Generate(); err == nil {
+				t.Errorf("Generate() of %s %s output without collapsing should fail for a missing spill file", outputType, format)
+			}
+		}
+	}
+}
+
+func TestPageContent_MissingSpillFile(t *testing.T) {
+	page := PageData{URL: "https://example.com/", ContentFile: filepath.Join(t.TempDir(), "missing.txt")}
+	if _, err := pageContent(page); err == nil {
+		t.Error("pageContent() should fail for a missing spill file")
+	}
+}
@@ -0,0 +1,32 @@
+package output
+
+import "docscraper/scraper"
+
+// DocumentationStats is the stats section of documentation.json and documentation_hierarchical.json
+type DocumentationStats struct {
+	MaxDepth    int `json:"max_depth"`    // Deepest crawl depth among the pages
+	TotalTokens int `json:"total_tokens"` // Estimated LLM tokens across the pages
+}
+
+// pageStats summarizes pages for the stats section
+func pageStats(pages []PageData) DocumentationStats {
+	stats := DocumentationStats{TotalTokens: buildTokenReport(pages).TotalTokens}
+	for _, page := range pages {
+		stats.MaxDepth = max(stats.MaxDepth, page.Depth)
+	}
+	return stats
+}
+
+// treeStats summarizes the tree's nodes for the stats section
+func (h *HierarchicalGenerator) treeStats() (DocumentationStats, error) {
+	var stats DocumentationStats
+	for _, node := range h.tree.GetAllNodes() {
+		content, err := h.nodeContent(node)
+		if err != nil {
+			return stats, err
+		}
+		stats.MaxDepth = max(stats.MaxDepth, node.Depth)
+		stats.TotalTokens += scraper.EstimateTokens(content)
+	}
+	return stats, nil
+}
@@ -0,0 +1,202 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"docscraper/scraper"
+)
+
+// GenerateStream writes pages as they arrive on the channel, so per-page markdown and text
+// files and JSON entries appear while the scrape is still running. Page content is dropped
+// once written. Outputs that need every page up front (single or per-depth files, other
+// formats) fall back to collecting the pages and calling Generate.
+func (g *Generator) GenerateStream(pages <-chan PageData) error {
+	// Drain the channel on error so the producer never blocks
+	defer func() {
+		for range pages {
+		}
+	}()
+
+	if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	formats := g.config.GetOutputFormats()
+	g.written = newPageFileLog(g.config.OutputDir)
+	if !g.streamable(formats) {
+		var collected []PageData
+		for page := range pages {
+			collected = append(collected, page)
+		}
+		g.pages = outputPages(g.config, collected)
+		return g.Generate()
+	}
+
+	writers := make([]*streamWriter, 0, len(formats))
+	for _, format := range formats {
+		formatGenerator, err := g.forFormat(format)
+		if err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+		writer, err := newStreamWriter(formatGenerator)
+		if err != nil {
+			return err
+		}
+		defer writer.close()
+		writers = append(writers, writer)
+	}
+
+	g.pages = nil
+	g.excerpts = nil
+	patterns := redactors(g.config)
+	for page := range pages {
+		if isSkippedRootPage(g.config, page) {
+			continue
+		}
+
+		content, err := pageContent(page)
+		if err != nil {
+			return err
+		}
+		content = redact(patterns, content)
+		for _, writer := range writers {
+			if err := writer.writePage(page, content, len(g.pages)); err != nil {
+				return err
+			}
+		}
+
+		// Keep only what the index, metadata and reports need
+		if page.TokenEstimate == 0 {
+			page.TokenEstimate = scraper.EstimateTokens(content)
+		}
+		page.Content = ""
+		page.ContentFile = ""
+		g.pages = append(g.pages, page)
+		g.excerpts = append(g.excerpts, TruncateExcerpt(content, excerptLength))
+	}
+
+	for _, writer := range writers {
+		writer.generator.pages = g.pages
+		writer.generator.excerpts = g.excerpts
+		if err := writer.finish(); err != nil {
+			return err
+		}
+	}
+
+	return g.generateSupportFiles(formats)
+}
+
+// streamable reports whether every format can be written one page at a time; skeleton_only
+// output, JSON sorted by sort_by and output under max_output_files are written from the
+// collected pages
+func (g *Generator) streamable(formats []string) bool {
+	if g.config.GetSkeletonOnly() || g.config.GetMaxOutputFiles() > 0 {
+		return false
+	}
+	for _, format := range formats {
+		switch {
+		case format == "json" && g.config.SortBy == "":
+		case (format == "markdown" || format == "text") && g.config.OutputType == "per-page":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// streamWriter writes one output format page by page
+type streamWriter struct {
+	generator *Generator
+	json      *outputFile // documentation.json, open for the json format
+}
+
+// newStreamWriter prepares the output of g's format, opening documentation.json for json
+func newStreamWriter(g *Generator) (*streamWriter, error) {
+	writer := &streamWriter{generator: g}
+	if g.config.OutputFormat != "json" {
+		return writer, nil
+	}
+
+	file, err := createOutputFile(g.config, filepath.Join(g.config.OutputDir, "documentation.json"))
+	if err != nil {
+		return nil, err
+	}
+	writer.json = file
+	_, err = io.WriteString(file, "{\n  \"pages\": [")
+	return writer, err
+}
+
+// writePage writes the page at index with its loaded content
+func (w *streamWriter) writePage(page PageData, content string, index int) error {
+	g := w.generator
+	page.Content = content
+	page.ContentFile = ""
+
+	switch g.config.OutputFormat {
+	case "markdown":
+		return g.writeMarkdownPage(filepath.Join(g.config.OutputDir, fmt.Sprintf("page_%03d.md", index+1)), page)
+	case "text":
+		return g.writeTextPage(page, index)
+	default:
+		if g.config.GetReproducibleOutput() {
+			page.Timestamp = reproducibleTime
+		}
+		data, err := json.MarshalIndent(page, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		separator := "\n    "
+		if index > 0 {
+			separator = ",\n    "
+		}
+		_, err = io.WriteString(w.json, separator+string(data))
+		return err
+	}
+}
+
+// finish writes what follows the pages: the markdown index, text metadata or the rest of
+// documentation.json, laid out as generateJSONOutput writes it, and closes documentation.json
+func (w *streamWriter) finish() error {
+	g := w.generator
+	switch g.config.OutputFormat {
+	case "markdown":
+		return g.writeMarkdownIndex()
+	case "text":
+		return g.generateMetadataFile()
+	}
+
+	pagesEnd := "\n  ],"
+	if len(g.pages) == 0 {
+		pagesEnd = "],"
+	}
+	rootURL, err := json.Marshal(g.config.RootURL)
+	if err != nil {
+		return err
+	}
+	scrapedAt, err := json.Marshal(generatedAt(g.config))
+	if err != nil {
+		return err
+	}
+	stats, err := json.MarshalIndent(pageStats(g.pages), "  ", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w.json, "%s\n  \"root_url\": %s,\n  \"scraped_at\": %s,\n  \"stats\": %s,\n  \"total_pages\": %d\n}\n", pagesEnd, rootURL, scrapedAt, stats, len(g.pages)); err != nil {
+		return err
+	}
+	return w.close()
+}
+
+// close closes documentation.json when it is still open
+func (w *streamWriter) close() error {
+	if w.json == nil {
+		return nil
+	}
+	file := w.json
+	w.json = nil
+	return file.Close()
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// syntheticPage returns the nth of a series of generated pages
+func syntheticPage(n int) PageData {
+	return PageData{
+		Title:     fmt.Sprintf("Page %d", n),
+		URL:       fmt.Sprintf("https://example.com/page%d", n),
+		Content:   fmt.Sprintf("Content of page %d & <more>.", n),
+		Timestamp: time.Date(2023, 12, 1, 10, 30, 0, 0, time.UTC),
+		Depth:     n % 3,
+	}
+}
+
+func TestGenerator_GenerateStream_WritesIncrementally(t *testing.T) {
+	const total = 1000
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+
+	pages := make(chan PageData)
+	done := make(chan error, 1)
+	go func() {
+		done <- New(cfg, nil).GenerateStream(pages)
+	}()
+
+	for i := 0; i < total; i++ {
+		pages <- syntheticPage(i)
+		// The unbuffered send of page i only returns once page i-1 has been written
+		if i > 0 && !fileExists(filepath.Join(tmpDir, fmt.Sprintf("page_%03d.md", i))) {
+			t.Fatalf("page_%03d.md not written before page %d was sent", i, i+1)
+		}
+	}
+	close(pages)
+
+	if err := <-done; err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	last, err := os.ReadFile(filepath.Join(tmpDir, fmt.Sprintf("page_%03d.md", total)))
+	if err != nil {
+		t.Fatalf("Failed to read last page: %v", err)
+	}
+	if !strings.Contains(string(last), fmt.Sprintf("Content of page %d", total-1)) {
+		t.Errorf("Expected last page content, got %q", last)
+	}
+
+	index, err := os.ReadFile(filepath.Join(tmpDir, "index.md"))
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), fmt.Sprintf("**Total Pages:** %d", total)) {
+		t.Errorf("Expected index to count %d pages", total)
+	}
+	if !strings.Contains(string(index), fmt.Sprintf("1000. [Page 999](page_%03d.md)", total)) {
+		t.Error("Expected index to link the last page")
+	}
+}
+
+func TestGenerator_GenerateStream_MatchesGenerate(t *testing.T) {
+	reproducible := true
+	skip := true
+
+	for _, tc := range []struct {
+		format     string
+		outputType string
+		files      []string
+	}{
+		{"json", "single", []string{"documentation.json"}},
+		{"json", "single", nil},
+		{"text", "per-page", []string{"metadata.yaml", "Page_1_0.txt", "Page_2_1.txt"}},
+		{"markdown", "single", []string{"documentation.md"}},
+	} {
+		t.Run(fmt.Sprintf("%s %s %d", tc.format, tc.outputType, len(tc.files)), func(t *testing.T) {
+			var pages []PageData
+			if tc.files != nil {
+				pages = []PageData{syntheticPage(0), syntheticPage(1), syntheticPage(2)}
+			}
+
+			write := func(stream bool) string {
+				cfg := &config.Config{
+					RootURL:            "https://example.com/page0",
+					OutputDir:          t.TempDir(),
+					OutputFormat:       tc.format,
+					OutputType:         tc.outputType,
+					ReproducibleOutput: &reproducible,
+					SkipRootInOutput:   &skip,
+				}
+				if !stream {
+					if err := New(cfg, pages).Generate(); err != nil {
+						t.Fatalf("Generate() error = %v", err)
+					}
+					return cfg.OutputDir
+				}
+
+				ch := make(chan PageData, len(pages))
+				for _, page := range pages {
+					ch <- page
+				}
+				close(ch)
+				if err := New(cfg, nil).GenerateStream(ch); err != nil {
+					t.Fatalf("GenerateStream() error = %v", err)
+				}
+				return cfg.OutputDir
+			}
+
+			want, got := readOutputTree(t, write(false)), readOutputTree(t, write(true))
+			if len(got) != len(want) {
+				t.Errorf("Expected files %v, got %v", fileNames(want), fileNames(got))
+			}
+			for name, content := range want {
+				if got[name] != content {
+					t.Errorf("%s differs:\nstream:   %q\ngenerate: %q", name, got[name], content)
+				}
+			}
+			for _, name := range tc.files {
+				if _, ok := got[name]; !ok {
+					t.Errorf("Expected %s in %v", name, fileNames(got))
+				}
+			}
+		})
+	}
+}
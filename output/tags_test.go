@@ -0,0 +1,66 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestGenerator_Tags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:       "https://example.com",
+		OutputDir:     tmpDir,
+		OutputFormat:  "markdown",
+		OutputFormats: []string{"markdown", "json", "text"},
+		OutputType:    "per-page",
+	}
+	pages := []PageData{
+		{Title: "Users API", URL: "https://example.com/api/users", Content: "Users", Timestamp: time.Now(), Tags: []string{"reference", "beta"}},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide", Timestamp: time.Now()},
+	}
+
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := readOutputTree(t, tmpDir)
+	if !strings.Contains(files["page_001.md"], "**Tags:** reference, beta") {
+		t.Errorf("Tagged page should list its tags, got:\n%s", files["page_001.md"])
+	}
+	if strings.Contains(files["page_002.md"], "**Tags:**") {
+		t.Errorf("Untagged page should not have a tags line, got:\n%s", files["page_002.md"])
+	}
+	if !strings.Contains(files["metadata.yaml"], "- reference") {
+		t.Errorf("metadata.yaml should include tags, got:\n%s", files["metadata.yaml"])
+	}
+
+	var textPage string
+	for path, content := range files {
+		if strings.HasSuffix(path, ".txt") && strings.Contains(content, "Users API") {
+			textPage = content
+		}
+	}
+	if !strings.Contains(textPage, "TAGS: reference, beta") {
+		t.Errorf("Text page should list its tags, got:\n%s", textPage)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "documentation.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Pages []PageData `json:"pages"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Pages) != 2 || strings.Join(result.Pages[0].Tags, ",") != "reference,beta" || result.Pages[1].Tags != nil {
+		t.Errorf("JSON page tags = %+v, want [reference beta] on the first page only", result.Pages)
+	}
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"docscraper/scraper"
+)
+
+// TokenReport summarizes estimated LLM token counts across the scraped pages
+type TokenReport struct {
+	TotalTokens int             `json:"total_tokens"`
+	TotalPages  int             `json:"total_pages"`
+	Sections    []SectionTokens `json:"sections"`
+}
+
+// SectionTokens is the token total for pages sharing a top-level URL path segment
+type SectionTokens struct {
+	Section string `json:"section"`
+	Pages   int    `json:"pages"`
+	Tokens  int    `json:"tokens"`
+}
+
+// pageSection returns the top-level path segment of a page URL, or "/" for the root page
+func pageSection(pageURL string) string {
+	return "/" + strings.Split(strings.Trim(extractPathFromURL(pageURL), "/"), "/")[0]
+}
+
+// buildTokenReport sums page token estimates overall and per section
+func buildTokenReport(pages []PageData) TokenReport {
+	report := TokenReport{TotalPages: len(pages), Sections: []SectionTokens{}}
+	sections := make(map[string]*SectionTokens)
+
+	for _, page := range pages {
+		tokens := page.TokenEstimate
+		if tokens == 0 {
+			content, _ := pageContent(page)
+			tokens = scraper.EstimateTokens(content)
+		}
+		report.TotalTokens += tokens
+
+		name := pageSection(page.URL)
+		if sections[name] == nil {
+			sections[name] = &SectionTokens{Section: name}
+		}
+		sections[name].Pages++
+		sections[name].Tokens += tokens
+	}
+
+	for _, section := range sections {
+		report.Sections = append(report.Sections, *section)
+	}
+	sort.Slice(report.Sections, func(i, j int) bool {
+		return report.Sections[i].Section < report.Sections[j].Section
+	})
+
+	return report
+}
+
+// writeTokenReport writes token_report.json
+func writeTokenReport(outputDir string, report TokenReport) error {
+	file, err := os.Create(filepath.Join(outputDir, "token_report.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// TokenReport returns the estimated token counts of the generator's pages
+func (g *Generator) TokenReport() TokenReport {
+	return buildTokenReport(g.pages)
+}
+
+// TokenReport returns the estimated token counts of the tree's pages
+func (h *HierarchicalGenerator) TokenReport() TokenReport {
+	var pages []PageData
+	for _, node := range h.tree.GetAllNodes() {
+		pages = append(pages, PageData{URL: node.URL, Content: node.Content, ContentFile: node.ContentFile})
+	}
+	return buildTokenReport(pages)
+}
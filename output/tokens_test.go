@@ -0,0 +1,90 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestBuildTokenReport(t *testing.T) {
+	pages := []PageData{
+		{URL: "https://example.com/", Content: strings.Repeat("a", 40)},
+		{URL: "https://example.com/docs", Content: strings.Repeat("b", 80)},
+		{URL: "https://example.com/docs/install", Content: "ignored", TokenEstimate: 50},
+		{URL: "https://example.com/blog/post", Content: strings.Repeat("c", 400)},
+	}
+
+	report := buildTokenReport(pages)
+
+	if report.TotalPages != 4 {
+		t.Errorf("TotalPages = %d, want 4", report.TotalPages)
+	}
+	if report.TotalTokens != 10+20+50+100 {
+		t.Errorf("TotalTokens = %d, want %d", report.TotalTokens, 10+20+50+100)
+	}
+
+	expected := []SectionTokens{
+		{Section: "/", Pages: 1, Tokens: 10},
+		{Section: "/blog", Pages: 1, Tokens: 100},
+		{Section: "/docs", Pages: 2, Tokens: 70},
+	}
+	if len(report.Sections) != len(expected) {
+		t.Fatalf("Sections = %+v, want %+v", report.Sections, expected)
+	}
+	sum := 0
+	for i, want := range expected {
+		if report.Sections[i] != want {
+			t.Errorf("Sections[%d] = %+v, want %+v", i, report.Sections[i], want)
+		}
+		sum += report.Sections[i].Tokens
+	}
+	if sum != report.TotalTokens {
+		t.Errorf("Section tokens sum to %d, want total %d", sum, report.TotalTokens)
+	}
+}
+
+func TestGenerator_TokenReport(t *testing.T) {
+	generate := true
+	pages := []PageData{
+		{Title: "Short", URL: "https://example.com/docs/short", Content: strings.Repeat("x", 100), Timestamp: time.Now()},
+		{Title: "Long", URL: "https://example.com/docs/long", Content: strings.Repeat("x", 1000), Timestamp: time.Now()},
+	}
+
+	for _, hierarchical := range []bool{false, true} {
+		tmpDir := t.TempDir()
+		cfg := &config.Config{
+			RootURL:             "https://example.com",
+			OutputDir:           tmpDir,
+			OutputFormat:        "json",
+			OutputType:          "single",
+			GenerateTokenReport: &generate,
+		}
+
+		var err error
+		if hierarchical {
+			err = NewHierarchical(cfg, pages).Generate()
+		} else {
+			err = New(cfg, pages).Generate()
+		}
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, "token_report.json"))
+		if err != nil {
+			t.Fatalf("token_report.json was not written: %v", err)
+		}
+		var report TokenReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.TotalTokens != 275 || len(report.Sections) != 1 || report.Sections[0].Tokens != 275 {
+			t.Errorf("hierarchical=%v: unexpected report %+v", hierarchical, report)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package output
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"docscraper/config"
+	"docscraper/scraper"
+)
+
+// usesTreeBuilder reports whether hierarchical output places pages with scraper.TreeBuilder,
+// which follows a navigation menu, breadcrumbs and navigation trails before URL paths and can
+// order children by their parent's table of contents
+func usesTreeBuilder(cfg *config.Config) bool {
+	return cfg.GetUseStructuredData() || cfg.GetUseNavigationTrail() || cfg.GetUseTableOfContents() ||
+		cfg.NavigationMenuSelector != ""
+}
+
+// treeConfig maps the configuration to the tree builder's strategies; children are sorted by
+// title, as sortedChildren does, before any table of contents reorders them. A navigation menu
+// captured from the root page takes over placement from trails and breadcrumbs.
+func treeConfig(cfg *config.Config, pages []PageData) scraper.TreeConfig {
+	treeCfg := scraper.TreeConfig{
+		UseBreadcrumbs:     true,
+		UseNavigation:      cfg.GetUseNavigationTrail(),
+		UseURLHierarchy:    true,
+		FallbackToRoot:     true,
+		SortChildren:       true,
+		SortBy:             scraper.SortByTitle,
+		UseTableOfContents: cfg.GetUseTableOfContents(),
+	}
+	if cfg.NavigationMenuSelector == "" {
+		return treeCfg
+	}
+	for _, page := range pages {
+		if page.NavigationMenu != "" {
+			treeCfg.UseNavigation = true
+			treeCfg.NavigationMenu = page.NavigationMenu
+			break
+		}
+	}
+	return treeCfg
+}
+
+// scrapedContents converts pages to tree builder input under a placeholder root, ordered so
+// pages with shorter trails and URL paths, the likely parents, are placed first
+func scrapedContents(pages []PageData) ([]string, map[string]scraper.ScrapedContent) {
+	contents := map[string]scraper.ScrapedContent{"": {}}
+	urls := make([]string, 0, len(pages))
+	for _, page := range pages {
+		if _, exists := contents[page.URL]; exists {
+			continue
+		}
+		lastModified := page.Timestamp
+		if page.LastModified != nil {
+			lastModified = *page.LastModified
+		}
+		contents[page.URL] = scraper.ScrapedContent{
+			URL:   page.URL,
+			Title: page.Title,
+			Metadata: scraper.NodeMetadata{
+				LastModified:    lastModified,
+				ContentType:     page.ContentType,
+				Tags:            page.Tags,
+				Breadcrumbs:     page.Breadcrumbs,
+				NavigationTrail: page.NavigationTrail,
+				TableOfContents: page.TableOfContents,
+			},
+		}
+		urls = append(urls, page.URL)
+	}
+
+	trailLength := func(rawURL string) int {
+		metadata := contents[rawURL].Metadata
+		return max(len(metadata.Breadcrumbs), len(metadata.NavigationTrail))
+	}
+	sort.SliceStable(urls, func(i, j int) bool {
+		if trailI, trailJ := trailLength(urls[i]), trailLength(urls[j]); trailI != trailJ {
+			return trailI < trailJ
+		}
+		return strings.Count(extractPathFromURL(urls[i]), "/") < strings.Count(extractPathFromURL(urls[j]), "/")
+	})
+	return append([]string{""}, urls...), contents
+}
+
+// buildTreeWithBuilder creates a document tree from page data whose shape comes from
+// scraper.TreeBuilder, the nodes keeping their pages' fields as buildTreeFromPages does
+func buildTreeWithBuilder(cfg *config.Config, pages []PageData) *DocumentTree {
+	root := &DocumentNode{
+		Title:    "Root",
+		Children: make([]*DocumentNode, 0),
+	}
+	nodeMap := make(map[string]*DocumentNode, len(pages))
+	for i, page := range pages {
+		if nodeMap[page.URL] == nil {
+			nodeMap[page.URL] = newPageNode(page, i)
+		}
+	}
+
+	urls, contents := scrapedContents(pages)
+	treeCfg := treeConfig(cfg, pages)
+	if treeCfg.NavigationMenu != "" {
+		// The menu's tree is rooted at the site root page, or an untitled root without one
+		delete(contents, "")
+	}
+	built := scraper.NewTreeBuilder(treeCfg).BuildTree(urls, contents)
+
+	var attach func(source *scraper.DocumentNode, parent *DocumentNode)
+	attach = func(source *scraper.DocumentNode, parent *DocumentNode) {
+		node := nodeMap[source.URL]
+		if node == nil {
+			// The placeholder root's children become top-level pages
+			for _, child := range source.Children {
+				attach(child, parent)
+			}
+			return
+		}
+
+		node.Parent = parent
+		node.Level = parent.Level + 1
+		parent.Children = append(parent.Children, node)
+		for _, child := range source.Children {
+			attach(child, node)
+		}
+	}
+	attach(built.Root, root)
+
+	return &DocumentTree{
+		Root:       root,
+		NodeMap:    nodeMap,
+		MaxDepth:   calculateMaxDepth(root),
+		TotalNodes: len(nodeMap),
+		BuildTime:  time.Now(),
+	}
+}
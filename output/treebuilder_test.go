@@ -0,0 +1,133 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// trailTestPages returns pages whose breadcrumbs and navigation trails disagree with their URLs
+func trailTestPages() []PageData {
+	return []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now()},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{
+			Title: "API", URL: "https://example.com/reference/api", Content: "API content", Timestamp: time.Now(), Depth: 1,
+			NavigationTrail: []string{"https://example.com/guide", "https://example.com/reference/api"},
+		},
+		{
+			Title: "Tutorial", URL: "https://example.com/tutorial", Content: "Tutorial content", Timestamp: time.Now(), Depth: 1,
+			Breadcrumbs: []string{"https://example.com/", "https://example.com/guide"},
+		},
+	}
+}
+
+// parentTitles maps each node's title to its parent's title
+func parentTitles(tree *DocumentTree) map[string]string {
+	parents := make(map[string]string)
+	for _, node := range tree.GetAllNodes() {
+		if node.Parent != nil {
+			parents[node.Title] = node.Parent.Title
+		}
+	}
+	return parents
+}
+
+func TestNewHierarchical_NavigationTrailsAndBreadcrumbs(t *testing.T) {
+	tmpDir := t.TempDir()
+	enabled := true
+	cfg := &config.Config{
+		RootURL:            "https://example.com/",
+		OutputDir:          tmpDir,
+		OutputFormat:       "markdown",
+		OutputType:         "per-page",
+		UseStructuredData:  &enabled,
+		UseNavigationTrail: &enabled,
+	}
+
+	generator := NewHierarchical(cfg, trailTestPages())
+	parents := parentTitles(generator.tree)
+	want := map[string]string{"Home": "Root", "Guide": "Home", "API": "Guide", "Tutorial": "Guide"}
+	for title, parent := range want {
+		if parents[title] != parent {
+			t.Errorf("Expected %s under %s, got %q", title, parent, parents[title])
+		}
+	}
+
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, expected := range []string{
+		filepath.Join("home", "guide", "api", "index.md"),
+		filepath.Join("home", "guide", "tutorial", "index.md"),
+	} {
+		if !fileExists(filepath.Join(tmpDir, expected)) {
+			t.Errorf("Expected file %s was not created", expected)
+		}
+	}
+}
+
+func TestNewHierarchical_IgnoresTrailsByDefault(t *testing.T) {
+	cfg := &config.Config{RootURL: "https://example.com/", OutputFormat: "markdown"}
+
+	parents := parentTitles(NewHierarchical(cfg, trailTestPages()).tree)
+	if parents["API"] != "Root" || parents["Tutorial"] != "Root" {
+		t.Errorf("Expected pages placed by URL path without trail settings, got %v", parents)
+	}
+}
+
+func TestNewHierarchical_TableOfContentsOrder(t *testing.T) {
+	pages := []PageData{
+		{
+			Title: "Guides", URL: "https://example.com/guides", Content: "Guides content", Timestamp: time.Now(),
+			TableOfContents: []string{"https://example.com/guides/gamma", "https://example.com/guides/alpha"},
+		},
+		{Title: "Alpha", URL: "https://example.com/guides/alpha", Content: "Alpha content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Beta", URL: "https://example.com/guides/beta", Content: "Beta content", Timestamp: time.Now(), Depth: 1},
+		{Title: "Gamma", URL: "https://example.com/guides/gamma", Content: "Gamma content", Timestamp: time.Now(), Depth: 1},
+	}
+	childTitles := func(generator *HierarchicalGenerator) []string {
+		var titles []string
+		for _, child := range generator.sortedChildren(generator.tree.NodeMap["https://example.com/guides"]) {
+			titles = append(titles, child.Title)
+		}
+		return titles
+	}
+
+	cfg := &config.Config{RootURL: "https://example.com/", OutputFormat: "markdown"}
+	if got := childTitles(NewHierarchical(cfg, pages)); fmt.Sprint(got) != "[Alpha Beta Gamma]" {
+		t.Errorf("Expected children sorted by title without use_table_of_contents, got %v", got)
+	}
+
+	enabled := true
+	cfg.UseTableOfContents = &enabled
+	if got := childTitles(NewHierarchical(cfg, pages)); fmt.Sprint(got) != "[Gamma Alpha Beta]" {
+		t.Errorf("Expected listed children in table of contents order before the rest, got %v", got)
+	}
+}
+
+func TestNewHierarchical_NavigationMenu(t *testing.T) {
+	pages := trailTestPages()
+	pages[0].NavigationMenu = `<base href="https://example.com/"><ul>
+		<li><a href="/reference/api">API</a><ul><li><a href="tutorial">Tutorial</a></li></ul></li>
+		<li><a href="guide">Guide</a></li>
+	</ul>`
+	cfg := &config.Config{RootURL: "https://example.com/", OutputFormat: "markdown"}
+
+	parents := parentTitles(NewHierarchical(cfg, pages).tree)
+	if parents["API"] != "Root" || parents["Tutorial"] != "Root" {
+		t.Errorf("Expected the menu ignored without navigation_menu_selector, got %v", parents)
+	}
+
+	cfg.NavigationMenuSelector = "nav ul"
+	parents = parentTitles(NewHierarchical(cfg, pages).tree)
+	want := map[string]string{"Home": "Root", "API": "Home", "Tutorial": "API", "Guide": "Home"}
+	for title, parent := range want {
+		if parents[title] != parent {
+			t.Errorf("Expected %s under %s, got %q", title, parent, parents[title])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// URLIndexFile is the file in output_dir that generate_url_index writes
+const URLIndexFile = "index.json"
+
+// URLIndexEntry is a captured page as listed in index.json
+type URLIndexEntry struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	Depth int    `json:"depth"`
+}
+
+// writeURLIndex writes index.json, the pages' URLs, titles and depths without their content
+func writeURLIndex(outputDir string, entries []URLIndexEntry) error {
+	file, err := os.Create(filepath.Join(outputDir, URLIndexFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// URLIndex lists the generator's pages in output order
+func (g *Generator) URLIndex() []URLIndexEntry {
+	entries := make([]URLIndexEntry, len(g.pages))
+	for i, page := range g.pages {
+		entries[i] = URLIndexEntry{URL: page.URL, Title: page.Title, Depth: page.Depth}
+	}
+	return entries
+}
+
+// URLIndex lists the tree's pages in depth-first order
+func (h *HierarchicalGenerator) URLIndex() []URLIndexEntry {
+	nodes := h.tree.GetAllNodes()
+	entries := make([]URLIndexEntry, 0, len(nodes))
+	for _, node := range nodes {
+		entries = append(entries, URLIndexEntry{URL: node.URL, Title: node.Title, Depth: node.Depth})
+	}
+	return entries
+}
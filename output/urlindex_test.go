@@ -0,0 +1,124 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// readURLIndex parses the index.json in dir
+func readURLIndex(t *testing.T, dir string) []URLIndexEntry {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, URLIndexFile))
+	if err != nil {
+		t.Fatalf("Expected %s: %v", URLIndexFile, err)
+	}
+	var entries []URLIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("%s is not valid JSON: %v", URLIndexFile, err)
+	}
+	return entries
+}
+
+func TestGenerator_URLIndex(t *testing.T) {
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Welcome home", Timestamp: time.Now(), Depth: 1},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Read the guide", Timestamp: time.Now(), Depth: 2},
+		{Title: "Setup", URL: "https://example.com/guide/setup", Content: "Set it up", Timestamp: time.Now(), Depth: 3},
+	}
+	want := []URLIndexEntry{
+		{URL: "https://example.com/", Title: "Home", Depth: 1},
+		{URL: "https://example.com/guide", Title: "Guide", Depth: 2},
+		{URL: "https://example.com/guide/setup", Title: "Setup", Depth: 3},
+	}
+
+	for _, format := range []string{"markdown", "text", "json", "csv"} {
+		for _, outputType := range []string{"single", "per-page"} {
+			t.Run(format+"/"+outputType, func(t *testing.T) {
+				cfg := &config.Config{
+					RootURL:      "https://example.com",
+					OutputDir:    t.TempDir(),
+					OutputFormat: format,
+					OutputType:   outputType,
+				}
+				if err := New(cfg, pages).Generate(); err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				if got := readURLIndex(t, cfg.OutputDir); !reflect.DeepEqual(got, want) {
+					t.Errorf("index.json = %v, want %v", got, want)
+				}
+			})
+		}
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		cfg := &config.Config{
+			RootURL:      "https://example.com",
+			OutputDir:    t.TempDir(),
+			OutputFormat: "markdown",
+			OutputType:   "per-page",
+		}
+		stream := make(chan PageData, len(pages))
+		for _, page := range pages {
+			stream <- page
+		}
+		close(stream)
+		if err := New(cfg, nil).GenerateStream(stream); err != nil {
+			t.Fatalf("GenerateStream() error = %v", err)
+		}
+		if got := readURLIndex(t, cfg.OutputDir); !reflect.DeepEqual(got, want) {
+			t.Errorf("index.json = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		disabled := false
+		cfg := &config.Config{
+			RootURL:          "https://example.com",
+			OutputDir:        t.TempDir(),
+			OutputFormat:     "markdown",
+			OutputType:       "single",
+			GenerateURLIndex: &disabled,
+		}
+		if err := New(cfg, pages).Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fileExists(filepath.Join(cfg.OutputDir, URLIndexFile)) {
+			t.Errorf("Expected no %s with generate_url_index off", URLIndexFile)
+		}
+	})
+}
+
+func TestHierarchicalGenerator_URLIndex(t *testing.T) {
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    t.TempDir(),
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+	pages := hierarchicalTestPages()
+	if err := NewHierarchical(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	entries := readURLIndex(t, cfg.OutputDir)
+	if len(entries) != len(pages) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(pages), len(entries), entries)
+	}
+	byURL := make(map[string]URLIndexEntry, len(entries))
+	for _, entry := range entries {
+		byURL[entry.URL] = entry
+	}
+	for _, page := range pages {
+		entry, listed := byURL[page.URL]
+		if !listed || entry.Title != page.Title || entry.Depth != page.Depth {
+			t.Errorf("Expected %s listed as %q at depth %d, got %+v", page.URL, page.Title, page.Depth, entry)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// writeURLMap writes url_map.json mapping each page URL to its output file, relative to outputDir
+func writeURLMap(outputDir string, urlMap map[string]string) error {
+	file, err := os.Create(filepath.Join(outputDir, "url_map.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(urlMap)
+}
+
+// urlMapFormats orders formats for url_map.json, markdown first as for the glossary
+func urlMapFormats(formats []string) []string {
+	ordered := []string{glossaryFormat(formats)}
+	for _, format := range formats {
+		if format != ordered[0] {
+			ordered = append(ordered, format)
+		}
+	}
+	return ordered
+}
+
+// pageFileLog records the file each page was written to, per format, as the per-page writers
+// create them
+type pageFileLog struct {
+	root  string // Output directory the recorded paths are relative to
+	mutex sync.Mutex
+	files map[string]map[string]string // Format to page URL to file
+}
+
+// newPageFileLog returns an empty log of files written under root
+func newPageFileLog(root string) *pageFileLog {
+	return &pageFileLog{root: root, files: make(map[string]map[string]string)}
+}
+
+// record notes that the page at pageURL was written to filename in format; a nil log records
+// nothing
+func (l *pageFileLog) record(format, pageURL, filename string) {
+	if l == nil {
+		return
+	}
+	rel, err := filepath.Rel(l.root, filename)
+	if err != nil {
+		rel = filename
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.files[format] == nil {
+		l.files[format] = make(map[string]string)
+	}
+	l.files[format][pageURL] = filepath.ToSlash(rel)
+}
+
+// urlMap returns the files written in the first of formats that wrote one file per page, or
+// an empty map
+func (l *pageFileLog) urlMap(formats []string) map[string]string {
+	urlMap := make(map[string]string)
+	if l == nil {
+		return urlMap
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, format := range formats {
+		if files := l.files[format]; len(files) > 0 {
+			for pageURL, file := range files {
+				urlMap[pageURL] = file
+			}
+			break
+		}
+	}
+	return urlMap
+}
+
+// URLMap maps each page URL to the file it was written to by the last Generate, in the first
+// format written one file per page, or returns an empty map when every format is a single file
+func (g *Generator) URLMap() map[string]string {
+	return g.written.urlMap(urlMapFormats(g.config.GetOutputFormats()))
+}
+
+// recordPageFile notes that page was written to filename for url_map.json
+func (g *Generator) recordPageFile(page PageData, filename string) {
+	g.written.record(g.config.OutputFormat, page.URL, filename)
+}
+
+// URLMap maps each node URL to the index.md written for it by the last Generate under per-page
+// hierarchical markdown, or returns an empty map when the hierarchy is written as single files
+func (h *HierarchicalGenerator) URLMap() map[string]string {
+	return h.written.urlMap([]string{"markdown"})
+}
@@ -0,0 +1,166 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// readURLMap reads url_map.json from dir
+func readURLMap(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "url_map.json"))
+	if err != nil {
+		t.Fatalf("Failed to read url_map.json: %v", err)
+	}
+	var urlMap map[string]string
+	if err := json.Unmarshal(data, &urlMap); err != nil {
+		t.Fatalf("Invalid url_map.json: %v", err)
+	}
+	return urlMap
+}
+
+func TestGenerator_Generate_URLMap(t *testing.T) {
+	generateURLMap := true
+	formatSubdirs := true
+	compress := true
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Welcome", Timestamp: time.Now(), Depth: 0},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now(), Depth: 1},
+		{Title: "API: Reference", URL: "https://example.com/api", Content: "API content", Timestamp: time.Now(), Depth: 2},
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.Config
+	}{
+		{"markdown per-page", config.Config{OutputFormat: "markdown", OutputType: "per-page"}},
+		{"markdown per-depth", config.Config{OutputFormat: "markdown", OutputType: "per-depth"}},
+		{"compressed markdown", config.Config{OutputFormat: "markdown", OutputType: "per-page", CompressOutput: &compress}},
+		{"compressed text per-depth", config.Config{OutputFormat: "text", OutputType: "per-depth", CompressOutput: &compress}},
+		{"text per-page", config.Config{OutputFormat: "text", OutputType: "per-page"}},
+		{"html per-depth", config.Config{OutputFormat: "html", OutputType: "per-depth"}},
+		{"auto", config.Config{OutputFormat: "auto", OutputType: "single"}},
+		{"format subdirs", config.Config{OutputFormats: []string{"json", "text"}, OutputType: "per-page", FormatSubdirs: &formatSubdirs}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.RootURL = "https://example.com"
+			cfg.OutputDir = t.TempDir()
+			cfg.GenerateURLMap = &generateURLMap
+
+			if err := New(&cfg, pages).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			urlMap := readURLMap(t, cfg.OutputDir)
+			if len(urlMap) != len(pages) {
+				t.Errorf("Expected %d entries, got %v", len(pages), urlMap)
+			}
+			for _, page := range pages {
+				path, ok := urlMap[page.URL]
+				if !ok {
+					t.Errorf("Missing entry for %s", page.URL)
+					continue
+				}
+				if filepath.IsAbs(path) || !fileExists(filepath.Join(cfg.OutputDir, filepath.FromSlash(path))) {
+					t.Errorf("Mapped path %q for %s does not exist in the output", path, page.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_URLMapSingleFile(t *testing.T) {
+	generateURLMap := true
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormat:   "markdown",
+		OutputType:     "single",
+		GenerateURLMap: &generateURLMap,
+	}
+
+	if err := New(cfg, []PageData{{Title: "Home", URL: "https://example.com/", Content: "Welcome"}}).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if urlMap := readURLMap(t, cfg.OutputDir); len(urlMap) != 0 {
+		t.Errorf("Expected no entries for single-file output, got %v", urlMap)
+	}
+}
+
+func TestGenerator_GenerateStream_URLMap(t *testing.T) {
+	generateURLMap := true
+	cfg := &config.Config{
+		RootURL:        "https://example.com",
+		OutputDir:      t.TempDir(),
+		OutputFormats:  []string{"json", "markdown"},
+		OutputType:     "per-page",
+		GenerateURLMap: &generateURLMap,
+	}
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Welcome"},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content"},
+	}
+
+	stream := make(chan PageData, len(pages))
+	for _, page := range pages {
+		stream <- page
+	}
+	close(stream)
+	if err := New(cfg, nil).GenerateStream(stream); err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	urlMap := readURLMap(t, cfg.OutputDir)
+	for i, page := range pages {
+		if want := fmt.Sprintf("page_%03d.md", i+1); urlMap[page.URL] != want {
+			t.Errorf("Expected %s mapped to %s, got %q", page.URL, want, urlMap[page.URL])
+		}
+	}
+}
+
+func TestHierarchicalGenerator_Generate_URLMap(t *testing.T) {
+	for _, sectionIndexes := range []bool{false, true} {
+		t.Run(fmt.Sprintf("section indexes %v", sectionIndexes), func(t *testing.T) {
+			generateURLMap := true
+			cfg := &config.Config{
+				RootURL:        "https://example.com",
+				OutputDir:      t.TempDir(),
+				OutputFormat:   "markdown",
+				OutputType:     "per-page",
+				GenerateURLMap: &generateURLMap,
+				SectionIndexes: &sectionIndexes,
+			}
+
+			pages := hierarchicalTestPages()
+			if err := NewHierarchical(cfg, pages).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			urlMap := readURLMap(t, cfg.OutputDir)
+			for _, page := range pages {
+				path, ok := urlMap[page.URL]
+				if !ok {
+					t.Errorf("Missing entry for %s in %v", page.URL, urlMap)
+					continue
+				}
+				if !strings.HasSuffix(path, "/index.md") && path != "index.md" {
+					t.Errorf("Expected %s mapped to its content file, got %q", page.URL, path)
+				}
+				if !fileExists(filepath.Join(cfg.OutputDir, filepath.FromSlash(path))) {
+					t.Errorf("Mapped path %q for %s does not exist in the output", path, page.URL)
+				}
+			}
+		})
+	}
+}
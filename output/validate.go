@@ -0,0 +1,201 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// markdownLinkPattern matches inline markdown links, capturing the target
+var markdownLinkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)\)`)
+
+// ValidationIssue is a problem found in generated output
+type ValidationIssue struct {
+	Type     string `json:"type"`
+	Severity string `json:"severity"` // "critical" or "warning"
+	Message  string `json:"message"`
+}
+
+// ValidateOutput checks generated output for integrity: every markdown link resolves to an
+// existing file and anchor, no file is empty, JSON parses and front matter is valid YAML
+func ValidateOutput(dir string) []ValidationIssue {
+	var issues []ValidationIssue
+	anchors := make(map[string]map[string]bool)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		if info.Size() == 0 {
+			issues = append(issues, ValidationIssue{
+				Type:     "empty_file",
+				Severity: "warning",
+				Message:  fmt.Sprintf("%s is empty", rel),
+			})
+			return nil
+		}
+
+		switch filepath.Ext(path) {
+		case ".json":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if !json.Valid(data) {
+				issues = append(issues, ValidationIssue{
+					Type:     "invalid_json",
+					Severity: "critical",
+					Message:  fmt.Sprintf("%s does not contain valid JSON", rel),
+				})
+			}
+		case ".md":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, validateFrontMatter(rel, data)...)
+			issues = append(issues, validateLinks(dir, path, data, anchors)...)
+		}
+		return nil
+	})
+	if err != nil {
+		issues = append(issues, ValidationIssue{
+			Type:     "unreadable_output",
+			Severity: "critical",
+			Message:  fmt.Sprintf("Cannot read output directory: %v", err),
+		})
+	}
+
+	return issues
+}
+
+// validateFrontMatter checks that a leading "---" block, when present, is valid YAML
+func validateFrontMatter(rel string, data []byte) []ValidationIssue {
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		return nil
+	}
+
+	end := bytes.Index(data[4:], []byte("\n---"))
+	if end < 0 {
+		return []ValidationIssue{{
+			Type:     "invalid_front_matter",
+			Severity: "critical",
+			Message:  fmt.Sprintf("%s has unterminated front matter", rel),
+		}}
+	}
+
+	var frontMatter map[string]interface{}
+	if err := yaml.Unmarshal(data[4:4+end], &frontMatter); err != nil {
+		return []ValidationIssue{{
+			Type:     "invalid_front_matter",
+			Severity: "critical",
+			Message:  fmt.Sprintf("%s has invalid front matter: %v", rel, err),
+		}}
+	}
+
+	return nil
+}
+
+// validateLinks reports relative markdown links whose file or anchor does not exist
+func validateLinks(dir, path string, data []byte, anchors map[string]map[string]bool) []ValidationIssue {
+	var issues []ValidationIssue
+	rel, _ := filepath.Rel(dir, path)
+
+	// Links shown inside fenced code are examples, not references
+	var prose []string
+	lines := strings.Split(string(data), "\n")
+	proseLines(lines, func(i int) { prose = append(prose, lines[i]) })
+
+	for _, match := range markdownLinkPattern.FindAllStringSubmatch(strings.Join(prose, "\n"), -1) {
+		target := match[1]
+		if parsed, err := url.Parse(target); err != nil || parsed.Scheme != "" || parsed.Host != "" {
+			continue
+		}
+
+		targetPath, anchor := target, ""
+		if idx := strings.Index(target, "#"); idx >= 0 {
+			targetPath, anchor = target[:idx], target[idx+1:]
+		}
+
+		resolved := path
+		if targetPath != "" {
+			resolved = filepath.Join(filepath.Dir(path), filepath.FromSlash(targetPath))
+			if _, err := os.Stat(resolved); err != nil {
+				issues = append(issues, ValidationIssue{
+					Type:     "dangling_link",
+					Severity: "critical",
+					Message:  fmt.Sprintf("%s links to missing file %s", rel, targetPath),
+				})
+				continue
+			}
+		}
+
+		if anchor == "" || filepath.Ext(resolved) != ".md" {
+			continue
+		}
+		if _, ok := anchors[resolved]; !ok {
+			anchors[resolved] = markdownAnchors(resolved)
+		}
+		if !anchors[resolved][anchor] {
+			issues = append(issues, ValidationIssue{
+				Type:     "dangling_anchor",
+				Severity: "critical",
+				Message:  fmt.Sprintf("%s links to missing anchor %s", rel, target),
+			})
+		}
+	}
+
+	return issues
+}
+
+// markdownAnchors returns the anchors defined by the headings of a markdown file, from explicit
+// {#id} markers and from heading text
+func markdownAnchors(path string) map[string]bool {
+	anchors := make(map[string]bool)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return anchors
+	}
+
+	lines := strings.Split(string(data), "\n")
+	headingLines(lines, func(i, _ int) {
+		match := markdownHeadingPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			return
+		}
+		text, id := splitHeadingID(match[2])
+		if id != "" {
+			anchors[id] = true
+		}
+		anchors[(&Generator{}).createAnchor(text)] = true
+	})
+
+	return anchors
+}
+
+// validationError summarizes the critical issues found in generated output
+func validationError(issues []ValidationIssue) error {
+	var messages []string
+	for _, issue := range issues {
+		if issue.Severity == "critical" {
+			messages = append(messages, issue.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("output validation failed: %s", strings.Join(messages, "; "))
+}
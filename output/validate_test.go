@@ -0,0 +1,139 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// hasIssue reports whether issues contain one of the given type mentioning text
+func hasIssue(issues []ValidationIssue, issueType, text string) bool {
+	for _, issue := range issues {
+		if issue.Type == issueType && strings.Contains(issue.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGenerator_ValidateOutput_GeneratedOutputIsValid(t *testing.T) {
+	validate := true
+	glossary := true
+	schema := true
+
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now(), Depth: 0},
+		{Title: "Getting Started!", URL: "https://example.com/start", Content: "Start content", Timestamp: time.Now(), Depth: 1},
+	}
+
+	for _, outputType := range []string{"single", "per-page", "per-depth"} {
+		t.Run(outputType, func(t *testing.T) {
+			cfg := &config.Config{
+				RootURL:            "https://example.com",
+				OutputDir:          t.TempDir(),
+				OutputFormat:       "markdown",
+				OutputFormats:      []string{"markdown", "text", "json"},
+				OutputType:         outputType,
+				GenerateGlossary:   &glossary,
+				GenerateJSONSchema: &schema,
+				ValidateOutput:     &validate,
+			}
+			if err := New(cfg, pages).Generate(); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			hierarchicalCfg := *cfg
+			hierarchicalCfg.OutputDir = t.TempDir()
+			if err := NewHierarchical(&hierarchicalCfg, hierarchicalTestPages()).Generate(); err != nil {
+				t.Fatalf("Hierarchical Generate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateOutput_DanglingLink(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "markdown",
+		OutputType:   "per-page",
+	}
+	pages := []PageData{
+		{Title: "Home", URL: "https://example.com/", Content: "Home content", Timestamp: time.Now()},
+		{Title: "Guide", URL: "https://example.com/guide", Content: "Guide content", Timestamp: time.Now()},
+	}
+	if err := New(cfg, pages).Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if issues := ValidateOutput(tmpDir); len(issues) != 0 {
+		t.Fatalf("Expected no issues before corruption, got %v", issues)
+	}
+
+	indexFile := filepath.Join(tmpDir, "index.md")
+	index, err := os.ReadFile(indexFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := strings.Replace(string(index), "(page_002.md)", "(page_999.md)", 1)
+	if err := os.WriteFile(indexFile, []byte(corrupted), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := ValidateOutput(tmpDir)
+	if !hasIssue(issues, "dangling_link", "page_999.md") {
+		t.Errorf("Expected dangling_link issue for page_999.md, got %v", issues)
+	}
+	if err := validationError(issues); err == nil {
+		t.Error("validationError() should fail on a dangling link")
+	}
+}
+
+func TestValidateOutput_Issues(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"empty.md":       "",
+		"broken.json":    `{"pages": [`,
+		"front.md":       "---\ntitle: [unclosed\n---\n\n# Front\n",
+		"anchors.md":     "# Intro\n\n## Usage {#how-to}\n\n- [Intro](#intro)\n- [Usage](#how-to)\n- [Missing](#nowhere)\n- [Site](https://example.com/#nowhere)\n- [Fenced](#fenced)\n\n```sh\n# Fenced\n```\n",
+		"sub/linked.md":  "[Back](../anchors.md#intro) [Up](../anchors.md#missing)\n",
+		"valid.json":     `{"ok": true}`,
+		"front-good.md":  "---\ntitle: Good\n---\n\n# Good\n",
+		"sub/plain.txt":  "text",
+		"sub/assets.yml": "a: 1",
+		"code.md":        "# Code\n\n```md\n[Example](missing.md)\n```\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := ValidateOutput(tmpDir)
+
+	expected := []struct{ issueType, text string }{
+		{"empty_file", "empty.md"},
+		{"invalid_json", "broken.json"},
+		{"invalid_front_matter", "front.md"},
+		{"dangling_anchor", "#nowhere"},
+		{"dangling_anchor", "#fenced"},
+		{"dangling_anchor", "../anchors.md#missing"},
+	}
+	for _, want := range expected {
+		if !hasIssue(issues, want.issueType, want.text) {
+			t.Errorf("Expected %s issue mentioning %q, got %v", want.issueType, want.text, issues)
+		}
+	}
+	if len(issues) != len(expected) {
+		t.Errorf("Expected %d issues, got %d: %v", len(expected), len(issues), issues)
+	}
+}
@@ -0,0 +1,180 @@
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// warcVersion is the WARC specification version written in each record
+const warcVersion = "WARC/1.1"
+
+// WARCResponse is a raw request/response exchange recorded into WARC output
+type WARCResponse struct {
+	URL             string
+	Method          string
+	RequestHeaders  http.Header
+	StatusCode      int
+	ResponseHeaders http.Header
+	Body            []byte
+	FetchedAt       time.Time
+}
+
+// SetResponses provides the raw responses used by the "warc" output format
+func (g *Generator) SetResponses(responses []WARCResponse) {
+	g.responses = redactResponses(g.config, responses)
+}
+
+// generateWARCOutput writes every captured exchange to output.warc.gz, one gzip member per record
+func (g *Generator) generateWARCOutput() error {
+	filename := filepath.Join(g.config.OutputDir, "output.warc.gz")
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info := fmt.Sprintf("software: docscraper\r\nformat: WARC File Format 1.1\r\nroot-url: %s\r\n", g.config.RootURL)
+	if err := writeWARCRecord(file, map[string]string{
+		"WARC-Type":      "warcinfo",
+		"WARC-Record-ID": newWARCRecordID(),
+		"WARC-Date":      generatedAt(g.config),
+		"WARC-Filename":  "output.warc.gz",
+		"Content-Type":   "application/warc-fields",
+	}, []byte(info)); err != nil {
+		return err
+	}
+
+	for _, response := range g.responses {
+		responseID := newWARCRecordID()
+		date := warcDate(response.FetchedAt)
+
+		responseBlock, err := httpResponseBlock(response)
+		if err != nil {
+			return err
+		}
+		if err := writeWARCRecord(file, map[string]string{
+			"WARC-Type":           "response",
+			"WARC-Record-ID":      responseID,
+			"WARC-Date":           date,
+			"WARC-Target-URI":     response.URL,
+			"WARC-Payload-Digest": warcDigest(response.Body),
+			"Content-Type":        "application/http;msgtype=response",
+		}, responseBlock); err != nil {
+			return err
+		}
+
+		requestBlock, err := httpRequestBlock(response)
+		if err != nil {
+			return err
+		}
+		if err := writeWARCRecord(file, map[string]string{
+			"WARC-Type":          "request",
+			"WARC-Record-ID":     newWARCRecordID(),
+			"WARC-Date":          date,
+			"WARC-Target-URI":    response.URL,
+			"WARC-Concurrent-To": responseID,
+			"Content-Type":       "application/http;msgtype=request",
+		}, requestBlock); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeWARCRecord writes a single record as its own gzip member
+func writeWARCRecord(file *os.File, headers map[string]string, block []byte) error {
+	var record bytes.Buffer
+	record.WriteString(warcVersion + "\r\n")
+
+	// WARC-Type and WARC-Record-ID lead, remaining fields follow in a stable order
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		if name != "WARC-Type" && name != "WARC-Record-ID" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{"WARC-Type", "WARC-Record-ID"}, names...)
+
+	for _, name := range names {
+		fmt.Fprintf(&record, "%s: %s\r\n", name, headers[name])
+	}
+	fmt.Fprintf(&record, "WARC-Block-Digest: %s\r\n", warcDigest(block))
+	fmt.Fprintf(&record, "Content-Length: %d\r\n\r\n", len(block))
+	record.Write(block)
+	record.WriteString("\r\n\r\n")
+
+	gz := gzip.NewWriter(file)
+	if _, err := gz.Write(record.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// httpResponseBlock renders the status line, headers and body of a response
+func httpResponseBlock(response WARCResponse) ([]byte, error) {
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "HTTP/1.1 %d %s\r\n", response.StatusCode, http.StatusText(response.StatusCode))
+	if err := response.ResponseHeaders.Write(&block); err != nil {
+		return nil, err
+	}
+	block.WriteString("\r\n")
+	block.Write(response.Body)
+	return block.Bytes(), nil
+}
+
+// httpRequestBlock renders the request line and headers of a request
+func httpRequestBlock(response WARCResponse) ([]byte, error) {
+	target, err := url.Parse(response.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	method := response.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var block bytes.Buffer
+	fmt.Fprintf(&block, "%s %s HTTP/1.1\r\n", method, target.RequestURI())
+	fmt.Fprintf(&block, "Host: %s\r\n", target.Host)
+	if err := response.RequestHeaders.Write(&block); err != nil {
+		return nil, err
+	}
+	block.WriteString("\r\n")
+	return block.Bytes(), nil
+}
+
+// warcDigest returns the base32 SHA-1 digest used by WARC digest fields
+func warcDigest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// warcDate formats a timestamp as required by WARC-Date
+func warcDate(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// newWARCRecordID returns a random UUID URN for WARC-Record-ID
+func newWARCRecordID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,169 @@
+package output
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// warcTestRecord is a parsed WARC record
+type warcTestRecord struct {
+	Version string
+	Headers textproto.MIMEHeader
+	Block   []byte
+}
+
+// readWARCRecords parses every record from a gzip-compressed WARC file
+func readWARCRecords(t *testing.T, filename string) []warcTestRecord {
+	t.Helper()
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(gz)
+
+	var records []warcTestRecord
+	for {
+		version, err := reader.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+		if err != nil {
+			t.Fatalf("Failed to read record headers: %v", err)
+		}
+
+		length, err := strconv.Atoi(headers.Get("Content-Length"))
+		if err != nil {
+			t.Fatalf("Invalid Content-Length: %v", err)
+		}
+
+		block := make([]byte, length)
+		if _, err := io.ReadFull(reader, block); err != nil {
+			t.Fatal(err)
+		}
+
+		trailer := make([]byte, 4)
+		if _, err := io.ReadFull(reader, trailer); err != nil || string(trailer) != "\r\n\r\n" {
+			t.Fatalf("Record not terminated by CRLF CRLF: %q", trailer)
+		}
+
+		records = append(records, warcTestRecord{Version: strings.TrimSpace(version), Headers: headers, Block: block})
+	}
+
+	return records
+}
+
+func TestGenerator_Generate_WARC(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "test-output-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &config.Config{
+		RootURL:      "https://example.com",
+		OutputDir:    tmpDir,
+		OutputFormat: "warc",
+		OutputType:   "single",
+	}
+
+	responses := []WARCResponse{
+		{
+			URL:             "https://example.com/",
+			Method:          "GET",
+			RequestHeaders:  http.Header{"User-Agent": {"docscraper"}},
+			StatusCode:      200,
+			ResponseHeaders: http.Header{"Content-Type": {"text/html"}},
+			Body:            []byte("<html><body>Home</body></html>"),
+			FetchedAt:       time.Now(),
+		},
+		{
+			URL:             "https://example.com/docs?page=2",
+			Method:          "GET",
+			StatusCode:      404,
+			ResponseHeaders: http.Header{"Content-Type": {"text/plain"}},
+			Body:            []byte("not found"),
+			FetchedAt:       time.Now(),
+		},
+	}
+
+	generator := New(cfg, nil)
+	generator.SetResponses(responses)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	records := readWARCRecords(t, filepath.Join(tmpDir, "output.warc.gz"))
+	if len(records) != 5 {
+		t.Fatalf("Expected warcinfo plus request/response pairs (5 records), got %d", len(records))
+	}
+
+	for _, record := range records {
+		if record.Version != "WARC/1.1" {
+			t.Errorf("Unexpected record version %q", record.Version)
+		}
+		for _, field := range []string{"WARC-Type", "WARC-Record-ID", "WARC-Date", "WARC-Block-Digest"} {
+			if record.Headers.Get(field) == "" {
+				t.Errorf("Record missing mandatory field %s", field)
+			}
+		}
+		if record.Headers.Get("WARC-Block-Digest") != warcDigest(record.Block) {
+			t.Error("WARC-Block-Digest does not match the record block")
+		}
+	}
+
+	if records[0].Headers.Get("WARC-Type") != "warcinfo" {
+		t.Errorf("First record should be warcinfo, got %s", records[0].Headers.Get("WARC-Type"))
+	}
+
+	response, request := records[1], records[2]
+	if response.Headers.Get("WARC-Type") != "response" || request.Headers.Get("WARC-Type") != "request" {
+		t.Fatalf("Expected response then request records, got %s and %s",
+			response.Headers.Get("WARC-Type"), request.Headers.Get("WARC-Type"))
+	}
+	if response.Headers.Get("WARC-Target-URI") != "https://example.com/" {
+		t.Errorf("Unexpected target URI %s", response.Headers.Get("WARC-Target-URI"))
+	}
+	if request.Headers.Get("WARC-Concurrent-To") != response.Headers.Get("WARC-Record-ID") {
+		t.Error("Request record should reference its response record")
+	}
+
+	httpResponse, err := http.ReadResponse(bufio.NewReader(strings.NewReader(string(response.Block))), nil)
+	if err != nil {
+		t.Fatalf("Response block is not a valid HTTP response: %v", err)
+	}
+	body, _ := io.ReadAll(httpResponse.Body)
+	if httpResponse.StatusCode != 200 || string(body) != "<html><body>Home</body></html>" {
+		t.Errorf("Unexpected response block: status %d body %q", httpResponse.StatusCode, body)
+	}
+
+	httpRequest, err := http.ReadRequest(bufio.NewReader(strings.NewReader(string(records[4].Block))))
+	if err != nil {
+		t.Fatalf("Request block is not a valid HTTP request: %v", err)
+	}
+	if httpRequest.URL.RequestURI() != "/docs?page=2" || httpRequest.Host != "example.com" {
+		t.Errorf("Unexpected request line: %s %s", httpRequest.Host, httpRequest.URL.RequestURI())
+	}
+}
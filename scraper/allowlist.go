@@ -0,0 +1,47 @@
+package scraper
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadURLAllowlist reads the URLs listed one per line in allowlistFile, keyed by urlMatchKey.
+// Blank lines and lines starting with # are ignored. A nil map means no allowlist.
+func loadURLAllowlist(allowlistFile string) (map[string]bool, error) {
+	if allowlistFile == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(allowlistFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open url allowlist file: %v", err)
+	}
+	defer file.Close()
+
+	allowed := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		allowed[urlMatchKey(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read url allowlist file: %v", err)
+	}
+
+	return allowed, nil
+}
+
+// isAllowlisted reports whether rawURL may be visited under url_allowlist_file; the root URL
+// is always allowed so the crawl can discover links from it
+func (s *Scraper) isAllowlisted(rawURL string) bool {
+	if s.allowlist == nil {
+		return true
+	}
+	key := urlMatchKey(rawURL)
+	return s.allowlist[key] || key == urlMatchKey(s.config.RootURL)
+}
@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_URLAllowlistFile(t *testing.T) {
+	var mu sync.Mutex
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched = append(fetched, r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, `<main><p>Documentation content for this page.</p>
+			<a href="/listed">Listed</a><a href="/unlisted">Unlisted</a><a href="/listed/child">Child</a></main>`))
+	}))
+	defer server.Close()
+
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist.txt")
+	allowlist := fmt.Sprintf("# pages to scrape\n%s/listed/\n\n%s/listed/child\n", server.URL, server.URL)
+	if err := os.WriteFile(allowlistFile, []byte(allowlist), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	recordFrontier := true
+	s := newTestScraper(t, &config.Config{
+		RootURL:          server.URL + "/",
+		MaxDepth:         3,
+		URLAllowlistFile: allowlistFile,
+		RecordFrontier:   &recordFrontier,
+		OutputDir:        t.TempDir(),
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	sort.Strings(fetched)
+	want := []string{"/", "/listed", "/listed/child"}
+	if fmt.Sprint(fetched) != fmt.Sprint(want) {
+		t.Errorf("Expected only allowlisted URLs fetched %v, got %v", want, fetched)
+	}
+
+	rejected := s.rejected[server.URL+"/unlisted"]
+	if rejected.Reason != RejectNotAllowlisted {
+		t.Errorf("Expected /unlisted recorded as %q, got %+v", RejectNotAllowlisted, rejected)
+	}
+}
+
+func TestLoadURLAllowlist_MissingFile(t *testing.T) {
+	if _, err := loadURLAllowlist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for a missing allowlist file")
+	}
+}
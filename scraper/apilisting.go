@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxAPIListingPages bounds how many pages of a paginated listing are followed
+const maxAPIListingPages = 100
+
+// LoadAPIListing fetches a JSON listing endpoint, extracts page URLs from the configured
+// JSON path, follows pagination via the configured next-page path, and enqueues the URLs
+func (s *Scraper) LoadAPIListing(listingURL string) ([]string, error) {
+	var urls []string
+	seen := make(map[string]bool)
+	nextURL := listingURL
+
+	for page := 0; nextURL != "" && page < maxAPIListingPages; page++ {
+		if seen[nextURL] {
+			break
+		}
+		seen[nextURL] = true
+
+		base, err := url.Parse(nextURL)
+		if err != nil {
+			return urls, fmt.Errorf("invalid listing URL %s: %v", nextURL, err)
+		}
+
+		data, err := s.fetchJSON(nextURL)
+		if err != nil {
+			return urls, err
+		}
+
+		for _, value := range extractJSONPath(data, s.config.APIListingPath) {
+			link, ok := value.(string)
+			if !ok || link == "" {
+				continue
+			}
+			linkURL, err := url.Parse(link)
+			if err != nil {
+				s.logger.Printf("Skipping invalid listing URL %q: %v", link, err)
+				continue
+			}
+			urls = append(urls, base.ResolveReference(linkURL).String())
+		}
+
+		nextURL = ""
+		if s.config.APIListingNextPath != "" {
+			for _, value := range extractJSONPath(data, s.config.APIListingNextPath) {
+				if next, ok := value.(string); ok && next != "" {
+					if nextParsed, err := url.Parse(next); err == nil {
+						nextURL = base.ResolveReference(nextParsed).String()
+					}
+					break
+				}
+			}
+		}
+	}
+
+	s.logger.Printf("Loaded %d URLs from API listing %s", len(urls), listingURL)
+
+	for _, link := range urls {
+		if err := s.collector.Visit(link); err != nil {
+			s.logger.Printf("Could not enqueue listing URL %s: %v", link, err)
+		}
+	}
+
+	return urls, nil
+}
+
+// fetchJSON fetches and decodes a JSON document
+func (s *Scraper) fetchJSON(rawURL string) (interface{}, error) {
+	resp, err := s.fetch(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch listing %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing %s returned status %d", rawURL, resp.StatusCode)
+	}
+
+	var data interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse listing %s: %v", rawURL, err)
+	}
+
+	return data, nil
+}
+
+// extractJSONPath returns all values matching a dot-separated path. A "*" segment
+// matches every element of an array or object, and numeric segments index arrays.
+// An empty path returns the document itself (or its elements, if it is an array).
+func extractJSONPath(data interface{}, path string) []interface{} {
+	current := []interface{}{data}
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			var next []interface{}
+			for _, value := range current {
+				next = append(next, jsonPathStep(value, segment)...)
+			}
+			current = next
+		}
+	}
+
+	// A path ending at an array yields its elements
+	var results []interface{}
+	for _, value := range current {
+		if arr, ok := value.([]interface{}); ok {
+			results = append(results, arr...)
+		} else {
+			results = append(results, value)
+		}
+	}
+
+	return results
+}
+
+// jsonPathStep applies a single path segment to a value
+func jsonPathStep(value interface{}, segment string) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if segment == "*" {
+			values := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				values = append(values, child)
+			}
+			return values
+		}
+		if child, ok := v[segment]; ok {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if segment == "*" {
+			return v
+		}
+		if idx, err := strconv.Atoi(segment); err == nil && idx >= 0 && idx < len(v) {
+			return []interface{}{v[idx]}
+		}
+		// Apply the segment to every element, so "items.url" works like "items.*.url"
+		var values []interface{}
+		for _, child := range v {
+			values = append(values, jsonPathStep(child, segment)...)
+		}
+		return values
+	}
+
+	return nil
+}
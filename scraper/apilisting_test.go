@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"testing"
+
+	"docscraper/config"
+)
+
+// newTestScraper creates a scraper for cfg logging to a temporary file
+func newTestScraper(t *testing.T, cfg *config.Config) *Scraper {
+	t.Helper()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	t.Cleanup(func() { os.Remove(logFile.Name()) })
+
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "markdown"
+	}
+	if cfg.OutputType == "" {
+		cfg.OutputType = "single"
+	}
+	cfg.LogFile = logFile.Name()
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// htmlPage renders a minimal HTML document with the given title and body
+func htmlPage(title, body string) string {
+	return fmt.Sprintf("<html><head><title>%s</title></head><body><main>%s</main></body></html>", title, body)
+}
+
+func TestScraper_LoadAPIListing(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/pages", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"items": []map[string]string{{"url": "/docs/c"}}},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":  map[string]interface{}{"items": []map[string]string{{"url": "/docs/a"}, {"url": "/docs/b"}}},
+			"links": map[string]string{"next": "/api/pages?page=2"},
+		})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "Content for "+r.URL.Path))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL + "/",
+		MaxDepth:           2,
+		APIListingURL:      server.URL + "/api/pages",
+		APIListingPath:     "data.items.*.url",
+		APIListingNextPath: "links.next",
+	})
+
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	var scraped []string
+	for _, page := range s.GetPages() {
+		scraped = append(scraped, page.URL)
+	}
+	sort.Strings(scraped)
+
+	expected := []string{server.URL + "/", server.URL + "/docs/a", server.URL + "/docs/b", server.URL + "/docs/c"}
+	if fmt.Sprint(scraped) != fmt.Sprint(expected) {
+		t.Errorf("Scraped URLs = %v, want %v", scraped, expected)
+	}
+}
+
+func TestExtractJSONPath(t *testing.T) {
+	var data interface{}
+	doc := `{"items": [{"url": "/a"}, {"url": "/b"}], "nested": {"list": ["/c", "/d"]}}`
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"items.*.url", []string{"/a", "/b"}},
+		{"items.url", []string{"/a", "/b"}},
+		{"items.1.url", []string{"/b"}},
+		{"nested.list", []string{"/c", "/d"}},
+		{"missing", nil},
+	}
+
+	for _, tt := range tests {
+		var result []string
+		for _, value := range extractJSONPath(data, tt.path) {
+			result = append(result, value.(string))
+		}
+		if fmt.Sprint(result) != fmt.Sprint(tt.expected) {
+			t.Errorf("extractJSONPath(%q) = %v, want %v", tt.path, result, tt.expected)
+		}
+	}
+}
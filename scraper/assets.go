@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// isDownloadAsset reports whether u ends in one of the download_extensions
+func (s *Scraper) isDownloadAsset(u *url.URL) bool {
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext == "" {
+		return false
+	}
+	for _, want := range s.config.DownloadExtensions {
+		want = strings.ToLower(strings.TrimSpace(want))
+		if !strings.HasPrefix(want, ".") {
+			want = "." + want
+		}
+		if ext == want {
+			return true
+		}
+	}
+	return false
+}
+
+// assetPath returns where an asset is saved: its URL path under the asset directory
+func (s *Scraper) assetPath(u *url.URL) string {
+	return filepath.Join(s.config.GetAssetDir(), filepath.FromSlash(path.Clean("/"+u.Path)))
+}
+
+// saveAsset writes a downloaded asset to the asset directory and records where it went
+func (s *Scraper) saveAsset(r *colly.Response) {
+	assetURL := r.Request.URL.String()
+	filename := s.assetPath(r.Request.URL)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		s.logger.Printf("Could not create asset directory for %s: %v", assetURL, err)
+		return
+	}
+
+	// colly cuts bodies off at MaxBodySize, so an asset that reached it is downloaded again in full
+	if s.collector.MaxBodySize > 0 && len(r.Body) >= s.collector.MaxBodySize {
+		if err := s.downloadAsset(assetURL, filename); err != nil {
+			s.logger.Printf("Could not download asset %s in full: %v", assetURL, err)
+			os.Remove(filename)
+			s.recordFailedURL(assetURL)
+			return
+		}
+	} else if err := os.WriteFile(filename, r.Body, 0644); err != nil {
+		s.logger.Printf("Could not save asset %s: %v", assetURL, err)
+		return
+	}
+
+	s.assetsMutex.Lock()
+	if s.assets == nil {
+		s.assets = make(map[string]string)
+	}
+	s.assets[assetURL] = filename
+	s.assetsMutex.Unlock()
+	s.logger.Printf("Downloaded asset %s to %s", assetURL, filename)
+}
+
+// downloadAsset streams an asset to filename without a body size limit
+func (s *Scraper) downloadAsset(assetURL, filename string) error {
+	resp, err := s.fetch(assetURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(file, resp.Body)
+	s.addDownloadedBytes(int(n))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// GetDownloadedAssets returns the saved path of each downloaded asset, keyed by URL
+func (s *Scraper) GetDownloadedAssets() map[string]string {
+	s.assetsMutex.Lock()
+	defer s.assetsMutex.Unlock()
+
+	assets := make(map[string]string, len(s.assets))
+	for assetURL, filename := range s.assets {
+		assets[assetURL] = filename
+	}
+	return assets
+}
@@ -0,0 +1,98 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_DownloadExtensions(t *testing.T) {
+	pdf := []byte("%PDF-1.4 small test document")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, htmlPage("Home", `<main><p>Download the manual or view the diagram.</p>
+				<a href="/files/manual.PDF">Manual</a><a href="/images/diagram.png">Diagram</a></main>`))
+		case "/files/manual.PDF":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(pdf)
+		case "/images/diagram.png":
+			t.Errorf("Unexpected request for a non-listed extension: %s", r.URL.Path)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	assetDir := t.TempDir()
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL + "/",
+		MaxDepth:           1,
+		DownloadExtensions: []string{"pdf"},
+		AssetDir:           assetDir,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	want := filepath.Join(assetDir, "files", "manual.PDF")
+	saved, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatalf("Expected asset at %s: %v", want, err)
+	}
+	if string(saved) != string(pdf) {
+		t.Errorf("Expected saved asset %q, got %q", pdf, saved)
+	}
+
+	assets := s.GetDownloadedAssets()
+	if len(assets) != 1 || assets[server.URL+"/files/manual.PDF"] != want {
+		t.Errorf("Expected asset mapping to %s, got %v", want, assets)
+	}
+	if len(s.GetPages()) != 1 {
+		t.Errorf("Expected the asset not to be stored as a page, got %d pages", len(s.GetPages()))
+	}
+}
+
+func TestScraper_DownloadsAssetsPastMaxBodySize(t *testing.T) {
+	pdf := []byte("%PDF-1.4 " + strings.Repeat("x", 4096))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, htmlPage("Home", `<main><p>Download the manual.</p><a href="/manual.pdf">Manual</a></main>`))
+		case "/manual.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(pdf)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	assetDir := t.TempDir()
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL + "/",
+		MaxDepth:           1,
+		DownloadExtensions: []string{"pdf"},
+		AssetDir:           assetDir,
+	})
+	s.collector.MaxBodySize = 1024
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(assetDir, "manual.pdf"))
+	if err != nil {
+		t.Fatalf("Expected the asset to be saved: %v", err)
+	}
+	if string(saved) != string(pdf) {
+		t.Errorf("Expected the asset saved in full (%d bytes), got %d bytes", len(pdf), len(saved))
+	}
+}
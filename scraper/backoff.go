@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff strategies for retry delays
+const (
+	BackoffExponential = "exponential"  // base * 2^attempt
+	BackoffFullJitter  = "full-jitter"  // random between 0 and the exponential delay
+	BackoffEqualJitter = "equal-jitter" // half the exponential delay plus a random half
+	BackoffConstant    = "constant"     // always base
+)
+
+// DefaultMaxBackoff caps computed retry delays
+const DefaultMaxBackoff = 60 * time.Second
+
+// Backoff computes retry delays according to a strategy
+type Backoff struct {
+	strategy string
+	base     time.Duration
+	max      time.Duration
+	rng      *rand.Rand
+	mutex    sync.Mutex
+}
+
+// NewBackoff creates a backoff calculator; an unknown strategy falls back to exponential
+func NewBackoff(strategy string, base, max time.Duration) *Backoff {
+	switch strategy {
+	case BackoffExponential, BackoffFullJitter, BackoffEqualJitter, BackoffConstant:
+	default:
+		strategy = BackoffExponential
+	}
+
+	return &Backoff{
+		strategy: strategy,
+		base:     base,
+		max:      max,
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Delay returns the delay before retry number attempt (starting at 0)
+func (b *Backoff) Delay(attempt int) time.Duration {
+	if b.strategy == BackoffConstant {
+		return b.capped(b.base)
+	}
+
+	exponential := b.exponential(attempt)
+
+	switch b.strategy {
+	case BackoffFullJitter:
+		return b.jitter(exponential)
+	case BackoffEqualJitter:
+		half := exponential / 2
+		return half + b.jitter(exponential-half)
+	default:
+		return exponential
+	}
+}
+
+// exponential returns min(max, base * 2^attempt) without overflowing
+func (b *Backoff) exponential(attempt int) time.Duration {
+	delay := b.base
+	for i := 0; i < attempt; i++ {
+		if b.max > 0 && delay >= b.max/2 {
+			return b.max
+		}
+		delay *= 2
+	}
+	return b.capped(delay)
+}
+
+// jitter returns a random duration in [0, limit]
+func (b *Backoff) jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return time.Duration(b.rng.Int63n(int64(limit) + 1))
+}
+
+// capped limits a delay to the configured maximum
+func (b *Backoff) capped(delay time.Duration) time.Duration {
+	if b.max > 0 && delay > b.max {
+		return b.max
+	}
+	return delay
+}
@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := 2 * time.Second
+
+	tests := []struct {
+		strategy string
+		attempt  int
+		min      time.Duration
+		max      time.Duration
+	}{
+		{BackoffExponential, 0, 100 * time.Millisecond, 100 * time.Millisecond},
+		{BackoffExponential, 3, 800 * time.Millisecond, 800 * time.Millisecond},
+		{BackoffExponential, 10, max, max},
+		{BackoffFullJitter, 0, 0, 100 * time.Millisecond},
+		{BackoffFullJitter, 3, 0, 800 * time.Millisecond},
+		{BackoffFullJitter, 10, 0, max},
+		{BackoffEqualJitter, 0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{BackoffEqualJitter, 3, 400 * time.Millisecond, 800 * time.Millisecond},
+		{BackoffEqualJitter, 10, max / 2, max},
+		{BackoffConstant, 0, base, base},
+		{BackoffConstant, 10, base, base},
+	}
+
+	for _, tt := range tests {
+		backoff := NewBackoff(tt.strategy, base, max)
+
+		var sum time.Duration
+		samples := 2000
+		for i := 0; i < samples; i++ {
+			delay := backoff.Delay(tt.attempt)
+			if delay < tt.min || delay > tt.max {
+				t.Fatalf("%s attempt %d: delay %v outside [%v, %v]", tt.strategy, tt.attempt, delay, tt.min, tt.max)
+			}
+			sum += delay
+		}
+
+		// Jittered strategies should spread around the midpoint of their range
+		if tt.min != tt.max {
+			mean := sum / time.Duration(samples)
+			midpoint := (tt.min + tt.max) / 2
+			tolerance := (tt.max - tt.min) / 10
+			if mean < midpoint-tolerance || mean > midpoint+tolerance {
+				t.Errorf("%s attempt %d: mean delay %v not near midpoint %v", tt.strategy, tt.attempt, mean, midpoint)
+			}
+		}
+	}
+}
+
+func TestNewBackoff_UnknownStrategy(t *testing.T) {
+	backoff := NewBackoff("bogus", time.Second, 10*time.Second)
+	if delay := backoff.Delay(2); delay != 4*time.Second {
+		t.Errorf("Unknown strategy should fall back to exponential, got %v", delay)
+	}
+}
@@ -0,0 +1,50 @@
+package scraper
+
+import "sync/atomic"
+
+// addDownloadedBytes counts a response body toward max_download_bytes, logging when the
+// budget is first exceeded
+func (s *Scraper) addDownloadedBytes(n int) {
+	total := atomic.AddInt64(&s.downloadedBytes, int64(n))
+	limit := int64(s.config.GetMaxDownloadBytes())
+	if limit > 0 && total > limit && total-int64(n) <= limit {
+		s.logger.Printf("Download budget of %d bytes exceeded after %d bytes, stopping the crawl", limit, total)
+	}
+}
+
+// overByteBudget reports whether downloads have exceeded max_download_bytes
+func (s *Scraper) overByteBudget() bool {
+	limit := s.config.GetMaxDownloadBytes()
+	return limit > 0 && atomic.LoadInt64(&s.downloadedBytes) > int64(limit)
+}
+
+// GetDownloadedBytes returns the total size of response bodies received so far
+func (s *Scraper) GetDownloadedBytes() int64 {
+	return atomic.LoadInt64(&s.downloadedBytes)
+}
+
+// reservePage claims a slot under max_pages for a page about to be stored, logging when the
+// limit is first reached; it reports false once every slot is taken
+func (s *Scraper) reservePage() bool {
+	limit := int64(s.config.GetMaxPages())
+	count := atomic.AddInt64(&s.reservedPages, 1)
+	if limit <= 0 || count < limit {
+		return true
+	}
+	if count == limit {
+		s.logger.Printf("Page limit of %d reached, stopping the crawl", limit)
+		return true
+	}
+	return false
+}
+
+// releasePage gives back a slot claimed by reservePage for a page that was not stored
+func (s *Scraper) releasePage() {
+	atomic.AddInt64(&s.reservedPages, -1)
+}
+
+// overPageLimit reports whether max_pages pages have been stored
+func (s *Scraper) overPageLimit() bool {
+	limit := s.config.GetMaxPages()
+	return limit > 0 && atomic.LoadInt64(&s.reservedPages) >= int64(limit)
+}
@@ -0,0 +1,106 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_MaxDownloadBytes(t *testing.T) {
+	// A chain of equally sized pages, each linking only to the next
+	page := func(n int) string {
+		return htmlPage("Page "+strconv.Itoa(n), fmt.Sprintf(`<main><p>Chapter %03d of the guide.</p><a href="/page/%03d">Next</a></main>`, n, n+1))
+	}
+	pageSize := len(page(1))
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/page/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page(n))
+	}))
+	defer server.Close()
+
+	// Two pages stay within the budget; the third exceeds it and is the last one fetched
+	budget := pageSize*2 + pageSize/2
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/page/001", MaxDepth: 20, MaxDownloadBytes: &budget})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(requested) != 3 {
+		t.Errorf("Expected 3 requests before the budget stopped the crawl, got %v", requested)
+	}
+	if got := len(s.GetPages()); got != 3 {
+		t.Errorf("Expected 3 pages kept as partial results, got %d", got)
+	}
+	if got := s.GetDownloadedBytes(); got != int64(pageSize*3) {
+		t.Errorf("Expected %d downloaded bytes, got %d", pageSize*3, got)
+	}
+}
+
+func TestScraper_MaxPages(t *testing.T) {
+	// A hub linking to more pages than the limit allows
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			var links strings.Builder
+			for i := 1; i <= 10; i++ {
+				fmt.Fprintf(&links, `<a href="/page/%d">Page %d</a> `, i, i)
+			}
+			fmt.Fprint(w, htmlPage("Hub", "<p>Index of every page in the guide.</p>"+links.String()))
+			return
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "<p>Chapter "+r.URL.Path+" of the guide explains one topic.</p>"))
+	}))
+	defer server.Close()
+
+	maxPages := 4
+	enableQuality := true
+	tests := []struct {
+		name    string
+		scrape  func(cfg *config.Config) ([]PageData, error)
+		quality *bool
+	}{
+		{"base", func(cfg *config.Config) ([]PageData, error) {
+			s := newTestScraper(t, cfg)
+			err := s.Scrape()
+			return s.GetPages(), err
+		}, nil},
+		{"quality analysis", func(cfg *config.Config) ([]PageData, error) {
+			es, err := NewWithFeatures(newTestScraper(t, cfg).config)
+			if err != nil {
+				return nil, err
+			}
+			return es.ScrapeWithFeatures()
+		}, &enableQuality},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := tt.scrape(&config.Config{
+				RootURL:               server.URL + "/",
+				MaxDepth:              2,
+				MaxPages:              &maxPages,
+				EnableQualityAnalysis: tt.quality,
+				QualityAnalysis:       config.QualityConfig{MinScore: 0.01, MinWordCount: 1},
+			})
+			if err != nil {
+				t.Fatalf("scrape error = %v", err)
+			}
+			if len(pages) != maxPages {
+				t.Errorf("Expected exactly %d pages, got %d", maxPages, len(pages))
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package scraper
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// fencedCodeBlock renders a <pre> element as a fenced markdown block, keeping its indentation
+// and tagging it with the language from a "language-xxx" class on the <code> or <pre>
+func fencedCodeBlock(pre *html.Node) string {
+	code := strings.TrimRight(strings.TrimLeft(nodeText(pre), "\r\n"), " \t\r\n")
+	if strings.TrimSpace(code) == "" {
+		return ""
+	}
+
+	// A fence must be longer than any backtick run inside the block
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + codeLanguage(pre) + "\n" + code + "\n" + fence
+}
+
+// markdownFence is a fenced code block found in markdown text
+type markdownFence struct {
+	language string
+	code     string
+}
+
+// splitFencedBlocks returns the backtick-fenced code blocks of text and the text outside them.
+// A block only closes on a line of backticks at least as long as its opening fence, so a longer
+// fence can hold shorter ones; an unclosed fence is left as text.
+func splitFencedBlocks(text string) ([]markdownFence, string) {
+	var blocks []markdownFence
+	var outside []string
+
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		fence := trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, "`"))]
+		if len(fence) < 3 {
+			outside = append(outside, lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !closesFence(lines[end], fence) {
+			end++
+		}
+		if end == len(lines) {
+			outside = append(outside, lines[i])
+			continue
+		}
+
+		block := markdownFence{language: strings.TrimPrefix(trimmed, fence)}
+		if fields := strings.Fields(block.language); len(fields) > 0 {
+			block.language = fields[0]
+		}
+		if end > i+1 {
+			block.code = strings.Join(lines[i+1:end], "\n") + "\n"
+		}
+		blocks = append(blocks, block)
+		i = end
+	}
+	return blocks, strings.Join(outside, "\n")
+}
+
+// closesFence reports whether line is a closing fence for an opening fence of backticks
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, "`") == ""
+}
+
+// codeLanguage returns the language named by a "language-xxx" class on pre or its <code> child
+func codeLanguage(pre *html.Node) string {
+	candidates := []*html.Node{pre}
+	for child := pre.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "code" {
+			candidates = append([]*html.Node{child}, candidates...)
+			break
+		}
+	}
+
+	for _, node := range candidates {
+		for _, class := range strings.Fields(attrValue(node, "class")) {
+			if lang := strings.TrimPrefix(class, "language-"); lang != class && lang != "" {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// nodeText returns the concatenated text of node and its descendants
+func nodeText(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var text strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text.WriteString(nodeText(child))
+	}
+	return text.String()
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestContentExtractor_ExtractContent_CodeBlocks(t *testing.T) {
+	html := `<html><body><main>
+		<p>Create a   server:</p>
+		<pre><code class="hljs language-go">func main() {
+	http.ListenAndServe(":8080", nil)
+}
+</code></pre>
+		<p>Then run <code>go run .</code> to start it.</p>
+		<pre class="language-shell">$ curl localhost:8080</pre>
+		<pre><code>plain   text</code></pre>
+	</main></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	expected := "Create a server:\n\n" +
+		"```go\nfunc main() {\n\thttp.ListenAndServe(\":8080\", nil)\n}\n```\n\n" +
+		"Then run `go run .` to start it.\n\n" +
+		"```shell\n$ curl localhost:8080\n```\n\n" +
+		"```\nplain   text\n```"
+
+	extractor := NewContentExtractor()
+	result := extractor.ExtractContent(doc.Selection)
+	if result != expected {
+		t.Errorf("ExtractContent() = %q, want %q", result, expected)
+	}
+
+	// Callbacks share the parsed document, so extracting again must give the same result
+	if again := extractor.ExtractContent(doc.Selection); again != expected {
+		t.Errorf("Second ExtractContent() = %q, want %q", again, expected)
+	}
+
+	blocks := NewContentQualityAnalyzer(QualityConfig{}).ExtractCodeBlocks(result)
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 code blocks, got %d: %+v", len(blocks), blocks)
+	}
+	if blocks[0].Language != "go" || !strings.Contains(blocks[0].Content, "ListenAndServe") {
+		t.Errorf("Expected go block, got %+v", blocks[0])
+	}
+	if blocks[1].Language != "shell" {
+		t.Errorf("Expected shell language from the pre class, got %q", blocks[1].Language)
+	}
+}
+
+func TestFencedCodeBlock_LongerFence(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<pre><code class=\"language-markdown\">```go\nx := 1\n```</code></pre>"))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	expected := "````markdown\n```go\nx := 1\n```\n````"
+	if result := fencedCodeBlock(doc.Find("pre").Nodes[0]); result != expected {
+		t.Errorf("fencedCodeBlock() = %q, want %q", result, expected)
+	}
+}
+
+func TestSplitFencedBlocks_LongerFence(t *testing.T) {
+	content := "Before\n````markdown\n```go\nx := 1\n```\n````\nAfter `one` and ```"
+
+	blocks, outside := splitFencedBlocks(content)
+	if len(blocks) != 1 {
+		t.Fatalf("Expected the inner fence to stay inside one block, got %+v", blocks)
+	}
+	if blocks[0].language != "markdown" || blocks[0].code != "```go\nx := 1\n```\n" {
+		t.Errorf("Unexpected block %+v", blocks[0])
+	}
+	// The trailing fence is never closed, so it stays text
+	if outside != "Before\nAfter `one` and ```" {
+		t.Errorf("Unexpected text outside blocks %q", outside)
+	}
+
+	if count := NewContentQualityAnalyzer(QualityConfig{}).countCodeBlocks(content); count != 1 {
+		t.Errorf("countCodeBlocks() = %d, want 1", count)
+	}
+}
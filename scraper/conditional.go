@@ -0,0 +1,189 @@
+package scraper
+
+import (
+	"net/http"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// ConditionalInfo holds a page's validators from an earlier crawl and what to reuse when the
+// server reports the page unchanged
+type ConditionalInfo struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"` // Last-Modified header, sent back verbatim
+	Page         *PageData `json:"-"`                       // Page stored from the response, reused on 304 Not Modified
+	Stored       bool      `json:"stored,omitempty"`        // The page is in state_file's page store instead of Page
+	Links        []string  `json:"links,omitempty"`         // Links followed from the page, followed again on 304
+}
+
+// SetConditionalHeaders sets the validators sent as If-None-Match and If-Modified-Since, keyed
+// by page URL. A page answering 304 Not Modified is replaced by its saved Page, if any, and its
+// saved Links are followed as if it had been fetched.
+func (s *Scraper) SetConditionalHeaders(info map[string]ConditionalInfo) {
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+
+	s.conditional = make(map[string]ConditionalInfo, len(info))
+	for pageURL, entry := range info {
+		s.conditional[urlMatchKey(pageURL)] = entry
+	}
+}
+
+// GetConditionalHeaders returns the validators, pages and links recorded by this crawl under
+// conditional_requests, keyed by page URL, for SetConditionalHeaders on the next crawl
+func (s *Scraper) GetConditionalHeaders() map[string]ConditionalInfo {
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+
+	info := make(map[string]ConditionalInfo, len(s.validators))
+	for pageURL, entry := range s.validators {
+		info[pageURL] = entry
+	}
+	return info
+}
+
+// hasConditional reports whether an earlier crawl left validators for a URL
+func (s *Scraper) hasConditional(rawURL string) bool {
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+
+	_, exists := s.conditional[urlMatchKey(rawURL)]
+	return exists
+}
+
+// refetchKey is the request context key set when a URL's saved page is missing and it is fetched
+// again in full; followed links share their parent's context, so the key carries the URL
+func refetchKey(r *colly.Request) string {
+	return "conditional_refetch_" + r.URL.String()
+}
+
+// addConditionalHeaders makes a request conditional on the validators of an earlier crawl
+func (s *Scraper) addConditionalHeaders(r *colly.Request) {
+	if refetch, _ := r.Ctx.GetAny(refetchKey(r)).(bool); refetch {
+		return
+	}
+
+	s.conditionalMutex.Lock()
+	info, exists := s.conditional[urlMatchKey(r.URL.String())]
+	s.conditionalMutex.Unlock()
+	if !exists {
+		return
+	}
+
+	if info.ETag != "" {
+		r.Headers.Set("If-None-Match", info.ETag)
+	}
+	if info.LastModified != "" {
+		r.Headers.Set("If-Modified-Since", info.LastModified)
+	}
+}
+
+// recordValidators starts this crawl's conditional entry for a response carrying an ETag or
+// Last-Modified header
+func (s *Scraper) recordValidators(r *colly.Response) {
+	if !s.config.GetConditionalRequests() {
+		return
+	}
+	etag, lastModified := r.Headers.Get("ETag"), r.Headers.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+	if s.validators == nil {
+		s.validators = make(map[string]ConditionalInfo)
+	}
+	s.validators[r.Request.URL.String()] = ConditionalInfo{ETag: etag, LastModified: lastModified}
+}
+
+// recordConditionalPage notes that a stored page can be reused with its validators; with
+// state_file set the page itself is in the page store, otherwise it is kept with content inline
+// so it outlives spill_to_disk's temp files
+func (s *Scraper) recordConditionalPage(page PageData) {
+	if !s.config.GetConditionalRequests() {
+		return
+	}
+
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+	entry, exists := s.validators[page.URL]
+	if !exists {
+		return
+	}
+	if s.config.StateFile != "" {
+		entry.Stored = true
+	} else {
+		if content, err := page.LoadContent(); err == nil {
+			page.Content, page.ContentFile = content, ""
+		}
+		entry.Page = &page
+	}
+	s.validators[page.URL] = entry
+}
+
+// recordConditionalLink adds a link followed from a page to the page's conditional entry
+func (s *Scraper) recordConditionalLink(parent *colly.Request, link string) {
+	if !s.config.GetConditionalRequests() {
+		return
+	}
+
+	s.conditionalMutex.Lock()
+	defer s.conditionalMutex.Unlock()
+	entry, exists := s.validators[parent.URL.String()]
+	if !exists {
+		return
+	}
+	entry.Links = append(entry.Links, link)
+	s.validators[parent.URL.String()] = entry
+}
+
+// reuseNotModified handles a 304 Not Modified answer to a conditional request: the saved page is
+// stored again and its saved links followed, and its validators carried over to this crawl
+func (s *Scraper) reuseNotModified(r *colly.Response) bool {
+	if r.StatusCode != http.StatusNotModified {
+		return false
+	}
+
+	pageURL := r.Request.URL.String()
+	s.conditionalMutex.Lock()
+	info, exists := s.conditional[urlMatchKey(pageURL)]
+	if exists && s.config.GetConditionalRequests() {
+		if s.validators == nil {
+			s.validators = make(map[string]ConditionalInfo)
+		}
+		s.validators[pageURL] = ConditionalInfo{ETag: info.ETag, LastModified: info.LastModified}
+	}
+	s.conditionalMutex.Unlock()
+	if !exists {
+		return false
+	}
+
+	page := info.Page
+	if page == nil && info.Stored {
+		stored, err := readStoredPage(pageStoreDir(s.config.StateFile), pageURL)
+		if err != nil {
+			s.logger.Printf("Warning: Could not reuse saved page %s, fetching it again: %v", pageURL, err)
+			r.Ctx.Put(refetchKey(r.Request), true)
+			r.Request.Headers.Del("If-None-Match")
+			r.Request.Headers.Del("If-Modified-Since")
+			if err := r.Request.Retry(); err != nil {
+				s.logger.Printf("Could not fetch %s again: %v", pageURL, err)
+			}
+			return true
+		}
+		page = &stored
+	}
+
+	s.logger.Printf("Not modified, reusing saved page: %s", pageURL)
+	if page != nil {
+		reused := *page
+		s.storePage(reused)
+	}
+	for _, link := range info.Links {
+		if s.shouldFollowLink(link, r.Request.URL) {
+			s.enqueueLink(r.Request, link)
+		}
+	}
+	return true
+}
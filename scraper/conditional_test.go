@@ -0,0 +1,222 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+// etagServer serves a home page linking to /guide and /api, honoring If-None-Match with each
+// page's current version as its ETag; it records the status of every response
+type etagServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	versions map[string]int
+	statuses map[string][]int
+}
+
+func newETagServer(t *testing.T) *etagServer {
+	t.Helper()
+
+	server := &etagServer{
+		versions: map[string]int{"/": 1, "/guide": 1, "/api": 1},
+		statuses: make(map[string][]int),
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+
+		version, exists := server.versions[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		etag := fmt.Sprintf(`"%s-v%d"`, r.URL.Path, version)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			server.statuses[r.URL.Path] = append(server.statuses[r.URL.Path], http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		server.statuses[r.URL.Path] = append(server.statuses[r.URL.Path], http.StatusOK)
+		w.Header().Set("Content-Type", "text/html")
+		body := fmt.Sprintf("<p>Version %d of %s.</p>", version, r.URL.Path)
+		if r.URL.Path == "/" {
+			body += `<a href="/guide">Guide</a> <a href="/api">API</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// bump changes a page, so its next response is a full 200 with a new ETag
+func (s *etagServer) bump(path string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.versions[path]++
+}
+
+// lastStatuses returns the status of the most recent response to each path and resets them
+func (s *etagServer) lastStatuses() map[string]int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	last := make(map[string]int)
+	for path, statuses := range s.statuses {
+		last[path] = statuses[len(statuses)-1]
+	}
+	s.statuses = make(map[string][]int)
+	return last
+}
+
+// pageContents maps each page's path to its content
+func pageContents(t *testing.T, server *etagServer, pages []PageData) map[string]string {
+	t.Helper()
+
+	contents := make(map[string]string)
+	for _, page := range pages {
+		contents[page.URL[len(server.URL):]] = page.Content
+	}
+	return contents
+}
+
+func TestScraper_ConditionalRequests(t *testing.T) {
+	server := newETagServer(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	conditional := true
+
+	crawl := func() []PageData {
+		t.Helper()
+		s := newTestScraper(t, &config.Config{
+			RootURL:             server.URL + "/",
+			MaxDepth:            2,
+			StateFile:           statePath,
+			ConditionalRequests: &conditional,
+		})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		return s.GetPages()
+	}
+
+	first := pageContents(t, server, crawl())
+	if len(first) != 3 {
+		t.Fatalf("Expected 3 pages from the first crawl, got %v", first)
+	}
+	server.lastStatuses()
+
+	// Unchanged pages answer 304 and are reused; the home page's links are still followed
+	server.bump("/api")
+	second := pageContents(t, server, crawl())
+	want := map[string]int{"/": http.StatusNotModified, "/guide": http.StatusNotModified, "/api": http.StatusOK}
+	if got := server.lastStatuses(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected statuses %v on the second crawl, got %v", want, got)
+	}
+
+	paths := make([]string, 0, len(second))
+	for path := range second {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if fmt.Sprint(paths) != "[/ /api /guide]" {
+		t.Fatalf("Expected all 3 pages from the second crawl, got %v", paths)
+	}
+	if second["/"] != first["/"] || second["/guide"] != first["/guide"] {
+		t.Errorf("Expected unchanged pages to reuse their saved content, got %v", second)
+	}
+	if second["/api"] == first["/api"] {
+		t.Errorf("Expected the changed page to be fetched again, got %q", second["/api"])
+	}
+
+	// The second crawl's validators are saved too, so a third crawl fetches nothing in full
+	crawl()
+	for path, status := range server.lastStatuses() {
+		if status != http.StatusNotModified {
+			t.Errorf("Expected %s to be unchanged on the third crawl, got status %d", path, status)
+		}
+	}
+}
+
+func TestScraper_SetConditionalHeaders(t *testing.T) {
+	server := newETagServer(t)
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 1})
+	saved := PageData{Title: "Saved home", URL: server.URL + "/", Content: "Saved content"}
+	s.SetConditionalHeaders(map[string]ConditionalInfo{
+		server.URL + "/": {ETag: `"/-v1"`, Page: &saved},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if status := server.lastStatuses()["/"]; status != http.StatusNotModified {
+		t.Errorf("Expected the request to carry If-None-Match and get 304, got status %d", status)
+	}
+	pages := s.GetPages()
+	if len(pages) != 1 || pages[0].Content != "Saved content" {
+		t.Errorf("Expected the saved page to be reused, got %+v", pages)
+	}
+	if len(s.GetConditionalHeaders()) != 0 {
+		t.Errorf("Expected no validators recorded without conditional_requests, got %v", s.GetConditionalHeaders())
+	}
+}
+
+func TestScraper_ConditionalPagesKeptInPageStore(t *testing.T) {
+	server := newETagServer(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	conditional := true
+
+	crawl := func() map[string]string {
+		t.Helper()
+		s := newTestScraper(t, &config.Config{
+			RootURL:             server.URL + "/",
+			MaxDepth:            2,
+			StateFile:           statePath,
+			ConditionalRequests: &conditional,
+		})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		return pageContents(t, server, s.GetPages())
+	}
+
+	crawl()
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Version 1") {
+		t.Errorf("Expected page content kept out of the state file, got:\n%s", data)
+	}
+
+	// A page changed on the second crawl is reused with its new content on the third
+	server.bump("/api")
+	crawl()
+	server.lastStatuses()
+	if got := crawl(); !strings.Contains(got["/api"], "Version 2") {
+		t.Errorf("Expected the page store updated with the changed page, got %q", got["/api"])
+	}
+
+	// A saved page missing from the page store is fetched again in full
+	if err := os.Remove(pageStoreFile(pageStoreDir(statePath), server.URL+"/guide")); err != nil {
+		t.Fatal(err)
+	}
+	server.lastStatuses()
+	got := crawl()
+	if status := server.lastStatuses()["/guide"]; status != http.StatusOK {
+		t.Errorf("Expected /guide fetched again after its saved page went missing, got status %d", status)
+	}
+	if !strings.Contains(got["/guide"], "Version 1") {
+		t.Errorf("Expected /guide scraped again, got %v", got)
+	}
+}
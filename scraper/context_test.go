@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"docscraper/config"
+
+	"github.com/gocolly/colly/v2"
+)
+
+func TestScraper_ScrapeWithContext_Cancel(t *testing.T) {
+	var requests int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Page", fmt.Sprintf(`<main><p>Some page content here.</p><a href="%s/next">Next</a></main>`, r.URL.Path)))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/start", MaxDepth: 50})
+	s.collector.OnScraped(func(*colly.Response) { cancel() })
+
+	done := make(chan error, 1)
+	go func() { done <- s.ScrapeWithContext(ctx) }()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ScrapeWithContext() did not stop after cancellation")
+	}
+
+	if got := atomic.LoadInt64(&requests); got > 2 {
+		t.Errorf("Expected the crawl to stop after the first page, got %d requests", got)
+	}
+	pages := s.GetPages()
+	if len(pages) == 0 || pages[0].URL != server.URL+"/start" {
+		t.Errorf("Expected the first page kept as a partial result, got %+v", pages)
+	}
+}
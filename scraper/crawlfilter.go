@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+
+	"docscraper/config"
+)
+
+// crawlFilter holds the compiled crawl_filter patterns
+type crawlFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// compileCrawlFilter compiles the configured include and exclude patterns
+func compileCrawlFilter(cfg config.CrawlFilterConfig) (*crawlFilter, error) {
+	filter := &crawlFilter{}
+	for _, pattern := range cfg.IncludePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %v", pattern, err)
+		}
+		filter.include = append(filter.include, re)
+	}
+	for _, pattern := range cfg.ExcludePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %v", pattern, err)
+		}
+		filter.exclude = append(filter.exclude, re)
+	}
+	return filter, nil
+}
+
+// configured reports whether any patterns are set, replacing the built-in skip lists
+func (f *crawlFilter) configured() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+// rejection returns the reason link is filtered out, or "" when it may be followed
+func (f *crawlFilter) rejection(link string) string {
+	for _, re := range f.exclude {
+		if re.MatchString(link) {
+			return RejectExcludePattern
+		}
+	}
+	if len(f.include) == 0 {
+		return ""
+	}
+	for _, re := range f.include {
+		if re.MatchString(link) {
+			return ""
+		}
+	}
+	return RejectIncludePattern
+}
@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"net/url"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_shouldFollowLink_CrawlFilter(t *testing.T) {
+	baseURL, _ := url.Parse("https://example.com/docs/")
+
+	tests := []struct {
+		name     string
+		filter   config.CrawlFilterConfig
+		expected map[string]bool
+	}{
+		{
+			name:   "include only",
+			filter: config.CrawlFilterConfig{IncludePatterns: []string{`/api/`, `/docs/`}},
+			expected: map[string]bool{
+				"/api/reference": true, // Built-in /api/ skip no longer applies
+				"/docs/guide":    true,
+				"/blog/post":     false,
+				"/docs/spec.pdf": true,
+			},
+		},
+		{
+			name:   "exclude only",
+			filter: config.CrawlFilterConfig{ExcludePatterns: []string{`/blog/`, `\.pdf$`}},
+			expected: map[string]bool{
+				"/api/reference": true,
+				"/login":         true,
+				"/blog/post":     false,
+				"/docs/spec.pdf": false,
+			},
+		},
+		{
+			name: "include and exclude",
+			filter: config.CrawlFilterConfig{
+				IncludePatterns: []string{`/api/`},
+				ExcludePatterns: []string{`/api/internal/`},
+			},
+			expected: map[string]bool{
+				"/api/reference":     true,
+				"/api/internal/keys": false,
+				"/docs/guide":        false,
+			},
+		},
+		{
+			name: "no filter keeps built-in skips",
+			expected: map[string]bool{
+				"/api/reference": false,
+				"/docs/spec.pdf": false,
+				"/docs/guide":    true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recordFrontier := true
+			s := newTestScraper(t, &config.Config{
+				RootURL:        "https://example.com",
+				CrawlFilter:    tt.filter,
+				RecordFrontier: &recordFrontier,
+			})
+			for link, want := range tt.expected {
+				if got := s.shouldFollowLink(link, baseURL); got != want {
+					t.Errorf("shouldFollowLink(%q) = %v, want %v", link, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScraper_CrawlFilterRejectionReasons(t *testing.T) {
+	recordFrontier := true
+	s := newTestScraper(t, &config.Config{
+		RootURL: "https://example.com",
+		CrawlFilter: config.CrawlFilterConfig{
+			IncludePatterns: []string{`/docs/`},
+			ExcludePatterns: []string{`/docs/old/`},
+		},
+		RecordFrontier: &recordFrontier,
+	})
+	baseURL, _ := url.Parse("https://example.com/docs/")
+	s.shouldFollowLink("/docs/old/page", baseURL)
+	s.shouldFollowLink("/blog/post", baseURL)
+
+	if reason := s.rejected["https://example.com/docs/old/page"].Reason; reason != RejectExcludePattern {
+		t.Errorf("Expected %q, got %q", RejectExcludePattern, reason)
+	}
+	if reason := s.rejected["https://example.com/blog/post"].Reason; reason != RejectIncludePattern {
+		t.Errorf("Expected %q, got %q", RejectIncludePattern, reason)
+	}
+}
+
+func TestCompileCrawlFilter_InvalidPattern(t *testing.T) {
+	if _, err := compileCrawlFilter(config.CrawlFilterConfig{ExcludePatterns: []string{"("}}); err == nil {
+		t.Error("Expected error for an invalid exclude pattern")
+	}
+}
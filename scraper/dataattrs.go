@@ -0,0 +1,38 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// dataAttributeElements are searched in order for capture_data_attributes; the first value found wins
+var dataAttributeElements = []string{"body", "html", "meta"}
+
+// ExtractDataAttributes returns the values of the named data-* attributes found on the page's
+// <body>, <html> or <meta> elements, keyed by the name without its "data-" prefix
+func (e *ContentExtractor) ExtractDataAttributes(doc *goquery.Selection, names []string) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	var metadata map[string]string
+	for _, name := range names {
+		key := strings.TrimPrefix(name, "data-")
+		if _, ok := metadata[key]; ok {
+			continue
+		}
+		for _, selector := range dataAttributeElements {
+			value, ok := doc.Find(selector + "[" + name + "]").First().Attr(name)
+			if !ok {
+				continue
+			}
+			if metadata == nil {
+				metadata = make(map[string]string)
+			}
+			metadata[key] = strings.TrimSpace(value)
+			break
+		}
+	}
+	return metadata
+}
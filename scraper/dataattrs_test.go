@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestContentExtractor_ExtractDataAttributes(t *testing.T) {
+	extractor := NewContentExtractor()
+	body := `<html data-theme="dark"><head><meta name="doc" data-category="guides"></head>` +
+		`<body data-doc-version="2.1" data-build=" 42 "><p>Text</p></body></html>`
+
+	tests := []struct {
+		name  string
+		names []string
+		want  map[string]string
+	}{
+		{
+			name:  "body, html and meta attributes",
+			names: []string{"data-doc-version", "data-category", "data-theme"},
+			want:  map[string]string{"doc-version": "2.1", "category": "guides", "theme": "dark"},
+		},
+		{
+			name:  "values are trimmed",
+			names: []string{"data-build"},
+			want:  map[string]string{"build": "42"},
+		},
+		{
+			name:  "missing attributes are omitted",
+			names: []string{"data-missing"},
+			want:  nil,
+		},
+		{
+			name:  "nothing configured",
+			names: nil,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractor.ExtractDataAttributes(parseTestDocument(t, body), tt.names)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractDataAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScraper_CaptureDataAttributes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><head><title>Guide</title><meta name="x" data-category="tutorials"></head>`+
+			`<body data-doc-version="3.0" data-tracking-id="abc"><main><p>Guide content for the project.</p></main></body></html>`)
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:               server.URL,
+		MaxDepth:              1,
+		CaptureDataAttributes: []string{"data-doc-version", "data-category"},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+	want := map[string]string{"doc-version": "3.0", "category": "tutorials"}
+	if !reflect.DeepEqual(pages[0].Metadata, want) {
+		t.Errorf("Expected metadata %v, got %v", want, pages[0].Metadata)
+	}
+}
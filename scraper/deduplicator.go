@@ -1,9 +1,13 @@
 package scraper
 
 import (
+	"hash/fnv"
+	"math/bits"
 	"net/url"
+	"path"
 	"sort"
 	"strings"
+	"sync"
 )
 
 // URLNormalizer defines options for URL normalization
@@ -14,6 +18,9 @@ type URLNormalizer struct {
 	LowerCase       bool // Convert to lowercase
 	RemoveWWW       bool // Remove www. prefix
 	SortQueryParams bool // Sort query parameters
+
+	AMPPatterns []string // AMP path segments ("amp") or segment suffixes (".amp") to strip
+	CleanPath   bool     // Collapse "//", "/./" and "/../" in the path
 }
 
 // LinkDeduplicator handles duplicate URL detection and filtering
@@ -23,6 +30,16 @@ type LinkDeduplicator struct {
 	duplicateCount int
 	normalizeFunc  func(string) string
 	normalizer     URLNormalizer
+	mutex          sync.Mutex
+}
+
+// cleanURLPath applies path.Clean to a URL path, keeping its trailing slash
+func cleanURLPath(p string) string {
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
 }
 
 // NewLinkDeduplicator creates a new link deduplicator with the given configuration
@@ -81,6 +98,18 @@ func (ld *LinkDeduplicator) NormalizeURL(rawURL string) (string, error) {
 		}
 	}
 
+	// Collapse duplicate slashes and dot segments, keeping any trailing slash for RemoveTrailing
+	if ld.normalizer.CleanPath && parsedURL.Path != "" {
+		parsedURL.Path = cleanURLPath(parsedURL.Path)
+		parsedURL.RawPath = ""
+	}
+
+	// Merge AMP variants with their canonical page
+	if len(ld.normalizer.AMPPatterns) > 0 {
+		parsedURL.Path = stripAMP(parsedURL.Path, ld.normalizer.AMPPatterns)
+		parsedURL.RawPath = ""
+	}
+
 	// Remove trailing slash if configured
 	if ld.normalizer.RemoveTrailing && parsedURL.Path != "/" {
 		parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/")
@@ -95,6 +124,36 @@ func (ld *LinkDeduplicator) NormalizeURL(rawURL string) (string, error) {
 	return normalizedURL, nil
 }
 
+// stripAMP removes AMP path segments and segment suffixes, so /docs/page/amp,
+// /amp/docs/page and /docs/page.amp all map to /docs/page
+func stripAMP(path string, patterns []string) string {
+	segments := strings.Split(path, "/")
+	kept := make([]string, 0, len(segments))
+
+	for _, segment := range segments {
+		stripped := segment
+		for _, pattern := range patterns {
+			if strings.HasPrefix(pattern, ".") {
+				if len(stripped) > len(pattern) && strings.HasSuffix(strings.ToLower(stripped), strings.ToLower(pattern)) {
+					stripped = stripped[:len(stripped)-len(pattern)]
+				}
+			} else if strings.EqualFold(stripped, pattern) {
+				stripped = ""
+			}
+		}
+		if stripped == "" && segment != "" {
+			continue
+		}
+		kept = append(kept, stripped)
+	}
+
+	result := strings.Join(kept, "/")
+	if strings.HasPrefix(path, "/") && !strings.HasPrefix(result, "/") {
+		result = "/" + result
+	}
+	return result
+}
+
 // IsDuplicate checks if the URL has already been processed
 func (ld *LinkDeduplicator) IsDuplicate(rawURL string) bool {
 	normalized, err := ld.NormalizeURL(rawURL)
@@ -103,12 +162,18 @@ func (ld *LinkDeduplicator) IsDuplicate(rawURL string) bool {
 		return true
 	}
 
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
 	return ld.seenURLs[normalized]
 }
 
 // AddURL adds a URL to the seen set, returns false if it was already seen (duplicate)
 func (ld *LinkDeduplicator) AddURL(rawURL string) bool {
 	normalized, err := ld.NormalizeURL(rawURL)
+
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
+
 	if err != nil {
 		// If normalization fails, don't add it and consider it a duplicate
 		ld.duplicateCount++
@@ -127,12 +192,17 @@ func (ld *LinkDeduplicator) AddURL(rawURL string) bool {
 
 // GetDuplicateCount returns the number of duplicates found
 func (ld *LinkDeduplicator) GetDuplicateCount() int {
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
 	return ld.duplicateCount
 }
 
 // GetCanonicalURL returns the canonical version of a URL
 func (ld *LinkDeduplicator) GetCanonicalURL(rawURL string) string {
-	if canonical, exists := ld.canonicalMap[rawURL]; exists {
+	ld.mutex.Lock()
+	canonical, exists := ld.canonicalMap[rawURL]
+	ld.mutex.Unlock()
+	if exists {
 		return canonical
 	}
 
@@ -147,12 +217,167 @@ func (ld *LinkDeduplicator) GetCanonicalURL(rawURL string) string {
 
 // GetSeenURLsCount returns the number of unique URLs seen
 func (ld *LinkDeduplicator) GetSeenURLsCount() int {
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
 	return len(ld.seenURLs)
 }
 
 // Reset clears all stored URLs and counters
 func (ld *LinkDeduplicator) Reset() {
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
 	ld.seenURLs = make(map[string]bool)
 	ld.canonicalMap = make(map[string]string)
 	ld.duplicateCount = 0
 }
+
+// Export returns every URL added so far mapped to its normalized form, for Import
+func (ld *LinkDeduplicator) Export() map[string]string {
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
+
+	exported := make(map[string]string, len(ld.canonicalMap))
+	for rawURL, normalized := range ld.canonicalMap {
+		exported[rawURL] = normalized
+	}
+	return exported
+}
+
+// Import marks the URLs of an Export as seen, so they are reported as duplicates
+func (ld *LinkDeduplicator) Import(urls map[string]string) {
+	ld.mutex.Lock()
+	defer ld.mutex.Unlock()
+
+	for rawURL, normalized := range urls {
+		ld.seenURLs[normalized] = true
+		ld.canonicalMap[rawURL] = normalized
+	}
+}
+
+// simHashShingleSize is the number of consecutive words hashed into each SimHash feature
+const simHashShingleSize = 3
+
+// ContentDeduplicator detects pages whose content was already seen at another URL, by exact
+// hash of the whitespace-normalized content and, with a threshold, by SimHash distance
+type ContentDeduplicator struct {
+	hashes    map[string]bool
+	simHashes []uint64
+	threshold int // Max Hamming distance for near-duplicates, 0 disables SimHash
+	mutex     sync.Mutex
+}
+
+// NewContentDeduplicator creates a content deduplicator; threshold is the largest SimHash
+// Hamming distance treated as a near-duplicate, or 0 to match exact content only
+func NewContentDeduplicator(threshold int) *ContentDeduplicator {
+	return &ContentDeduplicator{
+		hashes:    make(map[string]bool),
+		threshold: threshold,
+	}
+}
+
+// IsDuplicateContent reports whether content matches, or nearly matches, content already added
+func (cd *ContentDeduplicator) IsDuplicateContent(content string) bool {
+	normalized := normalizeContent(content)
+
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	return cd.seen(normalized, cd.fingerprint(normalized))
+}
+
+// AddContent records content as seen
+func (cd *ContentDeduplicator) AddContent(content string) {
+	normalized := normalizeContent(content)
+
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	cd.add(normalized, cd.fingerprint(normalized))
+}
+
+// CheckAndAdd reports whether content matches, or nearly matches, content already added, and
+// records it otherwise; concurrent callers with the same content see exactly one miss
+func (cd *ContentDeduplicator) CheckAndAdd(content string) bool {
+	normalized := normalizeContent(content)
+	fingerprint := cd.fingerprint(normalized)
+
+	cd.mutex.Lock()
+	defer cd.mutex.Unlock()
+	if cd.seen(normalized, fingerprint) {
+		return true
+	}
+	cd.add(normalized, fingerprint)
+	return false
+}
+
+// fingerprint returns the SimHash of normalized content, or 0 when near-duplicates are off
+func (cd *ContentDeduplicator) fingerprint(normalized string) uint64 {
+	if cd.threshold <= 0 {
+		return 0
+	}
+	return SimHash(normalized)
+}
+
+// seen reports whether normalized content was added; cd.mutex must be held
+func (cd *ContentDeduplicator) seen(normalized string, fingerprint uint64) bool {
+	if cd.hashes[ContentHash(normalized)] {
+		return true
+	}
+	if cd.threshold <= 0 {
+		return false
+	}
+
+	for _, seen := range cd.simHashes {
+		if bits.OnesCount64(fingerprint^seen) <= cd.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// add records normalized content; cd.mutex must be held
+func (cd *ContentDeduplicator) add(normalized string, fingerprint uint64) {
+	cd.hashes[ContentHash(normalized)] = true
+	if cd.threshold > 0 {
+		cd.simHashes = append(cd.simHashes, fingerprint)
+	}
+}
+
+// normalizeContent collapses whitespace so formatting differences don't defeat hashing
+func normalizeContent(content string) string {
+	return strings.Join(strings.Fields(content), " ")
+}
+
+// SimHash returns a 64-bit fingerprint of text in which similar texts differ in few bits,
+// built from lowercased word shingles
+func SimHash(text string) uint64 {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return 0
+	}
+
+	size := simHashShingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		feature := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if feature&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << uint(bit)
+		}
+	}
+	return fingerprint
+}
@@ -1,7 +1,17 @@
 package scraper
 
 import (
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"docscraper/config"
 )
 
 func TestNewLinkDeduplicator(t *testing.T) {
@@ -69,6 +79,62 @@ func TestLinkDeduplicator_NormalizeURL(t *testing.T) {
 			input:    "https://example.com/",
 			expected: "https://example.com/",
 		},
+		{
+			name: "collapse double slashes",
+			config: URLNormalizer{
+				CleanPath: true,
+			},
+			input:    "https://example.com/docs//guide",
+			expected: "https://example.com/docs/guide",
+		},
+		{
+			name: "collapse dot segments",
+			config: URLNormalizer{
+				CleanPath: true,
+			},
+			input:    "https://example.com/docs/./guide",
+			expected: "https://example.com/docs/guide",
+		},
+		{
+			name: "resolve dot-dot segments",
+			config: URLNormalizer{
+				CleanPath: true,
+			},
+			input:    "https://example.com/docs/api/../guide?q=1",
+			expected: "https://example.com/docs/guide?q=1",
+		},
+		{
+			name: "clean path keeps trailing slash",
+			config: URLNormalizer{
+				CleanPath: true,
+			},
+			input:    "https://example.com/docs//guide/",
+			expected: "https://example.com/docs/guide/",
+		},
+		{
+			name: "clean path with trailing slash removal",
+			config: URLNormalizer{
+				CleanPath:      true,
+				RemoveTrailing: true,
+			},
+			input:    "https://example.com/docs/./guide//",
+			expected: "https://example.com/docs/guide",
+		},
+		{
+			name: "clean path keeps root",
+			config: URLNormalizer{
+				CleanPath:      true,
+				RemoveTrailing: true,
+			},
+			input:    "https://example.com//",
+			expected: "https://example.com/",
+		},
+		{
+			name:     "paths left alone without clean path",
+			config:   URLNormalizer{},
+			input:    "https://example.com/docs//guide",
+			expected: "https://example.com/docs//guide",
+		},
 		{
 			name: "lowercase",
 			config: URLNormalizer{
@@ -267,3 +333,328 @@ func TestLinkDeduplicator_Reset(t *testing.T) {
 		t.Error("AddURL() should succeed after reset")
 	}
 }
+
+func TestLinkDeduplicator_AMPPatterns(t *testing.T) {
+	dedup := NewLinkDeduplicator(URLNormalizer{
+		RemoveTrailing: true,
+		AMPPatterns:    []string{"amp", ".amp"},
+	})
+
+	if !dedup.AddURL("https://example.com/docs/page") {
+		t.Fatal("AddURL() should accept the canonical page")
+	}
+	for _, variant := range []string{
+		"https://example.com/docs/page/amp",
+		"https://example.com/docs/page/amp/",
+		"https://example.com/amp/docs/page",
+		"https://example.com/docs/page.amp",
+	} {
+		if !dedup.IsDuplicate(variant) {
+			t.Errorf("IsDuplicate(%q) = false, want AMP variant to dedupe with /docs/page", variant)
+		}
+	}
+
+	if dedup.IsDuplicate("https://example.com/docs/example") {
+		t.Error("Segments merely containing the pattern should not be stripped")
+	}
+	if dedup.IsDuplicate("https://example.com/docs/page/ampere") {
+		t.Error("Segments starting with the pattern should not be stripped")
+	}
+}
+
+func TestStripAMP(t *testing.T) {
+	tests := []struct {
+		path     string
+		patterns []string
+		expected string
+	}{
+		{"/docs/page/amp", []string{"amp"}, "/docs/page"},
+		{"/AMP/docs/page", []string{"amp"}, "/docs/page"},
+		{"/amp", []string{"amp"}, "/"},
+		{"/docs/page.amp", []string{".amp"}, "/docs/page"},
+		{"/docs/.amp", []string{".amp"}, "/docs/.amp"},
+		{"/docs/page/amp", nil, "/docs/page/amp"},
+	}
+
+	for _, tt := range tests {
+		if got := stripAMP(tt.path, tt.patterns); got != tt.expected {
+			t.Errorf("stripAMP(%q, %v) = %q, want %q", tt.path, tt.patterns, got, tt.expected)
+		}
+	}
+}
+
+// dedupArticle is long enough that a one-word edit changes few SimHash features
+const dedupArticle = `The configuration file controls how the scraper discovers pages, how long it waits
+between requests and which output formats are produced once the crawl has finished. Each setting
+has a sensible default, so a minimal file only needs the root URL and an output directory. Larger
+sites usually benefit from tuning the delay, the maximum depth and the number of concurrent
+requests, while documentation behind a proxy may also need proxy and user agent settings.`
+
+func TestContentDeduplicator_ExactDuplicates(t *testing.T) {
+	dedup := NewContentDeduplicator(0)
+
+	if dedup.IsDuplicateContent(dedupArticle) {
+		t.Fatal("Unseen content should not be a duplicate")
+	}
+	dedup.AddContent(dedupArticle)
+
+	reformatted := strings.Join(strings.Fields(dedupArticle), "\n  ")
+	if !dedup.IsDuplicateContent(reformatted) {
+		t.Error("Content differing only in whitespace should be a duplicate")
+	}
+
+	edited := strings.Replace(dedupArticle, "sensible", "reasonable", 1)
+	if dedup.IsDuplicateContent(edited) {
+		t.Error("Edited content should not be an exact duplicate without a SimHash threshold")
+	}
+}
+
+func TestContentDeduplicator_NearDuplicates(t *testing.T) {
+	dedup := NewContentDeduplicator(10)
+	dedup.AddContent(dedupArticle)
+
+	edited := strings.Replace(dedupArticle, "sensible", "reasonable", 1)
+	if distance := bits.OnesCount64(SimHash(dedupArticle) ^ SimHash(edited)); distance > 10 {
+		t.Fatalf("Expected a small SimHash distance for a one-word edit, got %d", distance)
+	}
+	if !dedup.IsDuplicateContent(edited) {
+		t.Error("Content with a one-word edit should be a near-duplicate")
+	}
+
+	unrelated := `Release notes list every change shipped in a version, grouped into new features,
+fixes and deprecations, with links to the pull requests that introduced them and upgrade advice.`
+	if dedup.IsDuplicateContent(unrelated) {
+		t.Errorf("Unrelated content should not be a near-duplicate (distance %d)",
+			bits.OnesCount64(SimHash(dedupArticle)^SimHash(unrelated)))
+	}
+}
+
+func TestEnhancedScraper_DedupeByContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<p>Start here for the guides.</p><a href="/config">Config</a><a href="/mirror/config">Mirror</a>`))
+		default:
+			fmt.Fprint(w, htmlPage("Config", "<p>"+dedupArticle+"</p>"))
+		}
+	}))
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	enableQuality := true
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:               server.URL + "/",
+		MaxDepth:              2,
+		OutputFormat:          "markdown",
+		OutputType:            "single",
+		LogFile:               logFile.Name(),
+		EnableQualityAnalysis: &enableQuality,
+		Deduplication:         config.DeduplicationConfig{DedupeByContent: true},
+		QualityAnalysis:       config.QualityConfig{MinScore: 0.01, MinWordCount: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.ScrapeWithFeatures(); err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+
+	log, err := os.ReadFile(logFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped := strings.Count(string(log), "Skipping page with duplicate content"); skipped != 1 {
+		t.Errorf("Expected exactly one mirror page skipped, got %d\n%s", skipped, log)
+	}
+}
+
+func TestContentDeduplicator_CheckAndAdd(t *testing.T) {
+	dedup := NewContentDeduplicator(3)
+
+	var misses int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !dedup.CheckAndAdd(dedupArticle) {
+				atomic.AddInt64(&misses, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if misses != 1 {
+		t.Errorf("Expected exactly one concurrent caller to add the content, got %d", misses)
+	}
+	if !dedup.IsDuplicateContent(dedupArticle) {
+		t.Error("Content added by CheckAndAdd should be a duplicate")
+	}
+}
+
+func TestEnhancedScraper_DedupeByContentWithoutQualityAnalysis(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<p>Start here for the guides.</p><a href="/config">Config</a><a href="/mirror/config">Mirror</a>`))
+		default:
+			fmt.Fprint(w, htmlPage("Config", "<p>"+dedupArticle+"</p>"))
+		}
+	}))
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:       server.URL + "/",
+		MaxDepth:      2,
+		OutputFormat:  "markdown",
+		OutputType:    "single",
+		LogFile:       logFile.Name(),
+		Deduplication: config.DeduplicationConfig{DedupeByContent: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages, err := es.ScrapeWithFeatures()
+	if err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+
+	if len(pages) != 2 {
+		t.Errorf("Expected the home page and one copy of the config page, got %d pages", len(pages))
+	}
+}
+
+func TestScraper_DedupeByContentIgnoresDroppedPages(t *testing.T) {
+	maxPages := 1
+	s := newTestScraper(t, &config.Config{RootURL: "http://example.com/", MaxPages: &maxPages})
+	s.contentDedup = NewContentDeduplicator(0)
+
+	s.storePage(PageData{URL: "http://example.com/", Content: "The home page."})
+	s.storePage(PageData{URL: "http://example.com/config", Content: dedupArticle})
+
+	if s.contentDedup.IsDuplicateContent(dedupArticle) {
+		t.Error("A page dropped at max_pages should not be recorded as seen content")
+	}
+	if pages := s.GetPages(); len(pages) != 1 {
+		t.Errorf("Expected 1 stored page, got %d", len(pages))
+	}
+}
+
+func TestEnhancedScraper_QueryIsIdentity(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", `<p>Welcome to the docs home page.</p><a href="/docs?id=a">A</a> <a href="/docs?id=b">B</a>`))
+	})
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		fmt.Fprint(w, htmlPage("Docs "+id, "<p>Documentation section "+id+" explains one topic in detail.</p>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		queryIsIdentity bool
+		wantDocs        int
+	}{
+		{"query stripped", false, 1},
+		{"query is identity", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile, err := os.CreateTemp("", "test-log-*.log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			logFile.Close()
+			defer os.Remove(logFile.Name())
+
+			queryIsIdentity := tt.queryIsIdentity
+			es, err := NewWithFeatures(&config.Config{
+				RootURL:         server.URL + "/",
+				MaxDepth:        2,
+				OutputFormat:    "markdown",
+				OutputType:      "single",
+				LogFile:         logFile.Name(),
+				QueryIsIdentity: &queryIsIdentity,
+				Deduplication:   config.DeduplicationConfig{RemoveQueryParams: true},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages, err := es.ScrapeWithFeatures()
+			if err != nil {
+				t.Fatalf("ScrapeWithFeatures() error = %v", err)
+			}
+
+			docs := 0
+			for _, page := range pages {
+				if strings.Contains(page.URL, "/docs?id=") {
+					docs++
+				}
+			}
+			if docs != tt.wantDocs {
+				log, _ := os.ReadFile(logFile.Name())
+				t.Errorf("Expected %d docs pages, got %d\n%s", tt.wantDocs, docs, log)
+			}
+		})
+	}
+}
+
+func TestEnhancedScraper_DeduplicationDetachesPlainLinks(t *testing.T) {
+	var docsRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", `<p>Welcome to the docs home page.</p><a href="/docs?a=1">One</a> <a href="/docs?a=2">Two</a>`))
+	})
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&docsRequests, 1)
+		fmt.Fprint(w, htmlPage("Docs", "<p>Documentation explains one topic in detail.</p>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	enableDeduplication := true
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:             server.URL + "/",
+		MaxDepth:            2,
+		OutputFormat:        "markdown",
+		OutputType:          "single",
+		LogFile:             logFile.Name(),
+		EnableDeduplication: &enableDeduplication,
+		Deduplication:       config.DeduplicationConfig{RemoveQueryParams: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.ScrapeWithFeatures(); err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+
+	// Without detaching the plain link callback, both query variants are fetched
+	if got := atomic.LoadInt32(&docsRequests); got != 1 {
+		log, _ := os.ReadFile(logFile.Name())
+		t.Errorf("Expected /docs to be requested once, got %d\n%s", got, log)
+	}
+}
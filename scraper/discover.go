@@ -0,0 +1,149 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// discovery tracks a Discover crawl: leaf links already checked and the pages Scrape would store
+type discovery struct {
+	mutex   sync.Mutex
+	checked map[string]bool
+	found   map[string]bool
+	rootErr error
+}
+
+// Discover runs the crawl Scrape would run, with the same seeds, filters and page limit, and
+// returns the sorted URLs of the pages it would store. Nothing is written and the receiver's
+// state is left alone. Pages at max_depth are checked with HEAD requests when their bodies are
+// not needed to decide whether they are stored, falling back to GET when HEAD fails.
+func (s *Scraper) Discover() ([]string, error) {
+	cfg := *s.config
+	cfg.StateFile = ""
+	cfg.RecordFrontier = nil
+	cfg.CheckExternalLinks = nil
+	cfg.SpillToDisk = nil
+	if cfg.GetSitemapIncremental() {
+		// Every sitemap URL is checked instead of reusing the crawl manifest
+		useSitemap := true
+		cfg.UseSitemap = &useSitemap
+		cfg.SitemapIncremental = nil
+	}
+
+	d, err := New(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	d.discovery = &discovery{
+		checked: make(map[string]bool),
+		found:   make(map[string]bool),
+	}
+
+	if err := d.Scrape(); err != nil {
+		return nil, err
+	}
+
+	d.discovery.mutex.Lock()
+	defer d.discovery.mutex.Unlock()
+	if !d.discovery.found[cfg.RootURL] && d.discovery.rootErr != nil {
+		return nil, fmt.Errorf("failed to discover %s: %v", cfg.RootURL, d.discovery.rootErr)
+	}
+
+	urls := make([]string, 0, len(d.discovery.found))
+	for pageURL := range d.discovery.found {
+		urls = append(urls, pageURL)
+	}
+	sort.Strings(urls)
+	return urls, nil
+}
+
+// claimDiscovered reports whether a page is reported for the first time; a leaf checked with HEAD
+// may also be fetched from a shallower page
+func (s *Scraper) claimDiscovered(pageURL string) bool {
+	s.discovery.mutex.Lock()
+	defer s.discovery.mutex.Unlock()
+	if s.discovery.found[pageURL] {
+		return false
+	}
+	s.discovery.found[pageURL] = true
+	return true
+}
+
+// checksWithHead reports whether a link is only checked with HEAD while discovering: assets and
+// pages at max_depth, whose links are never followed, unless their bodies decide whether they
+// are stored
+func (s *Scraper) checksWithHead(target string, depth int) bool {
+	if s.discovery == nil || s.config.GetRespectMetaRobots() || s.config.GetFollowMetaRefresh() || len(s.skipHashes) > 0 {
+		return false
+	}
+	if depth >= s.config.MaxDepth {
+		return true
+	}
+	u, err := url.Parse(target)
+	return err == nil && s.isDownloadAsset(u)
+}
+
+// checkLink sends a HEAD request for a link found on parent, once per URL
+func (s *Scraper) checkLink(parent *colly.Request, target string) {
+	s.discovery.mutex.Lock()
+	checked := s.discovery.checked[target]
+	s.discovery.checked[target] = true
+	s.discovery.mutex.Unlock()
+	if checked {
+		return
+	}
+
+	req, err := parent.New(http.MethodHead, target, nil)
+	if err != nil {
+		return
+	}
+	req.Depth = parent.Depth + 1
+	if err := req.Do(); err != nil {
+		s.logger.Printf("Could not check %s: %v", target, err)
+	}
+}
+
+// discoverHead reports a page checked with HEAD that Scrape would store, judging by its content
+// type
+func (s *Scraper) discoverHead(r *colly.Response) {
+	contentType := responseContentType(r)
+	if !strings.Contains(contentType, "html") && !(s.generatesFormat("auto") && isSourceContentType(contentType)) {
+		return
+	}
+	s.storePage(PageData{
+		URL:         r.Request.URL.String(),
+		Depth:       r.Request.Depth,
+		ContentType: contentType,
+	})
+}
+
+// discoverFailed handles an error while discovering: a failed HEAD is retried with GET, since
+// some servers refuse HEAD, and a failed root URL is remembered for Discover to report. It
+// reports whether the request was handed over to GET.
+func (s *Scraper) discoverFailed(r *colly.Response, err error) bool {
+	if r.Request.Method == http.MethodHead {
+		get, newErr := r.Request.New(http.MethodGet, r.Request.URL.String(), nil)
+		if newErr != nil {
+			return false
+		}
+		get.Depth = r.Request.Depth
+		s.logger.Printf("HEAD failed for %s, retrying with GET", r.Request.URL)
+		if doErr := get.Do(); doErr != nil {
+			s.logger.Printf("Could not fetch %s: %v", r.Request.URL, doErr)
+		}
+		return true
+	}
+
+	if r.Request.URL.String() == s.config.RootURL {
+		s.discovery.mutex.Lock()
+		s.discovery.rootErr = err
+		s.discovery.mutex.Unlock()
+	}
+	return false
+}
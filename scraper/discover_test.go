@@ -0,0 +1,142 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_DiscoverThenScrape(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Home", `<p>Home page content.</p><a href="/docs">Docs</a>`))
+	})
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Docs", `<p>Docs page content.</p><a href="/docs/missing">Missing</a>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 3})
+
+	urls, err := s.Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	// The missing page answers 404 and would not be scraped
+	expected := []string{server.URL + "/", server.URL + "/docs"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected discovered URLs %v, got %v", expected, urls)
+	}
+	if s.GetPageCount() != 0 {
+		t.Errorf("Expected discovery to store no pages, got %d", s.GetPageCount())
+	}
+
+	// Discovery leaves the visited set alone, so a real crawl afterwards fetches every page
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if s.GetPageCount() != 2 {
+		t.Errorf("Expected 2 pages scraped after discovery, got %d", s.GetPageCount())
+	}
+}
+
+func TestScraper_DiscoverMatchesScrape(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			fmt.Fprint(w, urlSet(server.URL+"/orphan"))
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<p>Home page content.</p><a href="/hidden">Hidden</a>`))
+		case "/hidden":
+			fmt.Fprint(w, `<html><head><title>Hidden</title><meta name="robots" content="noindex"></head><body><p>Hidden content.</p></body></html>`)
+		default:
+			fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "Content for "+r.URL.Path))
+		}
+	}))
+	defer server.Close()
+
+	useSitemap := true
+	respectMetaRobots := true
+	cfg := &config.Config{RootURL: server.URL + "/", MaxDepth: 2, UseSitemap: &useSitemap, RespectMetaRobots: &respectMetaRobots}
+
+	urls, err := newTestScraper(t, cfg).Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	// The sitemap seed is discovered and the noindex page is not
+	expected := []string{server.URL + "/", server.URL + "/orphan"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected discovered URLs %v, got %v", expected, urls)
+	}
+
+	s := newTestScraper(t, cfg)
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape failed: %v", err)
+	}
+	if s.GetPageCount() != len(urls) {
+		t.Errorf("Expected Scrape to store the %d discovered pages, got %d", len(urls), s.GetPageCount())
+	}
+}
+
+func TestScraper_DiscoverStopsAtMaxPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, `<p>Content.</p><a href="/a">A</a><a href="/b">B</a><a href="/c">C</a>`))
+	}))
+	defer server.Close()
+
+	maxPages := 2
+	urls, err := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2, MaxPages: &maxPages}).Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(urls) != maxPages {
+		t.Errorf("Expected %d discovered URLs, got %v", maxPages, urls)
+	}
+}
+
+func TestScraper_DiscoverFallsBackToGetWhenHeadFails(t *testing.T) {
+	var mutex sync.Mutex
+	var methods []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", `<p>Home page content.</p><a href="/leaf">Leaf</a>`))
+	})
+	mux.HandleFunc("/leaf", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		methods = append(methods, r.Method)
+		mutex.Unlock()
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Leaf", "<p>Leaf content.</p>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	urls, err := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2}).Discover()
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	expected := []string{server.URL + "/", server.URL + "/leaf"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected discovered URLs %v, got %v", expected, urls)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !reflect.DeepEqual(methods, []string{http.MethodHead, http.MethodGet}) {
+		t.Errorf("Expected HEAD then GET for the leaf page, got %v", methods)
+	}
+}
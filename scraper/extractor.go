@@ -1,23 +1,113 @@
 package scraper
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"docscraper/config"
+)
+
+// Title selection strategies
+const (
+	TitleStrategySmart = "smart" // Compare <title> and <h1> and pick the cleaner one
+	TitleStrategyFirst = "first" // Use the first non-empty title source
 )
 
+// titleSuffixSeparators separate a page title from a trailing site name
+var titleSuffixSeparators = []string{" | ", " - ", " – ", " — ", " · ", " :: "}
+
+// defaultNoisePatterns are common boilerplate phrases removed from extracted text
+var defaultNoisePatterns = []string{
+	`(?i).*?Skip to .*?content\s*`,
+	`(?i)Click here to \w+\s*`,
+	`(?i)Subscribe to \w+ \w+\s*`,
+	`(?i)Follow us on \w+\s*`,
+}
+
+// lastModifiedLayouts are the date formats tried when parsing a last-modified match
+var lastModifiedLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	"2006/01/02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"01/02/2006",
+}
+
+// metaRefreshPattern captures the delay and URL of a meta refresh content value such as
+// "0; url='/next'"
+var metaRefreshPattern = regexp.MustCompile(`(?i)^\s*([\d.]*)\s*[;,]\s*(?:url\s*=\s*)?['"]?([^'"]+?)['"]?\s*$`)
+
+// whitespacePattern matches runs of whitespace
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// ExtractorConfig defines configuration for content extraction
+type ExtractorConfig struct {
+	TitleStrategy          string   `yaml:"title_strategy"`
+	CleaningPatterns       []string `yaml:"cleaning_patterns"`        // Extra noise regexes removed from text
+	ReplaceDefaultCleaning bool     `yaml:"replace_default_cleaning"` // Use only CleaningPatterns, dropping the defaults
+	LastModifiedPatterns   []string `yaml:"last_modified_patterns"`   // Regexes locating a "last updated" date in content
+	PreserveHeadingIDs     bool     `yaml:"preserve_heading_ids"`     // Append heading ids to markdown headings as "## Title {#id}"
+
+	ExcludeMarkers []config.MarkerPair `yaml:"exclude_markers"` // Comment pairs whose enclosed content is removed
+}
+
 // ContentExtractor handles content extraction from HTML
 type ContentExtractor struct {
+	config ExtractorConfig
 	// Content selectors in order of preference
 	contentSelectors []string
 	// Selectors to remove from content
 	removeSelectors []string
+	// Compiled noise patterns applied by cleanText
+	noisePatterns []*regexp.Regexp
+	// Compiled patterns used by ExtractLastModified
+	lastModifiedPatterns []*regexp.Regexp
 }
 
 // NewContentExtractor creates a new content extractor
 func NewContentExtractor() *ContentExtractor {
+	extractor, _ := NewContentExtractorWithConfig(ExtractorConfig{TitleStrategy: TitleStrategySmart})
+	return extractor
+}
+
+// NewContentExtractorWithConfig creates a new content extractor with the given configuration
+func NewContentExtractorWithConfig(config ExtractorConfig) (*ContentExtractor, error) {
+	patterns := config.CleaningPatterns
+	if !config.ReplaceDefaultCleaning {
+		patterns = append(append([]string{}, defaultNoisePatterns...), config.CleaningPatterns...)
+	}
+
+	noisePatterns := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cleaning pattern %q: %v", pattern, err)
+		}
+		noisePatterns = append(noisePatterns, re)
+	}
+
+	lastModifiedPatterns := make([]*regexp.Regexp, 0, len(config.LastModifiedPatterns))
+	for _, pattern := range config.LastModifiedPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid last_modified pattern %q: %v", pattern, err)
+		}
+		lastModifiedPatterns = append(lastModifiedPatterns, re)
+	}
+
 	return &ContentExtractor{
+		config:               config,
+		noisePatterns:        noisePatterns,
+		lastModifiedPatterns: lastModifiedPatterns,
 		contentSelectors: []string{
 			"main", ".main", "#main",
 			".content", "#content", ".main-content",
@@ -35,11 +125,17 @@ func NewContentExtractor() *ContentExtractor {
 			".comments", ".social-share",
 			".advertisement", ".ads",
 		},
-	}
+	}, nil
 }
 
 // ExtractTitle extracts the page title
 func (e *ContentExtractor) ExtractTitle(doc *goquery.Selection) string {
+	if e.config.TitleStrategy == TitleStrategySmart {
+		if title := e.selectCleanTitle(doc); title != "" {
+			return title
+		}
+	}
+
 	// Try different title sources
 	titleSelectors := []string{
 		"title",
@@ -65,8 +161,87 @@ func (e *ContentExtractor) ExtractTitle(doc *goquery.Selection) string {
 	return "Untitled"
 }
 
+// selectCleanTitle compares <title> and the first <h1> and returns the cleaner of the two.
+// The <h1> wins when it matches the <title> once the site-name suffix is stripped; otherwise
+// the authored <title> is kept, since a shorter <h1> is often just a word of it.
+func (e *ContentExtractor) selectCleanTitle(doc *goquery.Selection) string {
+	title := strings.TrimSpace(doc.Find("title").First().Text())
+	h1 := strings.TrimSpace(doc.Find("h1").First().Text())
+
+	if title == "" || h1 == "" {
+		if title != "" {
+			return title
+		}
+		return h1
+	}
+
+	stripped := stripTitleSuffix(title)
+	if strings.EqualFold(h1, stripped) {
+		return h1
+	}
+
+	return title
+}
+
+// stripTitleSuffix removes a trailing site name such as " | Docs" from a title
+func stripTitleSuffix(title string) string {
+	cut := -1
+	for _, sep := range titleSuffixSeparators {
+		if idx := strings.LastIndex(title, sep); idx > cut {
+			cut = idx
+		}
+	}
+
+	if cut <= 0 {
+		return title
+	}
+
+	return strings.TrimSpace(title[:cut])
+}
+
+// MetaRobots holds page-level directives from <meta name="robots">
+type MetaRobots struct {
+	NoIndex  bool
+	NoFollow bool
+}
+
+// ExtractMetaRobots reads the robots meta tag of the document containing doc
+func (e *ContentExtractor) ExtractMetaRobots(doc *goquery.Selection) MetaRobots {
+	var directives MetaRobots
+
+	root := doc.Closest("html")
+	if root.Length() == 0 {
+		root = doc
+	}
+
+	root.Find("meta[name]").Each(func(_ int, meta *goquery.Selection) {
+		name, _ := meta.Attr("name")
+		if !strings.EqualFold(strings.TrimSpace(name), "robots") {
+			return
+		}
+
+		content, _ := meta.Attr("content")
+		for _, directive := range strings.Split(strings.ToLower(content), ",") {
+			switch strings.TrimSpace(directive) {
+			case "noindex":
+				directives.NoIndex = true
+			case "nofollow":
+				directives.NoFollow = true
+			case "none":
+				directives.NoIndex = true
+				directives.NoFollow = true
+			}
+		}
+	})
+
+	return directives
+}
+
 // ExtractContent extracts clean text content from HTML
 func (e *ContentExtractor) ExtractContent(doc *goquery.Selection) string {
+	// Remove regions marked for exclusion
+	removeMarkedRegions(doc, e.config.ExcludeMarkers)
+
 	// Remove unwanted elements
 	for _, selector := range e.removeSelectors {
 		doc.Find(selector).Remove()
@@ -74,10 +249,15 @@ func (e *ContentExtractor) ExtractContent(doc *goquery.Selection) string {
 
 	// Try to find main content areas
 	var content string
+	var blocks []string
 	for _, selector := range e.contentSelectors {
 		if contentEl := doc.Find(selector); contentEl.Length() > 0 {
-			content = contentEl.Text()
-			if len(strings.TrimSpace(content)) > 100 { // Ensure substantial content
+			content, blocks = e.markdownText(contentEl)
+			length := len(strings.TrimSpace(content))
+			for _, block := range blocks {
+				length += len(block)
+			}
+			if length > 100 { // Ensure substantial content
 				break
 			}
 		}
@@ -85,34 +265,110 @@ func (e *ContentExtractor) ExtractContent(doc *goquery.Selection) string {
 
 	// Fallback to body if no main content found
 	if content == "" {
-		content = doc.Find("body").Text()
+		content, blocks = e.markdownText(doc.Find("body"))
 	}
 
 	// Clean up the content
-	return e.cleanText(content)
+	content = e.cleanTextAroundBlocks(content)
+	return restoreBlocks(content, blocks)
 }
 
 // cleanText cleans and normalizes extracted text
 func (e *ContentExtractor) cleanText(text string) string {
-	// Remove common noise patterns first (before whitespace normalization)
-	noisePatterns := []string{
-		`(?i).*?Skip to .*?content\s*`,
-		`(?i)Click here to \w+\s*`,
-		`(?i)Subscribe to \w+ \w+\s*`,
-		`(?i)Follow us on \w+\s*`,
-	}
-
-	for _, pattern := range noisePatterns {
-		re := regexp.MustCompile(pattern)
+	// Remove noise patterns first (before whitespace normalization)
+	for _, re := range e.noisePatterns {
 		text = re.ReplaceAllString(text, "")
 	}
 
 	// Remove excessive whitespace
-	re := regexp.MustCompile(`\s+`)
-	text = re.ReplaceAllString(text, " ")
+	text = whitespacePattern.ReplaceAllString(text, " ")
 
 	// Remove leading/trailing whitespace
 	text = strings.TrimSpace(text)
 
 	return text
 }
+
+// ExtractBaseURL returns the URL relative links on the page resolve against: the <base href>
+// of the document containing doc when declared, otherwise pageURL
+func (e *ContentExtractor) ExtractBaseURL(doc *goquery.Selection, pageURL *url.URL) *url.URL {
+	root := doc.Closest("html")
+	if root.Length() == 0 {
+		root = doc
+	}
+
+	href, exists := root.Find("base[href]").First().Attr("href")
+	if !exists || strings.TrimSpace(href) == "" {
+		return pageURL
+	}
+
+	base, err := pageURL.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return pageURL
+	}
+	return base
+}
+
+// ExtractMetaRefresh returns the target and delay of a <meta http-equiv="refresh"> tag in the
+// document containing doc, the target resolved against baseURL. A refresh without a URL reloads
+// the page and is ignored.
+func (e *ContentExtractor) ExtractMetaRefresh(doc *goquery.Selection, baseURL *url.URL) (string, time.Duration, bool) {
+	root := doc.Closest("html")
+	if root.Length() == 0 {
+		root = doc
+	}
+
+	var target string
+	var delay time.Duration
+	root.Find("meta[http-equiv]").EachWithBreak(func(_ int, meta *goquery.Selection) bool {
+		if !strings.EqualFold(strings.TrimSpace(meta.AttrOr("http-equiv", "")), "refresh") {
+			return true
+		}
+		match := metaRefreshPattern.FindStringSubmatch(meta.AttrOr("content", ""))
+		if match == nil {
+			return true
+		}
+		if resolved, err := baseURL.Parse(strings.TrimSpace(match[2])); err == nil {
+			target = resolved.String()
+		}
+		if seconds, err := strconv.ParseFloat(match[1], 64); err == nil {
+			delay = time.Duration(seconds * float64(time.Second))
+		}
+		return false
+	})
+
+	return target, delay, target != ""
+}
+
+// ExtractLastModified returns the first date matched by the configured last-modified patterns.
+// The first capture group is parsed when present, otherwise the whole match.
+func (e *ContentExtractor) ExtractLastModified(content string) *time.Time {
+	for _, re := range e.lastModifiedPatterns {
+		match := re.FindStringSubmatch(content)
+		if match == nil {
+			continue
+		}
+
+		value := match[0]
+		if len(match) > 1 {
+			value = match[1]
+		}
+
+		if parsed, ok := parseLastModified(value); ok {
+			return &parsed
+		}
+	}
+
+	return nil
+}
+
+// parseLastModified parses a date string using lastModifiedLayouts
+func parseLastModified(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range lastModifiedLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -1,12 +1,25 @@
 package scraper
 
 import (
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// parseTestDocument parses html into the selection colly hands to HTML callbacks
+func parseTestDocument(t *testing.T, html string) *goquery.Selection {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc.Selection
+}
+
 func TestNewContentExtractor(t *testing.T) {
 	extractor := NewContentExtractor()
 
@@ -95,6 +108,41 @@ func TestContentExtractor_ExtractTitle(t *testing.T) {
 			html:     `<html><body><h1>First H1</h1><h1>Second H1</h1></body></html>`,
 			expected: "First H1",
 		},
+		{
+			name:     "h1 matches title without site suffix",
+			html:     `<html><head><title>Install | Docs</title></head><body><h1>Install</h1></body></html>`,
+			expected: "Install",
+		},
+		{
+			name:     "h1 matches title with dash suffix",
+			html:     `<html><head><title>Getting Started - Example Docs</title></head><body><h1>Getting Started</h1></body></html>`,
+			expected: "Getting Started",
+		},
+		{
+			name:     "h1 is site name - title kept",
+			html:     `<html><head><title>Install | Docs</title></head><body><h1>Docs</h1></body></html>`,
+			expected: "Install | Docs",
+		},
+		{
+			name:     "short h1 within title - title kept",
+			html:     `<html><head><title>Installing the Go SDK | Docs</title></head><body><h1>Go</h1></body></html>`,
+			expected: "Installing the Go SDK | Docs",
+		},
+		{
+			name:     "h1 substring of a word - title kept",
+			html:     `<html><head><title>Configuration Reference | Docs</title></head><body><h1>on</h1></body></html>`,
+			expected: "Configuration Reference | Docs",
+		},
+		{
+			name:     "h1 prefix of title - title kept",
+			html:     `<html><head><title>Installing the Go SDK | Docs</title></head><body><h1>Installing</h1></body></html>`,
+			expected: "Installing the Go SDK | Docs",
+		},
+		{
+			name:     "unrelated h1 - title kept",
+			html:     `<html><head><title>Configuration</title></head><body><h1>Welcome</h1></body></html>`,
+			expected: "Configuration",
+		},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +160,42 @@ func TestContentExtractor_ExtractTitle(t *testing.T) {
 	}
 }
 
+func TestContentExtractor_ExtractTitle_FirstStrategy(t *testing.T) {
+	extractor, err := NewContentExtractorWithConfig(ExtractorConfig{TitleStrategy: TitleStrategyFirst})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	html := `<html><head><title>Install | Docs</title></head><body><h1>Install</h1></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	if result := extractor.ExtractTitle(doc.Selection); result != "Install | Docs" {
+		t.Errorf("ExtractTitle() = %q, want %q", result, "Install | Docs")
+	}
+}
+
+func TestStripTitleSuffix(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Install | Docs", "Install"},
+		{"API Reference - Example", "API Reference"},
+		{"Guide · Site", "Guide"},
+		{"No Suffix", "No Suffix"},
+		{"| Docs", "| Docs"},
+	}
+
+	for _, tt := range tests {
+		if result := stripTitleSuffix(tt.input); result != tt.expected {
+			t.Errorf("stripTitleSuffix(%q) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
 func TestContentExtractor_ExtractContent(t *testing.T) {
 	extractor := NewContentExtractor()
 
@@ -281,3 +365,170 @@ func TestContentExtractor_cleanText(t *testing.T) {
 		})
 	}
 }
+
+func TestContentExtractor_CustomCleaningPatterns(t *testing.T) {
+	input := "Intro text We use cookies to improve your experience. Accept all Body text Follow us on Twitter"
+
+	t.Run("merged with defaults", func(t *testing.T) {
+		extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+			CleaningPatterns: []string{`(?i)We use cookies[^.]*\.\s*Accept all\s*`},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "Intro text Body text"
+		if result := extractor.cleanText(input); result != expected {
+			t.Errorf("cleanText() = %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("replacing defaults", func(t *testing.T) {
+		extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+			CleaningPatterns:       []string{`(?i)We use cookies[^.]*\.\s*Accept all\s*`},
+			ReplaceDefaultCleaning: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "Intro text Body text Follow us on Twitter"
+		if result := extractor.cleanText(input); result != expected {
+			t.Errorf("cleanText() = %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("placeholders left alone", func(t *testing.T) {
+		extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+			CleaningPatterns:       []string{`\d+`},
+			ReplaceDefaultCleaning: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The pattern strips digits from the text but not from the heading's placeholder
+		doc := parseTestDocument(t, `<html><body><main><h2>Setup</h2><p>Version 2 notes</p></main></body></html>`)
+		expected := "## Setup\n\nVersion notes"
+		if result := extractor.ExtractContent(doc); result != expected {
+			t.Errorf("ExtractContent() = %q, want %q", result, expected)
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		if _, err := NewContentExtractorWithConfig(ExtractorConfig{CleaningPatterns: []string{"("}}); err == nil {
+			t.Error("Expected error for invalid cleaning pattern")
+		}
+	})
+}
+
+func TestContentExtractor_ExtractLastModified(t *testing.T) {
+	extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+		LastModifiedPatterns: []string{
+			`(?i)last updated:\s*(\d{4}-\d{2}-\d{2})`,
+			`(?i)updated on ([A-Z][a-z]+ \d{1,2}, \d{4})`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected *time.Time
+	}{
+		{"iso date", "Intro. Last updated: 2024-01-15", timePtr(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))},
+		{"long date", "Updated on March 3, 2023. Body", timePtr(time.Date(2023, 3, 3, 0, 0, 0, 0, time.UTC))},
+		{"unparseable date", "Last updated: 2024-13-45", nil},
+		{"no match", "No freshness info here", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := extractor.ExtractLastModified(tt.content)
+			if tt.expected == nil {
+				if result != nil {
+					t.Errorf("ExtractLastModified() = %v, want nil", result)
+				}
+				return
+			}
+			if result == nil || !result.Equal(*tt.expected) {
+				t.Errorf("ExtractLastModified() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+
+	if NewContentExtractor().ExtractLastModified("Last updated: 2024-01-15") != nil {
+		t.Error("Expected no last-modified date without configured patterns")
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestContentExtractor_PreserveHeadingIDs(t *testing.T) {
+	html := `<html><body><main>
+		<p>Intro text.</p>
+		<h2 id="install">Install   the CLI</h2>
+		<p>Run the installer.</p>
+		<h3>Untagged heading</h3>
+		<p>More text.</p>
+	</main></body></html>`
+
+	extractor, err := NewContentExtractorWithConfig(ExtractorConfig{PreserveHeadingIDs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	expected := "Intro text.\n\n## Install the CLI {#install}\n\nRun the installer.\n\n### Untagged heading\n\nMore text."
+	if result := extractor.ExtractContent(doc.Selection); result != expected {
+		t.Errorf("ExtractContent() = %q, want %q", result, expected)
+	}
+
+	// Extracting again from the same document must not mark headings twice
+	if result := extractor.ExtractContent(doc.Selection); result != expected {
+		t.Errorf("Second ExtractContent() = %q, want %q", result, expected)
+	}
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(html))
+	if result := NewContentExtractor().ExtractContent(doc.Selection); strings.Contains(result, "{#install}") || !strings.Contains(result, "\n## Install the CLI\n") {
+		t.Errorf("Heading ids should not be kept by default, got %q", result)
+	}
+}
+
+func TestContentExtractor_ExtractBaseURL(t *testing.T) {
+	extractor := NewContentExtractor()
+	pageURL, _ := url.Parse("https://example.com/section/page.html")
+
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"no base", `<html><head></head><body><a href="x">x</a></body></html>`, "https://example.com/section/page.html"},
+		{"relative base", `<html><head><base href="/docs/v2/"></head><body><a href="x">x</a></body></html>`, "https://example.com/docs/v2/"},
+		{"absolute base", `<html><head><base href="https://example.com/other/"></head><body></body></html>`, "https://example.com/other/"},
+		{"empty base", `<html><head><base href=""></head><body></body></html>`, "https://example.com/section/page.html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			// Resolve from a link element, as the link callbacks do
+			if result := extractor.ExtractBaseURL(doc.Find("body").First(), pageURL); result.String() != tt.expected {
+				t.Errorf("ExtractBaseURL() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
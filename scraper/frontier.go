@@ -0,0 +1,153 @@
+package scraper
+
+import (
+	"container/heap"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"docscraper/config"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// FrontierEntry is a discovered link waiting to be visited
+type FrontierEntry struct {
+	URL      string
+	Priority int
+	parent   *colly.Request // Request the link was found on, preserving depth and context
+	sequence int            // Discovery order, keeps equal priorities FIFO
+}
+
+// PriorityFrontier orders discovered links by the priority of the first matching pattern
+type PriorityFrontier struct {
+	patterns   []*regexp.Regexp
+	priorities []int
+	entries    frontierHeap
+	sequence   int
+	mutex      sync.Mutex
+}
+
+// NewPriorityFrontier compiles the priority patterns into a frontier
+func NewPriorityFrontier(patterns []config.PriorityPattern) (*PriorityFrontier, error) {
+	frontier := &PriorityFrontier{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority pattern %q: %v", pattern.Pattern, err)
+		}
+		frontier.patterns = append(frontier.patterns, re)
+		frontier.priorities = append(frontier.priorities, pattern.Priority)
+	}
+	return frontier, nil
+}
+
+// PriorityOf returns the priority of the first pattern matching link, or 0 when none match
+func (f *PriorityFrontier) PriorityOf(link string) int {
+	for i, re := range f.patterns {
+		if re.MatchString(link) {
+			return f.priorities[i]
+		}
+	}
+	return 0
+}
+
+// Push adds a link discovered on parent to the frontier
+func (f *PriorityFrontier) Push(parent *colly.Request, link string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.sequence++
+	heap.Push(&f.entries, &FrontierEntry{
+		URL:      link,
+		Priority: f.PriorityOf(link),
+		parent:   parent,
+		sequence: f.sequence,
+	})
+}
+
+// Pop removes and returns the highest-priority link
+func (f *PriorityFrontier) Pop() (*FrontierEntry, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.entries) == 0 {
+		return nil, false
+	}
+	return heap.Pop(&f.entries).(*FrontierEntry), true
+}
+
+// Len returns the number of links waiting in the frontier
+func (f *PriorityFrontier) Len() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.entries)
+}
+
+// depths returns the depth each waiting link will be visited at, for state_file
+func (f *PriorityFrontier) depths() map[string]int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	depths := make(map[string]int, len(f.entries))
+	for _, entry := range f.entries {
+		depths[entry.URL] = entry.parent.Depth + 1
+	}
+	return depths
+}
+
+// frontierHeap implements heap.Interface, highest priority first
+type frontierHeap []*FrontierEntry
+
+func (h frontierHeap) Len() int { return len(h) }
+
+func (h frontierHeap) Less(i, j int) bool {
+	if h[i].Priority != h[j].Priority {
+		return h[i].Priority > h[j].Priority
+	}
+	return h[i].sequence < h[j].sequence
+}
+
+func (h frontierHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *frontierHeap) Push(x interface{}) { *h = append(*h, x.(*FrontierEntry)) }
+
+func (h *frontierHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// enqueueLink visits link now, or defers it to the priority frontier when priority patterns are set
+func (s *Scraper) enqueueLink(parent *colly.Request, link string) {
+	s.recordConditionalLink(parent, parent.AbsoluteURL(link))
+	if s.checksWithHead(parent.AbsoluteURL(link), parent.Depth+1) {
+		s.checkLink(parent, parent.AbsoluteURL(link))
+		return
+	}
+	if s.frontier == nil {
+		parent.Visit(link)
+		return
+	}
+	s.frontier.Push(parent, parent.AbsoluteURL(link))
+}
+
+// drainFrontier visits queued links in priority order, wave by wave, until no new links are found
+func (s *Scraper) drainFrontier() {
+	if s.frontier == nil {
+		return
+	}
+
+	for s.frontier.Len() > 0 {
+		for {
+			entry, ok := s.frontier.Pop()
+			if !ok {
+				break
+			}
+			s.logger.Printf("Visiting %s (priority %d)", entry.URL, entry.Priority)
+			entry.parent.Visit(entry.URL)
+		}
+		s.collector.Wait()
+	}
+}
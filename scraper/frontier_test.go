@@ -0,0 +1,98 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestPriorityFrontier_Order(t *testing.T) {
+	frontier, err := NewPriorityFrontier([]config.PriorityPattern{
+		{Pattern: `/docs/`, Priority: 10},
+		{Pattern: `/blog/`, Priority: -5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// All discovered on the same page, in an order that favours low-priority links
+	discovered := []string{
+		"https://example.com/blog/post-1",
+		"https://example.com/about",
+		"https://example.com/docs/install",
+		"https://example.com/blog/post-2",
+		"https://example.com/docs/configure",
+		"https://example.com/contact",
+	}
+	for _, link := range discovered {
+		frontier.Push(nil, link)
+	}
+
+	expected := []string{
+		"https://example.com/docs/install",
+		"https://example.com/docs/configure",
+		"https://example.com/about",
+		"https://example.com/contact",
+		"https://example.com/blog/post-1",
+		"https://example.com/blog/post-2",
+	}
+
+	if frontier.Len() != len(expected) {
+		t.Fatalf("Len() = %d, want %d", frontier.Len(), len(expected))
+	}
+
+	for i, want := range expected {
+		entry, ok := frontier.Pop()
+		if !ok {
+			t.Fatalf("Pop() #%d returned no entry", i)
+		}
+		if entry.URL != want {
+			t.Errorf("Pop() #%d = %s, want %s", i, entry.URL, want)
+		}
+	}
+
+	if _, ok := frontier.Pop(); ok {
+		t.Error("Expected empty frontier after popping every entry")
+	}
+}
+
+func TestNewPriorityFrontier_InvalidPattern(t *testing.T) {
+	if _, err := NewPriorityFrontier([]config.PriorityPattern{{Pattern: "(", Priority: 1}}); err == nil {
+		t.Error("Expected error for invalid priority pattern")
+	}
+}
+
+func TestScraper_PriorityPatternsCrawlEverything(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "<p>Content for "+r.URL.Path+"</p>"))
+			return
+		}
+		fmt.Fprint(w, htmlPage("Home", `<a href="/blog/news">News</a><a href="/docs/start">Start</a><a href="/faq">FAQ</a>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:  server.URL + "/",
+		MaxDepth: 2,
+		PriorityPatterns: []config.PriorityPattern{
+			{Pattern: `/docs/`, Priority: 10},
+			{Pattern: `/blog/`, Priority: -1},
+		},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if s.GetPageCount() != 4 {
+		t.Errorf("Expected all 4 pages to be scraped through the frontier, got %d", s.GetPageCount())
+	}
+	if s.frontier.Len() != 0 {
+		t.Errorf("Expected drained frontier, %d links left", s.frontier.Len())
+	}
+}
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// hostLimiter installs a colly limit rule per host the first time it is requested, so each
+// host gets its own parallelism instead of sharing a single "*" rule
+type hostLimiter struct {
+	collector   *colly.Collector
+	parallelism int
+
+	hosts map[string]bool
+	mutex sync.Mutex
+}
+
+// newHostLimiter creates a limiter applying parallelism to every host
+func newHostLimiter(c *colly.Collector, parallelism int) *hostLimiter {
+	return &hostLimiter{
+		collector:   c,
+		parallelism: parallelism,
+		hosts:       make(map[string]bool),
+	}
+}
+
+// ensureRule adds the limit rule for the request's host before it is fetched
+func (l *hostLimiter) ensureRule(r *colly.Request) {
+	host := r.URL.Host
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.hosts[host] {
+		return
+	}
+	l.hosts[host] = true
+	l.collector.Limit(&colly.LimitRule{
+		DomainGlob:  host,
+		Parallelism: l.parallelism,
+	})
+}
+
+// workerCapTransport bounds the number of in-flight requests across all hosts. A slot is
+// held from the round trip until the response body is closed.
+type workerCapTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+// newWorkerCapTransport wraps base, allowing at most workers concurrent requests
+func newWorkerCapTransport(base http.RoundTripper, workers int) *workerCapTransport {
+	return &workerCapTransport{base: base, slots: make(chan struct{}, workers)}
+}
+
+// RoundTrip waits for a free worker slot and performs the request
+func (t *workerCapTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.slots <- struct{}{}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		<-t.slots
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { <-t.slots }}
+	return resp, nil
+}
+
+// releasingBody frees its worker slot once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+// Close closes the body and releases the worker slot
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
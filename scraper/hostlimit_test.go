@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// concurrencyRecorder tracks in-flight requests per host and overall
+type concurrencyRecorder struct {
+	mutex      sync.Mutex
+	inFlight   map[string]int
+	maxPerHost map[string]int
+	total      int
+	maxTotal   int
+}
+
+// handler wraps next, recording concurrency for host while a request is served
+func (c *concurrencyRecorder) handler(host string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c.mutex.Lock()
+		c.inFlight[host]++
+		c.total++
+		if c.inFlight[host] > c.maxPerHost[host] {
+			c.maxPerHost[host] = c.inFlight[host]
+		}
+		if c.total > c.maxTotal {
+			c.maxTotal = c.total
+		}
+		c.mutex.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		next(w, r)
+
+		c.mutex.Lock()
+		c.inFlight[host]--
+		c.total--
+		c.mutex.Unlock()
+	}
+}
+
+func TestScraper_PerHostParallelism(t *testing.T) {
+	recorder := &concurrencyRecorder{inFlight: make(map[string]int), maxPerHost: make(map[string]int)}
+	page := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "Content for "+r.URL.Path))
+	}
+
+	serverB := httptest.NewServer(recorder.handler("b", page))
+	defer serverB.Close()
+
+	var serverA *httptest.Server
+	serverA = httptest.NewServer(recorder.handler("a", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listing" {
+			page(w, r)
+			return
+		}
+		var items []map[string]string
+		for i := 1; i <= 4; i++ {
+			items = append(items,
+				map[string]string{"url": fmt.Sprintf("%s/docs/a%d", serverA.URL, i)},
+				map[string]string{"url": fmt.Sprintf("%s/docs/b%d", serverB.URL, i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+	defer serverA.Close()
+
+	perHost, global := 1, 4
+	s := newTestScraper(t, &config.Config{
+		RootURL:            serverA.URL + "/",
+		MaxDepth:           1,
+		ConcurrentRequests: &global,
+		PerHostParallelism: &perHost,
+		APIListingURL:      serverA.URL + "/listing",
+		APIListingPath:     "items.*.url",
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if s.GetPageCount() != 9 {
+		t.Errorf("Expected 9 pages across both hosts, got %d", s.GetPageCount())
+	}
+	for host, max := range recorder.maxPerHost {
+		if max > perHost {
+			t.Errorf("Host %s served %d concurrent requests, want at most %d", host, max, perHost)
+		}
+	}
+	if recorder.maxTotal < 2 {
+		t.Errorf("Expected concurrency across hosts to exceed the per-host limit, max was %d", recorder.maxTotal)
+	}
+	if recorder.maxTotal > global {
+		t.Errorf("Total concurrency %d exceeded the global cap %d", recorder.maxTotal, global)
+	}
+}
+
+func TestWorkerCapTransport(t *testing.T) {
+	recorder := &concurrencyRecorder{inFlight: make(map[string]int), maxPerHost: make(map[string]int)}
+	server := httptest.NewServer(recorder.handler("a", func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newWorkerCapTransport(http.DefaultTransport, 2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if recorder.maxTotal > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", recorder.maxTotal)
+	}
+}
@@ -0,0 +1,127 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CrawlManifest records a finished crawl so the next sitemap_incremental run can reuse its pages
+type CrawlManifest struct {
+	ScrapedAt time.Time  `json:"scraped_at"`
+	Pages     []PageData `json:"pages"`
+}
+
+// sitemapLastMods maps each sitemap <loc> to its parsed <lastmod>; entries without a
+// parseable lastmod map to the zero time
+func (s *Scraper) sitemapLastMods(sitemapURL string) (map[string]time.Time, error) {
+	entries, err := s.collectSitemapEntries(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	lastMods := make(map[string]time.Time, len(entries))
+	for _, entry := range entries {
+		loc := strings.TrimSpace(entry.Loc)
+		if loc == "" {
+			continue
+		}
+		lastMod, _ := parseLastModified(strings.TrimSpace(entry.LastMod))
+		lastMods[urlMatchKey(loc)] = lastMod
+	}
+	return lastMods, nil
+}
+
+// urlMatchKey normalizes a URL for matching sitemap, manifest, request and frontier URLs
+func urlMatchKey(rawURL string) string {
+	return strings.TrimSuffix(rawURL, "/")
+}
+
+// loadManifest reads the manifest left by a previous crawl
+func loadManifest(path string) (*CrawlManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest CrawlManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid crawl manifest %s: %v", path, err)
+	}
+	return &manifest, nil
+}
+
+// prepareIncremental reuses pages from the prior manifest whose sitemap lastmod is not newer
+// than the last scrape and returns the URLs that must be fetched again. Without a manifest or
+// sitemap nothing is reused and the crawl runs in full.
+func (s *Scraper) prepareIncremental() []string {
+	manifest, err := loadManifest(s.config.GetManifestFile())
+	if err != nil {
+		s.logger.Printf("No usable crawl manifest, running a full crawl: %v", err)
+		return nil
+	}
+
+	lastMods, err := s.sitemapLastMods(s.config.GetSitemapURL())
+	if err != nil {
+		s.logger.Printf("Warning: Could not load sitemap, running a full crawl: %v", err)
+		return nil
+	}
+
+	s.reusedURLs = make(map[string]bool)
+	var seeds []string
+	known := make(map[string]bool, len(manifest.Pages))
+	for _, page := range manifest.Pages {
+		key := urlMatchKey(page.URL)
+		known[key] = true
+
+		lastMod, listed := lastMods[key]
+		if listed && !lastMod.IsZero() && !lastMod.After(manifest.ScrapedAt) {
+			s.reusedURLs[key] = true
+			s.storePage(page)
+			continue
+		}
+		seeds = append(seeds, page.URL)
+	}
+
+	for loc := range lastMods {
+		if !known[loc] {
+			seeds = append(seeds, loc)
+		}
+	}
+
+	s.logger.Printf("Incremental crawl: reusing %d pages, fetching %d changed or new URLs", len(s.reusedURLs), len(seeds))
+	return seeds
+}
+
+// isReused reports whether a URL's page was carried over from the prior manifest
+func (s *Scraper) isReused(rawURL string) bool {
+	return s.reusedURLs[urlMatchKey(rawURL)]
+}
+
+// saveManifest records this crawl's pages for the next incremental run
+func (s *Scraper) saveManifest(scrapedAt time.Time) error {
+	path := s.config.GetManifestFile()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %v", err)
+	}
+
+	// Spilled content lives in temp files, so the manifest stores it inline
+	pages := make([]PageData, len(s.pages))
+	for i, page := range s.pages {
+		content, err := page.LoadContent()
+		if err != nil {
+			return err
+		}
+		page.Content, page.ContentFile = content, ""
+		pages[i] = page
+	}
+
+	data, err := json.MarshalIndent(CrawlManifest{ScrapedAt: scrapedAt, Pages: pages}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,148 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// newSitemapServer serves a root page linking to /a and /b plus a sitemap with the given
+// lastmod per path, and counts page fetches
+func newSitemapServer(t *testing.T, lastMods map[string]string) (*httptest.Server, func() map[string]int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	fetched := make(map[string]int)
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		for _, path := range []string{"/", "/a", "/b"} {
+			fmt.Fprintf(w, "<url><loc>%s%s</loc><lastmod>%s</lastmod></url>", server.URL, path, lastMods[path])
+		}
+		fmt.Fprint(w, `</urlset>`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetched[r.URL.Path]++
+		mu.Unlock()
+
+		body := "Fresh content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/a">A</a> <a href="/b">B</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		result := make(map[string]int, len(fetched))
+		for path, count := range fetched {
+			result[path] = count
+		}
+		return result
+	}
+}
+
+func TestScraper_SitemapIncremental(t *testing.T) {
+	server, fetched := newSitemapServer(t, map[string]string{
+		"/":  "2024-05-01",
+		"/a": "2024-05-01",
+		"/b": "2024-07-01T12:00:00Z",
+	})
+
+	outputDir := t.TempDir()
+	manifest := CrawlManifest{
+		ScrapedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Pages: []PageData{
+			{Title: "Root", URL: server.URL + "/", Content: "Stored content for /", Depth: 1},
+			{Title: "A", URL: server.URL + "/a", Content: "Stored content for /a", Depth: 2},
+			{Title: "B", URL: server.URL + "/b", Content: "Stored content for /b", Depth: 2},
+		},
+	}
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(filepath.Join(outputDir, "crawl_manifest.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	incremental := true
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL,
+		OutputDir:          outputDir,
+		MaxDepth:           3,
+		SitemapIncremental: &incremental,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	got := fetched()
+	if len(got) != 1 || got["/b"] != 1 {
+		t.Errorf("only /b should be re-fetched, got %v", got)
+	}
+
+	contents := make(map[string]string)
+	for _, page := range s.GetPages() {
+		contents[page.URL] = page.Content
+	}
+	if len(contents) != 3 {
+		t.Fatalf("expected 3 pages, got %v", contents)
+	}
+	if contents[server.URL+"/a"] != "Stored content for /a" {
+		t.Errorf("unchanged page should be reused, got %q", contents[server.URL+"/a"])
+	}
+	if contents[server.URL+"/b"] == "Stored content for /b" {
+		t.Errorf("changed page should be re-fetched, got stored content")
+	}
+
+	saved, err := loadManifest(filepath.Join(outputDir, "crawl_manifest.json"))
+	if err != nil {
+		t.Fatalf("manifest should be rewritten: %v", err)
+	}
+	if !saved.ScrapedAt.After(manifest.ScrapedAt) || len(saved.Pages) != 3 {
+		t.Errorf("rewritten manifest = %v with %d pages", saved.ScrapedAt, len(saved.Pages))
+	}
+}
+
+func TestScraper_SitemapIncrementalWithoutManifest(t *testing.T) {
+	server, fetched := newSitemapServer(t, map[string]string{"/": "2024-05-01", "/a": "2024-05-01", "/b": "2024-05-01"})
+
+	outputDir := t.TempDir()
+	incremental := true
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL,
+		OutputDir:          outputDir,
+		MaxDepth:           3,
+		SitemapIncremental: &incremental,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	got := fetched()
+	if got["/"] != 1 || got["/a"] != 1 || got["/b"] != 1 {
+		t.Errorf("first run should crawl every page, got %v", got)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "crawl_manifest.json")); err != nil {
+		t.Errorf("manifest should be written after the first run: %v", err)
+	}
+}
+
+func TestURLMatchKey(t *testing.T) {
+	if urlMatchKey("https://example.com/") != urlMatchKey("https://example.com") {
+		t.Error("trailing slash should not affect URL matching")
+	}
+}
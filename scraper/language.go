@@ -0,0 +1,23 @@
+package scraper
+
+// EnglishWords are the common English words DetectLanguage scores by default
+var EnglishWords = []string{"the", "and", "or", "but", "in", "on", "at", "to", "for", "of", "with", "by"}
+
+// CommonLanguageWords maps language codes to frequent words, for NewContentQualityAnalyzerWithLanguages
+var CommonLanguageWords = map[string][]string{
+	"en": EnglishWords,
+	"es": {"el", "la", "los", "las", "de", "del", "que", "y", "en", "un", "una", "por", "con", "para", "es", "se"},
+	"fr": {"le", "la", "les", "de", "des", "du", "et", "en", "un", "une", "est", "pour", "dans", "que", "qui", "sur"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "den", "dem", "ein", "eine", "zu", "auf", "für", "von", "sie"},
+}
+
+// NewContentQualityAnalyzerWithLanguages creates a content quality analyzer whose language
+// detection scores each language code by its list of common words
+func NewContentQualityAnalyzerWithLanguages(config QualityConfig, langs map[string][]string) *ContentQualityAnalyzer {
+	analyzer := NewContentQualityAnalyzer(config)
+	analyzer.languages = make(map[string]map[string]bool, len(langs))
+	for lang, words := range langs {
+		analyzer.languages[lang] = wordSet(words)
+	}
+	return analyzer
+}
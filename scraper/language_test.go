@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestContentQualityAnalyzer_DetectLanguage_WithLanguages(t *testing.T) {
+	analyzer := NewContentQualityAnalyzerWithLanguages(QualityConfig{}, CommonLanguageWords)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"spanish snippet", "La instalación del paquete es sencilla: descarga el archivo y ejecuta el comando en la terminal.", "es"},
+		{"french snippet", "Le serveur démarre sur le port par défaut et les requêtes sont enregistrées dans la console.", "fr"},
+		{"german snippet", "Der Server startet auf dem Standardport und die Anfragen werden in der Konsole protokolliert.", "de"},
+		{"english snippet", "The server starts on the default port and logs requests to the console.", "en"},
+		{"no common words", "Kubernetes Helm Terraform Ansible", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := analyzer.DetectLanguage(tt.content)
+			if got != tt.want {
+				t.Errorf("DetectLanguage() = %q (confidence %.2f), want %q", got, confidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentQualityAnalyzer_DetectLanguage_DefaultsToEnglish(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{})
+
+	if got, _ := analyzer.DetectLanguage("The server starts on the default port."); got != "en" {
+		t.Errorf("Expected English detected by default, got %q", got)
+	}
+	if got, _ := analyzer.DetectLanguage("La instalación del paquete es sencilla y rápida."); got != "unknown" {
+		t.Errorf("Expected Spanish to be unknown without its word list, got %q", got)
+	}
+}
+
+func TestContentQualityAnalyzer_DetectLanguage_Confidence(t *testing.T) {
+	analyzer := NewContentQualityAnalyzerWithLanguages(QualityConfig{}, CommonLanguageWords)
+
+	spanish := strings.Repeat("La instalación del paquete es sencilla: descarga el archivo y ejecuta el comando en la terminal. ", 5)
+	lang, long := analyzer.DetectLanguage(spanish)
+	if lang != "es" || long < defaultMinLanguageConfidence {
+		t.Errorf("Expected confident Spanish for long text, got %q (%.2f)", lang, long)
+	}
+
+	if _, short := analyzer.DetectLanguage("el archivo"); short >= defaultMinLanguageConfidence {
+		t.Errorf("Expected low confidence for a two-word snippet, got %.2f", short)
+	}
+
+	// "de la" and "en" are common to Spanish and French, so they decide nothing
+	if _, shared := analyzer.DetectLanguage(strings.Repeat("de la en ", 20)); shared != 0 {
+		t.Errorf("Expected zero confidence when languages tie, got %.2f", shared)
+	}
+}
+
+func TestEnhancedScraper_FilterByLanguage(t *testing.T) {
+	bodies := map[string]string{
+		"/":        `<a href="/en">English</a> <a href="/es">Spanish</a> <a href="/unknown">Unknown</a> ` + strings.Repeat("The guides below are available in several languages. ", 8),
+		"/en":      strings.Repeat("The server starts on the default port and logs requests to the console. ", 8),
+		"/es":      strings.Repeat("La instalación del paquete es sencilla: descarga el archivo y ejecuta el comando en la terminal. ", 8),
+		"/unknown": strings.Repeat("Kubernetes Helm Terraform Ansible Prometheus Grafana ", 12),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Page", "<main><p>"+body+"</p></main>"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		allowUnknown bool
+		want         []string
+	}{
+		{"unknown dropped", false, []string{"/", "/en"}},
+		{"unknown allowed", true, []string{"/", "/en", "/unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile, err := os.CreateTemp("", "test-log-*.log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			logFile.Close()
+			defer os.Remove(logFile.Name())
+
+			enableQuality := true
+			es, err := NewWithFeatures(&config.Config{
+				RootURL:               server.URL + "/",
+				MaxDepth:              2,
+				OutputDir:             t.TempDir(),
+				OutputFormat:          "markdown",
+				OutputType:            "single",
+				LogFile:               logFile.Name(),
+				EnableQualityAnalysis: &enableQuality,
+				QualityAnalysis: config.QualityConfig{
+					MinScore:             0.01,
+					MinWordCount:         1,
+					FilterByLanguage:     "en",
+					AllowUnknownLanguage: tt.allowUnknown,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages, err := es.ScrapeWithFeatures()
+			if err != nil {
+				t.Fatalf("ScrapeWithFeatures() error = %v", err)
+			}
+
+			var got []string
+			for _, page := range pages {
+				got = append(got, strings.TrimPrefix(page.URL, server.URL))
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected pages %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package scraper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LinkCheckResult holds the outcome of checking a single link
+type LinkCheckResult struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	Broken     bool   `json:"broken"`
+}
+
+// LinkChecker validates external links with bounded concurrency
+type LinkChecker struct {
+	client      *http.Client
+	concurrency int
+}
+
+// NewLinkChecker creates a link checker running at most concurrency requests at once
+func NewLinkChecker(concurrency int, timeout time.Duration) *LinkChecker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	return &LinkChecker{
+		client:      &http.Client{Timeout: timeout},
+		concurrency: concurrency,
+	}
+}
+
+// Check checks all URLs and returns one result per URL in input order
+func (lc *LinkChecker) Check(urls []string) []LinkCheckResult {
+	results := make([]LinkCheckResult, len(urls))
+	sem := make(chan struct{}, lc.concurrency)
+	var wg sync.WaitGroup
+
+	for i, link := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, link string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = lc.checkLink(link)
+		}(i, link)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// checkLink issues a HEAD request, falling back to GET for servers that reject HEAD
+func (lc *LinkChecker) checkLink(link string) LinkCheckResult {
+	result := LinkCheckResult{URL: link}
+
+	resp, err := lc.client.Head(link)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = lc.client.Get(link)
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Broken = true
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.Broken = resp.StatusCode >= 400
+	return result
+}
+
+// NewLinkChecker creates a link checker using the scraper's link-check settings
+func (s *Scraper) NewLinkChecker() *LinkChecker {
+	return NewLinkChecker(
+		s.config.GetLinkCheckConcurrency(),
+		time.Duration(s.config.GetLinkCheckTimeout())*time.Second,
+	)
+}
+
+// linkCheckReport is the layout of link_check.json
+type linkCheckReport struct {
+	Total   int               `json:"total"`
+	Broken  int               `json:"broken"`
+	Results []LinkCheckResult `json:"results"`
+}
+
+// recordExternalLink remembers an http(s) link to another host for check_external_links
+func (s *Scraper) recordExternalLink(u *url.URL) {
+	if !s.config.GetCheckExternalLinks() || (u.Scheme != "http" && u.Scheme != "https") {
+		return
+	}
+
+	link := *u
+	link.Fragment = ""
+
+	s.linkCheckMutex.Lock()
+	defer s.linkCheckMutex.Unlock()
+	if s.externalLinks == nil {
+		s.externalLinks = make(map[string]bool)
+	}
+	s.externalLinks[link.String()] = true
+}
+
+// checkExternalLinks checks the external links found during the crawl and writes the results
+// to link_check.json in the output directory
+func (s *Scraper) checkExternalLinks() error {
+	s.linkCheckMutex.Lock()
+	links := make([]string, 0, len(s.externalLinks))
+	for link := range s.externalLinks {
+		links = append(links, link)
+	}
+	s.linkCheckMutex.Unlock()
+	sort.Strings(links)
+
+	results := s.NewLinkChecker().Check(links)
+	broken := 0
+	for _, result := range results {
+		if result.Broken {
+			broken++
+			s.logger.Printf("Broken external link: %s (status %d) %s", result.URL, result.StatusCode, result.Error)
+		}
+	}
+	s.logger.Printf("Checked %d external links, %d broken", len(results), broken)
+
+	s.linkCheckMutex.Lock()
+	s.linkCheckResults = results
+	s.linkCheckMutex.Unlock()
+
+	if err := os.MkdirAll(s.config.OutputDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(linkCheckReport{Total: len(results), Broken: broken, Results: results}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.config.OutputDir, "link_check.json"), data, 0644)
+}
+
+// GetLinkCheckResults returns the outcome of checking external links under check_external_links,
+// sorted by URL
+func (s *Scraper) GetLinkCheckResults() []LinkCheckResult {
+	s.linkCheckMutex.Lock()
+	defer s.linkCheckMutex.Unlock()
+	return append([]LinkCheckResult(nil), s.linkCheckResults...)
+}
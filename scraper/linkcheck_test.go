@@ -0,0 +1,135 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestLinkChecker_RespectsConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	urls := make([]string, 0, 12)
+	for i := 0; i < 11; i++ {
+		urls = append(urls, fmt.Sprintf("%s/page%d", server.URL, i))
+	}
+	urls = append(urls, server.URL+"/missing")
+
+	checker := NewLinkChecker(3, 5*time.Second)
+	results := checker.Check(urls)
+
+	if max := atomic.LoadInt32(&maxInFlight); max > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, observed %d", max)
+	}
+
+	if len(results) != len(urls) {
+		t.Fatalf("Expected %d results, got %d", len(urls), len(results))
+	}
+
+	for i, result := range results {
+		if result.URL != urls[i] {
+			t.Errorf("Result %d URL = %s, want %s", i, result.URL, urls[i])
+		}
+	}
+
+	if !results[len(results)-1].Broken || results[len(results)-1].StatusCode != http.StatusNotFound {
+		t.Errorf("Expected /missing to be reported broken with 404, got %+v", results[len(results)-1])
+	}
+	if results[0].Broken {
+		t.Errorf("Expected /page0 to be healthy, got %+v", results[0])
+	}
+}
+
+func TestLinkChecker_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	checker := NewLinkChecker(1, 50*time.Millisecond)
+	results := checker.Check([]string{server.URL})
+
+	if !results[0].Broken || results[0].Error == "" {
+		t.Errorf("Expected timed out link to be broken with an error, got %+v", results[0])
+	}
+}
+
+func TestScraper_CheckExternalLinks(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer external.Close()
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", `<p>See the references.</p>`+
+			`<a href="`+external.URL+`/ok#intro">OK</a><a href="`+external.URL+`/gone">Gone</a>`+
+			`<a href="`+external.URL+`/ok">OK again</a>`))
+	}))
+	defer site.Close()
+
+	check := true
+	outputDir := t.TempDir()
+	s := newTestScraper(t, &config.Config{
+		RootURL:            site.URL + "/",
+		MaxDepth:           1,
+		OutputDir:          outputDir,
+		CheckExternalLinks: &check,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	// Each link is checked once, without its fragment
+	want := []LinkCheckResult{
+		{URL: external.URL + "/gone", StatusCode: http.StatusNotFound, Broken: true},
+		{URL: external.URL + "/ok", StatusCode: http.StatusOK},
+	}
+	if got := s.GetLinkCheckResults(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetLinkCheckResults() = %+v, want %+v", got, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "link_check.json"))
+	if err != nil {
+		t.Fatalf("Expected link_check.json: %v", err)
+	}
+	var report linkCheckReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Invalid link_check.json: %v", err)
+	}
+	if report.Total != 2 || report.Broken != 1 {
+		t.Errorf("Expected 2 links with 1 broken, got %d with %d broken", report.Total, report.Broken)
+	}
+}
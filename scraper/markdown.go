@@ -0,0 +1,206 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// blockMarker delimits the index of a markdown block held out of whitespace normalization
+const blockMarker = "\uE001"
+
+// blockPlaceholderPattern matches a markdown block placeholder
+var blockPlaceholderPattern = regexp.MustCompile(blockMarker + `(\d+)` + blockMarker)
+
+// blockPlaceholderRunPattern matches adjacent placeholders together with surrounding whitespace
+var blockPlaceholderRunPattern = regexp.MustCompile(`(?:\s*` + blockMarker + `\d+` + blockMarker + `)+\s*`)
+
+// markdownText flattens sel to text like Selection.Text, except that headings, lists and <pre>
+// blocks become placeholders for the returned markdown blocks and inline <code> is wrapped in
+// backticks. The document is not modified, since callbacks share it.
+func (e *ContentExtractor) markdownText(sel *goquery.Selection) (string, []string) {
+	var text strings.Builder
+	var blocks []string
+
+	hold := func(block string) {
+		if block != "" {
+			text.WriteString(blockMarker + strconv.Itoa(len(blocks)) + blockMarker)
+			blocks = append(blocks, block)
+		}
+	}
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			return
+		case html.ElementNode:
+			switch node.Data {
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				hold(e.headingMarkdown(node))
+				return
+			case "ul", "ol":
+				hold(strings.Join(e.listMarkdown(node, ""), "\n"))
+				return
+			case "pre":
+				hold(fencedCodeBlock(node))
+				return
+			case "code":
+				text.WriteString(inlineCode(node))
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	for _, node := range sel.Nodes {
+		walk(node)
+	}
+	return text.String(), blocks
+}
+
+// cleanTextAroundBlocks runs cleanText over the text between the placeholders left by
+// markdownText, so noise patterns cannot eat into a placeholder
+func (e *ContentExtractor) cleanTextAroundBlocks(text string) string {
+	placeholders := blockPlaceholderPattern.FindAllString(text, -1)
+	var cleaned strings.Builder
+	for i, segment := range blockPlaceholderPattern.Split(text, -1) {
+		cleaned.WriteString(e.cleanText(segment))
+		if i < len(placeholders) {
+			cleaned.WriteString(" " + placeholders[i] + " ")
+		}
+	}
+	return strings.TrimSpace(cleaned.String())
+}
+
+// restoreBlocks replaces the placeholders left by markdownText with their markdown blocks,
+// each set off by blank lines
+func restoreBlocks(text string, blocks []string) string {
+	if len(blocks) == 0 {
+		return text
+	}
+	text = blockPlaceholderRunPattern.ReplaceAllStringFunc(text, func(run string) string {
+		var markdown []string
+		for _, match := range blockPlaceholderPattern.FindAllStringSubmatch(run, -1) {
+			if index, err := strconv.Atoi(match[1]); err == nil && index < len(blocks) {
+				markdown = append(markdown, blocks[index])
+			}
+		}
+		if len(markdown) == 0 {
+			return " "
+		}
+		return "\n\n" + strings.Join(markdown, "\n\n") + "\n\n"
+	})
+	return strings.TrimSpace(text)
+}
+
+// headingMarkdown renders an <hN> element as a "#"-prefixed line, with a {#id} attribute when
+// heading ids are preserved
+func (e *ContentExtractor) headingMarkdown(heading *html.Node) string {
+	text := e.cleanText(inlineText(heading))
+	if text == "" {
+		return ""
+	}
+
+	line := strings.Repeat("#", int(heading.Data[1]-'0')) + " " + text
+	if id := strings.TrimSpace(attrValue(heading, "id")); id != "" && e.config.PreserveHeadingIDs {
+		line += fmt.Sprintf(" {#%s}", id)
+	}
+	return line
+}
+
+// listMarkdown renders a <ul> or <ol> as "-" or "1." lines, nesting sublists under their item
+// indented to the item's text
+func (e *ContentExtractor) listMarkdown(list *html.Node, indent string) []string {
+	number := 1
+	if start, err := strconv.Atoi(strings.TrimSpace(attrValue(list, "start"))); err == nil {
+		number = start
+	}
+
+	var lines []string
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if list.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		if text := e.cleanText(inlineText(item)); text != "" {
+			lines = append(lines, indent+marker+text)
+		}
+
+		for _, sublist := range sublists(item) {
+			lines = append(lines, e.listMarkdown(sublist, indent+strings.Repeat(" ", len(marker)))...)
+		}
+	}
+	return lines
+}
+
+// sublists returns the outermost lists nested inside a list item
+func sublists(item *html.Node) []*html.Node {
+	var lists []*html.Node
+	var find func(node *html.Node)
+	find = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && (child.Data == "ul" || child.Data == "ol") {
+				lists = append(lists, child)
+				continue
+			}
+			find(child)
+		}
+	}
+	find(item)
+	return lists
+}
+
+// inlineText returns the text of node for a single markdown line: inline <code> is wrapped in
+// backticks and nested lists are left to listMarkdown
+func inlineText(node *html.Node) string {
+	var text strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch {
+		case node.Type == html.TextNode:
+			text.WriteString(node.Data)
+			return
+		case node.Type == html.ElementNode && (node.Data == "ul" || node.Data == "ol"):
+			return
+		case node.Type == html.ElementNode && node.Data == "code":
+			text.WriteString(inlineCode(node))
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	return text.String()
+}
+
+// inlineCode renders a <code> element as backtick-wrapped inline code
+func inlineCode(code *html.Node) string {
+	if text := strings.TrimSpace(nodeText(code)); text != "" {
+		return "`" + text + "`"
+	}
+	return ""
+}
+
+// attrValue returns the value of an attribute of node, or "" when absent
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
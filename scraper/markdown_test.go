@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestContentExtractor_ExtractContent_Markdown(t *testing.T) {
+	html := `<html><body><main>
+		<h1>Guide</h1>
+		<p>Intro   text.</p>
+		<h2>Install</h2>
+		<ul>
+			<li>Download the <code>cli</code> binary</li>
+			<li>Configure
+				<ol start="3">
+					<li>Set the token</li>
+					<li>Set the <a href="/regions">region</a>
+						<ul><li>us-east</li></ul>
+					</li>
+				</ol>
+			</li>
+		</ul>
+		<h3>Verify</h3>
+		<p>Run it.</p>
+	</main></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	expected := "# Guide\n\n" +
+		"Intro text.\n\n" +
+		"## Install\n\n" +
+		"- Download the `cli` binary\n" +
+		"- Configure\n" +
+		"  3. Set the token\n" +
+		"  4. Set the region\n" +
+		"     - us-east\n\n" +
+		"### Verify\n\n" +
+		"Run it."
+
+	extractor := NewContentExtractor()
+	result := extractor.ExtractContent(doc.Selection)
+	if result != expected {
+		t.Errorf("ExtractContent() = %q, want %q", result, expected)
+	}
+
+	// Callbacks share the parsed document, so extracting again must give the same result
+	if again := extractor.ExtractContent(doc.Selection); again != expected {
+		t.Errorf("Second ExtractContent() = %q, want %q", again, expected)
+	}
+
+	if headers := NewContentQualityAnalyzer(QualityConfig{}).countHeaders(result); headers != 3 {
+		t.Errorf("countHeaders() = %d, want 3", headers)
+	}
+}
+
+func TestContentExtractor_ExtractContent_EmptyHeadingsAndLists(t *testing.T) {
+	html := `<html><body><main><h2> </h2><p>Only text.</p><ul><li> </li></ul></main></body></html>`
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("Failed to parse HTML: %v", err)
+	}
+
+	if result := NewContentExtractor().ExtractContent(doc.Selection); result != "Only text." {
+		t.Errorf("ExtractContent() = %q, want %q", result, "Only text.")
+	}
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+
+	"docscraper/config"
+)
+
+// markerText normalizes a configured marker to the text of the HTML comment it matches,
+// so both "exclude-start" and "<!-- exclude-start -->" are accepted
+func markerText(marker string) string {
+	marker = strings.TrimSpace(marker)
+	marker = strings.TrimPrefix(marker, "<!--")
+	marker = strings.TrimSuffix(marker, "-->")
+	return strings.TrimSpace(marker)
+}
+
+// removeMarkedRegions removes every node between matching start and end marker comments.
+// Markers may sit at different nesting levels; a start marker without an end is ignored.
+func removeMarkedRegions(doc *goquery.Selection, markers []config.MarkerPair) {
+	for _, marker := range markers {
+		start, end := markerText(marker.Start), markerText(marker.End)
+		if start == "" || end == "" {
+			continue
+		}
+
+		for _, root := range doc.Nodes {
+			nodes := documentOrder(root)
+			for i := 0; i < len(nodes); i++ {
+				if !isComment(nodes[i], start) {
+					continue
+				}
+
+				j := i + 1
+				for j < len(nodes) && !isComment(nodes[j], end) {
+					j++
+				}
+				if j == len(nodes) {
+					break
+				}
+
+				for _, node := range nodes[i+1 : j] {
+					if node.Parent != nil && !isAncestor(node, nodes[j]) {
+						node.Parent.RemoveChild(node)
+					}
+				}
+				i = j
+			}
+		}
+	}
+}
+
+// documentOrder returns root and its descendants in pre-order
+func documentOrder(root *html.Node) []*html.Node {
+	var nodes []*html.Node
+	var visit func(node *html.Node)
+	visit = func(node *html.Node) {
+		nodes = append(nodes, node)
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+	}
+	visit(root)
+	return nodes
+}
+
+// isComment reports whether node is an HTML comment with the given text
+func isComment(node *html.Node, text string) bool {
+	return node.Type == html.CommentNode && strings.TrimSpace(node.Data) == text
+}
+
+// isAncestor reports whether node contains descendant
+func isAncestor(node, descendant *html.Node) bool {
+	for parent := descendant.Parent; parent != nil; parent = parent.Parent {
+		if parent == node {
+			return true
+		}
+	}
+	return false
+}
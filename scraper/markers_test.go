@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"docscraper/config"
+)
+
+func TestContentExtractor_ExcludeMarkers(t *testing.T) {
+	extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+		TitleStrategy: TitleStrategySmart,
+		ExcludeMarkers: []config.MarkerPair{
+			{Start: "exclude-start", End: "exclude-end"},
+			{Start: "<!-- generated:begin -->", End: "<!-- generated:end -->"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		html     string
+		kept     []string
+		excluded []string
+	}{
+		{
+			name: "siblings",
+			html: `<main><p>Keep before.</p><!-- exclude-start --><p>Hidden one.</p><div>Hidden two.</div><!-- exclude-end --><p>Keep after.</p></main>`,
+			kept: []string{"Keep before.", "Keep after."}, excluded: []string{"Hidden one.", "Hidden two."},
+		},
+		{
+			name: "different nesting levels",
+			html: `<main><div><p>Keep intro.</p><!-- exclude-start --><p>Hidden nested.</p></div><p>Hidden outer.</p><div><p>Hidden inner.</p><!-- exclude-end --><p>Keep tail.</p></div></main>`,
+			kept: []string{"Keep intro.", "Keep tail."}, excluded: []string{"Hidden nested.", "Hidden outer.", "Hidden inner."},
+		},
+		{
+			name: "comment syntax in config and multiple regions",
+			html: `<main><!-- generated:begin -->Hidden A.<!-- generated:end --><p>Keep middle.</p><!--exclude-start-->Hidden B.<!--exclude-end--></main>`,
+			kept: []string{"Keep middle."}, excluded: []string{"Hidden A.", "Hidden B."},
+		},
+		{
+			name: "unterminated marker is ignored",
+			html: `<main><p>Keep first.</p><!-- exclude-start --><p>Keep unterminated.</p></main>`,
+			kept: []string{"Keep first.", "Keep unterminated."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + tt.html + "</body></html>"))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			content := extractor.ExtractContent(doc.Selection)
+			for _, want := range tt.kept {
+				if !strings.Contains(content, want) {
+					t.Errorf("Content %q is missing %q", content, want)
+				}
+			}
+			for _, unwanted := range tt.excluded {
+				if strings.Contains(content, unwanted) {
+					t.Errorf("Content %q should not contain excluded %q", content, unwanted)
+				}
+			}
+		})
+	}
+}
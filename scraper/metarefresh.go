@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// metaRefreshRedirectDelay is the longest meta refresh delay treated as a redirect; pages that
+// refresh later show their own content first and are kept
+const metaRefreshRedirectDelay = time.Second
+
+// followMetaRefresh enqueues the meta refresh target of a page when follow_meta_refresh is set,
+// reporting whether the page is a redirect interstitial that should not be stored
+func (s *Scraper) followMetaRefresh(r *colly.Request, doc *goquery.Selection) bool {
+	if !s.config.GetFollowMetaRefresh() {
+		return false
+	}
+
+	base := s.extractor.ExtractBaseURL(doc, r.URL)
+	target, delay, ok := s.extractor.ExtractMetaRefresh(doc, base)
+	if !ok {
+		return false
+	}
+	if delay > metaRefreshRedirectDelay {
+		s.logger.Printf("Not following meta refresh after %s from %s to %s", delay, r.URL.String(), target)
+		return false
+	}
+
+	if s.shouldFollowLink(target, base) {
+		s.logger.Printf("Following meta refresh from %s to %s", r.URL.String(), target)
+		s.enqueueLink(r, target)
+	} else {
+		s.logger.Printf("Rejected meta refresh from %s to %s", r.URL.String(), target)
+	}
+	return true
+}
+
+// followRedirectWithoutLocation treats a 3xx response lacking a Location header as a redirect
+// to the meta refresh target in its body, which colly otherwise reports as an error
+func (s *Scraper) followRedirectWithoutLocation(r *colly.Response) {
+	if !s.config.GetFollowMetaRefresh() || r.StatusCode < 300 || r.StatusCode >= 400 {
+		return
+	}
+	if r.Headers != nil && r.Headers.Get("Location") != "" {
+		return
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(r.Body))
+	if err != nil {
+		return
+	}
+	s.followMetaRefresh(r.Request, doc.Selection)
+}
@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"docscraper/config"
+)
+
+func TestContentExtractor_ExtractMetaRefresh(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs/old")
+	tests := []struct {
+		name     string
+		meta     string
+		expected string
+		delay    time.Duration
+	}{
+		{"relative url", `<meta http-equiv="refresh" content="0;url=/docs/new">`, "https://example.com/docs/new", 0},
+		{"quoted url with spaces", `<meta http-equiv="Refresh" content="5; URL='next'">`, "https://example.com/docs/next", 5 * time.Second},
+		{"absolute url", `<meta http-equiv="refresh" content="0, url=https://example.com/other">`, "https://example.com/other", 0},
+		{"fractional delay", `<meta http-equiv="refresh" content="0.5;url=/docs/new">`, "https://example.com/docs/new", 500 * time.Millisecond},
+		{"no delay", `<meta http-equiv="refresh" content=";url=/docs/new">`, "https://example.com/docs/new", 0},
+		{"reload only", `<meta http-equiv="refresh" content="30">`, "", 0},
+		{"no refresh", `<meta name="description" content="0;url=/docs/new">`, "", 0},
+	}
+
+	extractor := NewContentExtractor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + tt.meta + "</head><body></body></html>"))
+			if err != nil {
+				t.Fatalf("Failed to parse HTML: %v", err)
+			}
+
+			target, delay, ok := extractor.ExtractMetaRefresh(doc.Selection, base)
+			if target != tt.expected || delay != tt.delay || ok != (tt.expected != "") {
+				t.Errorf("ExtractMetaRefresh() = %q, %v, %v, want %q, %v", target, delay, ok, tt.expected, tt.delay)
+			}
+		})
+	}
+}
+
+func TestScraper_FollowMetaRefresh(t *testing.T) {
+	refresh := func(delay int, target string) string {
+		return fmt.Sprintf(`<html><head><title>Moved</title><meta http-equiv="refresh" content="%d;url=%s"></head>`+
+			`<body><main>This page has moved to a new location.</main></body></html>`, delay, target)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, refresh(0, "/new"))
+	})
+	mux.HandleFunc("/notice", func(w http.ResponseWriter, r *http.Request) {
+		// A timed notice, not a redirect
+		fmt.Fprint(w, refresh(10, "/elsewhere"))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusFound)
+		fmt.Fprint(w, refresh(0, "/target"))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := "Content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/old">Old</a> <a href="/moved">Moved</a> <a href="/notice">Notice</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scrape := func(follow bool) []string {
+		s := newTestScraper(t, &config.Config{
+			RootURL:           server.URL + "/",
+			MaxDepth:          3,
+			FollowMetaRefresh: &follow,
+		})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		var paths []string
+		for _, page := range s.GetPages() {
+			paths = append(paths, strings.TrimPrefix(page.URL, server.URL))
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	if got := scrape(true); fmt.Sprint(got) != "[/ /new /notice /target]" {
+		t.Errorf("Scraped pages = %v, want refresh targets without interstitials", got)
+	}
+	if got := scrape(false); fmt.Sprint(got) != "[/ /notice /old]" {
+		t.Errorf("Scraped pages without follow_meta_refresh = %v, want [/ /notice /old]", got)
+	}
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// activeNavSelectors locate the link a page's own navigation highlights as the current page
+var activeNavSelectors = []string{
+	`a[aria-current="page"]`,
+	"li.active > a",
+	"li.current > a",
+	"li.is-active > a",
+	"a.active",
+	"a.current",
+}
+
+// ExtractNavigationTrail returns the URLs of the highlighted ("current") navigation item and the
+// menu items enclosing it, outermost first. When several menus highlight an item, the deepest
+// trail wins. Navigation is removed by ExtractContent, so doc must be read before it.
+func (e *ContentExtractor) ExtractNavigationTrail(doc *goquery.Selection, pageURL *url.URL) []string {
+	return navigationTrail(doc, e.ExtractBaseURL(doc, pageURL))
+}
+
+// navigationTrail finds the longest trail leading to a highlighted navigation link in doc
+func navigationTrail(doc *goquery.Selection, base *url.URL) []string {
+	var best []string
+	for _, selector := range activeNavSelectors {
+		doc.Find(selector).Each(func(_ int, active *goquery.Selection) {
+			if trail := trailTo(active, base); len(trail) > len(best) {
+				best = trail
+			}
+		})
+	}
+	return best
+}
+
+// trailTo returns the URLs of the menu items enclosing the active link, outermost first, ending
+// with the active link itself
+func trailTo(active *goquery.Selection, base *url.URL) []string {
+	self := resolveNavLink(active, base)
+	if self == "" {
+		return nil
+	}
+	trail := []string{self}
+
+	active.ParentsFiltered("li").Each(func(_ int, item *goquery.Selection) {
+		link := navItemLink(item)
+		if link.Length() == 0 || link.IsSelection(active) {
+			return
+		}
+		if resolved := resolveNavLink(link, base); resolved != "" && resolved != trail[0] {
+			trail = append([]string{resolved}, trail...)
+		}
+	})
+
+	return trail
+}
+
+// navItemLink returns the link labelling a menu item: its own link, not one from its nested list
+func navItemLink(item *goquery.Selection) *goquery.Selection {
+	link := item.ChildrenFiltered("a[href]").First()
+	if link.Length() == 0 {
+		link = item.Children().Not("ul, ol").Find("a[href]").First()
+	}
+	return link
+}
+
+// resolveNavLink resolves a navigation link's href against base, dropping any fragment
+func resolveNavLink(link *goquery.Selection, base *url.URL) string {
+	href, exists := link.Attr("href")
+	href = strings.TrimSpace(href)
+	if !exists || href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(parsed)
+	resolved.Fragment = ""
+	return resolved.String()
+}
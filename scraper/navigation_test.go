@@ -0,0 +1,98 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"docscraper/config"
+)
+
+const sidebarNav = `<nav class="sidebar"><ul>
+  <li><a href="/">Home</a></li>
+  <li><a href="/guides">Guides</a>
+    <ul>
+      <li><a href="/guides/install">Install</a></li>
+      <li><span><a href="/guides/advanced#top">Advanced</a></span>
+        <ul>
+          <li><a href="/articles/setup" aria-current="page">Setup</a></li>
+        </ul>
+      </li>
+    </ul>
+  </li>
+</ul></nav>`
+
+func TestContentExtractor_ExtractNavigationTrail(t *testing.T) {
+	extractor := NewContentExtractor()
+	pageURL, _ := url.Parse("https://example.com/articles/setup")
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "nested sidebar with aria-current",
+			body: htmlPage("Setup", sidebarNav),
+			want: []string{"https://example.com/guides", "https://example.com/guides/advanced", "https://example.com/articles/setup"},
+		},
+		{
+			name: "active list item class",
+			body: htmlPage("Setup", `<ul><li><a href="/guides">Guides</a><ul><li class="active"><a href="setup">Setup</a></li></ul></li></ul>`),
+			want: []string{"https://example.com/guides", "https://example.com/articles/setup"},
+		},
+		{
+			name: "deepest of several highlighted menus wins",
+			body: htmlPage("Setup", `<header><a class="active" href="/guides">Guides</a></header>`+sidebarNav),
+			want: []string{"https://example.com/guides", "https://example.com/guides/advanced", "https://example.com/articles/setup"},
+		},
+		{
+			name: "fragment-only active link is ignored",
+			body: htmlPage("Setup", `<ul><li class="current"><a href="#intro">Intro</a></li></ul>`),
+			want: nil,
+		},
+		{
+			name: "no highlighted navigation",
+			body: htmlPage("Setup", `<ul><li><a href="/guides">Guides</a></li></ul>`),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractor.ExtractNavigationTrail(parseTestDocument(t, tt.body), pageURL)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractNavigationTrail() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScraper_NavigationTrail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path != "/articles/setup" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Setup", sidebarNav+`<main><p>Setup instructions for the project.</p></main>`))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/articles/setup", MaxDepth: 1})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+	want := []string{server.URL + "/guides", server.URL + "/guides/advanced", server.URL + "/articles/setup"}
+	if !reflect.DeepEqual(pages[0].NavigationTrail, want) {
+		t.Errorf("Expected navigation trail %v, got %v", want, pages[0].NavigationTrail)
+	}
+}
@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// navMenuEntry is a link in a navigation menu and the link of the menu item enclosing it
+type navMenuEntry struct {
+	URL    string
+	Parent string // "" for top-level items
+}
+
+// parseNavMenu returns the entries of the nested <ul>/<li> menu in navHTML in document order.
+// Relative links are resolved against the document's <base href>, or left relative without one.
+func parseNavMenu(navHTML string) []navMenuEntry {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(navHTML))
+	if err != nil {
+		return nil
+	}
+
+	base := &url.URL{}
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if parsed, err := url.Parse(href); err == nil {
+			base = parsed
+		}
+	}
+
+	var entries []navMenuEntry
+	doc.Find("li").Each(func(_ int, item *goquery.Selection) {
+		link := resolveNavLink(navItemLink(item), base)
+		if link == "" {
+			return
+		}
+
+		parent := ""
+		item.ParentsFiltered("li").EachWithBreak(func(_ int, ancestor *goquery.Selection) bool {
+			parent = resolveNavLink(navItemLink(ancestor), base)
+			return parent == ""
+		})
+		entries = append(entries, navMenuEntry{URL: link, Parent: parent})
+	})
+	return entries
+}
+
+// captureNavigationMenu returns the navigation_menu_selector menu of the root page, prefixed
+// with a <base> so parseNavMenu resolves its links against the page, or "" for other pages
+func (s *Scraper) captureNavigationMenu(doc *goquery.Selection, pageURL *url.URL) string {
+	if s.config.NavigationMenuSelector == "" || urlMatchKey(pageURL.String()) != urlMatchKey(s.config.RootURL) {
+		return ""
+	}
+	menu := doc.Find(s.config.NavigationMenuSelector).First()
+	if menu.Length() == 0 {
+		s.logger.Printf("Warning: navigation menu %q not found on %s", s.config.NavigationMenuSelector, pageURL)
+		return ""
+	}
+	menuHTML, err := goquery.OuterHtml(menu)
+	if err != nil {
+		return ""
+	}
+	return `<base href="` + html.EscapeString(pageURL.String()) + `">` + menuHTML
+}
+
+// BuildTreeFromNavigation builds a tree whose parent/child relationships follow the nesting of
+// the navigation menu in navHTML, a nested <ul>/<li> structure such as a docs sidebar. Menu
+// items are matched to contents by URL, or by path when the menu links are relative. Menu items
+// without content are skipped, their children moving up to the nearest item with content, and
+// pages missing from the menu fall back to the URL hierarchy. The site root page, when scraped,
+// is the tree root; otherwise an untitled root holds the top-level items.
+func (tb *TreeBuilder) BuildTreeFromNavigation(navHTML string, contents map[string]ScrapedContent) *DocumentTree {
+	tree := &DocumentTree{
+		NodeMap:   make(map[string]*DocumentNode),
+		BuildTime: time.Now(),
+	}
+
+	// Content URLs in a stable order, with a path index for relative menu links
+	urls := make([]string, 0, len(contents))
+	byPath := make(map[string]string)
+	for contentURL := range contents {
+		urls = append(urls, contentURL)
+	}
+	sort.Strings(urls)
+	for _, contentURL := range urls {
+		if parsed, err := url.Parse(contentURL); err == nil {
+			if _, exists := byPath[parsed.RequestURI()]; !exists {
+				byPath[parsed.RequestURI()] = contentURL
+			}
+		}
+	}
+	match := func(link string) string {
+		if _, exists := contents[link]; exists {
+			return link
+		}
+		return byPath[link]
+	}
+
+	tree.Root = &DocumentNode{Children: make([]*DocumentNode, 0)}
+	for _, contentURL := range urls {
+		if path := tb.extractPath(contentURL); path == "/" || path == "" {
+			tree.Root = tb.newNode(contentURL, contents[contentURL])
+			tree.NodeMap[contentURL] = tree.Root
+			break
+		}
+	}
+
+	// The menu's nearest enclosing item with content becomes each page's parent
+	entries := parseNavMenu(navHTML)
+	menuParents := make(map[string]string)
+	for _, entry := range entries {
+		if _, seen := menuParents[entry.URL]; !seen {
+			menuParents[entry.URL] = entry.Parent
+		}
+	}
+	index := 0
+	for _, entry := range entries {
+		contentURL := match(entry.URL)
+		if contentURL == "" || tree.NodeMap[contentURL] != nil {
+			continue
+		}
+
+		parent := tree.Root
+		link := entry.Parent
+		for steps := 0; link != "" && steps < len(entries); steps++ {
+			if parentNode := tree.NodeMap[match(link)]; parentNode != nil && match(link) != contentURL {
+				parent = parentNode
+				break
+			}
+			link = menuParents[link]
+		}
+
+		index++
+		node := tb.newNode(contentURL, contents[contentURL])
+		node.Index = index
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+		tree.NodeMap[contentURL] = node
+	}
+
+	// Pages missing from the menu, shallowest first so URL parents are placed before children
+	var rest []string
+	for _, contentURL := range urls {
+		if tree.NodeMap[contentURL] == nil {
+			rest = append(rest, contentURL)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		return strings.Count(tb.extractPath(rest[i]), "/") < strings.Count(tb.extractPath(rest[j]), "/")
+	})
+	for _, contentURL := range rest {
+		node := tb.newNode(contentURL, contents[contentURL])
+		parent := tb.findParentByURLHierarchy(node, tree)
+		if parent == nil {
+			parent = tree.Root
+		}
+		index++
+		node.Index = index
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+		tree.NodeMap[contentURL] = node
+	}
+
+	tb.finishTree(tree)
+	return tree
+}
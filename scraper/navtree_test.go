@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"docscraper/config"
+)
+
+// sampleNav is a docs sidebar whose nesting disagrees with the URL paths
+const sampleNav = `<nav class="sidebar">
+  <ul>
+    <li><a href="/getting-started">Getting Started</a>
+      <ul>
+        <li><a href="/install">Install</a></li>
+        <li><a href="/articles/first-steps">First Steps</a></li>
+      </ul>
+    </li>
+    <li><span>Reference</span>
+      <ul>
+        <li><a href="/reference/cli">CLI</a>
+          <ul>
+            <li><a href="/flags">Flags</a></li>
+          </ul>
+        </li>
+      </ul>
+    </li>
+  </ul>
+</nav>`
+
+func TestTreeBuilder_BuildTreeFromNavigation(t *testing.T) {
+	pages := map[string]string{
+		"https://example.com/":                     "Home",
+		"https://example.com/getting-started":      "Getting Started",
+		"https://example.com/install":              "Install",
+		"https://example.com/articles/first-steps": "First Steps",
+		"https://example.com/reference/cli":        "CLI",
+		"https://example.com/flags":                "Flags",
+		"https://example.com/install/windows":      "Windows", // not in the nav
+		"https://example.com/changelog":            "Changelog",
+	}
+	contents := make(map[string]ScrapedContent)
+	for pageURL, title := range pages {
+		contents[pageURL] = ScrapedContent{URL: pageURL, Title: title}
+	}
+
+	tree := NewTreeBuilder(TreeConfig{}).BuildTreeFromNavigation(sampleNav, contents)
+
+	if tree.Root == nil || tree.Root.Title != "Home" {
+		t.Fatalf("Expected the site root page as tree root, got %v", tree.Root)
+	}
+	if tree.TotalNodes != len(pages) {
+		t.Errorf("Expected %d nodes, got %d", len(pages), tree.TotalNodes)
+	}
+
+	wantParents := map[string]string{
+		"https://example.com/getting-started":      "Home",
+		"https://example.com/install":              "Getting Started",
+		"https://example.com/articles/first-steps": "Getting Started", // nav nesting wins over the URL
+		"https://example.com/reference/cli":        "Home",            // the "Reference" heading has no page
+		"https://example.com/flags":                "CLI",
+		"https://example.com/install/windows":      "Install", // URL hierarchy fallback
+		"https://example.com/changelog":            "Home",
+	}
+	for pageURL, want := range wantParents {
+		node := tree.NodeMap[pageURL]
+		if node == nil {
+			t.Errorf("Missing node for %s", pageURL)
+			continue
+		}
+		if node.Parent == nil || node.Parent.Title != want {
+			t.Errorf("Parent of %s = %v, want %s", pageURL, node.Parent, want)
+		}
+	}
+
+	if flags := tree.NodeMap["https://example.com/flags"]; flags.Level != 2 {
+		t.Errorf("Expected Flags at level 2, got %d", flags.Level)
+	}
+
+	// Children keep menu order
+	var order []string
+	for _, child := range tree.NodeMap["https://example.com/getting-started"].Children {
+		order = append(order, child.Title)
+	}
+	if len(order) != 2 || order[0] != "Install" || order[1] != "First Steps" {
+		t.Errorf("Expected children in menu order [Install First Steps], got %v", order)
+	}
+}
+
+func TestTreeBuilder_BuildTreeFromNavigationWithoutRootPage(t *testing.T) {
+	nav := `<base href="https://example.com/docs/"><ul><li><a href="intro">Intro</a><ul><li><a href="setup">Setup</a></li></ul></li></ul>`
+	contents := map[string]ScrapedContent{
+		"https://example.com/docs/intro": {Title: "Intro"},
+		"https://example.com/docs/setup": {Title: "Setup"},
+	}
+
+	tree := NewTreeBuilder(TreeConfig{}).BuildTreeFromNavigation(nav, contents)
+
+	if tree.Root == nil || tree.Root.URL != "" || len(tree.Root.Children) != 1 {
+		t.Fatalf("Expected an untitled root holding the top-level item, got %+v", tree.Root)
+	}
+	if setup := tree.NodeMap["https://example.com/docs/setup"]; setup == nil || setup.Parent.Title != "Intro" {
+		t.Errorf("Expected Setup under Intro via the <base href>, got %+v", setup)
+	}
+}
+
+func TestTreeBuilder_BuildTreeFollowsNavigationMenu(t *testing.T) {
+	contents := map[string]ScrapedContent{
+		"https://example.com/":        {URL: "https://example.com/", Title: "Home"},
+		"https://example.com/install": {URL: "https://example.com/install", Title: "Install"},
+		"https://example.com/flags":   {URL: "https://example.com/flags", Title: "Flags"},
+	}
+	urls := []string{"https://example.com/", "https://example.com/install", "https://example.com/flags"}
+	nav := `<base href="https://example.com/"><ul><li><a href="/install">Install</a><ul><li><a href="/flags">Flags</a></li></ul></li></ul>`
+
+	tree := NewTreeBuilder(TreeConfig{UseURLHierarchy: true, NavigationMenu: nav}).BuildTree(urls, contents)
+	if parent := tree.NodeMap["https://example.com/flags"].Parent; parent != tree.Root {
+		t.Errorf("Expected the menu ignored without use_navigation, got parent %q", parent.Title)
+	}
+
+	tree = NewTreeBuilder(TreeConfig{UseURLHierarchy: true, UseNavigation: true, NavigationMenu: nav}).BuildTree(urls, contents)
+	if parent := tree.NodeMap["https://example.com/flags"].Parent; parent == nil || parent.Title != "Install" {
+		t.Errorf("Expected Flags under Install from the menu, got %v", parent)
+	}
+}
+
+func TestScraper_CapturesNavigationMenu(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage(r.URL.Path, `<nav class="sidebar"><ul><li><a href="/docs/install">Install</a></li></ul></nav>
+			<main><p>See <a href="/docs/install">install</a>.</p></main>`))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:                server.URL + "/docs",
+		MaxDepth:               2,
+		NavigationMenuSelector: "nav.sidebar ul",
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 2 {
+		t.Fatalf("Expected 2 pages, got %d", len(pages))
+	}
+	for _, page := range pages {
+		switch page.URL {
+		case server.URL + "/docs":
+			if entries := parseNavMenu(page.NavigationMenu); len(entries) != 1 || entries[0].URL != server.URL+"/docs/install" {
+				t.Errorf("Expected the root page's menu with resolved links, got %q", page.NavigationMenu)
+			}
+		default:
+			if page.NavigationMenu != "" {
+				t.Errorf("Expected no menu captured from %s, got %q", page.URL, page.NavigationMenu)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Profiler receives the time and size of every response EnhancedScraper fetches, each failed
+// request, and the time spent extracting each page; devtools.PerformanceProfiler implements it
+type Profiler interface {
+	RecordPageScrape(duration time.Duration, dataSize int64, hadError bool)
+	RecordError()
+	RecordPageExtraction(url string, duration time.Duration)
+}
+
+// SetProfiler records each response's duration and body size, each failed request, and each
+// page's extraction time with profiler from now on; nil stops recording
+func (es *EnhancedScraper) SetProfiler(profiler Profiler) {
+	es.profilerMutex.Lock()
+	defer es.profilerMutex.Unlock()
+	es.profiler = profiler
+}
+
+// Profiler returns the profiler set with SetProfiler, or nil
+func (es *EnhancedScraper) Profiler() Profiler {
+	return es.currentProfiler()
+}
+
+// currentProfiler returns the profiler set with EnhancedScraper.SetProfiler, or nil
+func (s *Scraper) currentProfiler() Profiler {
+	s.profilerMutex.Lock()
+	defer s.profilerMutex.Unlock()
+	return s.profiler
+}
+
+// profileStartKey is the request context key holding when a request was sent; followed links
+// share their parent's context, so the key carries the request ID
+func profileStartKey(r *colly.Request) string {
+	return fmt.Sprintf("profile_start_%d", r.ID)
+}
+
+// startProfiling notes when a request is sent
+func (es *EnhancedScraper) startProfiling(r *colly.Request) {
+	if es.Profiler() != nil {
+		r.Ctx.Put(profileStartKey(r), time.Now())
+	}
+}
+
+// recordProfile reports a response's duration since its request was sent and its body size
+func (es *EnhancedScraper) recordProfile(r *colly.Response) {
+	profiler := es.Profiler()
+	if profiler == nil {
+		return
+	}
+
+	var duration time.Duration
+	if start, ok := r.Request.Ctx.GetAny(profileStartKey(r.Request)).(time.Time); ok {
+		duration = time.Since(start)
+	}
+	profiler.RecordPageScrape(duration, int64(len(r.Body)), false)
+}
+
+// recordProfileError reports a failed request; retries and not-modified responses land here
+// too, so they are kept out of the pages and timings recordProfile reports
+func (es *EnhancedScraper) recordProfileError(r *colly.Response, err error) {
+	if profiler := es.Profiler(); profiler != nil {
+		profiler.RecordError()
+	}
+}
+
+// recordExtraction reports the time spent extracting and analysing a page's content
+func (s *Scraper) recordExtraction(page PageData) {
+	if profiler := s.currentProfiler(); profiler != nil {
+		profiler.RecordPageExtraction(page.URL, page.ExtractionTime)
+	}
+}
@@ -0,0 +1,46 @@
+package scraper
+
+import (
+	"github.com/gocolly/colly/v2"
+)
+
+// trackDiscovered counts a request that will be fetched towards the progress total
+func (es *EnhancedScraper) trackDiscovered(r *colly.Request) {
+	if r.Depth > es.config.MaxDepth {
+		return
+	}
+
+	es.progressMutex.Lock()
+	es.discovered++
+	es.updateEstimate()
+	es.progressMutex.Unlock()
+}
+
+// reportProgress records a scraped page and notifies the progress callback
+func (es *EnhancedScraper) reportProgress(currentURL string) {
+	if es.progressCallback == nil {
+		return
+	}
+
+	es.progressMutex.Lock()
+	defer es.progressMutex.Unlock()
+
+	es.currentProgress++
+	es.updateEstimate()
+	es.progressCallback(es.currentProgress, es.totalEstimated, currentURL)
+}
+
+// updateEstimate grows the total estimate from requested URLs plus links still waiting in the
+// frontier. The estimate never shrinks, so reported totals stay monotonic. Callers hold progressMutex.
+func (es *EnhancedScraper) updateEstimate() {
+	estimate := es.discovered
+	if es.frontier != nil {
+		estimate += es.frontier.Len()
+	}
+	if estimate < es.currentProgress {
+		estimate = es.currentProgress
+	}
+	if estimate > es.totalEstimated {
+		es.totalEstimated = estimate
+	}
+}
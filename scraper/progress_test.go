@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestEnhancedScraper_ProgressTotalEstimate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		links := ""
+		if r.URL.Path == "/" {
+			for i := 1; i <= 4; i++ {
+				links += fmt.Sprintf(`<a href="/docs/%d">Doc %d</a> `, i, i)
+			}
+		}
+		body := strings.Repeat("Documentation content for "+r.URL.Path+". ", 20)
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "<p>"+body+"</p>"+links))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	enableQuality := true
+	enableDedup := false
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:               server.URL + "/",
+		MaxDepth:              2,
+		OutputFormat:          "markdown",
+		OutputType:            "single",
+		LogFile:               logFile.Name(),
+		EnableQualityAnalysis: &enableQuality,
+		EnableDeduplication:   &enableDedup,
+		QualityAnalysis: config.QualityConfig{
+			MinScore:     0.01,
+			MinWordCount: 1,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type report struct{ current, total int }
+	var mutex sync.Mutex
+	var reports []report
+	es.SetProgressCallback(func(current, total int, currentURL string) {
+		mutex.Lock()
+		reports = append(reports, report{current, total})
+		mutex.Unlock()
+	})
+
+	pages, err := es.ScrapeWithFeatures()
+	if err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+	if len(pages) == 0 || len(reports) == 0 {
+		t.Fatalf("Expected scraped pages and progress reports, got %d pages and %d reports", len(pages), len(reports))
+	}
+
+	previousTotal := 0
+	for i, r := range reports {
+		if r.total <= 0 {
+			t.Errorf("Report %d has total %d, want a non-zero estimate", i, r.total)
+		}
+		if r.current > r.total {
+			t.Errorf("Report %d has current %d above total %d", i, r.current, r.total)
+		}
+		if r.total < previousTotal {
+			t.Errorf("Report %d total dropped from %d to %d", i, previousTotal, r.total)
+		}
+		if r.current != i+1 {
+			t.Errorf("Report %d has current %d, want %d", i, r.current, i+1)
+		}
+		previousTotal = r.total
+	}
+
+	if last := reports[len(reports)-1]; last.total != 5 {
+		t.Errorf("Final total = %d, want 5 discovered pages", last.total)
+	}
+}
@@ -1,9 +1,17 @@
 package scraper
 
 import (
+	"encoding/json"
+	"math"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // QualityConfig defines configuration for content quality analysis
@@ -15,10 +23,32 @@ type QualityConfig struct {
 	RequireContent      bool     `yaml:"require_content"`
 	SkipNavigationPages bool     `yaml:"skip_navigation_pages"`
 	MinContentRatio     float64  `yaml:"min_content_ratio"`
+	MaxLinkRatio        float64  `yaml:"max_link_ratio"` // Zero means defaultMaxLinkRatio
 	BlacklistPatterns   []string `yaml:"blacklist_patterns"`
 	WhitelistPatterns   []string `yaml:"whitelist_patterns"`
+
+	MinLanguageConfidence float64 `yaml:"min_language_confidence"` // Zero means defaultMinLanguageConfidence
+	AllowUnknownLanguage  bool    `yaml:"allow_unknown_language"`  // An "unknown" detection never counts as a mismatch
 }
 
+// maxIssuePages caps how many URLs the quality report lists per issue type
+const maxIssuePages = 100
+
+// reportPageCount is how many pages the quality report lists as top and worst
+const reportPageCount = 10
+
+// defaultMaxLinkRatio is the link-to-word ratio above which a page looks like navigation
+const defaultMaxLinkRatio = 0.3
+
+// defaultMinLanguageConfidence is the detection confidence needed before a page's language is trusted
+const defaultMinLanguageConfidence = 0.5
+
+// languageSampleWords is the word count at which language detection reaches full confidence
+const languageSampleWords = 50
+
+// commonWordRatio is the share of a language's common words in typical prose
+const commonWordRatio = 0.2
+
 // QualityWeights defines weights for different quality metrics
 type QualityWeights struct {
 	WordCount     float64 `yaml:"word_count"`
@@ -75,11 +105,12 @@ type QualityStats struct {
 
 // QualityReport provides a summary of quality analysis
 type QualityReport struct {
-	Stats        QualityStats  `json:"stats"`
-	TopPages     []PageQuality `json:"top_pages"`
-	WorstPages   []PageQuality `json:"worst_pages"`
-	CommonIssues []IssueCount  `json:"common_issues"`
-	GeneratedAt  time.Time     `json:"generated_at"`
+	Stats        QualityStats        `json:"stats"`
+	TopPages     []PageQuality       `json:"top_pages"`
+	WorstPages   []PageQuality       `json:"worst_pages"`
+	CommonIssues []IssueCount        `json:"common_issues"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+	IssuePages   map[string][]string `json:"issue_pages"` // Issue type to affected URLs, at most maxIssuePages each
 }
 
 // PageQuality represents quality data for a specific page
@@ -100,6 +131,12 @@ type ContentQualityAnalyzer struct {
 	config QualityConfig
 	scorer QualityScorer
 	stats  QualityStats
+
+	issuePages map[string][]string // Issue type to affected URLs
+	pages      []PageQuality       // Every analyzed page, for the report rankings
+	mutex      sync.Mutex          // Guards stats, issuePages and pages
+
+	languages map[string]map[string]bool // Language code to common words, read-only after construction
 }
 
 // NewContentQualityAnalyzer creates a new content quality analyzer
@@ -118,14 +155,26 @@ func NewContentQualityAnalyzer(config QualityConfig) *ContentQualityAnalyzer {
 		config: config,
 		scorer: QualityScorer{weights: weights},
 		stats:  QualityStats{},
+
+		issuePages: make(map[string][]string),
+		languages:  map[string]map[string]bool{"en": wordSet(EnglishWords)},
+	}
+}
+
+// wordSet returns words as a set
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
 	}
+	return set
 }
 
 // AnalyzeContent analyzes the quality of scraped content
 func (cqa *ContentQualityAnalyzer) AnalyzeContent(content ScrapedContent) ContentQuality {
 	metrics := cqa.extractMetrics(content)
 	score := cqa.scorer.CalculateScore(metrics)
-	language := cqa.DetectLanguage(content.Content)
+	language, confidence := cqa.DetectLanguage(content.Content)
 	issues := cqa.DetectIssues(content)
 	tags := cqa.generateTags(content, metrics)
 
@@ -135,6 +184,7 @@ func (cqa *ContentQualityAnalyzer) AnalyzeContent(content ScrapedContent) Conten
 		CodeBlockCount:   metrics.CodeBlockCount,
 		ImageCount:       metrics.ImageCount,
 		LinkCount:        metrics.LinkCount,
+		LinkRatio:        cqa.linkRatio(content),
 		EmptyLineRatio:   metrics.EmptyLineRatio,
 		ContentRatio:     metrics.ContentRatio,
 		HasTitle:         metrics.HasTitle,
@@ -143,10 +193,14 @@ func (cqa *ContentQualityAnalyzer) AnalyzeContent(content ScrapedContent) Conten
 		Language:         language,
 		Issues:           issues,
 		Tags:             tags,
+
+		LanguageConfidence: confidence,
 	}
 
 	// Update statistics
 	cqa.updateStats(quality)
+	cqa.recordIssuePages(content.URL, issues)
+	cqa.recordPage(PageQuality{URL: content.URL, Title: content.Title, Quality: quality})
 
 	return quality
 }
@@ -193,13 +247,9 @@ func (cqa *ContentQualityAnalyzer) countWords(text string) int {
 
 // countCodeBlocks counts code blocks in text
 func (cqa *ContentQualityAnalyzer) countCodeBlocks(text string) int {
-	// Count markdown code blocks (```...```)
-	codeBlockPattern := regexp.MustCompile("```[\\s\\S]*?```")
-	matches := codeBlockPattern.FindAllString(text, -1)
-	count := len(matches)
-
-	// Remove code blocks from text to avoid counting their content as inline code
-	textWithoutBlocks := codeBlockPattern.ReplaceAllString(text, "")
+	// Count fenced markdown code blocks, leaving their content out of the inline code count
+	blocks, textWithoutBlocks := splitFencedBlocks(text)
+	count := len(blocks)
 
 	// Also count inline code (`...`) in the remaining text
 	inlineCodePattern := regexp.MustCompile("`[^`]+`")
@@ -309,55 +359,86 @@ func (qs *QualityScorer) CalculateScore(metrics ContentMetrics) float64 {
 	return score
 }
 
-// DetectLanguage attempts to detect the primary language of content
-func (cqa *ContentQualityAnalyzer) DetectLanguage(content string) string {
-	// Simple language detection based on common patterns
-	// In a real implementation, you might use a proper language detection library
-
-	// Count common English words
-	englishWords := []string{"the", "and", "or", "but", "in", "on", "at", "to", "for", "of", "with", "by"}
-	englishCount := 0
+// DetectLanguage returns the language whose common words make up the largest share of
+// content, or "unknown" when no language reaches 5%. The confidence from 0 to 1 grows with the
+// strength of the match and the amount of text, and shrinks when another language scores close.
+func (cqa *ContentQualityAnalyzer) DetectLanguage(content string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return "unknown", 0
+	}
 
-	words := strings.Fields(strings.ToLower(content))
-	wordMap := make(map[string]int)
+	counts := make(map[string]int, len(cqa.languages))
 	for _, word := range words {
-		wordMap[word]++
+		for lang, common := range cqa.languages {
+			if common[word] {
+				counts[lang]++
+			}
+		}
 	}
 
-	for _, englishWord := range englishWords {
-		englishCount += wordMap[englishWord]
+	// Pick the best and runner-up counts, breaking ties by language code
+	best, bestCount, runnerUp := "", 0, 0
+	for lang, count := range counts {
+		if count > bestCount || (count == bestCount && lang < best) {
+			best, bestCount, runnerUp = lang, count, bestCount
+		} else if count > runnerUp {
+			runnerUp = count
+		}
+	}
+
+	// Short content says little either way
+	sample := math.Min(1, float64(len(words))/languageSampleWords)
+	ratio := float64(bestCount) / float64(len(words))
+
+	if bestCount > len(words)/20 { // If more than 5% are common words of the language
+		margin := math.Min(1, 2*float64(bestCount-runnerUp)/float64(bestCount))
+		return best, sample * math.Min(1, ratio/commonWordRatio) * margin
 	}
 
-	if englishCount > len(words)/20 { // If more than 5% are common English words
-		return "en"
+	return "unknown", sample * (1 - ratio/0.05)
+}
+
+// IsLanguageMismatch reports whether quality's detected language confidently differs from
+// want; detections below the confidence threshold, and "unknown" detections under
+// AllowUnknownLanguage, never count as a mismatch
+func (cqa *ContentQualityAnalyzer) IsLanguageMismatch(quality ContentQuality, want string) bool {
+	if want == "" || quality.LanguageConfidence < cqa.minLanguageConfidence() {
+		return false
+	}
+	if quality.Language == "unknown" && cqa.config.AllowUnknownLanguage {
+		return false
 	}
+	return !strings.EqualFold(quality.Language, want)
+}
 
-	return "unknown"
+// minLanguageConfidence returns the configured language detection confidence threshold
+func (cqa *ContentQualityAnalyzer) minLanguageConfidence() float64 {
+	if cqa.config.MinLanguageConfidence <= 0 {
+		return defaultMinLanguageConfidence
+	}
+	return cqa.config.MinLanguageConfidence
 }
 
 // ExtractCodeBlocks extracts code blocks from content
 func (cqa *ContentQualityAnalyzer) ExtractCodeBlocks(content string) []CodeBlock {
 	var blocks []CodeBlock
 
-	// Extract markdown code blocks
-	codeBlockPattern := regexp.MustCompile("```(\\w*)\\n([\\s\\S]*?)```")
-	matches := codeBlockPattern.FindAllStringSubmatch(content, -1)
-
-	for _, match := range matches {
-		if len(match) >= 3 {
-			language := match[1]
-			if language == "" {
-				language = "text"
-			}
-			code := match[2]
-			lineCount := len(strings.Split(code, "\n"))
-
-			blocks = append(blocks, CodeBlock{
-				Language:  language,
-				Content:   code,
-				LineCount: lineCount,
-			})
+	// Extract fenced markdown code blocks
+	fences, _ := splitFencedBlocks(content)
+	for _, fence := range fences {
+		language := fence.language
+		if language == "" {
+			language = "text"
 		}
+
+		blocks = append(blocks, CodeBlock{
+			Language:  language,
+			Content:   fence.code,
+			LineCount: len(strings.Split(fence.code, "\n")),
+		})
 	}
 
 	return blocks
@@ -394,17 +475,62 @@ func (cqa *ContentQualityAnalyzer) IsNavigationPage(content ScrapedContent) bool
 	}
 
 	// If high ratio of links to content
-	linkPattern := regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
-	linkCount := len(linkPattern.FindAllString(content.Content, -1))
-	wordCount := len(strings.Fields(content.Content))
-
-	if wordCount > 0 && float64(linkCount)/float64(wordCount) > 0.3 {
+	if cqa.linkRatio(content) > cqa.maxLinkRatio() {
 		indicatorCount++
 	}
 
 	return indicatorCount >= 2
 }
 
+// markdownLinkPattern matches inline markdown links
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+
+// LinkRatio returns the number of markdown links per word of content
+func LinkRatio(content string) float64 {
+	wordCount := len(strings.Fields(content))
+	if wordCount == 0 {
+		return 0
+	}
+	linkCount := len(markdownLinkPattern.FindAllString(content, -1))
+	return float64(linkCount) / float64(wordCount)
+}
+
+// DOMLinkRatio returns the share of the words in doc's body that are a[href] text; run after
+// ExtractContent it covers the page's content, since navigation chrome has been removed
+func DOMLinkRatio(doc *goquery.Selection) float64 {
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		body = doc
+	}
+
+	wordCount := len(strings.Fields(body.Text()))
+	if wordCount == 0 {
+		return 0
+	}
+	linkWords := 0
+	body.Find("a[href]").Each(func(_ int, link *goquery.Selection) {
+		linkWords += len(strings.Fields(link.Text()))
+	})
+	return float64(linkWords) / float64(wordCount)
+}
+
+// linkRatio returns the link ratio measured from the page DOM, or counts markdown links in
+// the content when none was measured
+func (cqa *ContentQualityAnalyzer) linkRatio(content ScrapedContent) float64 {
+	if content.LinkRatio > 0 {
+		return content.LinkRatio
+	}
+	return LinkRatio(content.Content)
+}
+
+// maxLinkRatio returns the configured navigation link ratio threshold
+func (cqa *ContentQualityAnalyzer) maxLinkRatio() float64 {
+	if cqa.config.MaxLinkRatio <= 0 {
+		return defaultMaxLinkRatio
+	}
+	return cqa.config.MaxLinkRatio
+}
+
 // DetectIssues detects quality issues in content
 func (cqa *ContentQualityAnalyzer) DetectIssues(content ScrapedContent) []QualityIssue {
 	var issues []QualityIssue
@@ -521,6 +647,9 @@ func (cqa *ContentQualityAnalyzer) generateTags(content ScrapedContent, metrics
 
 // updateStats updates internal statistics
 func (cqa *ContentQualityAnalyzer) updateStats(quality ContentQuality) {
+	cqa.mutex.Lock()
+	defer cqa.mutex.Unlock()
+
 	cqa.stats.TotalPages++
 
 	if quality.Score >= 0.4 { // Default passing score
@@ -538,13 +667,96 @@ func (cqa *ContentQualityAnalyzer) updateStats(quality ContentQuality) {
 	cqa.stats.AverageWordCount = (totalWords + quality.WordCount) / cqa.stats.TotalPages
 }
 
+// recordIssuePages lists pageURL under each issue type it triggered, up to maxIssuePages per type
+func (cqa *ContentQualityAnalyzer) recordIssuePages(pageURL string, issues []QualityIssue) {
+	cqa.mutex.Lock()
+	defer cqa.mutex.Unlock()
+
+	for _, issue := range issues {
+		if len(cqa.issuePages[issue.Type]) < maxIssuePages {
+			cqa.issuePages[issue.Type] = append(cqa.issuePages[issue.Type], pageURL)
+		}
+	}
+}
+
+// recordPage keeps a page's quality for the report rankings
+func (cqa *ContentQualityAnalyzer) recordPage(page PageQuality) {
+	cqa.mutex.Lock()
+	defer cqa.mutex.Unlock()
+
+	cqa.pages = append(cqa.pages, page)
+}
+
 // GenerateReport generates a quality analysis report
 func (cqa *ContentQualityAnalyzer) GenerateReport() QualityReport {
+	cqa.mutex.Lock()
+	defer cqa.mutex.Unlock()
+
+	issuePages := make(map[string][]string, len(cqa.issuePages))
+	for issueType, pages := range cqa.issuePages {
+		issuePages[issueType] = append([]string(nil), pages...)
+	}
+
+	// Rank by score, best first; ties keep URL order so the report is stable
+	ranked := append([]PageQuality(nil), cqa.pages...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Quality.Score != ranked[j].Quality.Score {
+			return ranked[i].Quality.Score > ranked[j].Quality.Score
+		}
+		return ranked[i].URL < ranked[j].URL
+	})
+
+	count := reportPageCount
+	if len(ranked) < count {
+		count = len(ranked)
+	}
+	topPages := append([]PageQuality{}, ranked[:count]...)
+	worstPages := make([]PageQuality, 0, count)
+	for i := len(ranked) - 1; i >= len(ranked)-count; i-- {
+		worstPages = append(worstPages, ranked[i])
+	}
+
 	return QualityReport{
 		Stats:        cqa.stats,
-		TopPages:     []PageQuality{}, // Would be populated with actual data
-		WorstPages:   []PageQuality{}, // Would be populated with actual data
-		CommonIssues: []IssueCount{},  // Would be populated with actual data
+		TopPages:     topPages,
+		WorstPages:   worstPages,
+		CommonIssues: commonIssues(cqa.pages),
 		GeneratedAt:  time.Now(),
+		IssuePages:   issuePages,
+	}
+}
+
+// commonIssues counts pages per issue type, most frequent first
+func commonIssues(pages []PageQuality) []IssueCount {
+	counts := make(map[string]int)
+	for _, page := range pages {
+		seen := make(map[string]bool)
+		for _, issue := range page.Quality.Issues {
+			if !seen[issue.Type] {
+				seen[issue.Type] = true
+				counts[issue.Type]++
+			}
+		}
+	}
+
+	issues := make([]IssueCount, 0, len(counts))
+	for issueType, count := range counts {
+		issues = append(issues, IssueCount{IssueType: issueType, Count: count})
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Count != issues[j].Count {
+			return issues[i].Count > issues[j].Count
+		}
+		return issues[i].IssueType < issues[j].IssueType
+	})
+	return issues
+}
+
+// Save writes the report to filename as indented JSON
+func (r QualityReport) Save(filename string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(filename, data, 0644)
 }
@@ -1,9 +1,19 @@
 package scraper
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"docscraper/config"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 func TestNewContentQualityAnalyzer(t *testing.T) {
@@ -319,6 +329,127 @@ func TestContentQualityAnalyzer_IsNavigationPage(t *testing.T) {
 	}
 }
 
+func TestContentQualityAnalyzer_MaxLinkRatio(t *testing.T) {
+	// 2 links in 8 words; "menu" supplies the one other navigation indicator
+	content := ScrapedContent{
+		Title:   "Guides",
+		Content: "menu [Install](install) [Upgrade](upgrade) covers setup for new users",
+	}
+
+	tests := []struct {
+		name     string
+		maxRatio float64
+		expected bool
+	}{
+		{name: "ratio above threshold", maxRatio: 0.24, expected: true},
+		{name: "ratio below threshold", maxRatio: 0.26, expected: false},
+		{name: "default threshold", maxRatio: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewContentQualityAnalyzer(QualityConfig{MaxLinkRatio: tt.maxRatio})
+			if got := analyzer.IsNavigationPage(content); got != tt.expected {
+				t.Errorf("IsNavigationPage() = %v, want %v", got, tt.expected)
+			}
+
+			quality := analyzer.AnalyzeContent(content)
+			if quality.LinkRatio != 0.25 {
+				t.Errorf("Expected link ratio 0.25, got %v", quality.LinkRatio)
+			}
+			if quality.IsNavigationPage != tt.expected {
+				t.Errorf("Expected IsNavigationPage %v in quality, got %v", tt.expected, quality.IsNavigationPage)
+			}
+		})
+	}
+}
+
+func TestDOMLinkRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected float64
+	}{
+		{name: "no links", html: `<body><p>Plain prose with six words.</p></body>`, expected: 0},
+		{name: "some link text", html: `<body><p>Read <a href="/a">the install guide</a> first.</p></body>`, expected: 0.6},
+		{name: "anchors without href", html: `<body><p><a name="top">Top</a> of the page</p></body>`, expected: 0},
+		{name: "empty body", html: `<body></body>`, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := DOMLinkRatio(doc.Selection); got != tt.expected {
+				t.Errorf("DOMLinkRatio() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestContentQualityAnalyzer_MeasuredLinkRatio(t *testing.T) {
+	// Links are flattened to text by extraction, so only the measured ratio sees them
+	content := ScrapedContent{
+		Title:     "Guides",
+		Content:   "menu Install Upgrade covers setup for new users",
+		LinkRatio: 0.5,
+	}
+
+	analyzer := NewContentQualityAnalyzer(QualityConfig{})
+	quality := analyzer.AnalyzeContent(content)
+	if quality.LinkRatio != 0.5 {
+		t.Errorf("Expected the measured link ratio 0.5, got %v", quality.LinkRatio)
+	}
+	if !quality.IsNavigationPage {
+		t.Error("Expected a page with a high measured link ratio and a menu indicator to be navigation")
+	}
+}
+
+func TestEnhancedScraper_WarnsOnHighLinkRatio(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Reference", `<p>Pick a topic:</p><ul>
+			<li><a href="#install">Installing the command line tool</a></li>
+			<li><a href="#configure">Configuring output formats and paths</a></li>
+			<li><a href="#deploy">Deploying the generated documentation site</a></li>
+		</ul>`))
+	}))
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	enableQuality := true
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:               server.URL + "/",
+		MaxDepth:              1,
+		OutputFormat:          "markdown",
+		OutputType:            "single",
+		LogFile:               logFile.Name(),
+		EnableQualityAnalysis: &enableQuality,
+		QualityAnalysis:       config.QualityConfig{MinScore: 0.01, MinWordCount: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.ScrapeWithFeatures(); err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+
+	log, err := os.ReadFile(logFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "Warning: High link ratio") {
+		t.Errorf("Expected a high link ratio warning for a page of links\n%s", log)
+	}
+}
+
 func TestContentQualityAnalyzer_DetectIssues(t *testing.T) {
 	config := QualityConfig{
 		MinWordCount:      50,
@@ -513,3 +644,215 @@ func TestContentQualityAnalyzer_ShouldSkip(t *testing.T) {
 		})
 	}
 }
+
+func TestContentQualityAnalyzer_IssuePages(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{RequireTitle: true, MinWordCount: 3})
+
+	analyzer.AnalyzeContent(ScrapedContent{URL: "https://example.com/a", Title: "", Content: "Some page content here"})
+	analyzer.AnalyzeContent(ScrapedContent{URL: "https://example.com/b", Title: "Guide", Content: "Enough words in this page"})
+	analyzer.AnalyzeContent(ScrapedContent{URL: "https://example.com/c", Title: "Untitled", Content: "Short"})
+
+	report := analyzer.GenerateReport()
+
+	missing := report.IssuePages["missing_title"]
+	if len(missing) != 2 || missing[0] != "https://example.com/a" || missing[1] != "https://example.com/c" {
+		t.Errorf("Expected pages a and c under missing_title, got %v", missing)
+	}
+	if wordCount := report.IssuePages["word_count"]; len(wordCount) != 1 || wordCount[0] != "https://example.com/c" {
+		t.Errorf("Expected page c under word_count, got %v", wordCount)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"issue_pages":{`) {
+		t.Errorf("Expected issue_pages in report JSON, got %s", data)
+	}
+}
+
+func TestContentQualityAnalyzer_IssuePagesCapped(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{RequireTitle: true})
+
+	for i := 0; i < maxIssuePages+5; i++ {
+		analyzer.AnalyzeContent(ScrapedContent{URL: fmt.Sprintf("https://example.com/%d", i), Content: "Body"})
+	}
+
+	if pages := analyzer.GenerateReport().IssuePages["missing_title"]; len(pages) != maxIssuePages {
+		t.Errorf("Expected %d listed pages, got %d", maxIssuePages, len(pages))
+	}
+}
+
+func TestContentQualityAnalyzer_DetectLanguageConfidence(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{})
+	english := strings.Repeat("The server starts on the default port and logs requests to the console for each client. ", 6)
+
+	tests := []struct {
+		name          string
+		content       string
+		wantLanguage  string
+		minConfidence float64
+		maxConfidence float64
+	}{
+		{"empty", "", "unknown", 0, 0},
+		{"long english prose", english, "en", 0.9, 1},
+		{"short ambiguous snippet", "Install docker", "unknown", 0, 0.1},
+		{"short english snippet", "Click on the button", "en", 0, 0.2},
+		{"long non-english prose", strings.Repeat("Der Server startet auf dem Standardport und protokolliert Anfragen. ", 8), "unknown", 0.9, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			language, confidence := analyzer.DetectLanguage(tt.content)
+			if language != tt.wantLanguage {
+				t.Errorf("DetectLanguage() language = %q, want %q", language, tt.wantLanguage)
+			}
+			if confidence < tt.minConfidence || confidence > tt.maxConfidence {
+				t.Errorf("DetectLanguage() confidence = %.2f, want between %.2f and %.2f", confidence, tt.minConfidence, tt.maxConfidence)
+			}
+		})
+	}
+}
+
+func TestContentQualityAnalyzer_IsLanguageMismatch(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{})
+
+	short := analyzer.AnalyzeContent(ScrapedContent{URL: "https://example.com/a", Title: "A", Content: "Install docker"})
+	if short.LanguageConfidence >= defaultMinLanguageConfidence {
+		t.Fatalf("Expected low confidence for short content, got %.2f", short.LanguageConfidence)
+	}
+	if analyzer.IsLanguageMismatch(short, "en") {
+		t.Error("Expected short ambiguous content not to be filtered")
+	}
+
+	german := analyzer.AnalyzeContent(ScrapedContent{
+		URL:     "https://example.com/b",
+		Title:   "B",
+		Content: strings.Repeat("Der Server startet auf dem Standardport und protokolliert Anfragen. ", 8),
+	})
+	if !analyzer.IsLanguageMismatch(german, "en") {
+		t.Errorf("Expected confident non-English content to be filtered, got %+v", german)
+	}
+	if analyzer.IsLanguageMismatch(german, "") {
+		t.Error("Expected no mismatch without a language filter")
+	}
+
+	strict := NewContentQualityAnalyzer(QualityConfig{MinLanguageConfidence: 1})
+	german.LanguageConfidence = 0.95
+	if strict.IsLanguageMismatch(german, "en") {
+		t.Error("Expected a confidence below the configured threshold not to be filtered")
+	}
+}
+
+func TestContentQualityAnalyzer_GenerateReportRankings(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{RequireTitle: true, MinWordCount: 20})
+
+	rich := "# Guide\n\nThis guide covers installation and configuration in depth.\n\n```go\nfunc main() {}\n```\n\n" +
+		strings.Repeat("Each section explains the options with examples and notes. ", 20)
+	pages := []ScrapedContent{
+		{URL: "https://example.com/rich", Title: "Guide", Content: rich},
+		{URL: "https://example.com/medium", Title: "Overview", Content: strings.Repeat("A short overview of the project and its goals. ", 5)},
+		{URL: "https://example.com/empty", Title: "", Content: "Stub"},
+		{URL: "https://example.com/thin", Title: "Notes", Content: "A few words only"},
+	}
+	for _, page := range pages {
+		analyzer.AnalyzeContent(page)
+	}
+	for i := 0; i < reportPageCount; i++ {
+		analyzer.AnalyzeContent(ScrapedContent{URL: fmt.Sprintf("https://example.com/filler%02d", i), Title: "Filler", Content: strings.Repeat("Filler text for the ranking test. ", 2)})
+	}
+
+	report := analyzer.GenerateReport()
+	if len(report.TopPages) != reportPageCount || len(report.WorstPages) != reportPageCount {
+		t.Fatalf("Expected %d top and worst pages, got %d and %d", reportPageCount, len(report.TopPages), len(report.WorstPages))
+	}
+	if report.TopPages[0].URL != "https://example.com/rich" {
+		t.Errorf("Expected the rich page ranked first, got %s", report.TopPages[0].URL)
+	}
+	if report.WorstPages[0].URL != "https://example.com/empty" {
+		t.Errorf("Expected the empty page ranked worst, got %s", report.WorstPages[0].URL)
+	}
+	for i := 1; i < len(report.TopPages); i++ {
+		if report.TopPages[i].Quality.Score > report.TopPages[i-1].Quality.Score {
+			t.Errorf("Top pages not sorted by descending score at %d", i)
+		}
+		if report.WorstPages[i].Quality.Score < report.WorstPages[i-1].Quality.Score {
+			t.Errorf("Worst pages not sorted by ascending score at %d", i)
+		}
+	}
+
+	if len(report.CommonIssues) == 0 || report.CommonIssues[0].IssueType != "word_count" {
+		t.Fatalf("Expected word_count as the most common issue, got %+v", report.CommonIssues)
+	}
+	if report.CommonIssues[0].Count != 2+reportPageCount {
+		t.Errorf("Expected %d pages with word_count issues, got %d", 2+reportPageCount, report.CommonIssues[0].Count)
+	}
+}
+
+func TestQualityReport_Save(t *testing.T) {
+	analyzer := NewContentQualityAnalyzer(QualityConfig{})
+	analyzer.AnalyzeContent(ScrapedContent{URL: "https://example.com/a", Title: "A", Content: "Some page content"})
+
+	filename := filepath.Join(t.TempDir(), "quality_report.json")
+	if err := analyzer.GenerateReport().Save(filename); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report QualityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Invalid report JSON: %v", err)
+	}
+	if report.Stats.TotalPages != 1 || len(report.TopPages) != 1 || report.TopPages[0].URL != "https://example.com/a" {
+		t.Errorf("Unexpected saved report: %+v", report)
+	}
+}
+
+func TestEnhancedScraper_WritesQualityReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Home", "<main><p>"+strings.Repeat("Documentation content for the home page. ", 10)+"</p></main>"))
+	}))
+	defer server.Close()
+
+	logFile, err := os.CreateTemp("", "test-log-*.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile.Close()
+	defer os.Remove(logFile.Name())
+
+	enableQuality := true
+	outputDir := t.TempDir()
+	es, err := NewWithFeatures(&config.Config{
+		RootURL:               server.URL + "/",
+		MaxDepth:              1,
+		OutputDir:             outputDir,
+		OutputFormat:          "markdown",
+		OutputType:            "single",
+		LogFile:               logFile.Name(),
+		EnableQualityAnalysis: &enableQuality,
+		QualityAnalysis:       config.QualityConfig{MinScore: 0.01, MinWordCount: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.ScrapeWithFeatures(); err != nil {
+		t.Fatalf("ScrapeWithFeatures() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "quality_report.json"))
+	if err != nil {
+		t.Fatalf("Expected quality_report.json: %v", err)
+	}
+	var report QualityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Invalid report JSON: %v", err)
+	}
+	if len(report.TopPages) != 1 || report.TopPages[0].URL != server.URL+"/" {
+		t.Errorf("Expected the home page in the report, got %+v", report.TopPages)
+	}
+}
@@ -0,0 +1,141 @@
+package scraper
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// tokenBucket paces requests to one host: it holds up to burst tokens, refilled at rate per
+// second, and each request takes one
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token at now and returns how long the caller must wait for it. Tokens may
+// go negative, so concurrent callers queue up one interval apart instead of all waking at once.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	if b.last.IsZero() {
+		b.tokens = b.burst
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// hostPace is the request schedule of one host
+type hostPace struct {
+	bucket tokenBucket
+	next   time.Time // Earliest start of the next request under the minimum interval
+}
+
+// rateLimiter is the single place requests are paced: every request, first attempt or retry,
+// waits in OnRequest for its host's token bucket, for the minimum interval since the host's
+// last request, and for any retry backoff the host is serving
+type rateLimiter struct {
+	rate        float64 // Requests per second per host, 0 means unlimited
+	burst       float64
+	minInterval time.Duration
+	hosts       map[string]*hostPace
+	mutex       sync.Mutex
+}
+
+// newRateLimiter creates a limiter allowing rate requests per second to each host, at least
+// minInterval apart
+func newRateLimiter(rate float64, minInterval time.Duration) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: 1, minInterval: minInterval, hosts: make(map[string]*hostPace)}
+}
+
+// pace returns host's schedule, creating it on first use; l.mutex must be held
+func (l *rateLimiter) pace(host string) *hostPace {
+	pace, ok := l.hosts[host]
+	if !ok {
+		pace = &hostPace{bucket: tokenBucket{rate: l.rate, burst: l.burst}}
+		l.hosts[host] = pace
+	}
+	return pace
+}
+
+// reserve schedules a request to host and returns how long to wait before sending it
+func (l *rateLimiter) reserve(host string) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	pace := l.pace(host)
+	now := time.Now()
+	start := now
+	if l.rate > 0 {
+		start = now.Add(pace.bucket.reserve(now))
+	}
+	if start.Before(pace.next) {
+		start = pace.next
+	}
+	pace.next = start.Add(l.minInterval)
+	return start.Sub(now)
+}
+
+// backOff holds back the next request to host, a retry or any other, until delay from now
+func (l *rateLimiter) backOff(host string, delay time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	pace := l.pace(host)
+	if until := time.Now().Add(delay); until.After(pace.next) {
+		pace.next = until
+	}
+}
+
+// lowerRate slows every host to at most rate requests per second
+func (l *rateLimiter) lowerRate(rate float64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.rate > 0 && rate >= l.rate {
+		return
+	}
+	l.rate = rate
+	for _, pace := range l.hosts {
+		pace.bucket.rate = rate
+	}
+}
+
+// waitForSlot waits until r's host may receive another request and reports whether r may be
+// sent; when the crawl is cancelled first, r is aborted and stays pending. It runs in OnRequest,
+// so the wait is not counted against the HTTP client's request timeout.
+func (s *Scraper) waitForSlot(r *colly.Request) bool {
+	if s.limiter == nil {
+		return true
+	}
+	wait := s.limiter.reserve(r.URL.Host)
+	if wait <= 0 {
+		return true
+	}
+
+	var done <-chan struct{}
+	if s.ctx != nil {
+		done = s.ctx.Done()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		s.trackPending(r)
+		r.Abort()
+		return false
+	}
+}
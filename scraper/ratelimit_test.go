@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	start := time.Unix(0, 0)
+	bucket := &tokenBucket{rate: 2, burst: 1}
+
+	steps := []struct {
+		at   time.Duration
+		want time.Duration
+	}{
+		{0, 0},                      // the first request uses the initial token
+		{0, 500 * time.Millisecond}, // concurrent requests queue one interval apart
+		{0, time.Second},
+		{2 * time.Second, 0}, // refilled after the queue drained
+		{2*time.Second + 100*time.Millisecond, 400 * time.Millisecond}, // partly refilled
+	}
+	for i, step := range steps {
+		if got := bucket.reserve(start.Add(step.at)); got != step.want {
+			t.Errorf("step %d: reserve() = %v, want %v", i, got, step.want)
+		}
+	}
+}
+
+func TestScraper_RequestsPerSecond(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			var links strings.Builder
+			for i := 1; i <= 9; i++ {
+				fmt.Fprintf(&links, `<a href="/page/%d">Page %d</a> `, i, i)
+			}
+			fmt.Fprint(w, htmlPage("Hub", "<p>Index of every page.</p>"+links.String()))
+			return
+		}
+		fmt.Fprint(w, htmlPage("Page", "<p>Chapter "+r.URL.Path+".</p>"))
+	}))
+	defer server.Close()
+
+	rate := 20.0
+	parallelism := 4
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL + "/",
+		MaxDepth:           2,
+		ConcurrentRequests: &parallelism,
+		RequestsPerSecond:  &rate,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(times) != 10 {
+		t.Fatalf("Expected 10 requests, got %d", len(times))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// With a burst of one, n requests span at least (n-1)/rate seconds despite the parallelism
+	interval := time.Duration(float64(time.Second) / rate)
+	if elapsed, min := times[len(times)-1].Sub(times[0]), time.Duration(len(times)-1)*interval; elapsed < min*9/10 {
+		t.Errorf("Expected %d requests to take at least %v at %.0f/s, took %v", len(times), min, rate, elapsed)
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < interval/4 {
+			t.Errorf("Requests %d and %d were only %v apart, want about %v", i, i+1, gap, interval)
+		}
+	}
+}
+
+func TestScraper_RequestsPerSecondWaitOutsideTimeout(t *testing.T) {
+	var mu sync.Mutex
+	requested := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/" {
+			var links strings.Builder
+			for i := 1; i <= 6; i++ {
+				fmt.Fprintf(&links, `<a href="/page/%d">Page %d</a> `, i, i)
+			}
+			fmt.Fprint(w, htmlPage("Hub", "<p>Index of every page.</p>"+links.String()))
+			return
+		}
+		fmt.Fprint(w, htmlPage("Page", "<p>Chapter "+r.URL.Path+".</p>"))
+	}))
+	defer server.Close()
+
+	// The last queued page waits about two seconds for its turn, longer than the timeout
+	rate := 3.0
+	timeout := 1
+	parallelism := 7
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL + "/",
+		MaxDepth:           2,
+		ConcurrentRequests: &parallelism,
+		RequestsPerSecond:  &rate,
+		RequestTimeout:     &timeout,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if requested != 7 || len(s.GetPages()) != 7 {
+		t.Errorf("Expected 7 requests and pages despite the pacing, got %d and %d", requested, len(s.GetPages()))
+	}
+	if failed := s.GetFailedURLs(); len(failed) != 0 {
+		t.Errorf("Expected no timed out requests, got %v", failed)
+	}
+}
+
+func TestScraper_MinDelayAppliedOnce(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Page", `<p>Some page content.</p><a href="/next">Next</a>`))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2, MinDelay: 1, MaxDelay: 1})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(times) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(times))
+	}
+	// min_delay paces through min_request_interval alone, not again as a collector delay
+	if gap := times[1].Sub(times[0]); gap < 900*time.Millisecond || gap > 1500*time.Millisecond {
+		t.Errorf("Expected requests about 1s apart, got %v", gap)
+	}
+}
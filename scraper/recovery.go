@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// ExtractionError records a page whose processing panicked
+type ExtractionError struct {
+	URL       string    `json:"url"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// onHTML registers an HTML callback that recovers from panics, so a malformed page or a
+// bad custom pattern is reported as an extraction error instead of killing the crawl
+func (s *Scraper) onHTML(selector string, f colly.HTMLCallback) {
+	s.collector.OnHTML(selector, func(e *colly.HTMLElement) {
+		// HEAD responses of Discover carry no body to extract
+		if e.Request.Method == http.MethodHead {
+			return
+		}
+		defer s.recoverExtraction(e.Request.URL.String())
+		f(e)
+	})
+}
+
+// recoverExtraction records a panic raised while processing pageURL
+func (s *Scraper) recoverExtraction(pageURL string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	s.logger.Printf("Recovered from panic while processing %s: %v\n%s", pageURL, r, debug.Stack())
+
+	s.extractionErrorsMutex.Lock()
+	s.extractionErrors = append(s.extractionErrors, ExtractionError{
+		URL:       pageURL,
+		Message:   fmt.Sprint(r),
+		Timestamp: time.Now(),
+	})
+	s.extractionErrorsMutex.Unlock()
+}
+
+// GetExtractionErrors returns the pages whose processing panicked
+func (s *Scraper) GetExtractionErrors() []ExtractionError {
+	s.extractionErrorsMutex.Lock()
+	defer s.extractionErrorsMutex.Unlock()
+	return append([]ExtractionError(nil), s.extractionErrors...)
+}
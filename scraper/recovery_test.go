@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+
+	"docscraper/config"
+)
+
+func TestScraper_RecoversFromExtractionPanics(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := "Content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/bad">Bad</a> <a href="/good">Good</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2})
+
+	// Inject an extraction step that panics on one page
+	s.onHTML("html", func(e *colly.HTMLElement) {
+		if e.Request.URL.Path == "/bad" {
+			panic("malformed page")
+		}
+	})
+
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	var scraped []string
+	for _, page := range s.GetPages() {
+		scraped = append(scraped, strings.TrimPrefix(page.URL, server.URL))
+	}
+	sort.Strings(scraped)
+	if fmt.Sprint(scraped) != "[/ /bad /good]" {
+		t.Errorf("Scraped pages = %v, want the crawl to continue past the panic", scraped)
+	}
+
+	errors := s.GetExtractionErrors()
+	if len(errors) != 1 {
+		t.Fatalf("Expected 1 extraction error, got %v", errors)
+	}
+	if errors[0].URL != server.URL+"/bad" || errors[0].Message != "malformed page" {
+		t.Errorf("Unexpected extraction error: %+v", errors[0])
+	}
+}
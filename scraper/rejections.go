@@ -0,0 +1,105 @@
+package scraper
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Reasons a discovered URL was never visited, as recorded in frontier.json
+const (
+	RejectInvalidURL     = "invalid_url"
+	RejectExternalDomain = "external_domain"
+	RejectRobots         = "robots_disallowed"
+	RejectFileExtension  = "file_extension"
+	RejectNonContentPath = "non_content_path"
+	RejectMaxDepth       = "max_depth"
+	RejectNotAllowlisted = "not_allowlisted"
+	RejectExcludePattern = "exclude_pattern"
+	RejectIncludePattern = "no_include_match"
+	RejectSwitcherLink   = "switcher_link"
+	RejectResource       = "resource"
+)
+
+// RejectedURL is a discovered URL that was never visited and the reason it was skipped
+type RejectedURL struct {
+	URL     string `json:"url"`
+	Reason  string `json:"reason"`
+	FoundOn string `json:"found_on,omitempty"` // Page the link was found on, when known
+}
+
+// frontierReport is the content of frontier.json
+type frontierReport struct {
+	Total int           `json:"total"`
+	URLs  []RejectedURL `json:"urls"`
+}
+
+// recordRejection remembers why a URL was skipped, keeping the first reason seen
+func (s *Scraper) recordRejection(rawURL, reason string, foundOn *url.URL) {
+	if !s.config.GetRecordFrontier() {
+		return
+	}
+
+	s.frontierMutex.Lock()
+	defer s.frontierMutex.Unlock()
+
+	if s.rejected == nil {
+		s.rejected = make(map[string]RejectedURL)
+	}
+	key := urlMatchKey(rawURL)
+	if _, exists := s.rejected[key]; exists {
+		return
+	}
+
+	rejection := RejectedURL{URL: rawURL, Reason: reason}
+	if foundOn != nil {
+		rejection.FoundOn = foundOn.String()
+	}
+	s.rejected[key] = rejection
+}
+
+// recordFetched marks a URL as visited so it is left out of the frontier
+func (s *Scraper) recordFetched(rawURL string) {
+	if !s.config.GetRecordFrontier() {
+		return
+	}
+
+	s.frontierMutex.Lock()
+	defer s.frontierMutex.Unlock()
+
+	if s.fetched == nil {
+		s.fetched = make(map[string]bool)
+	}
+	s.fetched[urlMatchKey(rawURL)] = true
+}
+
+// GetRejectedURLs returns the discovered URLs that were never visited, sorted by URL
+func (s *Scraper) GetRejectedURLs() []RejectedURL {
+	s.frontierMutex.Lock()
+	defer s.frontierMutex.Unlock()
+
+	rejected := make([]RejectedURL, 0, len(s.rejected))
+	for key, rejection := range s.rejected {
+		if !s.fetched[key] {
+			rejected = append(rejected, rejection)
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].URL < rejected[j].URL })
+	return rejected
+}
+
+// writeFrontier writes frontier.json to the output directory
+func (s *Scraper) writeFrontier() error {
+	if err := os.MkdirAll(s.config.OutputDir, 0755); err != nil {
+		return err
+	}
+
+	rejected := s.GetRejectedURLs()
+	data, err := json.MarshalIndent(frontierReport{Total: len(rejected), URLs: rejected}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.config.OutputDir, "frontier.json"), data, 0644)
+}
@@ -0,0 +1,91 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_RecordFrontier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/":
+			body = `Root <a href="/guide">Guide</a> <a href="/files/manual.pdf">Manual</a> ` +
+				`<a href="https://external.example.org/docs">External</a> <a href="/admin/panel">Admin</a>`
+		case "/guide":
+			body = `Guide <a href="/guide/advanced">Advanced</a> <a href="/">Home</a>`
+		default:
+			body = "Content for " + r.URL.Path
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	record := true
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, OutputDir: outputDir, MaxDepth: 2, RecordFrontier: &record})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "frontier.json"))
+	if err != nil {
+		t.Fatalf("frontier.json should be written: %v", err)
+	}
+	var report frontierReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("frontier.json should be valid JSON: %v", err)
+	}
+
+	reasons := make(map[string]string)
+	for _, rejected := range report.URLs {
+		reasons[rejected.URL] = rejected.Reason
+	}
+
+	expected := map[string]string{
+		server.URL + "/guide/advanced":      RejectMaxDepth,
+		server.URL + "/files/manual.pdf":    RejectFileExtension,
+		"https://external.example.org/docs": RejectExternalDomain,
+		server.URL + "/admin/panel":         RejectNonContentPath,
+	}
+	for rawURL, reason := range expected {
+		if reasons[rawURL] != reason {
+			t.Errorf("reason for %s = %q, want %q", rawURL, reasons[rawURL], reason)
+		}
+	}
+	if report.Total != len(expected) || len(report.URLs) != len(expected) {
+		t.Errorf("expected %d rejected URLs, got %d: %v", len(expected), report.Total, reasons)
+	}
+	for _, rejected := range report.URLs {
+		if rejected.Reason == RejectFileExtension && rejected.FoundOn != server.URL {
+			t.Errorf("found_on = %q, want the root page", rejected.FoundOn)
+		}
+	}
+}
+
+func TestScraper_RecordFrontierDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Root", `Root <a href="/files/manual.pdf">Manual</a>`))
+	}))
+	defer server.Close()
+
+	outputDir := t.TempDir()
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, OutputDir: outputDir, MaxDepth: 2})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(s.GetRejectedURLs()) != 0 {
+		t.Errorf("rejections should not be recorded unless record_frontier is set")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "frontier.json")); !os.IsNotExist(err) {
+		t.Errorf("frontier.json should not be written, stat error = %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// resourceExtensions are page resources (styles, scripts, fonts, icons) rather than documents
+var resourceExtensions = map[string]bool{
+	".css": true, ".js": true, ".mjs": true, ".map": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true, ".ico": true,
+}
+
+// resourceLinkRels are <link rel> values naming a resource the page loads, not another page
+var resourceLinkRels = map[string]bool{
+	"stylesheet": true, "icon": true, "apple-touch-icon": true, "mask-icon": true,
+	"preload": true, "modulepreload": true, "prefetch": true, "manifest": true,
+}
+
+// isResourceURL reports whether u points at a stylesheet, script, font or icon by its extension
+func isResourceURL(u *url.URL) bool {
+	return resourceExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// isResourceLink reports whether a <link> element's rel names a loaded resource
+func isResourceLink(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if resourceLinkRels[value] {
+			return true
+		}
+	}
+	return false
+}
+
+// skipsResource reports whether u is a resource that must not be fetched as a page; resources
+// matching download_extensions are fetched, and saved as assets instead
+func (s *Scraper) skipsResource(u *url.URL) bool {
+	return isResourceURL(u) && !s.isDownloadAsset(u)
+}
+
+// recordResourceLink records the resource a <link> or <script src> element loads, downloading
+// it when it matches download_extensions; nothing found here is crawled as a page
+func (s *Scraper) recordResourceLink(e *colly.HTMLElement) {
+	ref := e.Attr("src")
+	if e.Name == "link" {
+		if !isResourceLink(e.Attr("rel")) {
+			return
+		}
+		ref = e.Attr("href")
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || ref == "" || parsed.Scheme == "data" {
+		return
+	}
+	resource := s.extractor.ExtractBaseURL(e.DOM, e.Request.URL).ResolveReference(parsed)
+	resource.Fragment = ""
+	s.addResourceLink(resource.String())
+
+	if s.isDownloadAsset(resource) && resource.Host == e.Request.URL.Host && s.robots.Allows(resource.RequestURI()) {
+		s.enqueueLink(e.Request, resource.String())
+	}
+}
+
+// addResourceLink remembers a resource URL found on a page
+func (s *Scraper) addResourceLink(resourceURL string) {
+	s.resourcesMutex.Lock()
+	defer s.resourcesMutex.Unlock()
+
+	if s.resources == nil {
+		s.resources = make(map[string]bool)
+	}
+	s.resources[resourceURL] = true
+}
+
+// GetResourceLinks returns the sorted <link> and <script src> URLs recorded under
+// record_resource_links
+func (s *Scraper) GetResourceLinks() []string {
+	s.resourcesMutex.Lock()
+	defer s.resourcesMutex.Unlock()
+
+	links := make([]string, 0, len(s.resources))
+	for link := range s.resources {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+	return links
+}
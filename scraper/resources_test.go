@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+// resourceTestServer serves a page loading a stylesheet, a script and an icon, and linking to
+// a stylesheet and a script as if they were pages; requests holds every path fetched
+func resourceTestServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mutex sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.URL.Path)
+		mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><head><title>Home</title>
+				<link rel="stylesheet" href="/css/site.css"><link rel="icon" href="/favicon.ico">
+				<link rel="next" href="/guide"><script src="/js/app.js"></script></head>
+				<body><main><p>Welcome to the docs. Read the guide or view the raw theme files.</p>
+				<a href="/guide">Guide</a> <a href="/css/theme.css">Theme</a> <a href="/js/app.js">Script</a>
+				</main></body></html>`)
+		case "/guide":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, htmlPage("Guide", "<p>The guide explains everything in detail.</p>"))
+		case "/css/site.css":
+			w.Header().Set("Content-Type", "text/css")
+			fmt.Fprint(w, `@import url("/css/base.css"); body { color: black; }`)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "resource")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, requests...)
+	}
+}
+
+func TestScraper_NeverEnqueuesResources(t *testing.T) {
+	server, requests := resourceTestServer(t)
+
+	enableDedup := true
+	for _, dedup := range []*bool{nil, &enableDedup} {
+		cfg := &config.Config{RootURL: server.URL + "/", MaxDepth: 3, EnableDeduplication: dedup}
+		es, err := NewWithFeatures(newTestScraper(t, cfg).config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages, err := es.ScrapeWithFeatures()
+		if err != nil {
+			t.Fatalf("ScrapeWithFeatures() error = %v", err)
+		}
+
+		for _, path := range requests() {
+			if strings.HasSuffix(path, ".css") || strings.HasSuffix(path, ".js") || strings.HasSuffix(path, ".ico") {
+				t.Errorf("Expected resources never to be requested (deduplication: %v), got %s", dedup != nil, path)
+			}
+		}
+		if len(pages) != 2 {
+			t.Errorf("Expected the home and guide pages only (deduplication: %v), got %d", dedup != nil, len(pages))
+		}
+		if links := es.GetResourceLinks(); len(links) != 0 {
+			t.Errorf("Expected no resources recorded without record_resource_links, got %v", links)
+		}
+	}
+}
+
+func TestScraper_SeededResourcesAreSkipped(t *testing.T) {
+	server, requests := resourceTestServer(t)
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:      server.URL + "/guide",
+		MaxDepth:     1,
+		SeedRequests: []config.RequestSpec{{URL: server.URL + "/css/site.css"}},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if got := requests(); len(got) != 1 || got[0] != "/guide" {
+		t.Errorf("Expected a seeded stylesheet not to be fetched, got requests %v", got)
+	}
+}
+
+func TestScraper_RecordResourceLinks(t *testing.T) {
+	server, requests := resourceTestServer(t)
+
+	record := true
+	assetDir := t.TempDir()
+	s := newTestScraper(t, &config.Config{
+		RootURL:             server.URL + "/",
+		MaxDepth:            1,
+		RecordResourceLinks: &record,
+		DownloadExtensions:  []string{".css"},
+		AssetDir:            assetDir,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	// Loaded resources are recorded; <link rel="next"> is a page link, not a resource
+	want := []string{server.URL + "/css/site.css", server.URL + "/favicon.ico", server.URL + "/js/app.js"}
+	if got := s.GetResourceLinks(); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("GetResourceLinks() = %v, want %v", got, want)
+	}
+
+	// The stylesheet matches download_extensions and is saved as an asset, not stored as a page,
+	// and its @import chain is not followed
+	if _, err := os.ReadFile(filepath.Join(assetDir, "css", "site.css")); err != nil {
+		t.Errorf("Expected the stylesheet to be downloaded: %v", err)
+	}
+	got := requests()
+	sort.Strings(got)
+	for _, path := range got {
+		if path == "/js/app.js" || path == "/favicon.ico" || path == "/css/base.css" {
+			t.Errorf("Expected only download_extensions resources to be fetched, got %s", path)
+		}
+	}
+	for _, page := range s.GetPages() {
+		if strings.HasSuffix(page.URL, ".css") {
+			t.Errorf("Expected the stylesheet not to be stored as a page, got %s", page.URL)
+		}
+	}
+}
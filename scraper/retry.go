@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"net/http"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// Retry policies for transient errors, each with its own retry count, backoff and attempt counter
+const (
+	RetryPolicyConnection = "connection" // DNS failures, refused or reset connections: no response at all
+	RetryPolicyHTTP       = "http"       // 5xx responses
+)
+
+// retryAttemptKey is the request context key holding how many times a request was retried under
+// a policy; followed links share their parent's context, so the key carries the method and URL
+func retryAttemptKey(r *colly.Request, policy string) string {
+	return "retry_attempt_" + policy + "_" + r.Method + " " + r.URL.String()
+}
+
+// retryPolicy classifies a failed response: a connection error that produced no response, a
+// 5xx status, or "" when the failure is not worth retrying
+func retryPolicy(r *colly.Response) string {
+	switch {
+	case r.StatusCode == 0:
+		return RetryPolicyConnection
+	case r.StatusCode >= http.StatusInternalServerError:
+		return RetryPolicyHTTP
+	default:
+		return ""
+	}
+}
+
+// isTransientError reports whether a failed response is worth retrying: a 5xx status or a
+// connection error that produced no response
+func isTransientError(r *colly.Response) bool {
+	return retryPolicy(r) != ""
+}
+
+// retryLimits returns a policy's retry count and backoff: connection_retries after a
+// connection_retry_delay base, or http_retries after a min_delay base
+func (s *Scraper) retryLimits(policy string) (int, *Backoff) {
+	if policy == RetryPolicyConnection {
+		return s.config.GetConnectionRetries(), s.connectionBackoff
+	}
+	return s.config.GetHTTPRetries(), s.httpBackoff
+}
+
+// retryRequest re-queues a request that failed with a transient error; the policy's backoff
+// holds back the host's next request in the rate limiter rather than blocking the error
+// callback. Requests that exhaust the policy's retries are recorded as failed. It reports
+// whether the error was transient.
+func (s *Scraper) retryRequest(r *colly.Response) bool {
+	policy := retryPolicy(r)
+	if policy == "" {
+		return false
+	}
+
+	retries, backoff := s.retryLimits(policy)
+	attempt, _ := r.Ctx.GetAny(retryAttemptKey(r.Request, policy)).(int)
+	if attempt >= retries {
+		s.recordFailedURL(r.Request.URL.String())
+		return true
+	}
+
+	delay := backoff.Delay(attempt)
+	s.logger.Printf("Retrying %s after %s error in %s (attempt %d of %d)", r.Request.URL, policy, delay, attempt+1, retries)
+	s.limiter.backOff(r.Request.URL.Host, delay)
+
+	r.Ctx.Put(retryAttemptKey(r.Request, policy), attempt+1)
+	if err := r.Request.Retry(); err != nil {
+		s.logger.Printf("Could not retry %s: %v", r.Request.URL, err)
+		s.recordFailedURL(r.Request.URL.String())
+	}
+	return true
+}
+
+// recordFailedURL remembers a URL whose retries were exhausted
+func (s *Scraper) recordFailedURL(rawURL string) {
+	s.failedMutex.Lock()
+	defer s.failedMutex.Unlock()
+	s.failedURLs = append(s.failedURLs, rawURL)
+}
+
+// GetFailedURLs returns the URLs that still failed after all retry attempts
+func (s *Scraper) GetFailedURLs() []string {
+	s.failedMutex.Lock()
+	defer s.failedMutex.Unlock()
+	return append([]string(nil), s.failedURLs...)
+}
@@ -0,0 +1,369 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"docscraper/config"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// flakyServer returns status for the first failures requests to each path, then a page
+func flakyServer(t *testing.T, status int, failures int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Recovered", "Content after transient failures"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+// droppingServer closes the connection without a response for the first failures requests,
+// then serves a page
+func droppingServer(t *testing.T, failures int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= failures {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, htmlPage("Recovered", "Content after dropped connections"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestScraper_RetriesTransientErrors(t *testing.T) {
+	server, hits := flakyServer(t, http.StatusServiceUnavailable, 2)
+
+	retries := 3
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1, RetryAttempts: &retries})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(hits); got != 3 {
+		t.Errorf("expected 2 failures and 1 success, got %d requests", got)
+	}
+	if s.GetPageCount() != 1 {
+		t.Errorf("page should be stored after retrying, got %d pages", s.GetPageCount())
+	}
+	if failed := s.GetFailedURLs(); len(failed) != 0 {
+		t.Errorf("no URLs should fail, got %v", failed)
+	}
+}
+
+func TestScraper_RetriesExhausted(t *testing.T) {
+	server, hits := flakyServer(t, http.StatusBadGateway, 100)
+
+	retries := 2
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1, RetryAttempts: &retries})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(hits); got != 3 {
+		t.Errorf("expected the first attempt plus 2 retries, got %d requests", got)
+	}
+	if failed := s.GetFailedURLs(); len(failed) != 1 || failed[0] != server.URL {
+		t.Errorf("GetFailedURLs() = %v, want [%s]", failed, server.URL)
+	}
+}
+
+func TestScraper_RetriesEachSiblingPage(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, htmlPage("Home", `<a href="/a">A</a> <a href="/b">B</a> <a href="/c">C</a>`))
+			return
+		}
+		mu.Lock()
+		hits[r.URL.Path]++
+		first := hits[r.URL.Path] == 1
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Recovered", "Content after one transient failure"))
+	}))
+	defer server.Close()
+
+	// Each sibling shares its parent's context, but gets its own retry
+	retries := 1
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2, RetryAttempts: &retries})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if failed := s.GetFailedURLs(); len(failed) != 0 {
+		t.Errorf("every sibling should recover after one retry, got failed %v", failed)
+	}
+	if s.GetPageCount() != 4 {
+		t.Errorf("expected the root and 3 siblings stored, got %d pages", s.GetPageCount())
+	}
+	for _, path := range []string{"/a", "/b", "/c"} {
+		if hits[path] != 2 {
+			t.Errorf("expected %s requested twice, got %d", path, hits[path])
+		}
+	}
+}
+
+func TestScraper_NoRetryOnClientErrors(t *testing.T) {
+	server, hits := flakyServer(t, http.StatusNotFound, 100)
+
+	retries := 3
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1, RetryAttempts: &retries})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if got := atomic.LoadInt32(hits); got != 1 {
+		t.Errorf("404 should not be retried, got %d requests", got)
+	}
+	if failed := s.GetFailedURLs(); len(failed) != 0 {
+		t.Errorf("client errors are not retry failures, got %v", failed)
+	}
+}
+
+func TestScraper_RetryPolicies(t *testing.T) {
+	none, two, delay := 0, 2, 0
+
+	tests := []struct {
+		name       string
+		server     func(t *testing.T) (*httptest.Server, *int32)
+		connection *int
+		http       *int
+		wantHits   int32
+		wantFailed bool
+	}{
+		{
+			name:       "connection error uses connection_retries",
+			server:     func(t *testing.T) (*httptest.Server, *int32) { return droppingServer(t, 2) },
+			connection: &two,
+			http:       &none,
+			wantHits:   3,
+		},
+		{
+			name:       "connection error ignores http_retries",
+			server:     func(t *testing.T) (*httptest.Server, *int32) { return droppingServer(t, 2) },
+			connection: &none,
+			http:       &two,
+			wantHits:   1,
+			wantFailed: true,
+		},
+		{
+			name:     "503 uses http_retries",
+			server:   func(t *testing.T) (*httptest.Server, *int32) { return flakyServer(t, http.StatusServiceUnavailable, 2) },
+			http:     &two,
+			wantHits: 3,
+		},
+		{
+			name:       "503 ignores connection_retries",
+			server:     func(t *testing.T) (*httptest.Server, *int32) { return flakyServer(t, http.StatusServiceUnavailable, 2) },
+			connection: &two,
+			http:       &none,
+			wantHits:   1,
+			wantFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hits := tt.server(t)
+
+			s := newTestScraper(t, &config.Config{
+				RootURL:              server.URL,
+				MaxDepth:             1,
+				ConnectionRetries:    tt.connection,
+				HTTPRetries:          tt.http,
+				ConnectionRetryDelay: &delay,
+			})
+			if err := s.Scrape(); err != nil {
+				t.Fatalf("Scrape() error = %v", err)
+			}
+
+			if got := atomic.LoadInt32(hits); got != tt.wantHits {
+				t.Errorf("expected %d requests, got %d", tt.wantHits, got)
+			}
+			if failed := len(s.GetFailedURLs()) == 1; failed != tt.wantFailed {
+				t.Errorf("GetFailedURLs() = %v, want failed %v", s.GetFailedURLs(), tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestScraper_ConnectionRetriesPerPage(t *testing.T) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, htmlPage("Home", `<a href="/dead">Dead</a> <a href="/flaky">Flaky</a>`))
+			return
+		}
+		mu.Lock()
+		hits[r.URL.Path]++
+		drop := r.URL.Path == "/dead" || hits[r.URL.Path] == 1
+		mu.Unlock()
+		if drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		fmt.Fprint(w, htmlPage("Recovered", "Content after a dropped connection"))
+	}))
+	// Without keep-alives the transport never silently resends a request on a dropped connection
+	server.Config.SetKeepAlivesEnabled(false)
+	server.Start()
+	defer server.Close()
+
+	// /dead uses up its connection retry before /flaky is fetched
+	retries, delay := 1, 0
+	s := newTestScraper(t, &config.Config{
+		RootURL:              server.URL + "/",
+		MaxDepth:             2,
+		ConnectionRetries:    &retries,
+		ConnectionRetryDelay: &delay,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if hits["/dead"] != 2 || hits["/flaky"] != 2 {
+		t.Errorf("expected each page requested twice, got %v", hits)
+	}
+	if failed := s.GetFailedURLs(); len(failed) != 1 || failed[0] != server.URL+"/dead" {
+		t.Errorf("GetFailedURLs() = %v, want [%s/dead]", failed, server.URL)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		status int
+		want   string
+	}{
+		{0, RetryPolicyConnection},
+		{http.StatusBadGateway, RetryPolicyHTTP},
+		{http.StatusServiceUnavailable, RetryPolicyHTTP},
+		{http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		if got := retryPolicy(&colly.Response{StatusCode: tt.status}); got != tt.want {
+			t.Errorf("retryPolicy(%d) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{0, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, false},
+	}
+
+	for _, tt := range tests {
+		if got := isTransientError(&colly.Response{StatusCode: tt.status}); got != tt.want {
+			t.Errorf("isTransientError(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestScraper_RetriesHonorMinRequestInterval(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Recovered", "Content after transient failures"))
+	}))
+	defer server.Close()
+
+	// min_delay is 0, so the retry backoff alone would re-send immediately
+	retries, interval := 2, 150
+	s := newTestScraper(t, &config.Config{
+		RootURL:            server.URL,
+		MaxDepth:           1,
+		HTTPRetries:        &retries,
+		MinRequestInterval: &interval,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if len(times) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(times))
+	}
+	// Arrival times jitter slightly around the scheduled send times
+	minGap := time.Duration(interval) * time.Millisecond * 9 / 10
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < minGap {
+			t.Errorf("Retry %d was sent %v after the previous request, want at least %dms", i, gap, interval)
+		}
+	}
+}
+
+func TestScraper_RetryBackoffStopsOnCancel(t *testing.T) {
+	server, hits := droppingServer(t, 1)
+
+	// The first connection retry is held back for a minute
+	retries, retryDelay := 1, 60000
+	s := newTestScraper(t, &config.Config{
+		RootURL:              server.URL,
+		MaxDepth:             1,
+		ConnectionRetries:    &retries,
+		ConnectionRetryDelay: &retryDelay,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.collector.OnError(func(*colly.Response, error) { cancel() })
+
+	done := make(chan error, 1)
+	go func() { done <- s.ScrapeWithContext(ctx) }()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ScrapeWithContext() waited out the retry backoff after cancellation")
+	}
+	if got := atomic.LoadInt32(hits); got != 1 {
+		t.Errorf("Expected the retry dropped on cancellation, got %d requests", got)
+	}
+}
@@ -0,0 +1,158 @@
+package scraper
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RobotsRules holds the robots.txt directives that apply to the scraper's user agent
+type RobotsRules struct {
+	Disallow   []string      // Disallowed path prefixes
+	Allow      []string      // Allowed overrides within disallowed prefixes
+	CrawlDelay time.Duration // Zero when no Crawl-delay is given
+}
+
+// robotsGroup is one block of User-agent lines and the rules that follow them
+type robotsGroup struct {
+	agents []string
+	rules  RobotsRules
+}
+
+// ParseRobotsTxt parses robots.txt and returns the rules of the group whose User-agent best
+// matches userAgent (the longest agent name contained in it), falling back to the "*" group
+func ParseRobotsTxt(r io.Reader, userAgent string) *RobotsRules {
+	var groups []*robotsGroup
+	var current *robotsGroup
+	inAgentLines := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		if field == "user-agent" {
+			// Consecutive User-agent lines share one group
+			if !inAgentLines {
+				current = &robotsGroup{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			inAgentLines = true
+			continue
+		}
+		inAgentLines = false
+		if current == nil {
+			continue
+		}
+
+		switch field {
+		case "disallow":
+			if value != "" {
+				current.rules.Disallow = append(current.rules.Disallow, value)
+			}
+		case "allow":
+			if value != "" {
+				current.rules.Allow = append(current.rules.Allow, value)
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.rules.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	return selectRobotsRules(groups, strings.ToLower(userAgent))
+}
+
+// selectRobotsRules merges the groups naming the most specific agent matching userAgent,
+// or the "*" groups when none match
+func selectRobotsRules(groups []*robotsGroup, userAgent string) *RobotsRules {
+	best := ""
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent != "*" && agent != "" && strings.Contains(userAgent, agent) && len(agent) > len(best) {
+				best = agent
+			}
+		}
+	}
+	if best == "" {
+		best = "*"
+	}
+
+	rules := &RobotsRules{}
+	for _, group := range groups {
+		for _, agent := range group.agents {
+			if agent == best {
+				rules.Disallow = append(rules.Disallow, group.rules.Disallow...)
+				rules.Allow = append(rules.Allow, group.rules.Allow...)
+				if group.rules.CrawlDelay > rules.CrawlDelay {
+					rules.CrawlDelay = group.rules.CrawlDelay
+				}
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// Allows reports whether path (with any query) may be crawled. The longest matching rule
+// wins and Allow wins ties; nil rules allow everything.
+func (r *RobotsRules) Allows(path string) bool {
+	if r == nil {
+		return true
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	longestDisallow := longestRobotsMatch(r.Disallow, path)
+	if longestDisallow < 0 {
+		return true
+	}
+	return longestRobotsMatch(r.Allow, path) >= longestDisallow
+}
+
+// longestRobotsMatch returns the length of the longest pattern matching path, or -1
+func longestRobotsMatch(patterns []string, path string) int {
+	longest := -1
+	for _, pattern := range patterns {
+		if len(pattern) > longest && robotsPatternMatches(pattern, path) {
+			longest = len(pattern)
+		}
+	}
+	return longest
+}
+
+// robotsPatternMatches matches a robots.txt path prefix, honoring "*" wildcards and a trailing "$" anchor
+func robotsPatternMatches(pattern, path string) bool {
+	if !strings.ContainsAny(pattern, "*$") {
+		return strings.HasPrefix(path, pattern)
+	}
+
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
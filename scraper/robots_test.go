@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+const testRobotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /private/
+Disallow: /tmp
+Allow: /private/public-page
+Crawl-delay: 1
+
+User-agent: DocBot
+User-agent: OtherBot
+Disallow: /drafts/
+Disallow: /*.pdf$
+Crawl-delay: 2.5
+
+User-agent: DocBot-Images
+Disallow: /
+`
+
+func TestParseRobotsTxt_UserAgentGroups(t *testing.T) {
+	tests := []struct {
+		name       string
+		userAgent  string
+		disallow   []string
+		crawlDelay time.Duration
+	}{
+		{"falls back to wildcard", "Mozilla/5.0 (X11; Linux x86_64)", []string{"/private/", "/tmp"}, time.Second},
+		{"matches named group", "DocBot/1.0 (+https://example.com/bot)", []string{"/drafts/", "/*.pdf$"}, 2500 * time.Millisecond},
+		{"shared group line", "otherbot/2.0", []string{"/drafts/", "/*.pdf$"}, 2500 * time.Millisecond},
+		{"most specific agent wins", "DocBot-Images/1.0", []string{"/"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := ParseRobotsTxt(strings.NewReader(testRobotsTxt), tt.userAgent)
+			if strings.Join(rules.Disallow, ",") != strings.Join(tt.disallow, ",") {
+				t.Errorf("Disallow = %v, want %v", rules.Disallow, tt.disallow)
+			}
+			if rules.CrawlDelay != tt.crawlDelay {
+				t.Errorf("CrawlDelay = %v, want %v", rules.CrawlDelay, tt.crawlDelay)
+			}
+		})
+	}
+}
+
+func TestRobotsRules_Allows(t *testing.T) {
+	wildcard := ParseRobotsTxt(strings.NewReader(testRobotsTxt), "Mozilla/5.0")
+	named := ParseRobotsTxt(strings.NewReader(testRobotsTxt), "DocBot/1.0")
+
+	tests := []struct {
+		name  string
+		rules *RobotsRules
+		path  string
+		want  bool
+	}{
+		{"unrestricted path", wildcard, "/docs/intro", true},
+		{"disallowed prefix", wildcard, "/private/keys", false},
+		{"disallowed without trailing slash", wildcard, "/tmp/cache", false},
+		{"allow overrides longer match", wildcard, "/private/public-page", true},
+		{"named group ignores wildcard rules", named, "/private/keys", true},
+		{"named group prefix", named, "/drafts/next", false},
+		{"anchored wildcard match", named, "/files/guide.pdf", false},
+		{"anchored wildcard miss", named, "/files/guide.pdf?download=1", true},
+		{"nil rules allow all", nil, "/private/keys", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Allows(tt.path); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScraper_RespectsRobotsRules(t *testing.T) {
+	var mu sync.Mutex
+	fetched := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\nCrawl-delay: 0.01\n")
+			return
+		}
+
+		mu.Lock()
+		fetched[r.URL.Path] = true
+		mu.Unlock()
+
+		body := "Content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/private/secret">Secret</a> <a href="/public">Public</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 2, RespectRobots: true})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if fetched["/private/secret"] {
+		t.Error("path disallowed by robots.txt should not be fetched")
+	}
+	if !fetched["/public"] {
+		t.Error("allowed path should be fetched")
+	}
+	if s.limiter.rate != 100 {
+		t.Errorf("crawl-delay should pace requests to 100/s, got %v/s", s.limiter.rate)
+	}
+}
+
+func TestScraper_RobotsDisallowsRoot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /docs\n")
+			return
+		}
+		fmt.Fprint(w, htmlPage("Docs", "Content"))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/docs/", MaxDepth: 1, RespectRobots: true})
+	if err := s.Scrape(); err == nil {
+		t.Error("Scrape() should fail when robots.txt disallows the root URL")
+	}
+}
@@ -1,18 +1,23 @@
 package scraper
 
 import (
-	"bufio"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"docscraper/config"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
 	"github.com/gocolly/colly/v2/proxy"
 )
@@ -24,6 +29,22 @@ type PageData struct {
 	Content   string    `json:"content"`
 	Timestamp time.Time `json:"timestamp"`
 	Depth     int       `json:"depth"`
+
+	LastModified *time.Time `json:"last_modified,omitempty"` // Parsed from last_modified_patterns
+
+	ContentType    string        `json:"content_type,omitempty"`    // Content-Type the page was served with
+	StructuredData []interface{} `json:"structured_data,omitempty"` // JSON-LD blocks embedded in the page
+	Breadcrumbs    []string      `json:"breadcrumbs,omitempty"`     // Ancestor URLs from a JSON-LD BreadcrumbList
+
+	NavigationTrail []string          `json:"navigation_trail,omitempty"`  // URLs of the page's highlighted nav item and its enclosing items
+	TableOfContents []string          `json:"table_of_contents,omitempty"` // Pages linked from the page's table of contents, in order
+	NavigationMenu  string            `json:"navigation_menu,omitempty"`   // The root page's navigation_menu_selector menu HTML
+	Metadata        map[string]string `json:"metadata,omitempty"`          // Values of capture_data_attributes, keyed without "data-"
+	TokenEstimate   int               `json:"token_estimate,omitempty"`    // Approximate LLM token count of Content
+	Tags            []string          `json:"tags,omitempty"`              // Assigned by tag_rules
+	ExtractionTime  time.Duration     `json:"extraction_time,omitempty"`   // Time spent extracting and analysing the page's content
+
+	ContentFile string `json:"content_file,omitempty"` // Temp file holding Content under spill_to_disk; see LoadContent
 }
 
 // ProgressCallback defines the signature for progress tracking callbacks
@@ -32,11 +53,13 @@ type ProgressCallback func(current, total int, currentURL string)
 // EnhancedScraper extends Scraper with advanced features
 type EnhancedScraper struct {
 	*Scraper
-	deduplicator     *LinkDeduplicator
+	languageFilter   string // Detectable filter_by_language code, "" when not filtering
 	qualityAnalyzer  *ContentQualityAnalyzer
 	progressCallback ProgressCallback
 	currentProgress  int
-	totalEstimated   int
+	totalEstimated   int        // Requested plus queued URLs; only ever grows
+	discovered       int        // Requests issued within max_depth
+	progressMutex    sync.Mutex // Guards the progress counters
 }
 
 // Scraper handles the web scraping functionality
@@ -46,6 +69,93 @@ type Scraper struct {
 	pages     []PageData
 	logger    *log.Logger
 	extractor *ContentExtractor
+
+	responses      []CapturedResponse // Raw responses kept for WARC output
+	responsesMutex sync.Mutex
+
+	frontier *PriorityFrontier // Non-nil when priority_patterns reorder the crawl
+
+	robots  *RobotsRules // Parsed robots.txt rules when respect_robots is set
+	hosts   *hostLimiter // Per-host rules under per_host_parallelism
+	limiter *rateLimiter // Per-host pacing and retry backoff
+
+	skipHashes map[string]bool // Content hashes of known-junk pages
+	allowlist  map[string]bool // URLs from url_allowlist_file, nil when unset
+	filter     *crawlFilter    // Compiled crawl_filter patterns
+	tagRules   []tagRule       // Compiled tag_rules
+	reusedURLs map[string]bool // Pages carried over from the prior crawl manifest
+
+	conditional      map[string]ConditionalInfo // Validators from an earlier crawl, keyed by urlMatchKey
+	validators       map[string]ConditionalInfo // This crawl's validators under conditional_requests, saved to state_file
+	conditionalMutex sync.Mutex
+
+	deduplicator *LinkDeduplicator         // Non-nil under enable_deduplication; its seen URLs are kept in state_file
+	contentDedup *ContentDeduplicator      // Non-nil under deduplication.dedupe_by_content
+	resumedURLs  map[string]bool           // Pages scraped by an earlier run, loaded from state_file
+	resumeDepths map[string]int            // Pending URLs of an earlier run to their depth, until requested again
+	scrapedURLs  []string                  // Pages scraped so far, including resumed ones, saved to state_file
+	pending      map[string]pendingRequest // Requested URLs not yet scraped or failed, saved to state_file
+	stateSavedAt time.Time                 // When state_file was last written
+	stateMutex   sync.Mutex
+
+	assets      map[string]string // Downloaded asset URLs to their saved paths
+	assetsMutex sync.Mutex
+
+	resources      map[string]bool // <link> and <script src> URLs, recorded under record_resource_links
+	resourcesMutex sync.Mutex
+
+	spillDir   string // Temp directory for spill_to_disk content, created on first use
+	spillErr   error
+	spillOnce  sync.Once
+	spillCount int64
+
+	extractionErrors      []ExtractionError // Pages whose processing panicked
+	extractionErrorsMutex sync.Mutex
+
+	pageRequests      map[string]*colly.Request // Requests behind stored pages, kept for reconciliation waves
+	pageRequestsMutex sync.Mutex
+
+	profiler      Profiler   // Set with EnhancedScraper.SetProfiler, nil when not profiling
+	profilerMutex sync.Mutex // Guards profiler
+
+	httpBackoff       *Backoff // Delays between retries of 5xx responses
+	connectionBackoff *Backoff // Delays between retries of connection errors
+	failedURLs        []string // URLs that exhausted their retries
+	failedMutex       sync.Mutex
+
+	switchers      map[*colly.Request]map[string][]switcherEntry // Switcher links per page being scraped, under switcher_version/switcher_locale
+	switchersMutex sync.Mutex
+
+	rejected      map[string]RejectedURL // Discovered URLs skipped by filters, when record_frontier is set
+	fetched       map[string]bool        // URLs that received a response or error
+	frontierMutex sync.Mutex
+
+	pagesMutex    sync.Mutex
+	stream        chan PageData // Receives pages instead of pages under ScrapeStream
+	streamedPages int64         // Pages sent on stream
+
+	discovery *discovery // Set on the scraper a Discover call crawls with
+
+	externalLinks    map[string]bool   // Links to other hosts, under check_external_links
+	linkCheckResults []LinkCheckResult // Outcome of checking externalLinks after the crawl
+	linkCheckMutex   sync.Mutex
+
+	ctx        context.Context // Context of the running ScrapeWithContext call
+	httpClient *http.Client    // Fetches robots.txt, sitemaps and API listings; see fetch
+
+	downloadedBytes int64 // Response body bytes received, checked against max_download_bytes
+	reservedPages   int64 // Pages stored or about to be, checked against max_pages
+}
+
+// CapturedResponse holds a raw request/response exchange for archival output
+type CapturedResponse struct {
+	URL             string
+	Method          string
+	RequestHeaders  http.Header
+	StatusCode      int
+	ResponseHeaders http.Header
+	Body            []byte
+	FetchedAt       time.Time
 }
 
 // New creates a new scraper instance
@@ -67,31 +177,61 @@ func New(cfg *config.Config) (*Scraper, error) {
 	c := colly.NewCollector(
 		colly.Async(true),
 	)
-
-	// Set limits including concurrent requests
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*",
-		Parallelism: cfg.GetConcurrentRequests(),
-		Delay:       time.Duration(cfg.MinDelay) * time.Second,
-	})
+	c.SetRequestTimeout(time.Duration(cfg.GetRequestTimeout()) * time.Second)
+
+	// Set concurrent requests. With per_host_parallelism each host gets its own rule and
+	// concurrent_requests becomes a global cap enforced by the transport. Delays between
+	// requests are left to the rate limiter, which min_delay feeds through min_request_interval.
+	var hosts *hostLimiter
+	if cfg.PerHostParallelism != nil {
+		hosts = newHostLimiter(c, *cfg.PerHostParallelism)
+	} else {
+		c.Limit(&colly.LimitRule{
+			DomainGlob:  "*",
+			Parallelism: cfg.GetConcurrentRequests(),
+		})
+	}
 
 	// Set allowed domains to prevent following external links
 	rootURL, err := url.Parse(cfg.RootURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid root URL")
 	}
-	c.AllowedDomains = []string{rootURL.Host}
+	c.AllowedDomains = []string{rootURL.Hostname()}
+
+	// Proxies and TLS settings go on a clone of the default transport, keeping its dialer,
+	// handshake and idle timeouts
+	var transport http.RoundTripper = http.DefaultTransport
+	if cfg.GetIgnoreSSLErrors() || cfg.HasProxies() {
+		custom := http.DefaultTransport.(*http.Transport).Clone()
+		if cfg.GetIgnoreSSLErrors() {
+			custom.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			logger.Printf("Warning: TLS certificate verification is disabled")
+		}
 
-	// Configure proxy if available (optional feature)
-	if cfg.HasProxies() {
-		rp, err := proxy.RoundRobinProxySwitcher(cfg.Proxies...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to setup proxy switcher: %v", err)
+		// Configure proxy if available (optional feature)
+		if cfg.HasProxies() {
+			rp, err := proxy.RoundRobinProxySwitcher(cfg.Proxies...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to setup proxy switcher: %v", err)
+			}
+			custom.Proxy = rp
+			logger.Printf("Configured %d proxies for rotation", len(cfg.Proxies))
 		}
-		c.SetProxyFunc(rp)
-		logger.Printf("Configured %d proxies for rotation", len(cfg.Proxies))
+
+		c.WithTransport(custom)
+		transport = custom
 	}
 
+	if hosts != nil {
+		transport = newWorkerCapTransport(transport, cfg.GetConcurrentRequests())
+		c.WithTransport(transport)
+		c.OnRequest(hosts.ensureRule)
+	}
+
+	// Pace each host as requests are dispatched, retries included; see waitForSlot
+	limiter := newRateLimiter(cfg.GetRequestsPerSecond(), time.Duration(cfg.GetMinRequestInterval())*time.Millisecond)
+
 	// Set depth limit
 	// This will be combined with other OnRequest logic in setupCallbacks
 
@@ -101,12 +241,54 @@ func New(cfg *config.Config) (*Scraper, error) {
 		// Verbose logging is handled through our custom logger
 	}
 
+	extractor, err := NewContentExtractorWithConfig(ExtractorConfig{
+		TitleStrategy:          cfg.GetTitleStrategy(),
+		CleaningPatterns:       cfg.CleaningPatterns,
+		ReplaceDefaultCleaning: cfg.ReplaceDefaultCleaning,
+		LastModifiedPatterns:   cfg.LastModifiedPatterns,
+		PreserveHeadingIDs:     cfg.GetPreserveHeadingIDs() && contains(cfg.GetOutputFormats(), "markdown"),
+		ExcludeMarkers:         cfg.ExcludeMarkers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	scraper := &Scraper{
 		config:    cfg,
 		collector: c,
 		pages:     make([]PageData, 0),
 		logger:    logger,
-		extractor: NewContentExtractor(),
+		extractor: extractor,
+		hosts:     hosts,
+		limiter:   limiter,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(cfg.GetRequestTimeout()) * time.Second,
+		},
+	}
+	scraper.httpBackoff = NewBackoff(cfg.GetBackoffStrategy(), time.Duration(cfg.MinDelay)*time.Second, DefaultMaxBackoff)
+	scraper.connectionBackoff = NewBackoff(cfg.GetBackoffStrategy(), time.Duration(cfg.GetConnectionRetryDelay())*time.Millisecond, DefaultMaxBackoff)
+
+	if scraper.skipHashes, err = loadSkipHashes(cfg.SkipContentHashes, cfg.SkipHashFile); err != nil {
+		return nil, err
+	}
+
+	if scraper.filter, err = compileCrawlFilter(cfg.CrawlFilter); err != nil {
+		return nil, err
+	}
+
+	if scraper.allowlist, err = loadURLAllowlist(cfg.URLAllowlistFile); err != nil {
+		return nil, err
+	}
+
+	if scraper.tagRules, err = compileTagRules(cfg.TagRules); err != nil {
+		return nil, err
+	}
+
+	if len(cfg.PriorityPatterns) > 0 {
+		if scraper.frontier, err = NewPriorityFrontier(cfg.PriorityPatterns); err != nil {
+			return nil, err
+		}
 	}
 
 	// Setup collector callbacks
@@ -119,29 +301,85 @@ func New(cfg *config.Config) (*Scraper, error) {
 func (s *Scraper) setupCallbacks() {
 	// Rotate user agents, add delays, and check depth
 	s.collector.OnRequest(func(r *colly.Request) {
-		// Check depth limit
-		if r.Depth > s.config.MaxDepth {
+		s.restoreDepth(r)
+
+		// Requests refused after cancellation stay pending so a resumed run picks them up
+		if s.ctx != nil && s.ctx.Err() != nil {
+			s.trackPending(r)
+			r.Abort()
+			return
+		}
+
+		if s.overByteBudget() {
+			s.logger.Printf("Skipping URL, download budget of %d bytes used up: %s", s.config.GetMaxDownloadBytes(), r.URL.String())
+			r.Abort()
+			return
+		}
+
+		if s.overPageLimit() {
+			s.logger.Printf("Skipping URL, page limit of %d reached: %s", s.config.GetMaxPages(), r.URL.String())
+			r.Abort()
+			return
+		}
+
+		// Check depth limit; assets are leaves, so one linked from the deepest page is still fetched
+		if r.Depth > s.config.MaxDepth && !(r.Depth == s.config.MaxDepth+1 && s.isDownloadAsset(r.URL)) {
 			s.logger.Printf("Skipping URL at depth %d (max: %d): %s", r.Depth, s.config.MaxDepth, r.URL.String())
+			s.recordRejection(r.URL.String(), RejectMaxDepth, nil)
 			r.Abort()
 			return
 		}
 
+		// Sitemaps, listings and waves can list resources too; they are never fetched as pages
+		if s.skipsResource(r.URL) {
+			s.logger.Printf("Skipping resource URL, not a page: %s", r.URL.String())
+			s.recordRejection(r.URL.String(), RejectResource, nil)
+			r.Abort()
+			return
+		}
+
+		// Seeds from sitemaps and listings bypass shouldFollowLink
+		if !s.isAllowlisted(r.URL.String()) {
+			s.logger.Printf("Skipping URL not in the allowlist: %s", r.URL.String())
+			s.recordRejection(r.URL.String(), RejectNotAllowlisted, nil)
+			r.Abort()
+			return
+		}
+
+		if s.isReused(r.URL.String()) {
+			s.logger.Printf("Skipping unchanged URL reused from the crawl manifest: %s", r.URL.String())
+			r.Abort()
+			return
+		}
+
+		if s.isResumed(r.URL.String()) {
+			s.logger.Printf("Skipping URL scraped by an earlier run: %s", r.URL.String())
+			r.Abort()
+			return
+		}
+
+		s.addConditionalHeaders(r)
+
 		if len(s.config.UserAgents) > 0 {
 			userAgent := s.config.UserAgents[rand.Intn(len(s.config.UserAgents))]
 			r.Headers.Set("User-Agent", userAgent)
 		}
 
-		// Add random delay
-		if s.config.MaxDelay > s.config.MinDelay {
-			delay := rand.Intn(s.config.MaxDelay-s.config.MinDelay) + s.config.MinDelay
-			time.Sleep(time.Duration(delay) * time.Second)
+		if !s.waitForSlot(r) {
+			return
 		}
 
+		s.trackPending(r)
 		s.logger.Printf("Visiting: %s (depth: %d)", r.URL.String(), r.Depth)
 	})
 
+	// Record resources before extraction strips scripts from the page
+	if s.config.GetRecordResourceLinks() {
+		s.onHTML("link[href], script[src]", s.recordResourceLink)
+	}
+
 	// Handle HTML responses
-	s.collector.OnHTML("html", func(e *colly.HTMLElement) {
+	s.onHTML("html", func(e *colly.HTMLElement) {
 		// Count total links on the page
 		linkCount := e.DOM.Find("a[href]").Length()
 		s.logger.Printf("Page %s contains %d links", e.Request.URL.String(), linkCount)
@@ -151,36 +389,94 @@ func (s *Scraper) setupCallbacks() {
 
 	// Find and follow links
 	linkCounter := 0
-	s.collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	s.onHTML("a[href]", func(e *colly.HTMLElement) {
 		linkCounter++
 		link := e.Attr("href")
 		s.logger.Printf("Processing link #%d: %s (current depth: %d)", linkCounter, link, e.Request.Depth)
 
-		if s.shouldFollowLink(link, e.Request.URL) {
+		if s.metaRobots(e.DOM).NoFollow {
+			s.logger.Printf("Rejected link #%d: %s (page is nofollow)", linkCounter, link)
+			return
+		}
+
+		if s.shouldFollowLink(link, s.extractor.ExtractBaseURL(e.DOM, e.Request.URL)) && !s.rejectsSwitcherLink(e, link) {
 			s.logger.Printf("Following link #%d: %s", linkCounter, link)
-			e.Request.Visit(link)
+			s.enqueueLink(e.Request, link)
 		} else {
 			s.logger.Printf("Rejected link #%d: %s", linkCounter, link)
 		}
 	})
 
+	if s.filtersSwitchers() {
+		s.collector.OnScraped(s.forgetPageSwitchers)
+	}
+
+	// A page is done once all its callbacks, link following included, have run
+	s.collector.OnScraped(func(r *colly.Response) {
+		s.finishPending(r.Request)
+	})
+
 	// Handle errors
 	s.collector.OnError(func(r *colly.Response, err error) {
 		s.logger.Printf("Error visiting %s: %v", r.Request.URL, err)
+		defer s.finishPending(r.Request)
+		if s.discovery != nil && s.discoverFailed(r, err) {
+			return
+		}
+		s.recordFetched(r.Request.URL.String())
+		if s.reuseNotModified(r) {
+			return
+		}
+		if s.retryRequest(r) {
+			return
+		}
+		s.followRedirectWithoutLocation(r)
 	})
 
 	// Log responses
 	s.collector.OnResponse(func(r *colly.Response) {
 		s.logger.Printf("Response from %s: %d", r.Request.URL, r.StatusCode)
+		s.recordFetched(r.Request.URL.String())
+		s.addDownloadedBytes(len(r.Body))
+		s.recordValidators(r)
+
+		if s.discovery != nil && r.Request.Method == http.MethodHead {
+			s.discoverHead(r)
+			return
+		}
+		if s.generatesFormat("warc") {
+			s.captureResponse(r)
+		}
+		if s.isDownloadAsset(r.Request.URL) {
+			// Discover writes nothing
+			if s.discovery == nil {
+				s.saveAsset(r)
+			}
+			return
+		}
+		if s.generatesFormat("auto") {
+			s.captureSourcePage(r)
+		}
 	})
 }
 
 // extractPageContent extracts and processes content from a page
 func (s *Scraper) extractPageContent(e *colly.HTMLElement) {
+	start := time.Now()
 	doc := e.DOM
 
+	if s.metaRobots(doc).NoIndex {
+		s.logger.Printf("Skipping noindex page: %s", e.Request.URL.String())
+		return
+	}
+
+	if s.followMetaRefresh(e.Request, doc) {
+		return
+	}
+
 	// Extract title
 	title := s.extractor.ExtractTitle(doc)
+	details := s.readPageDetails(e)
 
 	// Extract main content
 	content := s.extractor.ExtractContent(doc)
@@ -190,17 +486,83 @@ func (s *Scraper) extractPageContent(e *colly.HTMLElement) {
 		return
 	}
 
-	// Create page data
-	pageData := PageData{
+	if s.isSkippedContent(content) {
+		s.logger.Printf("Skipping page matching a skip hash: %s", e.Request.URL.String())
+		return
+	}
+
+	page := s.storeExtractedPage(e, title, content, details, start)
+	s.logger.Printf("Extracted content from: %s (Title: %s)", page.URL, page.Title)
+}
+
+// pageDetails are read from a page's DOM before ExtractContent strips its navigation, tables
+// of contents and scripts
+type pageDetails struct {
+	tableOfContents []string
+	navigationMenu  string
+	navigationTrail []string
+	structuredData  []interface{}
+	metadata        map[string]string
+}
+
+// readPageDetails reads the pageDetails of e's page; call it before ExtractContent
+func (s *Scraper) readPageDetails(e *colly.HTMLElement) pageDetails {
+	return pageDetails{
+		tableOfContents: s.extractor.ExtractTableOfContents(e.DOM, e.Request.URL),
+		navigationMenu:  s.captureNavigationMenu(e.DOM, e.Request.URL),
+		navigationTrail: s.extractor.ExtractNavigationTrail(e.DOM, e.Request.URL),
+		structuredData:  s.extractor.ExtractStructuredData(e.DOM),
+		metadata:        s.extractor.ExtractDataAttributes(e.DOM, s.config.CaptureDataAttributes),
+	}
+}
+
+// storeExtractedPage builds the page extracted from e, records its extraction time since start
+// and stores it
+func (s *Scraper) storeExtractedPage(e *colly.HTMLElement, title, content string, details pageDetails, start time.Time) PageData {
+	page := PageData{
 		Title:     strings.TrimSpace(title),
 		URL:       e.Request.URL.String(),
 		Content:   content,
 		Timestamp: time.Now(),
 		Depth:     e.Request.Depth,
+
+		LastModified:    s.extractor.ExtractLastModified(content),
+		ContentType:     responseContentType(e.Response),
+		TokenEstimate:   EstimateTokens(content),
+		NavigationTrail: details.navigationTrail,
+		TableOfContents: details.tableOfContents,
+		NavigationMenu:  details.navigationMenu,
+		Metadata:        details.metadata,
+	}
+	s.applyStructuredData(&page, details.structuredData)
+	s.applyTags(&page)
+	page.ExtractionTime = time.Since(start)
+	s.recordExtraction(page)
+	s.rememberPageRequest(page.URL, e.Request)
+	s.storePage(page)
+	return page
+}
+
+// applyStructuredData attaches the page's JSON-LD and, when use_structured_data is set,
+// derives breadcrumbs and a missing last-modified date from it
+func (s *Scraper) applyStructuredData(page *PageData, data []interface{}) {
+	page.StructuredData = data
+	if len(page.StructuredData) == 0 || !s.config.GetUseStructuredData() {
+		return
+	}
+
+	page.Breadcrumbs = StructuredBreadcrumbs(page.StructuredData)
+	if page.LastModified == nil {
+		page.LastModified = StructuredDate(page.StructuredData)
 	}
+}
 
-	s.pages = append(s.pages, pageData)
-	s.logger.Printf("Extracted content from: %s (Title: %s)", pageData.URL, pageData.Title)
+// metaRobots returns the page's robots meta directives when respect_meta_robots is enabled
+func (s *Scraper) metaRobots(doc *goquery.Selection) MetaRobots {
+	if !s.config.GetRespectMetaRobots() {
+		return MetaRobots{}
+	}
+	return s.extractor.ExtractMetaRobots(doc)
 }
 
 // shouldFollowLink determines if a link should be followed
@@ -211,6 +573,7 @@ func (s *Scraper) shouldFollowLink(link string, baseURL *url.URL) bool {
 	linkURL, err := url.Parse(link)
 	if err != nil {
 		s.logger.Printf("Failed to parse link '%s': %v", link, err)
+		s.recordRejection(link, RejectInvalidURL, baseURL)
 		return false
 	}
 
@@ -220,6 +583,7 @@ func (s *Scraper) shouldFollowLink(link string, baseURL *url.URL) bool {
 			!strings.HasPrefix(link, "?") && !strings.Contains(link, ".") &&
 			!strings.Contains(link, "/") && len(link) > 10)) {
 		s.logger.Printf("Invalid URL format: %s", link)
+		s.recordRejection(link, RejectInvalidURL, baseURL)
 		return false
 	}
 
@@ -230,35 +594,75 @@ func (s *Scraper) shouldFollowLink(link string, baseURL *url.URL) bool {
 	// Check if the resolved URL is valid (has a scheme and host for absolute URLs)
 	if linkURL.IsAbs() && (linkURL.Scheme == "" || linkURL.Host == "") {
 		s.logger.Printf("Invalid absolute URL: %s", link)
+		s.recordRejection(link, RejectInvalidURL, baseURL)
 		return false
 	}
 
 	// Only follow links from the same domain
 	if resolvedURL.Host != baseURL.Host {
 		s.logger.Printf("Skipping external domain: %s vs %s", resolvedURL.Host, baseURL.Host)
+		s.recordRejection(resolvedURL.String(), RejectExternalDomain, baseURL)
+		s.recordExternalLink(resolvedURL)
+		return false
+	}
+
+	if !s.robots.Allows(resolvedURL.RequestURI()) {
+		s.logger.Printf("Skipping path disallowed by robots.txt: %s", resolvedURL.String())
+		s.recordRejection(resolvedURL.String(), RejectRobots, baseURL)
+		return false
+	}
+
+	if !s.isAllowlisted(resolvedURL.String()) {
+		s.logger.Printf("Skipping URL not in the allowlist: %s", resolvedURL.String())
+		s.recordRejection(resolvedURL.String(), RejectNotAllowlisted, baseURL)
+		return false
+	}
+
+	// Skip fragments and query-only links
+	if resolvedURL.Path == baseURL.Path && resolvedURL.Fragment != "" {
+		s.logger.Printf("Skipping fragment-only link: %s", resolvedURL.String())
+		return false
+	}
+
+	// Stylesheets, scripts and fonts are loaded by pages, not pages themselves
+	if s.skipsResource(resolvedURL) {
+		s.logger.Printf("Skipping resource link: %s", resolvedURL.String())
+		s.recordRejection(resolvedURL.String(), RejectResource, baseURL)
 		return false
 	}
 
+	// Configured patterns replace the built-in skip lists below
+	if s.filter.configured() {
+		if reason := s.filter.rejection(resolvedURL.String()); reason != "" {
+			s.logger.Printf("Skipping link filtered by crawl_filter (%s): %s", reason, resolvedURL.String())
+			s.recordRejection(resolvedURL.String(), reason, baseURL)
+			return false
+		}
+		s.logger.Printf("Link approved for following: %s", resolvedURL.String())
+		return true
+	}
+
+	if s.isDownloadAsset(resolvedURL) {
+		s.logger.Printf("Link approved for download: %s", resolvedURL.String())
+		return true
+	}
+
 	// Skip certain file types
 	skipExtensions := []string{".pdf", ".jpg", ".jpeg", ".png", ".gif", ".zip", ".tar", ".gz", ".mp4", ".avi", ".mov"}
 	for _, ext := range skipExtensions {
 		if strings.HasSuffix(strings.ToLower(resolvedURL.Path), ext) {
 			s.logger.Printf("Skipping file extension %s for: %s", ext, resolvedURL.String())
+			s.recordRejection(resolvedURL.String(), RejectFileExtension, baseURL)
 			return false
 		}
 	}
 
-	// Skip fragments and query-only links
-	if resolvedURL.Path == baseURL.Path && resolvedURL.Fragment != "" {
-		s.logger.Printf("Skipping fragment-only link: %s", resolvedURL.String())
-		return false
-	}
-
 	// Skip common non-content paths
 	skipPaths := []string{"/login", "/register", "/api/", "/admin/", "/search"}
 	for _, path := range skipPaths {
 		if strings.Contains(resolvedURL.Path, path) {
 			s.logger.Printf("Skipping non-content path '%s' for: %s", path, resolvedURL.String())
+			s.recordRejection(resolvedURL.String(), RejectNonContentPath, baseURL)
 			return false
 		}
 	}
@@ -269,73 +673,232 @@ func (s *Scraper) shouldFollowLink(link string, baseURL *url.URL) bool {
 
 // Scrape starts the scraping process
 func (s *Scraper) Scrape() error {
-	// Check robots.txt if enabled
-	if s.config.RespectRobots {
-		if allowed, err := s.checkRobotsTxt(s.config.RootURL); err != nil {
-			s.logger.Printf("Warning: Could not check robots.txt: %v", err)
-		} else if !allowed {
-			return fmt.Errorf("robots.txt disallows scraping this site")
+	return s.ScrapeWithContext(context.Background())
+}
+
+// ScrapeWithContext runs the crawl until it finishes or ctx is cancelled. Cancelling aborts
+// pending requests and returns ctx.Err(); pages stored so far remain available from GetPages.
+func (s *Scraper) ScrapeWithContext(ctx context.Context) error {
+	s.ctx = ctx
+
+	// Give CDNs and edge caches a moment before the very first request, robots.txt included
+	if initialDelay := s.config.GetInitialDelay(); initialDelay > 0 {
+		s.logger.Printf("Waiting %ds before the first request", initialDelay)
+		select {
+		case <-time.After(time.Duration(initialDelay) * time.Second):
+		case <-ctx.Done():
+			s.logger.Printf("Scraping cancelled: %v", ctx.Err())
+			return ctx.Err()
 		}
 	}
 
+	if err := s.loadRobotsTxt(); err != nil {
+		return err
+	}
+
 	s.logger.Printf("Starting scrape of: %s with max depth: %d", s.config.RootURL, s.config.MaxDepth)
 
+	// Seed the crawl from a JSON listing endpoint if configured
+	if s.config.APIListingURL != "" {
+		if _, err := s.LoadAPIListing(s.config.APIListingURL); err != nil {
+			s.logger.Printf("Warning: Could not load API listing: %v", err)
+		}
+	}
+
+	// Seed the crawl from sitemap.xml so pages only linked from scripted menus are found
+	if s.config.GetUseSitemap() {
+		if _, err := s.LoadSitemap(s.config.GetSitemapURL()); err != nil {
+			s.logger.Printf("Warning: Could not load sitemap: %v", err)
+		}
+	}
+
+	// Reuse unchanged pages from the previous crawl and refetch the rest
+	crawlStart := time.Now()
+	var incrementalSeeds []string
+	if s.config.GetSitemapIncremental() {
+		incrementalSeeds = s.prepareIncremental()
+	}
+
+	// Pick up where an interrupted run left off
+	var pendingURLs []string
+	if s.config.StateFile != "" {
+		pendingURLs = s.resumeState()
+	}
+
 	// Start scraping
 	s.collector.Visit(s.config.RootURL)
+	s.visitSeedRequests()
+	for _, link := range pendingURLs {
+		if err := s.collector.Visit(link); err != nil {
+			s.logger.Printf("Could not enqueue pending URL %s: %v", link, err)
+		}
+	}
+	for _, link := range incrementalSeeds {
+		if err := s.collector.Visit(link); err != nil {
+			s.logger.Printf("Could not enqueue changed URL %s: %v", link, err)
+		}
+	}
 	s.collector.Wait()
+	s.drainFrontier()
+	s.runReconciliationWaves()
+
+	// State is saved periodically while pages are stored; the final save carries the pages,
+	// pending URLs and conditional links recorded since
+	if s.config.StateFile != "" {
+		if err := s.SaveState(s.config.StateFile); err != nil {
+			s.logger.Printf("Warning: Could not save state file: %v", err)
+		}
+	}
 
-	s.logger.Printf("Scraping completed. Total pages found: %d", len(s.pages))
+	s.logger.Printf("Scraping completed. Total pages found: %d", s.GetPageCount())
+	if failed := len(s.GetExtractionErrors()); failed > 0 {
+		s.logger.Printf("%d pages failed during extraction", failed)
+	}
+	if failed := len(s.GetFailedURLs()); failed > 0 {
+		s.logger.Printf("%d URLs failed after %d retries", failed, s.config.GetRetryAttempts())
+	}
 	for i, page := range s.pages {
 		s.logger.Printf("Page %d: %s (depth: %d)", i+1, page.URL, page.Depth)
 	}
 
+	if s.config.GetSitemapIncremental() && s.stream != nil {
+		s.logger.Printf("Warning: Not saving crawl manifest, streamed pages are not kept")
+	} else if s.config.GetSitemapIncremental() {
+		if err := s.saveManifest(crawlStart); err != nil {
+			s.logger.Printf("Warning: Could not save crawl manifest: %v", err)
+		}
+	}
+
+	if s.config.GetCheckExternalLinks() && ctx.Err() == nil {
+		if err := s.checkExternalLinks(); err != nil {
+			s.logger.Printf("Warning: Could not write link_check.json: %v", err)
+		}
+	}
+
+	if s.config.GetRecordFrontier() {
+		if err := s.writeFrontier(); err != nil {
+			s.logger.Printf("Warning: Could not write frontier.json: %v", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		s.logger.Printf("Scraping cancelled: %v", err)
+		return err
+	}
 	return nil
 }
 
-// checkRobotsTxt checks if scraping is allowed by robots.txt
-func (s *Scraper) checkRobotsTxt(rootURL string) (bool, error) {
+// loadRobotsTxt applies the root host's robots.txt when respect_robots is set, failing when it
+// disallows the root URL
+func (s *Scraper) loadRobotsTxt() error {
+	if !s.config.RespectRobots {
+		return nil
+	}
+
+	rules, err := s.checkRobotsTxt(s.config.RootURL)
+	if err != nil {
+		s.logger.Printf("Warning: Could not check robots.txt: %v", err)
+		return nil
+	}
+	rootURL, _ := url.Parse(s.config.RootURL)
+	if !rules.Allows(rootURL.RequestURI()) {
+		return fmt.Errorf("robots.txt disallows scraping this site")
+	}
+	s.robots = rules
+	s.applyCrawlDelay(rules.CrawlDelay)
+	return nil
+}
+
+// checkRobotsTxt fetches robots.txt for the root URL's host and parses the rules that apply
+// to the scraper's user agent; a missing or unreachable robots.txt allows everything
+func (s *Scraper) checkRobotsTxt(rootURL string) (*RobotsRules, error) {
 	parsedURL, err := url.Parse(rootURL)
 	if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
-		return false, fmt.Errorf("invalid URL")
+		return nil, fmt.Errorf("invalid URL")
 	}
 
 	robotsURL := fmt.Sprintf("%s://%s/robots.txt", parsedURL.Scheme, parsedURL.Host)
 
-	resp, err := http.Get(robotsURL)
+	resp, err := s.fetch(robotsURL)
 	if err != nil {
-		return true, nil // If robots.txt doesn't exist, assume allowed
+		return &RobotsRules{}, nil // If robots.txt doesn't exist, assume allowed
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return true, nil // No robots.txt found, assume allowed
+	if resp.StatusCode != http.StatusOK {
+		return &RobotsRules{}, nil // No robots.txt found, assume allowed
 	}
 
-	// Simple robots.txt parsing (basic implementation)
-	scanner := bufio.NewScanner(resp.Body)
-	userAgentMatch := false
+	return ParseRobotsTxt(resp.Body, s.robotsUserAgent()), nil
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(strings.ToLower(line), "user-agent:") {
-			agent := strings.TrimSpace(strings.TrimPrefix(line, "user-agent:"))
-			if agent == "*" {
-				userAgentMatch = true
-			}
-		} else if userAgentMatch && strings.HasPrefix(strings.ToLower(line), "disallow:") {
-			disallow := strings.TrimSpace(strings.TrimPrefix(line, "disallow:"))
-			if disallow == "/" {
-				return false, nil
-			}
-		}
+// fetch GETs a URL outside the collector, such as robots.txt, a sitemap or an API listing,
+// through the crawl's transport, request timeout and user agent
+func (s *Scraper) fetch(rawURL string) (*http.Response, error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("User-Agent", s.robotsUserAgent())
+	return s.httpClient.Do(req)
+}
+
+// robotsUserAgent returns the user agent matched against robots.txt groups: the first
+// configured user agent, or colly's default
+func (s *Scraper) robotsUserAgent() string {
+	if len(s.config.UserAgents) > 0 {
+		return s.config.UserAgents[0]
+	}
+	return s.collector.UserAgent
+}
 
-	return true, nil
+// applyCrawlDelay raises the delay between requests to a robots.txt Crawl-delay
+func (s *Scraper) applyCrawlDelay(delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	s.logger.Printf("Applying robots.txt crawl-delay of %s", delay)
+
+	if s.limiter != nil {
+		s.limiter.lowerRate(1 / delay.Seconds())
+	}
 }
 
 // GetPageCount returns the number of scraped pages
 func (s *Scraper) GetPageCount() int {
-	return len(s.pages)
+	return len(s.pages) + int(atomic.LoadInt64(&s.streamedPages))
+}
+
+// captureResponse records the raw exchange behind a response
+func (s *Scraper) captureResponse(r *colly.Response) {
+	captured := CapturedResponse{
+		URL:        r.Request.URL.String(),
+		Method:     r.Request.Method,
+		StatusCode: r.StatusCode,
+		Body:       append([]byte(nil), r.Body...),
+		FetchedAt:  time.Now(),
+	}
+	if r.Request.Headers != nil {
+		captured.RequestHeaders = r.Request.Headers.Clone()
+	}
+	if r.Headers != nil {
+		captured.ResponseHeaders = r.Headers.Clone()
+	}
+
+	s.responsesMutex.Lock()
+	s.responses = append(s.responses, captured)
+	s.responsesMutex.Unlock()
+}
+
+// GetResponses returns the raw responses captured for WARC output
+func (s *Scraper) GetResponses() []CapturedResponse {
+	s.responsesMutex.Lock()
+	defer s.responsesMutex.Unlock()
+	return append([]CapturedResponse(nil), s.responses...)
 }
 
 // GetPages returns the scraped pages
@@ -354,19 +917,29 @@ func NewWithFeatures(cfg *config.Config) (*EnhancedScraper, error) {
 	enhanced := &EnhancedScraper{
 		Scraper: baseScraper,
 	}
+	enhanced.collector.OnRequest(enhanced.trackDiscovered)
+	enhanced.collector.OnRequest(enhanced.startProfiling)
+	enhanced.collector.OnResponse(enhanced.recordProfile)
+	enhanced.collector.OnError(enhanced.recordProfileError)
 
 	// Initialize deduplicator if enabled
 	if cfg.GetEnableDeduplication() {
 		enhanced.deduplicator = NewLinkDeduplicator(URLNormalizer{
 			RemoveFragment:  cfg.Deduplication.RemoveFragments,
-			RemoveQuery:     cfg.Deduplication.RemoveQueryParams,
+			RemoveQuery:     cfg.Deduplication.RemoveQueryParams && !cfg.GetQueryIsIdentity(),
 			LowerCase:       cfg.Deduplication.IgnoreCase,
 			RemoveWWW:       cfg.Deduplication.IgnoreWWW,
 			RemoveTrailing:  cfg.Deduplication.IgnoreTrailingSlash,
 			SortQueryParams: true,
+			AMPPatterns:     cfg.Deduplication.AMPPatterns,
+			CleanPath:       !cfg.Deduplication.KeepPathSegments,
 		})
 	}
 
+	if cfg.Deduplication.DedupeByContent {
+		enhanced.contentDedup = NewContentDeduplicator(cfg.Deduplication.SimHashThreshold)
+	}
+
 	// Initialize quality analyzer if enabled
 	if cfg.GetEnableQualityAnalysis() {
 		qualityConfig := QualityConfig{
@@ -374,9 +947,19 @@ func NewWithFeatures(cfg *config.Config) (*EnhancedScraper, error) {
 			RequireTitle:        cfg.QualityAnalysis.RequireTitle,
 			RequireContent:      cfg.QualityAnalysis.RequireContent,
 			SkipNavigationPages: cfg.QualityAnalysis.SkipNavigation,
+			MaxLinkRatio:        cfg.GetMaxLinkRatio(),
 			BlacklistPatterns:   cfg.QualityAnalysis.BlacklistedPatterns,
+
+			MinLanguageConfidence: cfg.GetMinLanguageConfidence(),
+			AllowUnknownLanguage:  cfg.QualityAnalysis.AllowUnknownLanguage,
+		}
+		enhanced.qualityAnalyzer = NewContentQualityAnalyzerWithLanguages(qualityConfig, CommonLanguageWords)
+
+		if lang := cfg.QualityAnalysis.FilterByLanguage; lang != "" && CommonLanguageWords[lang] == nil {
+			baseScraper.logger.Printf("Warning: filter_by_language %q is not a detectable language and is ignored", lang)
+		} else {
+			enhanced.languageFilter = lang
 		}
-		enhanced.qualityAnalyzer = NewContentQualityAnalyzer(qualityConfig)
 	}
 
 	return enhanced, nil
@@ -404,17 +987,37 @@ func (es *EnhancedScraper) ScrapeWithFeatures() ([]PageData, error) {
 		return nil, err
 	}
 
+	if es.qualityAnalyzer != nil {
+		if err := es.saveQualityReport(); err != nil {
+			es.logger.Printf("Warning: Could not write quality_report.json: %v", err)
+		}
+	}
+
 	return es.GetPages(), nil
 }
 
+// saveQualityReport writes the quality analysis report to quality_report.json in the output directory
+func (es *EnhancedScraper) saveQualityReport() error {
+	if err := os.MkdirAll(es.config.OutputDir, 0755); err != nil {
+		return err
+	}
+	return es.qualityAnalyzer.GenerateReport().Save(filepath.Join(es.config.OutputDir, "quality_report.json"))
+}
+
 // setupDeduplicationCallbacks modifies the scraper to use deduplication
 func (es *EnhancedScraper) setupDeduplicationCallbacks() {
-	// Replace the original link handling with deduplication-aware version
-	es.collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+	// Replace the original link handling with deduplication-aware version; as with quality
+	// analysis, the plain callback must be detached or it enqueues the duplicates anyway
+	es.collector.OnHTMLDetach("a[href]")
+	es.onHTML("a[href]", func(e *colly.HTMLElement) {
 		link := e.Attr("href")
 
+		if es.metaRobots(e.DOM).NoFollow {
+			return
+		}
+
 		// Check if this URL should be followed (use existing logic)
-		if !es.shouldFollowLink(link, e.Request.URL) {
+		if !es.shouldFollowLink(link, es.extractor.ExtractBaseURL(e.DOM, e.Request.URL)) || es.rejectsSwitcherLink(e, link) {
 			return
 		}
 
@@ -429,23 +1032,42 @@ func (es *EnhancedScraper) setupDeduplicationCallbacks() {
 
 		// Add to deduplicator and visit
 		es.deduplicator.AddURL(absoluteURL)
-		e.Request.Visit(link)
+		es.enqueueLink(e.Request, link)
 	})
 }
 
 // setupQualityAnalysisCallbacks modifies the scraper to use quality analysis
 func (es *EnhancedScraper) setupQualityAnalysisCallbacks() {
-	// Replace the original HTML handling with quality-aware version
-	es.collector.OnHTML("html", func(e *colly.HTMLElement) {
+	// Replace the original HTML handling with quality-aware version; colly runs every callback
+	// registered for a selector, so the plain extraction must be detached or it stores every page
+	es.collector.OnHTMLDetach("html")
+	es.onHTML("html", func(e *colly.HTMLElement) {
+		start := time.Now()
+		if es.metaRobots(e.DOM).NoIndex {
+			es.logger.Printf("Skipping noindex page: %s", e.Request.URL.String())
+			return
+		}
+
+		if es.followMetaRefresh(e.Request, e.DOM) {
+			return
+		}
+
 		// Extract content using DOM
 		title := es.extractor.ExtractTitle(e.DOM)
+		details := es.readPageDetails(e)
 		content := es.extractor.ExtractContent(e.DOM)
 
+		if es.isSkippedContent(content) {
+			es.logger.Printf("Skipping page matching a skip hash: %s", e.Request.URL.String())
+			return
+		}
+
 		// Create ScrapedContent struct for quality analysis
 		scrapedContent := ScrapedContent{
-			URL:     e.Request.URL.String(),
-			Title:   title,
-			Content: content,
+			URL:       e.Request.URL.String(),
+			Title:     title,
+			Content:   content,
+			LinkRatio: DOMLinkRatio(e.DOM),
 			Metadata: NodeMetadata{
 				WordCount:    len(strings.Fields(content)),
 				LastModified: time.Now(),
@@ -468,6 +1090,20 @@ func (es *EnhancedScraper) setupQualityAnalysisCallbacks() {
 			return
 		}
 
+		if es.qualityAnalyzer.IsLanguageMismatch(quality, es.languageFilter) {
+			es.logger.Printf("Skipping page in language %q (confidence: %.2f, want %q): %s",
+				quality.Language, quality.LanguageConfidence, es.languageFilter, e.Request.URL.String())
+			return
+		}
+
+		if quality.IsNavigationPage && es.config.QualityAnalysis.SkipNavigation {
+			es.logger.Printf("Skipping navigation page (link ratio: %.2f): %s", quality.LinkRatio, e.Request.URL.String())
+			return
+		}
+		if quality.LinkRatio > es.config.GetMaxLinkRatio() {
+			es.logger.Printf("Warning: High link ratio (%.2f): %s", quality.LinkRatio, e.Request.URL.String())
+		}
+
 		// Check for blacklisted patterns
 		for _, pattern := range es.config.QualityAnalysis.BlacklistedPatterns {
 			if strings.Contains(strings.ToLower(content), strings.ToLower(pattern)) {
@@ -477,21 +1113,9 @@ func (es *EnhancedScraper) setupQualityAnalysisCallbacks() {
 		}
 
 		// If quality analysis passed, save the page
-		page := PageData{
-			Title:     title,
-			URL:       e.Request.URL.String(),
-			Content:   content,
-			Timestamp: time.Now(),
-			Depth:     e.Request.Depth,
-		}
+		page := es.storeExtractedPage(e, title, content, details, start)
 
-		es.pages = append(es.pages, page)
-
-		// Update progress
-		if es.progressCallback != nil {
-			es.currentProgress++
-			es.progressCallback(es.currentProgress, es.totalEstimated, e.Request.URL.String())
-		}
+		es.reportProgress(e.Request.URL.String())
 
 		es.logger.Printf("Scraped page: %s (quality score: %.2f)", page.URL, quality.Score)
 	})
@@ -1,13 +1,20 @@
 package scraper
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"docscraper/config"
+
+	"github.com/gocolly/colly/v2"
 )
 
 func TestNew(t *testing.T) {
@@ -118,6 +125,56 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_ProxyTransportKeepsDefaultTimeouts(t *testing.T) {
+	s := newTestScraper(t, &config.Config{
+		RootURL:  "https://example.com",
+		MaxDepth: 1,
+		Proxies:  []string{"http://proxy.example.com:8080"},
+	})
+
+	transport, ok := s.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", s.httpClient.Transport)
+	}
+	if transport.Proxy == nil {
+		t.Error("Expected the proxy switcher on the transport")
+	}
+
+	defaults := http.DefaultTransport.(*http.Transport)
+	if transport.DialContext == nil {
+		t.Error("Expected the default dialer to be kept")
+	}
+	if transport.TLSHandshakeTimeout != defaults.TLSHandshakeTimeout {
+		t.Errorf("TLSHandshakeTimeout = %v, want %v", transport.TLSHandshakeTimeout, defaults.TLSHandshakeTimeout)
+	}
+	if transport.IdleConnTimeout != defaults.IdleConnTimeout {
+		t.Errorf("IdleConnTimeout = %v, want %v", transport.IdleConnTimeout, defaults.IdleConnTimeout)
+	}
+}
+
+func TestScraper_IgnoreSSLErrors(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", "<p>Served with a self-signed certificate.</p>"))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted, so only an insecure crawl gets the page
+	for _, ignore := range []bool{false, true} {
+		s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 1, IgnoreSSLErrors: &ignore})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+
+		want := 0
+		if ignore {
+			want = 1
+		}
+		if s.GetPageCount() != want {
+			t.Errorf("Expected %d pages with ignore_ssl_errors %v, got %d", want, ignore, s.GetPageCount())
+		}
+	}
+}
+
 func TestScraper_shouldFollowLink(t *testing.T) {
 	// Create a test scraper
 	cfg := &config.Config{
@@ -277,7 +334,8 @@ func TestScraper_checkRobotsTxt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			allowed, err := scraper.checkRobotsTxt(tt.rootURL)
+			rules, err := scraper.checkRobotsTxt(tt.rootURL)
+			allowed := err == nil && rules.Allows("/")
 
 			if tt.wantErr {
 				if err == nil {
@@ -474,3 +532,422 @@ func TestScraper_Integration(t *testing.T) {
 	// }
 }
 */
+
+func TestScraper_InitialDelay(t *testing.T) {
+	var mu sync.Mutex
+	var requestTimes []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestTimes = append(requestTimes, time.Now())
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nAllow: /\n")
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `Home content <a href="/docs/next">Next</a>`))
+		default:
+			fmt.Fprint(w, htmlPage("Next", "Next content"))
+		}
+	}))
+	defer server.Close()
+
+	// The delay comes before robots.txt, the first request of the crawl
+	initialDelay := 1
+	s := newTestScraper(t, &config.Config{
+		RootURL:       server.URL + "/",
+		MaxDepth:      2,
+		InitialDelay:  &initialDelay,
+		RespectRobots: true,
+	})
+
+	start := time.Now()
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(requestTimes) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requestTimes))
+	}
+	if waited := requestTimes[0].Sub(start); waited < time.Second {
+		t.Errorf("First request sent after %v, want at least 1s", waited)
+	}
+	if gap := requestTimes[2].Sub(requestTimes[0]); gap >= time.Second {
+		t.Errorf("Subsequent requests delayed by %v, initial delay should apply only once", gap)
+	}
+}
+
+func TestScraper_InitialDelayCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request for %s during the initial delay", r.URL.Path)
+	}))
+	defer server.Close()
+
+	initialDelay := 30
+	s := newTestScraper(t, &config.Config{
+		RootURL:      server.URL + "/",
+		MaxDepth:     1,
+		InitialDelay: &initialDelay,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := s.ScrapeWithContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Cancelling during the initial delay took %v", elapsed)
+	}
+}
+
+func TestScraper_RespectMetaRobots(t *testing.T) {
+	robotsMeta := map[string]string{
+		"/index-follow":     "index, follow",
+		"/noindex":          "noindex",
+		"/nofollow":         "nofollow",
+		"/noindex-nofollow": "noindex,nofollow",
+		"/none":             "none",
+	}
+
+	var mu sync.Mutex
+	visited := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		visited[r.URL.Path] = true
+		mu.Unlock()
+
+		if r.URL.Path == "/" {
+			links := ""
+			for path := range robotsMeta {
+				links += fmt.Sprintf(`<a href="%s">%s</a> `, path, path)
+			}
+			fmt.Fprint(w, htmlPage("Home", "Home content "+links))
+			return
+		}
+
+		if directive, ok := robotsMeta[r.URL.Path]; ok {
+			fmt.Fprintf(w, `<html><head><title>%s</title><meta name="ROBOTS" content="%s"></head>
+				<body><main>Content of %s <a href="%s/child">Child</a></main></body></html>`,
+				r.URL.Path, directive, r.URL.Path, r.URL.Path)
+			return
+		}
+		fmt.Fprint(w, htmlPage("Child", "Child content"))
+	}))
+	defer server.Close()
+
+	respect := true
+	s := newTestScraper(t, &config.Config{
+		RootURL:           server.URL + "/",
+		MaxDepth:          3,
+		RespectMetaRobots: &respect,
+	})
+
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	stored := make(map[string]bool)
+	for _, page := range s.GetPages() {
+		stored[strings.TrimPrefix(page.URL, server.URL)] = true
+	}
+
+	tests := []struct {
+		path           string
+		wantStored     bool
+		wantChildFetch bool
+	}{
+		{"/index-follow", true, true},
+		{"/noindex", false, true},
+		{"/nofollow", true, false},
+		{"/noindex-nofollow", false, false},
+		{"/none", false, false},
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, tt := range tests {
+		if stored[tt.path] != tt.wantStored {
+			t.Errorf("%s stored = %v, want %v", tt.path, stored[tt.path], tt.wantStored)
+		}
+		if visited[tt.path+"/child"] != tt.wantChildFetch {
+			t.Errorf("%s child fetched = %v, want %v", tt.path, visited[tt.path+"/child"], tt.wantChildFetch)
+		}
+	}
+}
+
+func TestScraper_MetaRobotsIgnoredByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Hidden</title><meta name="robots" content="noindex"></head><body><main>Content</main></body></html>`)
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 1})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if s.GetPageCount() != 1 {
+		t.Errorf("Expected noindex page to be stored when respect_meta_robots is unset, got %d pages", s.GetPageCount())
+	}
+}
+
+func TestScraper_LastModifiedPatterns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Release Notes", "<p>Changes in this release.</p><p>Updated on 2024-01-15</p>"))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:              server.URL + "/",
+		MaxDepth:             1,
+		LastModifiedPatterns: []string{`Updated on (\d{4}-\d{2}-\d{2})`},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+
+	expected := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	if pages[0].LastModified == nil || !pages[0].LastModified.Equal(expected) {
+		t.Errorf("LastModified = %v, want %v", pages[0].LastModified, expected)
+	}
+}
+
+func TestScraper_CapturesResponsesForWARC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Home", "<p>Archived content</p>"))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 1, OutputFormat: "warc"})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	responses := s.GetResponses()
+	if len(responses) != 1 {
+		t.Fatalf("Expected 1 captured response, got %d", len(responses))
+	}
+	if responses[0].StatusCode != 200 || !strings.Contains(string(responses[0].Body), "Archived content") {
+		t.Errorf("Unexpected captured response: %d %q", responses[0].StatusCode, responses[0].Body)
+	}
+	if responses[0].ResponseHeaders.Get("Content-Type") != "text/html" {
+		t.Errorf("Expected response headers to be captured, got %v", responses[0].ResponseHeaders)
+	}
+}
+
+func TestScraper_CapturesResponsesForWARCInOutputFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", "<p>Archived content</p>"))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:       server.URL + "/",
+		MaxDepth:      1,
+		OutputFormats: []string{"markdown", "warc"},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if responses := s.GetResponses(); len(responses) != 1 {
+		t.Fatalf("Expected 1 captured response, got %d", len(responses))
+	}
+	if pages := s.GetPages(); len(pages) != 1 {
+		t.Errorf("Expected 1 page, got %d", len(pages))
+	}
+}
+
+func TestScraper_PreserveHeadingIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Setup", `<p>Overview.</p><h2 id="install">Installation</h2><p>Steps.</p>`))
+	}))
+	defer server.Close()
+
+	// Markdown listed among output_formats preserves ids just like output_format markdown
+	preserve := true
+	for _, formats := range [][]string{{"markdown"}, {"json", "markdown"}} {
+		s := newTestScraper(t, &config.Config{
+			RootURL:            server.URL + "/",
+			MaxDepth:           1,
+			OutputFormat:       formats[0],
+			OutputFormats:      formats,
+			PreserveHeadingIDs: &preserve,
+		})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+
+		pages := s.GetPages()
+		if len(pages) != 1 {
+			t.Fatalf("Expected 1 page with formats %v, got %d", formats, len(pages))
+		}
+		if !strings.Contains(pages[0].Content, "\n## Installation {#install}\n") {
+			t.Errorf("Expected heading id preserved as markdown attribute with formats %v, got %q", formats, pages[0].Content)
+		}
+	}
+}
+
+func TestScraper_BaseHref(t *testing.T) {
+	var mutex sync.Mutex
+	requested := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requested[r.URL.Path] = true
+		mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/search/page.html":
+			fmt.Fprint(w, `<html><head><title>Page</title><base href="/docs/v2/"></head>`+
+				`<body><main><p>Content</p><a href="intro.html">Intro</a></main></body></html>`)
+		default:
+			fmt.Fprint(w, htmlPage("Other "+r.URL.Path, "<p>Other content</p>"))
+		}
+	}))
+	defer server.Close()
+
+	// Resolved against the request URL the link would land under the skipped /search path
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/search/page.html", MaxDepth: 2})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !requested["/docs/v2/intro.html"] {
+		t.Errorf("Expected link to resolve against <base href>, requested %v", requested)
+	}
+	if requested["/search/intro.html"] {
+		t.Error("Link should not resolve against the request URL when <base> is declared")
+	}
+}
+
+func TestScraper_ReconciliationWaves(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			// The hidden page is only mentioned in text, never linked with an anchor
+			fmt.Fprint(w, htmlPage("Home", "<p>Full reference at "+server.URL+"/hidden.</p>"))
+		case "/hidden":
+			fmt.Fprint(w, htmlPage("Hidden", "<p>Late content</p>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	hasHidden := func(s *Scraper) bool {
+		for _, page := range s.GetPages() {
+			if page.Title == "Hidden" {
+				return true
+			}
+		}
+		return false
+	}
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if hasHidden(s) {
+		t.Fatal("Hidden page should not be found without reconciliation waves")
+	}
+
+	waves := 2
+	s = newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2, MaxWaves: &waves})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if !hasHidden(s) {
+		t.Errorf("Expected hidden page to be scraped by a reconciliation wave, got %d pages", s.GetPageCount())
+	}
+}
+
+func TestScraper_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		fmt.Fprint(w, htmlPage("Slow", "Too late"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	timeout := 1
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1, RequestTimeout: &timeout})
+
+	var errMutex sync.Mutex
+	var errs []error
+	s.collector.OnError(func(r *colly.Response, err error) {
+		errMutex.Lock()
+		errs = append(errs, err)
+		errMutex.Unlock()
+	})
+
+	start := time.Now()
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("request should time out after about 1s, took %v", elapsed)
+	}
+
+	errMutex.Lock()
+	defer errMutex.Unlock()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Timeout") {
+		t.Errorf("expected a timeout routed through OnError, got %v", errs)
+	}
+	if s.GetPageCount() != 0 {
+		t.Errorf("timed out page should not be stored, got %d pages", s.GetPageCount())
+	}
+}
+
+func TestScraper_RecordsExtractionTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Guide", "<h1>Guide</h1><p>This guide explains how extraction timing is recorded.</p>"))
+	}))
+	defer server.Close()
+
+	enableQuality := true
+	for _, quality := range []*bool{nil, &enableQuality} {
+		cfg := &config.Config{
+			RootURL:               server.URL + "/",
+			MaxDepth:              1,
+			EnableQualityAnalysis: quality,
+			QualityAnalysis:       config.QualityConfig{MinScore: 0.01, MinWordCount: 1},
+		}
+		es, err := NewWithFeatures(newTestScraper(t, cfg).config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages, err := es.ScrapeWithFeatures()
+		if err != nil {
+			t.Fatalf("ScrapeWithFeatures() error = %v", err)
+		}
+
+		if len(pages) != 1 {
+			t.Fatalf("Expected 1 page (quality analysis: %v), got %d", quality != nil, len(pages))
+		}
+		if pages[0].ExtractionTime <= 0 {
+			t.Errorf("Expected a positive extraction time (quality analysis: %v), got %v", quality != nil, pages[0].ExtractionTime)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package scraper
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// visitSeedRequests enqueues the configured seed requests alongside the root URL. Each carries
+// its own method, body and headers; responses go through the normal extraction pipeline.
+func (s *Scraper) visitSeedRequests() {
+	for _, seed := range s.config.SeedRequests {
+		headers := http.Header{}
+		for name, value := range seed.Headers {
+			headers.Set(name, value)
+		}
+
+		var body io.Reader
+		if seed.Body != "" {
+			body = strings.NewReader(seed.Body)
+		}
+
+		if err := s.collector.Request(seed.GetMethod(), seed.URL, body, nil, headers); err != nil {
+			s.logger.Printf("Could not enqueue seed request %s %s: %v", seed.GetMethod(), seed.URL, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_SeedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			body, _ := io.ReadAll(r.Body)
+			if r.Method != http.MethodPost || string(body) != `{"query":"install"}` || r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "search requires a JSON POST", http.StatusMethodNotAllowed)
+				return
+			}
+			fmt.Fprint(w, htmlPage("Install results", "<p>Installing the CLI takes one command.</p>"))
+		default:
+			fmt.Fprint(w, htmlPage("Home", "<p>Welcome</p>"))
+		}
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:  server.URL + "/",
+		MaxDepth: 1,
+		SeedRequests: []config.RequestSpec{{
+			URL:     server.URL + "/search",
+			Method:  "post",
+			Body:    `{"query":"install"}`,
+			Headers: map[string]string{"Content-Type": "application/json"},
+		}},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	var found *PageData
+	pages := s.GetPages()
+	for i := range pages {
+		if pages[i].URL == server.URL+"/search" {
+			found = &pages[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("Expected the POST seed to be scraped, got %d pages", len(pages))
+	}
+	if found.Title != "Install results" || !strings.Contains(found.Content, "one command") {
+		t.Errorf("Unexpected seed page: %+v", found)
+	}
+}
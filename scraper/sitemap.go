@@ -0,0 +1,151 @@
+package scraper
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxSitemapDepth bounds how many levels of nested <sitemapindex> files are followed
+const maxSitemapDepth = 5
+
+// sitemapEntry is a <url> element of a urlset or a <sitemap> element of a sitemap index
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapDocument holds either a <urlset> or a <sitemapindex>
+type sitemapDocument struct {
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// fetchSitemap fetches and decodes one sitemap file, transparently gunzipping sitemap.xml.gz
+func (s *Scraper) fetchSitemap(sitemapURL string) (*sitemapDocument, error) {
+	resp, err := s.fetch(sitemapURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sitemap %s: %v", sitemapURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sitemap %s returned status %d", sitemapURL, resp.StatusCode)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var body io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzipped sitemap %s: %v", sitemapURL, err)
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid sitemap %s: %v", sitemapURL, err)
+	}
+	return &doc, nil
+}
+
+// collectSitemapEntries returns the <url> entries of a sitemap, following sitemap index files
+// recursively. Only a failure of the top-level file is returned; broken nested files are logged
+// and skipped.
+func (s *Scraper) collectSitemapEntries(sitemapURL string) ([]sitemapEntry, error) {
+	visited := make(map[string]bool)
+
+	var walk func(sitemapURL string, depth int) ([]sitemapEntry, error)
+	walk = func(sitemapURL string, depth int) ([]sitemapEntry, error) {
+		if visited[sitemapURL] || depth > maxSitemapDepth {
+			return nil, nil
+		}
+		visited[sitemapURL] = true
+
+		doc, err := s.fetchSitemap(sitemapURL)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := doc.URLs
+		for _, child := range doc.Sitemaps {
+			loc := strings.TrimSpace(child.Loc)
+			if loc == "" {
+				continue
+			}
+			nested, err := walk(loc, depth+1)
+			if err != nil {
+				s.logger.Printf("Warning: Skipping nested sitemap: %v", err)
+				continue
+			}
+			entries = append(entries, nested...)
+		}
+		return entries, nil
+	}
+
+	return walk(sitemapURL, 0)
+}
+
+// LoadSitemap fetches a sitemap (following sitemap index files), keeps the <loc> URLs that are
+// on an allowed domain and pass the link filters, and enqueues them
+func (s *Scraper) LoadSitemap(sitemapURL string) ([]string, error) {
+	entries, err := s.collectSitemapEntries(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	rootURL, err := url.Parse(s.config.RootURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root URL")
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		loc := strings.TrimSpace(entry.Loc)
+		if loc == "" || seen[loc] {
+			continue
+		}
+		seen[loc] = true
+
+		locURL, err := url.Parse(loc)
+		if err != nil || !s.isAllowedDomain(locURL.Hostname()) {
+			s.logger.Printf("Skipping sitemap URL outside allowed domains: %s", loc)
+			continue
+		}
+		if !s.shouldFollowLink(loc, rootURL) {
+			continue
+		}
+		urls = append(urls, loc)
+	}
+
+	s.logger.Printf("Loaded %d URLs from sitemap %s", len(urls), sitemapURL)
+
+	for _, link := range urls {
+		if err := s.collector.Visit(link); err != nil {
+			s.logger.Printf("Could not enqueue sitemap URL %s: %v", link, err)
+		}
+	}
+
+	return urls, nil
+}
+
+// isAllowedDomain reports whether host is one of the collector's allowed domains
+func (s *Scraper) isAllowedDomain(host string) bool {
+	if len(s.collector.AllowedDomains) == 0 {
+		return true
+	}
+	for _, domain := range s.collector.AllowedDomains {
+		if strings.EqualFold(domain, host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+// urlSet renders a sitemap urlset with the given locations
+func urlSet(locs ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	for _, loc := range locs {
+		fmt.Fprintf(&b, "<url><loc>%s</loc></url>", loc)
+	}
+	b.WriteString("</urlset>")
+	return b.String()
+}
+
+func TestScraper_LoadSitemap(t *testing.T) {
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+			`<sitemap><loc>%[1]s/sitemap-docs.xml</loc></sitemap>`+
+			`<sitemap><loc>%[1]s/sitemap-guides.xml.gz</loc></sitemap>`+
+			`<sitemap><loc>%[1]s/sitemap-broken.xml</loc></sitemap>`+
+			`<sitemap><loc>%[1]s/sitemap.xml</loc></sitemap>`+
+			`</sitemapindex>`, server.URL)
+	})
+	mux.HandleFunc("/sitemap-docs.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, urlSet(server.URL+"/docs/a", server.URL+"/docs/b", "https://external.example.org/docs", server.URL+"/login"))
+	})
+	mux.HandleFunc("/sitemap-guides.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(urlSet(server.URL+"/guides/intro", server.URL+"/docs/a", server.URL+"/files/manual.pdf")))
+		gz.Close()
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/sitemap-broken.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<urlset><url><loc>`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "Content for "+r.URL.Path))
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1})
+	urls, err := s.LoadSitemap(server.URL + "/sitemap.xml")
+	if err != nil {
+		t.Fatalf("LoadSitemap() error = %v", err)
+	}
+	s.collector.Wait()
+
+	sort.Strings(urls)
+	expected := []string{server.URL + "/docs/a", server.URL + "/docs/b", server.URL + "/guides/intro"}
+	if strings.Join(urls, ",") != strings.Join(expected, ",") {
+		t.Errorf("LoadSitemap() = %v, want %v", urls, expected)
+	}
+	if s.GetPageCount() != len(expected) {
+		t.Errorf("sitemap URLs should be enqueued, got %d pages", s.GetPageCount())
+	}
+}
+
+func TestScraper_LoadSitemapErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/malformed.xml" {
+			fmt.Fprint(w, `<urlset><url>`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 1})
+	for _, path := range []string{"/malformed.xml", "/missing.xml"} {
+		if _, err := s.LoadSitemap(server.URL + path); err == nil {
+			t.Errorf("LoadSitemap(%s) should return an error", path)
+		}
+	}
+}
+
+func TestScraper_LoadSitemapUsesCrawlClient(t *testing.T) {
+	release := make(chan struct{})
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.xml" {
+			<-release
+			return
+		}
+		userAgent = r.UserAgent()
+		fmt.Fprint(w, urlSet())
+	}))
+	defer server.Close()
+	defer close(release)
+
+	timeout := 1
+	s := newTestScraper(t, &config.Config{
+		RootURL:        server.URL,
+		MaxDepth:       1,
+		UserAgents:     []string{"docbot/1.0"},
+		RequestTimeout: &timeout,
+	})
+
+	if _, err := s.LoadSitemap(server.URL + "/sitemap.xml"); err != nil {
+		t.Fatalf("LoadSitemap() error = %v", err)
+	}
+	if userAgent != "docbot/1.0" {
+		t.Errorf("Expected the configured user agent, got %q", userAgent)
+	}
+
+	start := time.Now()
+	if _, err := s.LoadSitemap(server.URL + "/slow.xml"); err == nil {
+		t.Error("LoadSitemap() of a hanging sitemap should time out")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected request_timeout to bound the sitemap fetch, took %v", elapsed)
+	}
+}
+
+func TestScraper_UseSitemapSeedsCrawl(t *testing.T) {
+	var mu sync.Mutex
+	fetched := make(map[string]bool)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sitemap.xml" {
+			fmt.Fprint(w, urlSet(server.URL+"/orphan"))
+			return
+		}
+		mu.Lock()
+		fetched[r.URL.Path] = true
+		mu.Unlock()
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "Content for "+r.URL.Path))
+	}))
+	defer server.Close()
+
+	useSitemap := true
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 2, UseSitemap: &useSitemap})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if !fetched["/orphan"] {
+		t.Error("page listed only in the sitemap should be crawled")
+	}
+	if s.GetPageCount() != 2 {
+		t.Errorf("expected root and orphan pages, got %d", s.GetPageCount())
+	}
+}
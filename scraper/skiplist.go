@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ContentHash returns the hex SHA-256 of extracted page content, as listed in skip_content_hashes
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadSkipHashes merges configured content hashes with those listed one per line in hashFile.
+// Blank lines and lines starting with # are ignored.
+func loadSkipHashes(hashes []string, hashFile string) (map[string]bool, error) {
+	skip := make(map[string]bool)
+	for _, hash := range hashes {
+		if hash = strings.ToLower(strings.TrimSpace(hash)); hash != "" {
+			skip[hash] = true
+		}
+	}
+
+	if hashFile == "" {
+		return skip, nil
+	}
+
+	file, err := os.Open(hashFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open skip hash file: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		skip[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read skip hash file: %v", err)
+	}
+
+	return skip, nil
+}
+
+// isSkippedContent reports whether content matches a known-junk hash
+func (s *Scraper) isSkippedContent(content string) bool {
+	return len(s.skipHashes) > 0 && s.skipHashes[ContentHash(content)]
+}
@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestLoadSkipHashes(t *testing.T) {
+	hashFile := filepath.Join(t.TempDir(), "skip.txt")
+	fileHash := ContentHash("from file")
+	content := "# known placeholders\n\n" + strings.ToUpper(fileHash) + "\n"
+	if err := os.WriteFile(hashFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configHash := ContentHash("from config")
+	skip, err := loadSkipHashes([]string{" " + configHash + " "}, hashFile)
+	if err != nil {
+		t.Fatalf("loadSkipHashes() error = %v", err)
+	}
+
+	if len(skip) != 2 || !skip[configHash] || !skip[fileHash] {
+		t.Errorf("Expected config and file hashes (normalized), got %v", skip)
+	}
+
+	if _, err := loadSkipHashes(nil, filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for missing skip hash file")
+	}
+}
+
+func TestScraper_SkipContentHashes(t *testing.T) {
+	placeholder := "<p>Coming soon! This page is under construction.</p>"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<p>Welcome</p><a href="/a">A</a><a href="/b">B</a><a href="/real">Real</a>`))
+		case "/real":
+			fmt.Fprint(w, htmlPage("Real", "<p>Actual documentation</p>"))
+		default:
+			fmt.Fprint(w, htmlPage("Soon", placeholder))
+		}
+	}))
+	defer server.Close()
+
+	placeholderHash := ContentHash(NewContentExtractor().cleanText("Coming soon! This page is under construction."))
+	s := newTestScraper(t, &config.Config{
+		RootURL:           server.URL + "/",
+		MaxDepth:          2,
+		SkipContentHashes: []string{placeholderHash},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	for _, page := range s.GetPages() {
+		if strings.Contains(page.Content, "Coming soon") {
+			t.Errorf("Placeholder page %s should have been skipped", page.URL)
+		}
+	}
+	if s.GetPageCount() != 2 {
+		t.Errorf("Expected home and real pages only, got %d pages", s.GetPageCount())
+	}
+}
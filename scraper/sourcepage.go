@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// responseContentType returns the Content-Type a response was served with
+func responseContentType(r *colly.Response) string {
+	if r == nil || r.Headers == nil {
+		return ""
+	}
+	return r.Headers.Get("Content-Type")
+}
+
+// generatesFormat reports whether the crawl's output includes the given format
+func (s *Scraper) generatesFormat(format string) bool {
+	return contains(s.config.GetOutputFormats(), format)
+}
+
+// isSourceContentType reports whether a content type is kept as a source page by the "auto" format
+func isSourceContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case mediaType == "text/markdown" || mediaType == "text/x-markdown", mediaType == "text/plain":
+		return true
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return true
+	}
+	return false
+}
+
+// captureSourcePage records a non-HTML response (markdown, JSON or plain text) as a page
+// with its raw body, so the "auto" output format can render it in its own format
+func (s *Scraper) captureSourcePage(r *colly.Response) {
+	contentType := responseContentType(r)
+	if !isSourceContentType(contentType) {
+		return
+	}
+
+	content := strings.TrimSpace(string(r.Body))
+	if content == "" {
+		s.logger.Printf("No content found for: %s", r.Request.URL.String())
+		return
+	}
+
+	if s.isSkippedContent(content) {
+		s.logger.Printf("Skipping page matching a skip hash: %s", r.Request.URL.String())
+		return
+	}
+
+	page := PageData{
+		Title:       sourcePageTitle(content, contentType, r.Request.URL.Path),
+		URL:         r.Request.URL.String(),
+		Content:     content,
+		Timestamp:   time.Now(),
+		Depth:       r.Request.Depth,
+		ContentType: contentType,
+
+		TokenEstimate: EstimateTokens(content),
+	}
+
+	s.applyTags(&page)
+	s.storePage(page)
+	s.logger.Printf("Captured %s source from: %s (Title: %s)", contentType, page.URL, page.Title)
+}
+
+// sourcePageTitle derives a title from a markdown heading or a JSON spec's info.title,
+// falling back to the last path segment
+func sourcePageTitle(content, contentType, urlPath string) string {
+	if strings.Contains(contentType, "json") {
+		var spec struct {
+			Info struct {
+				Title string `json:"title"`
+			} `json:"info"`
+		}
+		if err := json.Unmarshal([]byte(content), &spec); err == nil && spec.Info.Title != "" {
+			return spec.Info.Title
+		}
+	} else {
+		for _, line := range strings.Split(content, "\n") {
+			if strings.HasPrefix(line, "# ") {
+				return strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			}
+		}
+	}
+
+	if base := path.Base(urlPath); base != "/" && base != "." {
+		return base
+	}
+	return urlPath
+}
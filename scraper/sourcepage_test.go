@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestSourcePageTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		contentType string
+		path        string
+		want        string
+	}{
+		{"markdown heading", "intro\n# Getting Started\ntext", "text/markdown", "/docs/start.md", "Getting Started"},
+		{"openapi title", `{"info": {"title": "Pets API"}}`, "application/json", "/openapi.json", "Pets API"},
+		{"json fallback", `{"a": 1}`, "application/json", "/data/config.json", "config.json"},
+		{"text fallback", "plain", "text/plain", "/notes.txt", "notes.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sourcePageTitle(tt.content, tt.contentType, tt.path); got != tt.want {
+				t.Errorf("sourcePageTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScraper_CapturesSourcePagesForAuto(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/guide.md", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/markdown")
+		fmt.Fprint(w, "# Guide\n\nMarkdown body")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Home", `Home content <a href="/guide.md">Guide</a>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	run := func(format string) map[string]PageData {
+		s := newTestScraper(t, &config.Config{
+			RootURL:      server.URL + "/",
+			MaxDepth:     2,
+			OutputFormat: format,
+		})
+		if err := s.Scrape(); err != nil {
+			t.Fatalf("Scrape() error = %v", err)
+		}
+		pages := make(map[string]PageData)
+		for _, page := range s.GetPages() {
+			pages[page.URL] = page
+		}
+		return pages
+	}
+
+	pages := run("auto")
+	guide, ok := pages[server.URL+"/guide.md"]
+	if !ok {
+		t.Fatalf("Expected markdown source page to be captured, got %v", pages)
+	}
+	if guide.ContentType != "text/markdown" || guide.Title != "Guide" || guide.Content != "# Guide\n\nMarkdown body" {
+		t.Errorf("Unexpected source page: %+v", guide)
+	}
+	if home := pages[server.URL+"/"]; home.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("HTML page content type = %q", home.ContentType)
+	}
+
+	if _, ok := run("markdown")[server.URL+"/guide.md"]; ok {
+		t.Error("Source pages should only be captured for the auto format")
+	}
+}
@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// LoadContent returns the page content, reading it back from disk when it was spilled
+func (p PageData) LoadContent() (string, error) {
+	if p.ContentFile == "" {
+		return p.Content, nil
+	}
+
+	data, err := os.ReadFile(p.ContentFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read spilled content for %s: %v", p.URL, err)
+	}
+	return string(data), nil
+}
+
+// spillContent moves a page's content to a temp file when spill_to_disk is enabled so only
+// a reference stays in memory; on failure the content is kept in memory
+func (s *Scraper) spillContent(page *PageData) {
+	if !s.config.GetSpillToDisk() || page.ContentFile != "" {
+		return
+	}
+
+	s.spillOnce.Do(func() {
+		s.spillDir, s.spillErr = os.MkdirTemp("", "docscraper-spill-*")
+	})
+	if s.spillErr != nil {
+		s.logger.Printf("Warning: Could not create spill directory, keeping content in memory: %v", s.spillErr)
+		return
+	}
+
+	filename := filepath.Join(s.spillDir, fmt.Sprintf("page_%06d.txt", atomic.AddInt64(&s.spillCount, 1)))
+	if err := os.WriteFile(filename, []byte(page.Content), 0600); err != nil {
+		s.logger.Printf("Warning: Could not spill content for %s, keeping it in memory: %v", page.URL, err)
+		return
+	}
+
+	page.ContentFile = filename
+	page.Content = ""
+}
+
+// RemoveSpilledContent deletes the temp files holding spilled page content; call it once
+// output has been generated
+func (s *Scraper) RemoveSpilledContent() error {
+	if s.spillDir == "" {
+		return nil
+	}
+	return os.RemoveAll(s.spillDir)
+}
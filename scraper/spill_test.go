@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_SpillToDisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := "Spilled content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/guide">Guide</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	}))
+	defer server.Close()
+
+	spill := true
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 2, SpillToDisk: &spill})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	for _, page := range pages {
+		if page.Content != "" {
+			t.Errorf("content of %s should not be retained in memory, got %q", page.URL, page.Content)
+		}
+		if page.ContentFile == "" {
+			t.Fatalf("page %s should reference its spilled content", page.URL)
+		}
+		content, err := page.LoadContent()
+		if err != nil {
+			t.Fatalf("LoadContent() error = %v", err)
+		}
+		if !strings.Contains(content, "Spilled content for") {
+			t.Errorf("spilled content for %s = %q", page.URL, content)
+		}
+		if page.TokenEstimate == 0 {
+			t.Errorf("token estimate for %s should be computed before spilling", page.URL)
+		}
+	}
+
+	if err := s.RemoveSpilledContent(); err != nil {
+		t.Fatalf("RemoveSpilledContent() error = %v", err)
+	}
+	if _, err := os.Stat(pages[0].ContentFile); !os.IsNotExist(err) {
+		t.Errorf("spilled files should be removed, stat error = %v", err)
+	}
+}
+
+func TestPageData_LoadContent(t *testing.T) {
+	page := PageData{URL: "https://example.com/", Content: "In memory"}
+	if content, err := page.LoadContent(); err != nil || content != "In memory" {
+		t.Errorf("LoadContent() = %q, %v, want in-memory content", content, err)
+	}
+
+	page = PageData{URL: "https://example.com/", ContentFile: "/nonexistent/spill.txt"}
+	if _, err := page.LoadContent(); err == nil {
+		t.Error("LoadContent() should fail for a missing spill file")
+	}
+}
@@ -0,0 +1,300 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// stateSaveInterval is how often state_file is rewritten while pages are stored; the crawl saves
+// it once more when it ends
+const stateSaveInterval = 5 * time.Second
+
+// CrawlState is the progress of a crawl saved to state_file, so an interrupted run can resume
+type CrawlState struct {
+	SeenURLs    map[string]string `json:"seen_urls,omitempty"` // LinkDeduplicator.Export of discovered links
+	ScrapedURLs []string          `json:"scraped_urls"`        // Pages already extracted, kept in the page store
+	Pending     map[string]int    `json:"pending,omitempty"`   // URLs requested or queued but not yet done, with their depth
+
+	Conditional map[string]ConditionalInfo `json:"conditional,omitempty"` // Validators, pages and links under conditional_requests
+}
+
+// pendingRequest is a URL of the crawl frontier: requested, or refused because the crawl was
+// cancelled, but not yet scraped or failed
+type pendingRequest struct {
+	depth int
+	id    uint32 // Request that added the entry, so a finished request never removes its retry
+}
+
+// pageStoreDir is where the state saved to path keeps stored pages, one file per URL, so saving
+// state never rewrites page content
+func pageStoreDir(path string) string {
+	return path + ".pages"
+}
+
+// pageStoreFile is the file in a page store holding a URL's page
+func pageStoreFile(dir, rawURL string) string {
+	sum := sha256.Sum256([]byte(urlMatchKey(rawURL)))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// writeStoredPage adds a page to the page store in dir, content inline so it outlives
+// spill_to_disk's temp files
+func writeStoredPage(dir string, page PageData) error {
+	content, err := page.LoadContent()
+	if err != nil {
+		return err
+	}
+	page.Content, page.ContentFile = content, ""
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create page store directory: %v", err)
+	}
+	return os.WriteFile(pageStoreFile(dir, page.URL), data, 0644)
+}
+
+// readStoredPage reads a URL's page from the page store in dir
+func readStoredPage(dir, rawURL string) (PageData, error) {
+	var page PageData
+	data, err := os.ReadFile(pageStoreFile(dir, rawURL))
+	if err != nil {
+		return page, err
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return page, fmt.Errorf("invalid stored page for %s: %v", rawURL, err)
+	}
+	return page, nil
+}
+
+// SaveState writes the deduplicator's seen URLs, the scraped page URLs and the pending URLs to
+// path; the pages themselves are written to the page store as they are scraped
+func (s *Scraper) SaveState(path string) error {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	return s.saveState(path)
+}
+
+// saveState writes the crawl state with stateMutex held, replacing path atomically so an
+// interrupted write never leaves a truncated file
+func (s *Scraper) saveState(path string) error {
+	state := CrawlState{ScrapedURLs: append([]string{}, s.scrapedURLs...)}
+	if s.deduplicator != nil {
+		state.SeenURLs = s.deduplicator.Export()
+	}
+	state.Pending = make(map[string]int, len(s.pending))
+	for rawURL, request := range s.pending {
+		state.Pending[rawURL] = request.depth
+	}
+	if s.frontier != nil {
+		for rawURL, depth := range s.frontier.depths() {
+			if _, exists := state.Pending[rawURL]; !exists {
+				state.Pending[rawURL] = depth
+			}
+		}
+	}
+	if s.config.GetConditionalRequests() {
+		state.Conditional = s.GetConditionalHeaders()
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	s.stateSavedAt = time.Now()
+	return nil
+}
+
+// LoadState restores the state saved by SaveState: seen URLs go back into the deduplicator,
+// scraped pages are skipped by this run, or under conditional_requests requested again with
+// their saved validators, and pending URLs are remembered with their depth for resumeState.
+// A missing file leaves the scraper unchanged.
+func (s *Scraper) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var state CrawlState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+
+	if s.config.GetConditionalRequests() && state.Conditional != nil {
+		s.SetConditionalHeaders(state.Conditional)
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+
+	if s.deduplicator != nil && state.SeenURLs != nil {
+		s.deduplicator.Import(state.SeenURLs)
+	}
+	s.resumedURLs = make(map[string]bool, len(state.ScrapedURLs))
+	for _, rawURL := range state.ScrapedURLs {
+		if !s.resumedURLs[urlMatchKey(rawURL)] {
+			s.resumedURLs[urlMatchKey(rawURL)] = true
+			s.scrapedURLs = append(s.scrapedURLs, rawURL)
+		}
+	}
+	s.resumeDepths = make(map[string]int, len(state.Pending))
+	for rawURL, depth := range state.Pending {
+		if !s.resumedURLs[urlMatchKey(rawURL)] {
+			s.resumeDepths[rawURL] = depth
+		}
+	}
+	return nil
+}
+
+// resumeState loads state_file, warning and starting fresh when it is corrupt, stores the pages
+// scraped by the earlier run again from the page store, and returns the pending URLs, which are
+// requested again at their saved depth
+func (s *Scraper) resumeState() []string {
+	if err := s.LoadState(s.config.StateFile); err != nil {
+		s.logger.Printf("Warning: Could not load state file, starting fresh: %v", err)
+		return nil
+	}
+
+	s.stateMutex.Lock()
+	scraped := append([]string{}, s.scrapedURLs...)
+	s.stateMutex.Unlock()
+
+	restored := 0
+	dir := pageStoreDir(s.config.StateFile)
+	for _, rawURL := range scraped {
+		if !s.isResumed(rawURL) {
+			continue
+		}
+		page, err := readStoredPage(dir, rawURL)
+		if err != nil {
+			// Without its page the URL is scraped again
+			s.logger.Printf("Warning: Could not restore %s, scraping it again: %v", rawURL, err)
+			s.stateMutex.Lock()
+			delete(s.resumedURLs, urlMatchKey(rawURL))
+			s.resumeDepths[rawURL] = 1
+			s.stateMutex.Unlock()
+			continue
+		}
+		s.storePage(page)
+		restored++
+	}
+
+	s.stateMutex.Lock()
+	pending := make([]string, 0, len(s.resumeDepths))
+	for rawURL := range s.resumeDepths {
+		pending = append(pending, rawURL)
+	}
+	s.stateMutex.Unlock()
+	sort.Strings(pending)
+
+	if restored > 0 || len(pending) > 0 {
+		s.logger.Printf("Resuming crawl: restored %d scraped pages, requesting %d pending URLs", restored, len(pending))
+	}
+	return pending
+}
+
+// restoreDepth gives a pending URL of an earlier run its saved depth when it is requested again
+func (s *Scraper) restoreDepth(r *colly.Request) {
+	if r.Depth != 1 {
+		return
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	if depth, exists := s.resumeDepths[r.URL.String()]; exists {
+		delete(s.resumeDepths, r.URL.String())
+		r.Depth = depth
+	}
+}
+
+// isResumed reports whether a URL's page was scraped by an earlier run; the root URL is always
+// fetched again so the crawl can discover links from it, as are pages with saved validators,
+// which are revalidated instead
+func (s *Scraper) isResumed(rawURL string) bool {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+
+	key := urlMatchKey(rawURL)
+	return s.resumedURLs[key] && key != urlMatchKey(s.config.RootURL) && !s.hasConditional(rawURL)
+}
+
+// trackPending adds a request to the frontier saved in state_file
+func (s *Scraper) trackPending(r *colly.Request) {
+	if s.config.StateFile == "" {
+		return
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	if s.pending == nil {
+		s.pending = make(map[string]pendingRequest)
+	}
+	s.pending[r.URL.String()] = pendingRequest{depth: r.Depth, id: r.ID}
+}
+
+// finishPending removes a scraped or failed request from the frontier saved in state_file; a
+// request cut short by cancelling the crawl stays pending
+func (s *Scraper) finishPending(r *colly.Request) {
+	if s.config.StateFile == "" || (s.ctx != nil && s.ctx.Err() != nil) {
+		return
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	if request, exists := s.pending[r.URL.String()]; exists && request.id == r.ID {
+		delete(s.pending, r.URL.String())
+	}
+}
+
+// recordScraped adds a stored page to the crawl state and, with state_file set, writes it to the
+// page store, saving the state itself at most every stateSaveInterval
+func (s *Scraper) recordScraped(page PageData) {
+	s.stateMutex.Lock()
+	resumed := s.resumedURLs[urlMatchKey(page.URL)]
+	if !resumed {
+		s.scrapedURLs = append(s.scrapedURLs, page.URL)
+	}
+	s.stateMutex.Unlock()
+
+	// Revalidated pages of an earlier run are written again in case they changed
+	if s.config.StateFile == "" || (resumed && !s.hasConditional(page.URL)) {
+		return
+	}
+
+	// A state saved before the page is written lists it without a page; resuming scrapes it again
+	if err := writeStoredPage(pageStoreDir(s.config.StateFile), page); err != nil {
+		s.logger.Printf("Warning: Could not save page %s to the state file's page store: %v", page.URL, err)
+	}
+
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+	if time.Since(s.stateSavedAt) >= stateSaveInterval {
+		if err := s.saveState(s.config.StateFile); err != nil {
+			s.logger.Printf("Warning: Could not save state file: %v", err)
+		}
+	}
+}
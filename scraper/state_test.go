@@ -0,0 +1,225 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+
+	"github.com/gocolly/colly/v2"
+)
+
+func TestScraper_SaveLoadState(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	s := newTestScraper(t, &config.Config{RootURL: "https://example.com/", MaxDepth: 1})
+	s.deduplicator = NewLinkDeduplicator(URLNormalizer{RemoveFragment: true})
+	s.deduplicator.AddURL("https://example.com/guide")
+	s.deduplicator.AddURL("https://example.com/api#users")
+	s.recordScraped(PageData{URL: "https://example.com/guide"})
+	if err := s.SaveState(statePath); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	resumed := newTestScraper(t, &config.Config{RootURL: "https://example.com/", MaxDepth: 1})
+	resumed.deduplicator = NewLinkDeduplicator(URLNormalizer{RemoveFragment: true})
+	if err := resumed.LoadState(statePath); err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	if !resumed.deduplicator.IsDuplicate("https://example.com/api#overview") {
+		t.Error("Expected a URL seen before the reload to be a duplicate")
+	}
+	if resumed.deduplicator.IsDuplicate("https://example.com/new") {
+		t.Error("Expected an unseen URL not to be a duplicate")
+	}
+	if !resumed.isResumed("https://example.com/guide") || resumed.isResumed("https://example.com/api") {
+		t.Error("Expected only the scraped page to be skipped")
+	}
+	if resumed.isResumed("https://example.com/") {
+		t.Error("Expected the root URL never to be skipped")
+	}
+}
+
+func TestScraper_LoadStateMissingFile(t *testing.T) {
+	s := newTestScraper(t, &config.Config{RootURL: "https://example.com/", MaxDepth: 1})
+	if err := s.LoadState(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("LoadState() of a missing file error = %v, want nil", err)
+	}
+}
+
+// stateServer serves a root page linking to /a and /b and counts requests per path
+func stateServer(t *testing.T) (*httptest.Server, func() map[string]int) {
+	t.Helper()
+
+	var mutex sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		hits[r.URL.Path]++
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<main><p>Home page</p><a href="/a">A</a> <a href="/b">B</a></main>`))
+		case "/a", "/b":
+			fmt.Fprint(w, htmlPage("Page "+r.URL.Path, "<main><p>Page content</p></main>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() map[string]int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		counts := make(map[string]int, len(hits))
+		for path, n := range hits {
+			counts[path] = n
+		}
+		return counts
+	}
+}
+
+func TestScraper_StateFileResume(t *testing.T) {
+	server, hits := stateServer(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	cfg := func() *config.Config {
+		return &config.Config{RootURL: server.URL + "/", MaxDepth: 2, StateFile: statePath}
+	}
+
+	first := newTestScraper(t, cfg())
+	if err := first.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if first.GetPageCount() != 3 {
+		t.Fatalf("Expected 3 pages on the first run, got %d", first.GetPageCount())
+	}
+
+	second := newTestScraper(t, cfg())
+	if err := second.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if got := hits(); got["/a"] != 1 || got["/b"] != 1 || got["/"] != 2 {
+		t.Errorf("Expected only the root refetched on resume, got hits %v", got)
+	}
+	depths := make(map[string]int)
+	for _, page := range second.GetPages() {
+		depths[strings.TrimPrefix(page.URL, server.URL)] = page.Depth
+	}
+	if fmt.Sprint(depths) != "map[/:1 /a:2 /b:2]" {
+		t.Errorf("Expected the scraped pages restored with their depths on resume, got %v", depths)
+	}
+}
+
+func TestScraper_StateFileResumesInterruptedCrawl(t *testing.T) {
+	var mutex sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		hits[r.URL.Path]++
+		mutex.Unlock()
+
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<main><p>Home page</p><a href="/a">A</a></main>`))
+		case "/a":
+			fmt.Fprint(w, htmlPage("Page A", `<main><p>Page A content</p><a href="/a/deep">Deep</a></main>`))
+		case "/a/deep":
+			fmt.Fprint(w, htmlPage("Deep", `<main><p>Deep page content</p><a href="/a/deeper">Deeper</a></main>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	cfg := func() *config.Config {
+		return &config.Config{RootURL: server.URL + "/", MaxDepth: 3, StateFile: statePath}
+	}
+
+	// Cancel once /a is scraped, so /a/deep is found but never requested
+	ctx, cancel := context.WithCancel(context.Background())
+	first := newTestScraper(t, cfg())
+	first.collector.OnScraped(func(r *colly.Response) {
+		if r.Request.URL.Path == "/a" {
+			cancel()
+		}
+	})
+	if err := first.ScrapeWithContext(ctx); err != context.Canceled {
+		t.Fatalf("ScrapeWithContext() error = %v, want context.Canceled", err)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state CrawlState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if depth, pending := state.Pending[server.URL+"/a/deep"]; !pending || depth != 3 {
+		t.Fatalf("Expected /a/deep pending at depth 3, got %v", state.Pending)
+	}
+
+	second := newTestScraper(t, cfg())
+	if err := second.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	depths := make(map[string]int)
+	for _, page := range second.GetPages() {
+		depths[strings.TrimPrefix(page.URL, server.URL)] = page.Depth
+	}
+	if fmt.Sprint(depths) != "map[/:1 /a:2 /a/deep:3]" {
+		t.Errorf("Expected the pending page scraped at its depth beside the restored ones, got %v", depths)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if hits["/a"] != 1 || hits["/a/deep"] != 1 || hits["/a/deeper"] != 0 {
+		t.Errorf("Expected only pending pages within max_depth fetched on resume, got hits %v", hits)
+	}
+}
+
+func TestScraper_CorruptStateFileStartsFresh(t *testing.T) {
+	server, _ := stateServer(t)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(statePath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 2, StateFile: statePath})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if s.GetPageCount() != 3 {
+		t.Errorf("Expected a full crawl after a corrupt state file, got %d pages", s.GetPageCount())
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for _, path := range []string{"/", "/a", "/b"} {
+		if strings.Contains(string(data), server.URL+path+`"`) {
+			urls = append(urls, path)
+		}
+	}
+	sort.Strings(urls)
+	if strings.Join(urls, ",") != "/,/a,/b" {
+		t.Errorf("Expected the state file rewritten with all scraped pages, got:\n%s", data)
+	}
+}
@@ -0,0 +1,82 @@
+package scraper
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ScrapeStream runs the crawl in the background and sends each page on the returned channel
+// as soon as it is extracted, instead of keeping it for GetPages. Both channels are closed
+// when the crawl ends; the error channel then carries ScrapeWithContext's error, such as
+// ctx.Err() after cancellation. Reconciliation waves and the incremental crawl manifest need
+// the stored pages, so they are skipped.
+func (s *Scraper) ScrapeStream(ctx context.Context) (<-chan PageData, <-chan error) {
+	pages := make(chan PageData)
+	errs := make(chan error, 1)
+
+	s.stream = pages
+
+	go func() {
+		defer close(errs)
+		defer close(pages)
+
+		if err := s.ScrapeWithContext(ctx); err != nil {
+			errs <- err
+		}
+	}()
+
+	return pages, errs
+}
+
+// storePage keeps an extracted page, or sends it on the stream under ScrapeStream, and
+// records it in state_file. Pages over max_pages or duplicating stored content are dropped,
+// and spill_to_disk moves the content of the rest to disk.
+func (s *Scraper) storePage(page PageData) {
+	if !s.reservePage() {
+		s.logger.Printf("Dropping page over the page limit of %d: %s", s.config.GetMaxPages(), page.URL)
+		return
+	}
+	if s.isDuplicateContent(page) {
+		s.releasePage()
+		s.logger.Printf("Skipping page with duplicate content: %s", page.URL)
+		return
+	}
+	if s.discovery != nil {
+		// Discover only reports which pages would be stored
+		if !s.claimDiscovered(page.URL) {
+			s.releasePage()
+			return
+		}
+		s.logger.Printf("Discovered: %s (depth: %d)", page.URL, page.Depth)
+		return
+	}
+	s.spillContent(&page)
+	defer s.recordScraped(page)
+	s.recordConditionalPage(page)
+
+	if s.stream == nil {
+		s.pagesMutex.Lock()
+		s.pages = append(s.pages, page)
+		s.pagesMutex.Unlock()
+		return
+	}
+
+	select {
+	case s.stream <- page:
+		atomic.AddInt64(&s.streamedPages, 1)
+	case <-s.ctx.Done():
+	}
+}
+
+// isDuplicateContent reports whether dedupe_by_content is set and the page's content was
+// already stored, recording it otherwise
+func (s *Scraper) isDuplicateContent(page PageData) bool {
+	if s.contentDedup == nil {
+		return false
+	}
+	content, err := page.LoadContent()
+	if err != nil {
+		return false
+	}
+	return s.contentDedup.CheckAndAdd(content)
+}
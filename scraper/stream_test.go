@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_ScrapeStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprint(w, htmlPage("Home", `<main><p>Welcome to the documentation.</p><a href="/a">A</a><a href="/b">B</a></main>`))
+		case "/a", "/b":
+			fmt.Fprint(w, htmlPage("Page "+r.URL.Path, `<main><p>Details about this topic.</p></main>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL, MaxDepth: 2})
+	pages, errs := s.ScrapeStream(context.Background())
+
+	var urls []string
+	for page := range pages {
+		urls = append(urls, page.URL)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("ScrapeStream() error = %v", err)
+	}
+
+	sort.Strings(urls)
+	want := []string{server.URL, server.URL + "/a", server.URL + "/b"}
+	if fmt.Sprint(urls) != fmt.Sprint(want) {
+		t.Errorf("Expected streamed pages %v, got %v", want, urls)
+	}
+	if len(s.GetPages()) != 0 {
+		t.Errorf("Expected streamed pages not to be kept, got %d", len(s.GetPages()))
+	}
+	if s.GetPageCount() != 3 {
+		t.Errorf("Expected page count 3, got %d", s.GetPageCount())
+	}
+}
+
+func TestScraper_ScrapeStream_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, htmlPage("Page", fmt.Sprintf(`<main><p>Some page content here.</p><a href="%s/next">Next</a></main>`, r.URL.Path)))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/start", MaxDepth: 50})
+	pages, errs := s.ScrapeStream(ctx)
+
+	<-pages
+	cancel()
+	for range pages {
+	}
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsonLDType is the script type carrying JSON-LD structured data
+const jsonLDType = "application/ld+json"
+
+// ExtractStructuredData parses every JSON-LD block in doc, which must be read before
+// ExtractContent strips scripts. Blocks holding an array contribute each element; blocks that
+// fail to parse are skipped.
+func (e *ContentExtractor) ExtractStructuredData(doc *goquery.Selection) []interface{} {
+	var data []interface{}
+	doc.Find("script").Each(func(_ int, script *goquery.Selection) {
+		scriptType, _ := script.Attr("type")
+		if !strings.EqualFold(strings.TrimSpace(scriptType), jsonLDType) {
+			return
+		}
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(script.Text()), &value); err != nil {
+			return
+		}
+
+		if items, ok := value.([]interface{}); ok {
+			data = append(data, items...)
+		} else {
+			data = append(data, value)
+		}
+	})
+
+	return data
+}
+
+// structuredObjects flattens JSON-LD data into its objects, expanding @graph containers
+func structuredObjects(data []interface{}) []map[string]interface{} {
+	var objects []map[string]interface{}
+	for _, item := range data {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		objects = append(objects, object)
+		if graph, ok := object["@graph"].([]interface{}); ok {
+			objects = append(objects, structuredObjects(graph)...)
+		}
+	}
+	return objects
+}
+
+// hasStructuredType reports whether a JSON-LD object's @type is, or includes, typeName
+func hasStructuredType(object map[string]interface{}, typeName string) bool {
+	switch value := object["@type"].(type) {
+	case string:
+		return value == typeName
+	case []interface{}:
+		for _, item := range value {
+			if item == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StructuredDate returns the dateModified, or failing that datePublished, declared in JSON-LD data
+func StructuredDate(data []interface{}) *time.Time {
+	objects := structuredObjects(data)
+	for _, field := range []string{"dateModified", "datePublished"} {
+		for _, object := range objects {
+			value, ok := object[field].(string)
+			if !ok {
+				continue
+			}
+			if parsed, ok := parseLastModified(value); ok {
+				return &parsed
+			}
+		}
+	}
+	return nil
+}
+
+// StructuredBreadcrumbs returns the URLs of a JSON-LD BreadcrumbList ordered by position
+func StructuredBreadcrumbs(data []interface{}) []string {
+	for _, object := range structuredObjects(data) {
+		if !hasStructuredType(object, "BreadcrumbList") {
+			continue
+		}
+
+		elements, _ := object["itemListElement"].([]interface{})
+		type crumb struct {
+			position float64
+			url      string
+		}
+		var crumbs []crumb
+		for i, element := range elements {
+			entry, ok := element.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			position, ok := entry["position"].(float64)
+			if !ok {
+				position = float64(i + 1)
+			}
+
+			if link := breadcrumbURL(entry["item"]); link != "" {
+				crumbs = append(crumbs, crumb{position: position, url: link})
+			}
+		}
+
+		sort.SliceStable(crumbs, func(i, j int) bool {
+			return crumbs[i].position < crumbs[j].position
+		})
+
+		urls := make([]string, len(crumbs))
+		for i, c := range crumbs {
+			urls[i] = c.url
+		}
+		return urls
+	}
+	return nil
+}
+
+// breadcrumbURL reads a ListItem's item, which is either a URL or an object with @id/url
+func breadcrumbURL(item interface{}) string {
+	switch value := item.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		if id, ok := value["@id"].(string); ok && id != "" {
+			return id
+		}
+		if link, ok := value["url"].(string); ok {
+			return link
+		}
+	}
+	return ""
+}
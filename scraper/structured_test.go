@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"docscraper/config"
+)
+
+const articleJSONLD = `<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@type": "Article",
+  "headline": "Configuring the CLI",
+  "author": {"@type": "Person", "name": "Ada Lovelace"},
+  "datePublished": "2024-01-10",
+  "dateModified": "2024-02-20T08:30:00Z"
+}
+</script>`
+
+const breadcrumbJSONLD = `<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [{
+    "@type": "BreadcrumbList",
+    "itemListElement": [
+      {"@type": "ListItem", "position": 2, "item": {"@id": "https://example.com/docs/guides", "name": "Guides"}},
+      {"@type": "ListItem", "position": 1, "item": "https://example.com/docs"}
+    ]
+  }]
+}
+</script>`
+
+func TestContentExtractor_ExtractStructuredData(t *testing.T) {
+	extractor := NewContentExtractor()
+
+	doc := parseTestDocument(t, `<html><head>`+articleJSONLD+`
+		<script type="application/ld+json">[{"@type": "WebSite"}, {"@type": "Organization"}]</script>
+		<script type="application/ld+json">{ not json</script>
+		<script>var ignored = {"@type": "Nope"};</script>
+	</head><body></body></html>`)
+
+	data := extractor.ExtractStructuredData(doc)
+	if len(data) != 3 {
+		t.Fatalf("Expected article plus two array entries, got %d blocks: %v", len(data), data)
+	}
+
+	article, ok := data[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected JSON object, got %T", data[0])
+	}
+	if article["headline"] != "Configuring the CLI" {
+		t.Errorf("headline = %v", article["headline"])
+	}
+	if author, _ := article["author"].(map[string]interface{}); author["name"] != "Ada Lovelace" {
+		t.Errorf("author = %v", article["author"])
+	}
+
+	if data := extractor.ExtractStructuredData(parseTestDocument(t, `<html><body>No data</body></html>`)); data != nil {
+		t.Errorf("Expected nil for pages without JSON-LD, got %v", data)
+	}
+}
+
+func TestStructuredDate(t *testing.T) {
+	data := NewContentExtractor().ExtractStructuredData(parseTestDocument(t, articleJSONLD))
+	expected := time.Date(2024, 2, 20, 8, 30, 0, 0, time.UTC)
+	if date := StructuredDate(data); date == nil || !date.Equal(expected) {
+		t.Errorf("StructuredDate() = %v, want %v", date, expected)
+	}
+
+	published := []interface{}{map[string]interface{}{"datePublished": "2023-05-01"}}
+	if date := StructuredDate(published); date == nil || date.Format("2006-01-02") != "2023-05-01" {
+		t.Errorf("StructuredDate() should fall back to datePublished, got %v", date)
+	}
+}
+
+func TestStructuredBreadcrumbs(t *testing.T) {
+	data := NewContentExtractor().ExtractStructuredData(parseTestDocument(t, breadcrumbJSONLD))
+	crumbs := StructuredBreadcrumbs(data)
+
+	expected := []string{"https://example.com/docs", "https://example.com/docs/guides"}
+	if len(crumbs) != len(expected) {
+		t.Fatalf("StructuredBreadcrumbs() = %v, want %v", crumbs, expected)
+	}
+	for i := range expected {
+		if crumbs[i] != expected[i] {
+			t.Errorf("StructuredBreadcrumbs()[%d] = %s, want %s", i, crumbs[i], expected[i])
+		}
+	}
+}
+
+func TestScraper_CapturesStructuredData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Configuring</title>`+articleJSONLD+breadcrumbJSONLD+
+			`</head><body><main><p>Configuration guide</p></main></body></html>`)
+	}))
+	defer server.Close()
+
+	useStructured := true
+	s := newTestScraper(t, &config.Config{
+		RootURL:           server.URL + "/",
+		MaxDepth:          1,
+		UseStructuredData: &useStructured,
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+	page := pages[0]
+
+	if len(page.StructuredData) != 2 {
+		t.Fatalf("Expected 2 JSON-LD blocks, got %d", len(page.StructuredData))
+	}
+	article := page.StructuredData[0].(map[string]interface{})
+	if article["@type"] != "Article" || article["datePublished"] != "2024-01-10" {
+		t.Errorf("Unexpected article data: %v", article)
+	}
+
+	if page.LastModified == nil || page.LastModified.Format("2006-01-02") != "2024-02-20" {
+		t.Errorf("Expected LastModified from dateModified, got %v", page.LastModified)
+	}
+	if len(page.Breadcrumbs) != 2 || page.Breadcrumbs[1] != "https://example.com/docs/guides" {
+		t.Errorf("Expected breadcrumbs from BreadcrumbList, got %v", page.Breadcrumbs)
+	}
+}
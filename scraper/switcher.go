@@ -0,0 +1,157 @@
+package scraper
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+// Kinds of path segment a docs switcher varies
+const (
+	switcherVersion = "version"
+	switcherLocale  = "locale"
+)
+
+var (
+	// versionSegmentPattern matches path segments naming a docs version ("v2", "1.4", "1.x",
+	// "latest"); bare numbers such as years or page numbers are left out
+	versionSegmentPattern = regexp.MustCompile(`^(v\d+(\.\d+)*(\.x)?|\d+(\.\d+)+(\.x)?|\d+\.x|latest|stable|next|dev|main|master)$`)
+
+	// localeSegmentPattern matches path segments naming a locale ("en", "pt-br", "zh_hans")
+	localeSegmentPattern = regexp.MustCompile(`^[a-z]{2}([-_][a-z]{2,4})?$`)
+)
+
+// switcherEntry is the version or locale a switcher link selects
+type switcherEntry struct {
+	Kind  string
+	Value string
+}
+
+// segmentKind returns whether a path segment looks like a version or a locale, or ""
+func segmentKind(segment string) string {
+	segment = strings.ToLower(segment)
+	switch {
+	case versionSegmentPattern.MatchString(segment):
+		return switcherVersion
+	case localeSegmentPattern.MatchString(segment):
+		return switcherLocale
+	}
+	return ""
+}
+
+// switcherLinks detects version and language switchers among a page's links: clusters of links
+// differing only by one version or locale path segment, with at least three values or with the
+// page's own segment among them. It maps each clustered URL to the entries it selects; URLs
+// outside any cluster are absent.
+func switcherLinks(page *url.URL, links []*url.URL) map[string][]switcherEntry {
+	type member struct {
+		url   string
+		value string
+	}
+	type cluster struct {
+		kind    string
+		index   int // Position of the varying segment
+		members []member
+		values  map[string]bool
+	}
+	clusters := make(map[string]*cluster)
+
+	for _, link := range links {
+		segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+		for i, segment := range segments {
+			kind := segmentKind(segment)
+			if kind == "" {
+				continue
+			}
+
+			rest := append(append([]string{}, segments[:i]...), "*")
+			rest = append(rest, segments[i+1:]...)
+			key := kind + " " + link.Host + "/" + strings.Join(rest, "/") + "?" + link.RawQuery
+
+			c := clusters[key]
+			if c == nil {
+				c = &cluster{kind: kind, index: i, values: make(map[string]bool)}
+				clusters[key] = c
+			}
+			value := strings.ToLower(segment)
+			c.members = append(c.members, member{url: link.String(), value: value})
+			c.values[value] = true
+		}
+	}
+
+	pageSegments := strings.Split(strings.Trim(page.Path, "/"), "/")
+	entries := make(map[string][]switcherEntry)
+	for _, c := range clusters {
+		onPage := c.index < len(pageSegments) && c.values[strings.ToLower(pageSegments[c.index])]
+		if len(c.values) < 2 || (len(c.values) < 3 && !onPage) {
+			continue
+		}
+		for _, m := range c.members {
+			entries[m.url] = append(entries[m.url], switcherEntry{Kind: c.kind, Value: m.value})
+		}
+	}
+	return entries
+}
+
+// filtersSwitchers reports whether switcher_version or switcher_locale is set
+func (s *Scraper) filtersSwitchers() bool {
+	return s.config.SwitcherVersion != "" || s.config.SwitcherLocale != ""
+}
+
+// pageSwitchers returns the switcher links on the page e belongs to, detecting them on first use;
+// the result is dropped once the page is scraped
+func (s *Scraper) pageSwitchers(e *colly.HTMLElement) map[string][]switcherEntry {
+	s.switchersMutex.Lock()
+	defer s.switchersMutex.Unlock()
+
+	if entries, ok := s.switchers[e.Request]; ok {
+		return entries
+	}
+
+	var links []*url.URL
+	e.DOM.Parents().Last().Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		if link, err := url.Parse(e.Request.AbsoluteURL(href)); err == nil && href != "" {
+			links = append(links, link)
+		}
+	})
+
+	entries := switcherLinks(e.Request.URL, links)
+	if s.switchers == nil {
+		s.switchers = make(map[*colly.Request]map[string][]switcherEntry)
+	}
+	s.switchers[e.Request] = entries
+	return entries
+}
+
+// forgetPageSwitchers drops the switcher links detected on r's page
+func (s *Scraper) forgetPageSwitchers(r *colly.Response) {
+	s.switchersMutex.Lock()
+	delete(s.switchers, r.Request)
+	s.switchersMutex.Unlock()
+}
+
+// rejectsSwitcherLink reports whether link, found on e's page, is a switcher entry for a version
+// or locale other than the configured switcher_version or switcher_locale
+func (s *Scraper) rejectsSwitcherLink(e *colly.HTMLElement, link string) bool {
+	if !s.filtersSwitchers() {
+		return false
+	}
+
+	absoluteURL := e.Request.AbsoluteURL(link)
+	for _, entry := range s.pageSwitchers(e)[absoluteURL] {
+		want := s.config.SwitcherVersion
+		if entry.Kind == switcherLocale {
+			want = s.config.SwitcherLocale
+		}
+		if want != "" && !strings.EqualFold(entry.Value, want) {
+			s.logger.Printf("Skipping %s switcher link to %s: %s", entry.Kind, entry.Value, absoluteURL)
+			s.recordRejection(absoluteURL, RejectSwitcherLink, e.Request.URL)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestSwitcherLinks(t *testing.T) {
+	var links []*url.URL
+	for _, raw := range []string{
+		"https://example.com/v1/guide/install",
+		"https://example.com/v2/guide/install",
+		"https://example.com/latest/guide/install",
+		"https://example.com/v2/guide/configure",
+		"https://example.com/en/guide",
+		"https://example.com/fr/guide",
+		"https://example.com/v3/api", // two versions, neither the page's
+		"https://example.com/v4/api",
+		"https://example.com/blog/2023/recap", // years, not versions
+		"https://example.com/blog/2024/recap",
+		"https://example.com/blog/2025/recap",
+		"https://example.com/about",
+	} {
+		link, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		links = append(links, link)
+	}
+
+	// The page's own locale makes the two-link locale cluster a switcher
+	page, _ := url.Parse("https://example.com/fr/start")
+	entries := switcherLinks(page, links)
+
+	want := map[string]switcherEntry{
+		"https://example.com/v1/guide/install":     {Kind: switcherVersion, Value: "v1"},
+		"https://example.com/v2/guide/install":     {Kind: switcherVersion, Value: "v2"},
+		"https://example.com/latest/guide/install": {Kind: switcherVersion, Value: "latest"},
+		"https://example.com/en/guide":             {Kind: switcherLocale, Value: "en"},
+		"https://example.com/fr/guide":             {Kind: switcherLocale, Value: "fr"},
+	}
+	if len(entries) != len(want) {
+		t.Errorf("Expected %d switcher links, got %v", len(want), entries)
+	}
+	for link, entry := range want {
+		got := entries[link]
+		if len(got) != 1 || got[0] != entry {
+			t.Errorf("switcherLinks()[%s] = %v, want [%v]", link, got, entry)
+		}
+	}
+}
+
+func TestScraper_SwitcherVersion(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		// Every page carries the version switcher for the guide
+		switcher := `<p>Version: <a href="/v1/guide">v1</a> <a href="/v2/guide">v2</a> <a href="/v3/guide">v3</a></p>`
+		if r.URL.Path == "/" {
+			fmt.Fprint(w, htmlPage("Home", "<p>Welcome to the docs.</p>"+switcher))
+			return
+		}
+		version := strings.Split(strings.Trim(r.URL.Path, "/"), "/")[0]
+		fmt.Fprint(w, htmlPage("Guide "+version, "<p>The "+version+" guide.</p>"+switcher+
+			fmt.Sprintf(`<a href="/%s/reference">Reference</a>`, version)))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/", MaxDepth: 3, SwitcherVersion: "v2"})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	sort.Strings(requested)
+	want := []string{"/", "/v2/guide", "/v2/reference"}
+	if strings.Join(requested, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected requests %v, got %v", want, requested)
+	}
+	if len(s.switchers) != 0 {
+		t.Errorf("Expected switcher links forgotten after each page, got %d pages", len(s.switchers))
+	}
+}
@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"docscraper/config"
+)
+
+// tagRule is a compiled tag_rules entry
+type tagRule struct {
+	pattern      *regexp.Regexp
+	tag          string
+	matchContent bool
+}
+
+// compileTagRules compiles the configured tag rules
+func compileTagRules(rules []config.TagRule) ([]tagRule, error) {
+	compiled := make([]tagRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag rule pattern %q: %v", rule.Pattern, err)
+		}
+		compiled = append(compiled, tagRule{
+			pattern:      re,
+			tag:          strings.TrimSpace(rule.Tag),
+			matchContent: rule.Match == "content",
+		})
+	}
+	return compiled, nil
+}
+
+// applyTags sets the page's tags from every matching tag rule, in rule order without duplicates
+func (s *Scraper) applyTags(page *PageData) {
+	for _, rule := range s.tagRules {
+		subject := page.URL
+		if rule.matchContent {
+			subject = page.Content
+		}
+		if rule.pattern.MatchString(subject) && !contains(page.Tags, rule.tag) {
+			page.Tags = append(page.Tags, rule.tag)
+		}
+	}
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+)
+
+func TestScraper_ApplyTags(t *testing.T) {
+	rules, err := compileTagRules([]config.TagRule{
+		{Pattern: `/api/`, Tag: "reference"},
+		{Pattern: `(?i)deprecated`, Tag: "deprecated", Match: "content"},
+		{Pattern: `/api/v1/`, Tag: "reference"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Scraper{tagRules: rules}
+
+	tests := []struct {
+		url      string
+		content  string
+		expected []string
+	}{
+		{"https://example.com/api/v1/users", "User endpoints", []string{"reference"}},
+		{"https://example.com/api/old", "This endpoint is Deprecated.", []string{"reference", "deprecated"}},
+		{"https://example.com/guide", "Getting started", nil},
+		{"https://example.com/guide/deprecated", "Current guide", nil},
+	}
+
+	for _, tt := range tests {
+		page := PageData{URL: tt.url, Content: tt.content}
+		s.applyTags(&page)
+		if fmt.Sprint(page.Tags) != fmt.Sprint(tt.expected) {
+			t.Errorf("applyTags(%s) tags = %v, want %v", tt.url, page.Tags, tt.expected)
+		}
+	}
+}
+
+func TestScraper_TagRulesDuringCrawl(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body := "Content for " + r.URL.Path
+		if r.URL.Path == "/" {
+			body += ` <a href="/reference/users">Users</a> <a href="/guide">Guide</a>`
+		}
+		fmt.Fprint(w, htmlPage("Page "+r.URL.Path, body))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{
+		RootURL:  server.URL + "/",
+		MaxDepth: 2,
+		TagRules: []config.TagRule{{Pattern: `/reference/`, Tag: "reference"}},
+	})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	for _, page := range s.GetPages() {
+		tagged := strings.Join(page.Tags, ",") == "reference"
+		if strings.Contains(page.URL, "/reference/") != tagged {
+			t.Errorf("Page %s has tags %v", page.URL, page.Tags)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// tocSelectors locate an on-page table of contents or listing of a section's child pages
+var tocSelectors = []string{
+	".toc", "#toc", ".table-of-contents", "#table-of-contents",
+	".section-index", ".children", ".child-pages", ".subpages",
+}
+
+// ExtractTableOfContents returns the pages linked from a page's table of contents, in the order
+// listed. Links to headings of the page itself are left out, and the first table of contents
+// linking other pages wins. It reads doc before ExtractContent strips tables of contents from it.
+func (e *ContentExtractor) ExtractTableOfContents(doc *goquery.Selection, pageURL *url.URL) []string {
+	base := e.ExtractBaseURL(doc, pageURL)
+	self := *pageURL
+	self.Fragment = ""
+
+	for _, selector := range tocSelectors {
+		var links []string
+		seen := map[string]bool{self.String(): true}
+		doc.Find(selector).First().Find("a[href]").Each(func(_ int, link *goquery.Selection) {
+			if resolved := resolveNavLink(link, base); resolved != "" && !seen[resolved] {
+				seen[resolved] = true
+				links = append(links, resolved)
+			}
+		})
+		if len(links) > 0 {
+			return links
+		}
+	}
+	return nil
+}
+
+// orderByTableOfContents moves the children a node's table of contents lists to the front, in
+// the order listed, leaving the others after them in their current order
+func orderByTableOfContents(node *DocumentNode) {
+	if node == nil {
+		return
+	}
+
+	if len(node.Metadata.TableOfContents) > 0 && len(node.Children) > 1 {
+		rank := make(map[string]int, len(node.Metadata.TableOfContents))
+		for i, link := range node.Metadata.TableOfContents {
+			if _, exists := rank[link]; !exists {
+				rank[link] = i
+			}
+		}
+		position := func(child *DocumentNode) int {
+			if i, listed := rank[child.URL]; listed {
+				return i
+			}
+			return len(rank)
+		}
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return position(node.Children[i]) < position(node.Children[j])
+		})
+	}
+
+	for _, child := range node.Children {
+		orderByTableOfContents(child)
+	}
+}
@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"docscraper/config"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestContentExtractor_ExtractTableOfContents(t *testing.T) {
+	extractor := NewContentExtractor()
+	pageURL, _ := url.Parse("https://example.com/guides")
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "child page listing in author order",
+			body: htmlPage("Guides", `<p>Intro</p><ul class="children">
+				<li><a href="/guides/setup">Setup</a></li>
+				<li><a href="/guides/config#options">Configuration</a></li>
+				<li><a href="advanced">Advanced</a></li>
+				<li><a href="/guides/setup">Setup again</a></li>
+			</ul>`),
+			want: []string{"https://example.com/guides/setup", "https://example.com/guides/config", "https://example.com/advanced"},
+		},
+		{
+			name: "heading-only toc falls through to the child listing",
+			body: htmlPage("Guides", `<nav class="toc"><a href="#intro">Intro</a><a href="/guides#usage">Usage</a></nav>
+				<div class="section-index"><a href="/guides/b">B</a><a href="/guides/a">A</a></div>`),
+			want: []string{"https://example.com/guides/b", "https://example.com/guides/a"},
+		},
+		{
+			name: "no table of contents",
+			body: htmlPage("Guides", `<p>Plain page</p><a href="/guides/setup">Setup</a>`),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := extractor.ExtractTableOfContents(doc.Selection, pageURL)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTableOfContents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTreeBuilder_TableOfContentsOrder(t *testing.T) {
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/guides",
+		"https://example.com/guides/advanced",
+		"https://example.com/guides/basics",
+		"https://example.com/guides/configuration",
+		"https://example.com/guides/appendix",
+	}
+	contents := map[string]ScrapedContent{
+		"https://example.com/": {URL: urls[0], Title: "Home"},
+		// The guide lists its chapters in reading order, not alphabetically; the appendix is unlisted
+		"https://example.com/guides": {URL: urls[1], Title: "Guides", Metadata: NodeMetadata{
+			TableOfContents: []string{
+				"https://example.com/guides/basics",
+				"https://example.com/guides/configuration",
+				"https://example.com/guides/advanced",
+			},
+		}},
+		"https://example.com/guides/advanced":      {URL: urls[2], Title: "Advanced"},
+		"https://example.com/guides/basics":        {URL: urls[3], Title: "Basics"},
+		"https://example.com/guides/configuration": {URL: urls[4], Title: "Configuration"},
+		"https://example.com/guides/appendix":      {URL: urls[5], Title: "Appendix"},
+	}
+	breadcrumbs := []string{"https://example.com/", "https://example.com/guides"}
+	for _, u := range urls[2:] {
+		content := contents[u]
+		content.Metadata.Breadcrumbs = breadcrumbs
+		contents[u] = content
+	}
+
+	titles := func(node *DocumentNode) []string {
+		var names []string
+		for _, child := range node.Children {
+			names = append(names, child.Title)
+		}
+		return names
+	}
+
+	config := TreeConfig{UseBreadcrumbs: true, UseURLHierarchy: true, FallbackToRoot: true, SortChildren: true, SortBy: SortByTitle}
+	sorted := NewTreeBuilder(config).BuildTree(urls, contents)
+	if got, want := titles(sorted.NodeMap["https://example.com/guides"]), []string{"Advanced", "Appendix", "Basics", "Configuration"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected alphabetical children %v without use_table_of_contents, got %v", want, got)
+	}
+
+	config.UseTableOfContents = true
+	config.AutoIndex = true
+	tree := NewTreeBuilder(config).BuildTree(urls, contents)
+	guides := tree.NodeMap["https://example.com/guides"]
+	if got, want := titles(guides), []string{"Basics", "Configuration", "Advanced", "Appendix"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected children in table of contents order %v, got %v", want, got)
+	}
+	for i := 1; i < len(guides.Children); i++ {
+		if guides.Children[i].Index <= guides.Children[i-1].Index {
+			t.Errorf("Expected indexes to follow table of contents order, got %d after %d", guides.Children[i].Index, guides.Children[i-1].Index)
+		}
+	}
+}
+
+func TestScraper_CapturesTableOfContents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, htmlPage("Guides", `<main><p>Guides overview.</p></main>
+			<nav class="toc"><a href="/guides/b">B</a><a href="/guides/a">A</a></nav>`))
+	}))
+	defer server.Close()
+
+	s := newTestScraper(t, &config.Config{RootURL: server.URL + "/guides", MaxDepth: 1})
+	if err := s.Scrape(); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	pages := s.GetPages()
+	if len(pages) != 1 {
+		t.Fatalf("Expected 1 page, got %d", len(pages))
+	}
+	want := []string{server.URL + "/guides/b", server.URL + "/guides/a"}
+	if !reflect.DeepEqual(pages[0].TableOfContents, want) {
+		t.Errorf("TableOfContents = %v, want %v", pages[0].TableOfContents, want)
+	}
+	if strings.Contains(pages[0].Content, "/guides/b") {
+		t.Errorf("Expected the table of contents stripped from the content, got %q", pages[0].Content)
+	}
+}
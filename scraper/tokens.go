@@ -0,0 +1,11 @@
+package scraper
+
+import "unicode/utf8"
+
+// charsPerToken is the rough number of characters per LLM token used for estimates
+const charsPerToken = 4
+
+// EstimateTokens approximates the LLM token count of content with a chars/4 heuristic
+func EstimateTokens(content string) int {
+	return (utf8.RuneCountInString(content) + charsPerToken - 1) / charsPerToken
+}
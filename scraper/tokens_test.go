@@ -0,0 +1,31 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("a", 400), 100},
+		{"héllo wörld", 3}, // counted in runes, not bytes
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.content); got != tt.expected {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.content, got, tt.expected)
+		}
+	}
+
+	short, long := EstimateTokens(strings.Repeat("word ", 100)), EstimateTokens(strings.Repeat("word ", 1000))
+	if long <= short || long != short*10 {
+		t.Errorf("Token estimate should scale with content length: %d for 100 words, %d for 1000", short, long)
+	}
+}
@@ -11,19 +11,21 @@ import (
 
 // ContentQuality represents quality metrics for content
 type ContentQuality struct {
-	Score            float64        `json:"score"`
-	WordCount        int            `json:"word_count"`
-	CodeBlockCount   int            `json:"code_block_count"`
-	ImageCount       int            `json:"image_count"`
-	LinkCount        int            `json:"link_count"`
-	EmptyLineRatio   float64        `json:"empty_line_ratio"`
-	ContentRatio     float64        `json:"content_ratio"`
-	HasTitle         bool           `json:"has_title"`
-	HasHeaders       bool           `json:"has_headers"`
-	IsNavigationPage bool           `json:"is_navigation_page"`
-	Language         string         `json:"language"`
-	Issues           []QualityIssue `json:"issues"`
-	Tags             []string       `json:"tags"`
+	Score              float64        `json:"score"`
+	WordCount          int            `json:"word_count"`
+	CodeBlockCount     int            `json:"code_block_count"`
+	ImageCount         int            `json:"image_count"`
+	LinkCount          int            `json:"link_count"`
+	LinkRatio          float64        `json:"link_ratio"` // Share of words that are link text, compared against the navigation threshold
+	EmptyLineRatio     float64        `json:"empty_line_ratio"`
+	ContentRatio       float64        `json:"content_ratio"`
+	HasTitle           bool           `json:"has_title"`
+	HasHeaders         bool           `json:"has_headers"`
+	IsNavigationPage   bool           `json:"is_navigation_page"`
+	Language           string         `json:"language"`
+	Issues             []QualityIssue `json:"issues"`
+	Tags               []string       `json:"tags"`
+	LanguageConfidence float64        `json:"language_confidence"` // 0-1, low for short or ambiguous content
 }
 
 // QualityIssue represents a content quality issue
@@ -42,6 +44,11 @@ type NodeMetadata struct {
 	HasImages     bool           `json:"has_images"`
 	Quality       ContentQuality `json:"quality"`
 	Tags          []string       `json:"tags"`
+	Breadcrumbs   []string       `json:"breadcrumbs,omitempty"` // Ancestor URLs, outermost first
+
+	NavigationTrail []string `json:"navigation_trail,omitempty"`  // Highlighted nav item and its enclosing items, outermost first
+	MergedURLs      []string `json:"merged_urls,omitempty"`       // URLs of duplicate siblings or collapsed ancestors merged into this node
+	TableOfContents []string `json:"table_of_contents,omitempty"` // Pages linked from the page's table of contents, in order
 }
 
 // DocumentNode represents a node in the documentation tree
@@ -89,14 +96,24 @@ type TreeConfig struct {
 	SortOrder             string       `yaml:"sort_order"`
 	AutoIndex             bool         `yaml:"auto_index"`
 	PreserveOriginalOrder bool         `yaml:"preserve_original_order"`
+
+	MergeDuplicateSiblings bool    `yaml:"merge_duplicate_siblings"` // Merge same-titled siblings with similar content
+	DuplicateSimilarity    float64 `yaml:"duplicate_similarity"`     // Content similarity (0.0-1.0) for merging, zero means default (0.8)
+
+	CollapseSingleChildChains bool `yaml:"collapse_single_child_chains"` // Merge content-less nodes with their only child
+
+	UseTableOfContents bool `yaml:"use_table_of_contents"` // Order children as their parent's table of contents lists them, after any sorting
+
+	NavigationMenu string `yaml:"navigation_menu"` // Nested <ul>/<li> menu HTML that places pages under use_navigation; see BuildTreeFromNavigation
 }
 
 // ScrapedContent represents content scraped from a page
 type ScrapedContent struct {
-	URL      string
-	Title    string
-	Content  string
-	Metadata NodeMetadata
+	URL       string
+	Title     string
+	Content   string
+	LinkRatio float64 // Share of the page's words that are link text, from DOMLinkRatio; 0 counts markdown links in Content
+	Metadata  NodeMetadata
 }
 
 // TreeBuilder builds documentation trees from scraped content
@@ -111,8 +128,13 @@ func NewTreeBuilder(config TreeConfig) *TreeBuilder {
 	}
 }
 
-// BuildTree builds a documentation tree from URLs and content
+// BuildTree builds a documentation tree from URLs and content, following the navigation menu
+// instead when use_navigation is set and one is configured
 func (tb *TreeBuilder) BuildTree(urls []string, contents map[string]ScrapedContent) *DocumentTree {
+	if tb.config.UseNavigation && tb.config.NavigationMenu != "" {
+		return tb.BuildTreeFromNavigation(tb.config.NavigationMenu, contents)
+	}
+
 	tree := &DocumentTree{
 		NodeMap:   make(map[string]*DocumentNode),
 		BuildTime: time.Now(),
@@ -147,6 +169,19 @@ func (tb *TreeBuilder) BuildTree(urls []string, contents map[string]ScrapedConte
 		}
 	}
 
+	tb.finishTree(tree)
+	return tree
+}
+
+// finishTree merges, levels, sorts and indexes a tree once all its nodes are placed
+func (tb *TreeBuilder) finishTree(tree *DocumentTree) {
+	if tb.config.MergeDuplicateSiblings && tree.Root != nil {
+		tb.mergeDuplicateSiblings(tree, tree.Root)
+	}
+	if tb.config.CollapseSingleChildChains && tree.Root != nil {
+		tb.collapseSingleChildChains(tree, tree.Root)
+	}
+
 	// Calculate depth and levels
 	tb.CalculateDepthAndLevel(tree)
 
@@ -154,6 +189,9 @@ func (tb *TreeBuilder) BuildTree(urls []string, contents map[string]ScrapedConte
 	if tb.config.SortChildren {
 		tb.sortTreeChildren(tree.Root)
 	}
+	if tb.config.UseTableOfContents {
+		orderByTableOfContents(tree.Root)
+	}
 
 	// Auto-index if configured
 	if tb.config.AutoIndex {
@@ -163,8 +201,6 @@ func (tb *TreeBuilder) BuildTree(urls []string, contents map[string]ScrapedConte
 	// Update tree statistics
 	tree.TotalNodes = len(tree.NodeMap)
 	tree.MaxDepth = tb.calculateMaxDepth(tree.Root)
-
-	return tree
 }
 
 // AddNode adds a new node to the tree
@@ -173,14 +209,7 @@ func (tb *TreeBuilder) AddNode(tree *DocumentTree, url string, content ScrapedCo
 		return fmt.Errorf("node with URL %s already exists", url)
 	}
 
-	node := &DocumentNode{
-		URL:      url,
-		Path:     tb.extractPath(url),
-		Title:    content.Title,
-		Content:  content.Content,
-		Children: make([]*DocumentNode, 0),
-		Metadata: content.Metadata,
-	}
+	node := tb.newNode(url, content)
 
 	// Determine parent
 	parent := tb.DetermineParent(node, tree)
@@ -196,8 +225,30 @@ func (tb *TreeBuilder) AddNode(tree *DocumentTree, url string, content ScrapedCo
 	return nil
 }
 
+// newNode creates an unattached node for a page
+func (tb *TreeBuilder) newNode(pageURL string, content ScrapedContent) *DocumentNode {
+	return &DocumentNode{
+		URL:      pageURL,
+		Path:     tb.extractPath(pageURL),
+		Title:    content.Title,
+		Content:  content.Content,
+		Children: make([]*DocumentNode, 0),
+		Metadata: content.Metadata,
+	}
+}
+
 // DetermineParent determines the parent node for a given node
 func (tb *TreeBuilder) DetermineParent(node *DocumentNode, tree *DocumentTree) *DocumentNode {
+	if tb.config.UseBreadcrumbs {
+		if parent := tb.findParentByBreadcrumbs(node, tree); parent != nil {
+			return parent
+		}
+	}
+	if tb.config.UseNavigation {
+		if parent := nearestTrailAncestor(node, node.Metadata.NavigationTrail, tree); parent != nil {
+			return parent
+		}
+	}
 	if tb.config.UseURLHierarchy {
 		return tb.findParentByURLHierarchy(node, tree)
 	}
@@ -205,6 +256,25 @@ func (tb *TreeBuilder) DetermineParent(node *DocumentNode, tree *DocumentTree) *
 	return nil
 }
 
+// findParentByBreadcrumbs returns the nearest breadcrumb ancestor already in the tree
+func (tb *TreeBuilder) findParentByBreadcrumbs(node *DocumentNode, tree *DocumentTree) *DocumentNode {
+	return nearestTrailAncestor(node, node.Metadata.Breadcrumbs, tree)
+}
+
+// nearestTrailAncestor returns the innermost URL of an outermost-first trail, other than the
+// node itself, that is already in the tree
+func nearestTrailAncestor(node *DocumentNode, trail []string, tree *DocumentTree) *DocumentNode {
+	for i := len(trail) - 1; i >= 0; i-- {
+		if trail[i] == node.URL {
+			continue
+		}
+		if parent, exists := tree.NodeMap[trail[i]]; exists {
+			return parent
+		}
+	}
+	return nil
+}
+
 // findParentByURLHierarchy finds parent based on URL path hierarchy
 func (tb *TreeBuilder) findParentByURLHierarchy(node *DocumentNode, tree *DocumentTree) *DocumentNode {
 	parsedURL, err := url.Parse(node.URL)
@@ -223,22 +293,24 @@ func (tb *TreeBuilder) findParentByURLHierarchy(node *DocumentNode, tree *Docume
 		parentPath = "/"
 	}
 
-	// Look for existing nodes that could be parents
+	// Look for existing nodes that could be parents, the nearest ancestor winning
+	var parent *DocumentNode
+	parentLength := -1
 	for existingURL, existingNode := range tree.NodeMap {
 		existingParsed, err := url.Parse(existingURL)
 		if err != nil {
 			continue
 		}
 
-		if existingParsed.Host == parsedURL.Host {
+		if existingParsed.Host == parsedURL.Host && len(existingParsed.Path) > parentLength {
 			if existingParsed.Path == parentPath ||
 				(parentPath != "/" && strings.HasPrefix(urlPath, existingParsed.Path+"/")) {
-				return existingNode
+				parent, parentLength = existingNode, len(existingParsed.Path)
 			}
 		}
 	}
 
-	return nil
+	return parent
 }
 
 // extractPath extracts the path component from a URL
@@ -269,7 +341,7 @@ func (tb *TreeBuilder) calculateNodeDepthAndLevel(node *DocumentNode, depth, lev
 
 // SortChildren sorts the children of a node according to the configured criteria
 func (tb *TreeBuilder) SortChildren(node *DocumentNode, sortBy SortCriteria) {
-	if len(node.Children) <= 1 {
+	if node == nil {
 		return
 	}
 
@@ -504,3 +504,72 @@ func TestTreeBuilder_SortChildren(t *testing.T) {
 		}
 	}
 }
+
+func TestTreeBuilder_Breadcrumbs(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{UseBreadcrumbs: true, UseURLHierarchy: true, FallbackToRoot: true})
+
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/guides",
+		"https://example.com/articles/setup",
+		"https://example.com/articles/other",
+	}
+	contents := map[string]ScrapedContent{
+		"https://example.com/":       {URL: urls[0], Title: "Home"},
+		"https://example.com/guides": {URL: urls[1], Title: "Guides"},
+		// Breadcrumbs place the article under Guides, although its URL does not
+		"https://example.com/articles/setup": {URL: urls[2], Title: "Setup", Metadata: NodeMetadata{
+			Breadcrumbs: []string{"https://example.com/", "https://example.com/guides", "https://example.com/articles/setup"},
+		}},
+		// Unknown breadcrumbs fall back to the URL hierarchy
+		"https://example.com/articles/other": {URL: urls[3], Title: "Other", Metadata: NodeMetadata{
+			Breadcrumbs: []string{"https://example.com/missing"},
+		}},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+
+	if parent := tree.NodeMap["https://example.com/articles/setup"].Parent; parent == nil || parent.Title != "Guides" {
+		t.Errorf("Expected breadcrumb parent Guides, got %v", parent)
+	}
+	if parent := tree.NodeMap["https://example.com/articles/other"].Parent; parent == nil || parent.Title != "Home" {
+		t.Errorf("Expected fallback parent Home, got %v", parent)
+	}
+}
+
+func TestTreeBuilder_Navigation(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{UseNavigation: true, UseURLHierarchy: true, FallbackToRoot: true})
+
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/guides",
+		"https://example.com/reference",
+		"https://example.com/articles/setup",
+		"https://example.com/reference/api/config",
+	}
+	contents := map[string]ScrapedContent{
+		"https://example.com/":          {URL: urls[0], Title: "Home"},
+		"https://example.com/guides":    {URL: urls[1], Title: "Guides"},
+		"https://example.com/reference": {URL: urls[2], Title: "Reference"},
+		// The active nav item sits under Guides, although the URL does not
+		"https://example.com/articles/setup": {URL: urls[3], Title: "Setup", Metadata: NodeMetadata{
+			NavigationTrail: []string{"https://example.com/guides", "https://example.com/articles/setup"},
+		}},
+		// The nav skips the missing /reference/api level the URL implies
+		"https://example.com/reference/api/config": {URL: urls[4], Title: "Config", Metadata: NodeMetadata{
+			NavigationTrail: []string{"https://example.com/reference/api", "https://example.com/reference", "https://example.com/guides", "https://example.com/reference/api/config"},
+		}},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+
+	if parent := tree.NodeMap["https://example.com/articles/setup"].Parent; parent == nil || parent.Title != "Guides" {
+		t.Errorf("Expected navigation parent Guides, got %v", parent)
+	}
+	if parent := tree.NodeMap["https://example.com/reference/api/config"].Parent; parent == nil || parent.Title != "Guides" {
+		t.Errorf("Expected innermost navigation parent Guides, got %v", parent)
+	}
+	if parent := tree.NodeMap["https://example.com/guides"].Parent; parent == nil || parent.Title != "Home" {
+		t.Errorf("Expected URL hierarchy parent Home for a page without a trail, got %v", parent)
+	}
+}
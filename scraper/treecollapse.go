@@ -0,0 +1,47 @@
+package scraper
+
+import "strings"
+
+// chainTitleSeparator joins the titles of nodes collapsed into one
+const chainTitleSeparator = " / "
+
+// collapseSingleChildChains merges each node below node that has no meaningful content and a
+// single child into that child, so a chain like /a -> /a/b -> /a/b/c becomes one "A / B / C"
+// node. The root is never collapsed, and the merged URLs are kept in the child's metadata.
+func (tb *TreeBuilder) collapseSingleChildChains(tree *DocumentTree, node *DocumentNode) {
+	for i, child := range node.Children {
+		for len(child.Children) == 1 && !HasMeaningfulContent(child.Content) {
+			only := child.Children[0]
+			only.Title = JoinChainTitle(child.Title, only.Title)
+			only.Parent = node
+			only.Metadata.MergedURLs = append(only.Metadata.MergedURLs, child.URL)
+			only.Metadata.MergedURLs = append(only.Metadata.MergedURLs, child.Metadata.MergedURLs...)
+			delete(tree.NodeMap, child.URL)
+			child = only
+		}
+		node.Children[i] = child
+		tb.collapseSingleChildChains(tree, child)
+	}
+}
+
+// JoinChainTitle joins a collapsed parent's title with its child's, skipping an empty title
+func JoinChainTitle(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	if child == "" {
+		return parent
+	}
+	return parent + chainTitleSeparator + child
+}
+
+// HasMeaningfulContent reports whether content has any text besides markdown headings
+func HasMeaningfulContent(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package scraper
+
+import "testing"
+
+func TestTreeBuilder_CollapseSingleChildChains(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{
+		CollapseSingleChildChains: true,
+		UseBreadcrumbs:            true,
+		UseURLHierarchy:           true,
+		FallbackToRoot:            true,
+	})
+
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/a",
+		"https://example.com/a/b",
+		"https://example.com/a/b/c",
+		"https://example.com/guides",
+		"https://example.com/guides/install",
+		"https://example.com/guides/configure",
+	}
+	contents := map[string]ScrapedContent{
+		urls[0]: {URL: urls[0], Title: "Home", Content: "Welcome to the docs."},
+		urls[1]: {URL: urls[1], Title: "A"},
+		urls[2]: {URL: urls[2], Title: "B", Content: "# B\n\n## Contents"},
+		urls[3]: {URL: urls[3], Title: "C", Content: "The page at the end of the chain.", Metadata: NodeMetadata{
+			Breadcrumbs: []string{urls[0], urls[1], urls[2]},
+		}},
+		// Branches: kept even without content
+		urls[4]: {URL: urls[4], Title: "Guides"},
+		urls[5]: {URL: urls[5], Title: "Install", Content: "Install the CLI."},
+		urls[6]: {URL: urls[6], Title: "Configure", Content: "Configure the project."},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+
+	if tree.Root.URL != urls[0] || len(tree.Root.Children) != 2 {
+		t.Fatalf("Expected Home with 2 children, got %s with %d", tree.Root.URL, len(tree.Root.Children))
+	}
+
+	chain := tree.NodeMap[urls[3]]
+	if chain == nil || chain.Parent != tree.Root {
+		t.Fatalf("Expected the end of the chain directly under the root")
+	}
+	if chain.Title != "A / B / C" {
+		t.Errorf("Expected collapsed title 'A / B / C', got %q", chain.Title)
+	}
+	if chain.Level != 1 || chain.Depth != 1 {
+		t.Errorf("Expected collapsed node at level 1 and depth 1, got level %d depth %d", chain.Level, chain.Depth)
+	}
+	if got := chain.Metadata.MergedURLs; len(got) != 2 || got[0] != urls[2] || got[1] != urls[1] {
+		t.Errorf("Expected merged URLs [%s %s], got %v", urls[2], urls[1], got)
+	}
+	for _, collapsed := range urls[1:3] {
+		if _, exists := tree.NodeMap[collapsed]; exists {
+			t.Errorf("Expected collapsed %s to be removed from the node map", collapsed)
+		}
+	}
+
+	guides := tree.NodeMap[urls[4]]
+	if guides == nil || guides.Title != "Guides" || len(guides.Children) != 2 {
+		t.Errorf("Expected branching Guides node to be preserved with 2 children")
+	}
+	if tree.TotalNodes != 5 {
+		t.Errorf("Expected 5 nodes after collapsing, got %d", tree.TotalNodes)
+	}
+}
+
+func TestTreeBuilder_CollapseSingleChildChainsDisabled(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{UseBreadcrumbs: true, UseURLHierarchy: true, FallbackToRoot: true})
+
+	urls := []string{"https://example.com/", "https://example.com/a", "https://example.com/a/b"}
+	contents := map[string]ScrapedContent{
+		urls[0]: {URL: urls[0], Title: "Home"},
+		urls[1]: {URL: urls[1], Title: "A"},
+		urls[2]: {URL: urls[2], Title: "B", Content: "Body."},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+	if node := tree.NodeMap[urls[1]]; node == nil || len(node.Children) != 1 {
+		t.Errorf("Expected the chain to be kept when collapsing is off")
+	}
+}
+
+func TestHasMeaningfulContent(t *testing.T) {
+	tests := map[string]bool{
+		"":                       false,
+		"   \n\n":                false,
+		"# Title\n\n## Section":  false,
+		"# Title\n\nSome text.":  true,
+		"- [Link](/somewhere)\n": true,
+	}
+	for content, want := range tests {
+		if got := HasMeaningfulContent(content); got != want {
+			t.Errorf("HasMeaningfulContent(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
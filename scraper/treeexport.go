@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// treeAnchorStripPattern matches characters dropped from heading anchors
+	treeAnchorStripPattern = regexp.MustCompile(`[^a-z0-9\s-]`)
+
+	// treeAnchorSpacePattern matches runs of whitespace replaced by a dash in heading anchors
+	treeAnchorSpacePattern = regexp.MustCompile(`\s+`)
+)
+
+// MarkdownOptions controls how DocumentTree.ToMarkdown writes a tree
+type MarkdownOptions struct {
+	Title           string // Document heading, omitted when empty
+	TableOfContents bool   // Write a nested list linking every node before the content
+	IncludeMetadata bool   // Write each node's URL, tags and last-modified date under its heading
+	OmitContent     bool   // Write headings only, as an outline of the tree
+	BaseLevel       int    // Heading level of the shallowest nodes, zero means default (2 with a Title, else 1)
+}
+
+// ToMarkdown writes the tree as a single markdown document, each node a heading nested by its
+// position in the tree followed by its content, children in their tree order. An untitled root
+// without a URL, as built when the site root was not scraped, is left out.
+func (dt *DocumentTree) ToMarkdown(w io.Writer, opts MarkdownOptions) error {
+	base := opts.BaseLevel
+	if base <= 0 {
+		base = 1
+		if opts.Title != "" {
+			base = 2
+		}
+	}
+
+	var b strings.Builder
+	if opts.Title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", opts.Title)
+	}
+
+	tops := dt.topNodes()
+	anchors := make(map[*DocumentNode]string)
+	used := make(map[string]int)
+	var assign func(node *DocumentNode)
+	assign = func(node *DocumentNode) {
+		anchor := treeAnchor(nodeTitle(node))
+		if anchor == "" {
+			anchor = "section"
+		}
+		if n := used[anchor]; n > 0 {
+			used[anchor]++
+			anchor = fmt.Sprintf("%s-%d", anchor, n)
+		} else {
+			used[anchor] = 1
+		}
+		anchors[node] = anchor
+		for _, child := range node.Children {
+			assign(child)
+		}
+	}
+	for _, node := range tops {
+		assign(node)
+	}
+
+	if opts.TableOfContents && len(tops) > 0 {
+		var toc func(node *DocumentNode, indent int)
+		toc = func(node *DocumentNode, indent int) {
+			fmt.Fprintf(&b, "%s- [%s](#%s)\n", strings.Repeat("  ", indent), nodeTitle(node), anchors[node])
+			for _, child := range node.Children {
+				toc(child, indent+1)
+			}
+		}
+		for _, node := range tops {
+			toc(node, 0)
+		}
+		b.WriteString("\n")
+	}
+
+	var write func(node *DocumentNode, level int)
+	write = func(node *DocumentNode, level int) {
+		heading := level
+		if heading > 6 {
+			heading = 6 // Markdown only supports up to h6
+		}
+		fmt.Fprintf(&b, "%s %s {#%s}\n\n", strings.Repeat("#", heading), nodeTitle(node), anchors[node])
+		if opts.IncludeMetadata {
+			writeNodeMetadata(&b, node)
+		}
+		if content := strings.TrimSpace(node.Content); content != "" && !opts.OmitContent {
+			fmt.Fprintf(&b, "%s\n\n", content)
+		}
+		for _, child := range node.Children {
+			write(child, level+1)
+		}
+	}
+	for _, node := range tops {
+		write(node, base)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ToJSON writes the tree as indented JSON, nodes nested under their parents with their metadata
+func (dt *DocumentTree) ToJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dt)
+}
+
+// topNodes returns the nodes written at the shallowest heading level: the root, or the root's
+// children when the root is a placeholder rather than a scraped page
+func (dt *DocumentTree) topNodes() []*DocumentNode {
+	if dt.Root == nil {
+		return nil
+	}
+	if dt.Root.URL == "" && dt.Root.Title == "" {
+		return dt.Root.Children
+	}
+	return []*DocumentNode{dt.Root}
+}
+
+// writeNodeMetadata writes a node's URL, tags and last-modified date as markdown lines
+func writeNodeMetadata(b *strings.Builder, node *DocumentNode) {
+	lines := []string{fmt.Sprintf("**URL:** %s", node.URL)}
+	if len(node.Metadata.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("**Tags:** %s", strings.Join(node.Metadata.Tags, ", ")))
+	}
+	if !node.Metadata.LastModified.IsZero() {
+		lines = append(lines, fmt.Sprintf("**Last Modified:** %s", node.Metadata.LastModified.Format(time.RFC3339)))
+	}
+	fmt.Fprintf(b, "%s\n\n", strings.Join(lines, "  \n"))
+}
+
+// nodeTitle returns a node's title, falling back to its path for untitled pages
+func nodeTitle(node *DocumentNode) string {
+	if node.Title != "" {
+		return node.Title
+	}
+	if node.Path != "" {
+		return node.Path
+	}
+	return node.URL
+}
+
+// treeAnchor returns the markdown heading anchor for a title
+func treeAnchor(title string) string {
+	anchor := treeAnchorStripPattern.ReplaceAllString(strings.ToLower(title), "")
+	anchor = treeAnchorSpacePattern.ReplaceAllString(anchor, "-")
+	return strings.Trim(anchor, "-")
+}
@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// exportTestTree builds Home > Guide > Install, with API as a second child of Home
+func exportTestTree() *DocumentTree {
+	modified := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/guide",
+		"https://example.com/guide/install",
+		"https://example.com/api",
+	}
+	contents := map[string]ScrapedContent{
+		urls[0]: {URL: urls[0], Title: "Home", Content: "Welcome."},
+		urls[1]: {URL: urls[1], Title: "Guide", Content: "The guide."},
+		urls[2]: {URL: urls[2], Title: "Install", Content: "Run the installer.", Metadata: NodeMetadata{Tags: []string{"setup"}, LastModified: modified}},
+		urls[3]: {URL: urls[3], Title: "API", Content: "The API."},
+	}
+	return NewTreeBuilder(TreeConfig{UseURLHierarchy: true, FallbackToRoot: true}).BuildTree(urls, contents)
+}
+
+func TestDocumentTree_ToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		opts MarkdownOptions
+		want string
+	}{
+		{
+			name: "default",
+			want: "# Home {#home}\n\nWelcome.\n\n" +
+				"## Guide {#guide}\n\nThe guide.\n\n" +
+				"### Install {#install}\n\nRun the installer.\n\n" +
+				"## API {#api}\n\nThe API.\n\n",
+		},
+		{
+			name: "title, contents and metadata",
+			opts: MarkdownOptions{Title: "Docs", TableOfContents: true, IncludeMetadata: true, OmitContent: true},
+			want: "# Docs\n\n" +
+				"- [Home](#home)\n  - [Guide](#guide)\n    - [Install](#install)\n  - [API](#api)\n\n" +
+				"## Home {#home}\n\n**URL:** https://example.com/\n\n" +
+				"### Guide {#guide}\n\n**URL:** https://example.com/guide\n\n" +
+				"#### Install {#install}\n\n**URL:** https://example.com/guide/install  \n**Tags:** setup  \n**Last Modified:** 2024-03-01T12:00:00Z\n\n" +
+				"### API {#api}\n\n**URL:** https://example.com/api\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exportTestTree().ToMarkdown(&buf, tt.opts); err != nil {
+				t.Fatalf("ToMarkdown() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("ToMarkdown() =\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentTree_ToMarkdown_PlaceholderRoot(t *testing.T) {
+	// Without a scraped site root the navigation tree has an untitled root, which is not written
+	tree := NewTreeBuilder(TreeConfig{}).BuildTreeFromNavigation(
+		`<ul><li><a href="https://example.com/a">A</a></li><li><a href="https://example.com/b">B</a></li></ul>`,
+		map[string]ScrapedContent{
+			"https://example.com/a": {URL: "https://example.com/a", Title: "A", Content: "First."},
+			"https://example.com/b": {URL: "https://example.com/b", Title: "A", Content: "Second."},
+		},
+	)
+
+	var buf bytes.Buffer
+	if err := tree.ToMarkdown(&buf, MarkdownOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "# A {#a}\n\nFirst.\n\n# A {#a-1}\n\nSecond.\n\n"
+	if buf.String() != want {
+		t.Errorf("ToMarkdown() =\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestDocumentTree_ToJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestTree().ToJSON(&buf); err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+
+	var decoded struct {
+		TotalNodes int `json:"total_nodes"`
+		Root       struct {
+			Title    string `json:"title"`
+			Children []struct {
+				Title    string `json:"title"`
+				Children []struct {
+					Title    string       `json:"title"`
+					Metadata NodeMetadata `json:"metadata"`
+				} `json:"children"`
+			} `json:"children"`
+		} `json:"root"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("ToJSON() wrote invalid JSON: %v", err)
+	}
+
+	if decoded.TotalNodes != 4 || decoded.Root.Title != "Home" || len(decoded.Root.Children) != 2 {
+		t.Fatalf("Expected Home with 2 children and 4 nodes, got %+v", decoded)
+	}
+	guide := decoded.Root.Children[0]
+	if guide.Title != "Guide" || len(guide.Children) != 1 || guide.Children[0].Title != "Install" {
+		t.Fatalf("Expected Guide > Install, got %+v", guide)
+	}
+	if tags := guide.Children[0].Metadata.Tags; len(tags) != 1 || tags[0] != "setup" {
+		t.Errorf("Expected Install's metadata to be kept, got %+v", guide.Children[0].Metadata)
+	}
+}
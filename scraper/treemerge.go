@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// defaultDuplicateSimilarity is the content similarity at which same-titled siblings are merged
+const defaultDuplicateSimilarity = 0.8
+
+// duplicateShingleSize is the number of consecutive words compared when measuring similarity
+const duplicateShingleSize = 3
+
+// mergeDuplicateSiblings merges children of node that share a normalized title and have similar
+// content, then recurses into the remaining children. The merged node keeps the canonical URL,
+// the first sibling's position and the children of both.
+func (tb *TreeBuilder) mergeDuplicateSiblings(tree *DocumentTree, node *DocumentNode) {
+	threshold := tb.config.DuplicateSimilarity
+	if threshold <= 0 {
+		threshold = defaultDuplicateSimilarity
+	}
+
+	merged := make([]*DocumentNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		if i := duplicateSibling(merged, child, threshold); i >= 0 {
+			merged[i] = mergeNodes(tree, merged[i], child)
+			continue
+		}
+		merged = append(merged, child)
+	}
+	node.Children = merged
+
+	for _, child := range node.Children {
+		tb.mergeDuplicateSiblings(tree, child)
+	}
+}
+
+// duplicateSibling returns the index of the sibling node duplicates, or -1
+func duplicateSibling(siblings []*DocumentNode, node *DocumentNode, threshold float64) int {
+	title := normalizeNodeTitle(node.Title)
+	if title == "" {
+		return -1
+	}
+	for i, sibling := range siblings {
+		if normalizeNodeTitle(sibling.Title) == title && contentSimilarity(sibling.Content, node.Content) >= threshold {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeNodes folds one of two duplicate siblings into the other, keeping the one with the
+// canonical URL and removing the other from the node map
+func mergeNodes(tree *DocumentTree, first, second *DocumentNode) *DocumentNode {
+	kept, dropped := first, second
+	if preferCanonicalURL(second.URL, first.URL) {
+		kept, dropped = second, first
+	}
+
+	children := append(first.Children, second.Children...)
+	for _, child := range children {
+		child.Parent = kept
+	}
+	kept.Children = children
+
+	kept.Metadata.MergedURLs = append(kept.Metadata.MergedURLs, dropped.URL)
+	kept.Metadata.MergedURLs = append(kept.Metadata.MergedURLs, dropped.Metadata.MergedURLs...)
+	delete(tree.NodeMap, dropped.URL)
+	return kept
+}
+
+// preferCanonicalURL reports whether candidate is a more canonical URL than current: one
+// without a query or fragment wins, then the shorter one
+func preferCanonicalURL(candidate, current string) bool {
+	candidateDecorated, currentDecorated := hasQueryOrFragment(candidate), hasQueryOrFragment(current)
+	if candidateDecorated != currentDecorated {
+		return currentDecorated
+	}
+	return len(candidate) < len(current)
+}
+
+// hasQueryOrFragment reports whether rawURL carries a query string or fragment
+func hasQueryOrFragment(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return parsed.RawQuery != "" || parsed.Fragment != ""
+}
+
+// normalizeNodeTitle lowercases a title and collapses its whitespace
+func normalizeNodeTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// contentSimilarity returns the Jaccard similarity of the word shingles of a and b; two empty
+// contents are identical
+func contentSimilarity(a, b string) float64 {
+	shinglesA, shinglesB := contentShingles(a), contentShingles(b)
+	if len(shinglesA) == 0 && len(shinglesB) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for shingle := range shinglesA {
+		if shinglesB[shingle] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(shinglesA)+len(shinglesB)-shared)
+}
+
+// contentShingles returns the set of normalized word n-grams in text
+func contentShingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	shingles := make(map[string]bool)
+	if len(words) < duplicateShingleSize {
+		if len(words) > 0 {
+			shingles[strings.Join(words, " ")] = true
+		}
+		return shingles
+	}
+
+	for i := 0; i+duplicateShingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+duplicateShingleSize], " ")] = true
+	}
+	return shingles
+}
@@ -0,0 +1,108 @@
+package scraper
+
+import "testing"
+
+func TestTreeBuilder_MergeDuplicateSiblings(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{
+		MergeDuplicateSiblings: true,
+		UseNavigation:          true,
+		UseURLHierarchy:        true,
+		FallbackToRoot:         true,
+	})
+
+	overview := "The guides walk through installing, configuring and deploying the service step by step."
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/guides",
+		"https://example.com/guides/overview?ref=nav",
+		"https://example.com/guides/overview",
+		"https://example.com/guides/start",
+		"https://example.com/guides/details",
+		"https://example.com/reference",
+		"https://example.com/reference/overview",
+	}
+	contents := map[string]ScrapedContent{
+		urls[0]: {URL: urls[0], Title: "Home"},
+		urls[1]: {URL: urls[1], Title: "Guides"},
+		urls[2]: {URL: urls[2], Title: "Overview", Content: overview},
+		urls[3]: {URL: urls[3], Title: "  overview ", Content: overview + " Updated."},
+		// Same title, unrelated content: not a crawl artifact
+		urls[4]: {URL: urls[4], Title: "Overview", Content: "Start here to create your first project and run it locally."},
+		// Hangs off the duplicate that gets dropped
+		urls[5]: {URL: urls[5], Title: "Details", Metadata: NodeMetadata{
+			NavigationTrail: []string{urls[2], urls[5]},
+		}},
+		urls[6]: {URL: urls[6], Title: "Reference"},
+		// Same title and content under a different parent is kept
+		urls[7]: {URL: urls[7], Title: "Overview", Content: overview},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+
+	guides := tree.NodeMap[urls[1]]
+	if len(guides.Children) != 2 {
+		t.Fatalf("Expected 2 children under Guides after merging, got %d", len(guides.Children))
+	}
+
+	merged := guides.Children[0]
+	if merged.URL != urls[3] {
+		t.Errorf("Expected merged node to keep canonical URL %s, got %s", urls[3], merged.URL)
+	}
+	if len(merged.Metadata.MergedURLs) != 1 || merged.Metadata.MergedURLs[0] != urls[2] {
+		t.Errorf("Expected merged URLs [%s], got %v", urls[2], merged.Metadata.MergedURLs)
+	}
+	if _, exists := tree.NodeMap[urls[2]]; exists {
+		t.Errorf("Expected dropped duplicate to be removed from the node map")
+	}
+	if len(merged.Children) != 1 || merged.Children[0].URL != urls[5] || merged.Children[0].Parent != merged {
+		t.Errorf("Expected Details to move under the merged node, got %v", merged.Children)
+	}
+	if merged.Children[0].Depth != 3 {
+		t.Errorf("Expected Details at depth 3, got %d", merged.Children[0].Depth)
+	}
+
+	if guides.Children[1].URL != urls[4] {
+		t.Errorf("Expected dissimilar Overview sibling to be kept, got %s", guides.Children[1].URL)
+	}
+	if node := tree.NodeMap[urls[7]]; node == nil || node.Parent.URL != urls[6] {
+		t.Errorf("Expected Overview under Reference to be kept")
+	}
+	if tree.TotalNodes != 7 {
+		t.Errorf("Expected 7 nodes after merging, got %d", tree.TotalNodes)
+	}
+}
+
+func TestTreeBuilder_MergeDuplicateSiblings_Disabled(t *testing.T) {
+	builder := NewTreeBuilder(TreeConfig{UseURLHierarchy: true, FallbackToRoot: true})
+
+	urls := []string{"https://example.com/", "https://example.com/a", "https://example.com/b"}
+	contents := map[string]ScrapedContent{
+		urls[0]: {URL: urls[0], Title: "Home"},
+		urls[1]: {URL: urls[1], Title: "Overview", Content: "Same text"},
+		urls[2]: {URL: urls[2], Title: "Overview", Content: "Same text"},
+	}
+
+	tree := builder.BuildTree(urls, contents)
+	if len(tree.Root.Children) != 2 {
+		t.Errorf("Expected duplicates to be kept when merging is disabled, got %d children", len(tree.Root.Children))
+	}
+}
+
+func TestContentSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"one two three four", "One, two; three four!", 1},
+		{"one two three four", "five six seven eight", 0},
+		{"one two three four", "one two three five", 1.0 / 3},
+		{"", "", 1},
+		{"words here", "", 0},
+	}
+
+	for _, tt := range tests {
+		if got := contentSimilarity(tt.a, tt.b); got != tt.expected {
+			t.Errorf("contentSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
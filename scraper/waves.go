@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// contentURLPattern matches absolute http(s) URLs mentioned in page text
+var contentURLPattern = regexp.MustCompile(`https?://[^\s"'<>()\[\]{}]+`)
+
+// rememberPageRequest keeps the request behind a stored page so later waves can follow
+// links found on it at the right depth
+func (s *Scraper) rememberPageRequest(pageURL string, r *colly.Request) {
+	if s.config.GetMaxWaves() == 0 {
+		return
+	}
+
+	s.pageRequestsMutex.Lock()
+	defer s.pageRequestsMutex.Unlock()
+	if s.pageRequests == nil {
+		s.pageRequests = make(map[string]*colly.Request)
+	}
+	s.pageRequests[pageURL] = r
+}
+
+// runReconciliationWaves re-scans stored pages for same-site links that were never visited
+// and crawls them, repeating up to max_waves times or until a wave finds nothing new
+func (s *Scraper) runReconciliationWaves() {
+	maxWaves := s.config.GetMaxWaves()
+	for wave := 1; wave <= maxWaves; wave++ {
+		queued := s.reconcile()
+		s.logger.Printf("Reconciliation wave %d: %d new links", wave, queued)
+		if queued == 0 {
+			return
+		}
+
+		s.collector.Wait()
+		s.drainFrontier()
+	}
+}
+
+// reconcile enqueues unvisited same-site links found in stored pages and returns how many were queued
+func (s *Scraper) reconcile() int {
+	queued := make(map[string]bool)
+
+	for _, page := range s.pages {
+		s.pageRequestsMutex.Lock()
+		request := s.pageRequests[page.URL]
+		s.pageRequestsMutex.Unlock()
+		if request == nil {
+			continue
+		}
+		if content, err := page.LoadContent(); err == nil {
+			page.Content = content
+		}
+
+		for _, link := range reconciliationCandidates(page) {
+			if queued[link] || !s.shouldFollowLink(link, request.URL) {
+				continue
+			}
+			if visited, _ := s.collector.HasVisited(link); visited {
+				continue
+			}
+
+			queued[link] = true
+			s.enqueueLink(request, link)
+		}
+	}
+
+	return len(queued)
+}
+
+// reconciliationCandidates returns the links a stored page mentions outside of its anchors
+func reconciliationCandidates(page PageData) []string {
+	var links []string
+	for _, match := range contentURLPattern.FindAllString(page.Content, -1) {
+		links = append(links, strings.TrimRight(match, ".,;:!?"))
+	}
+	links = append(links, page.Breadcrumbs...)
+
+	candidates := make([]string, 0, len(links))
+	for _, link := range links {
+		parsed, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		parsed.Fragment = ""
+		candidates = append(candidates, parsed.String())
+	}
+	return candidates
+}
@@ -1,6 +1,8 @@
 package integration_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -72,14 +74,28 @@ func TestAdvancedFeaturesIntegration(t *testing.T) {
 	})
 
 	t.Run("Dry Run Mode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			if r.URL.Path == "/" {
+				w.Write([]byte(`<html><body><a href="/guide">Guide</a></body></html>`))
+				return
+			}
+			w.Write([]byte(`<html><body><p>Guide</p></body></html>`))
+		}))
+		defer server.Close()
+
 		dryRunCfg := *cfg
+		dryRunCfg.RootURL = server.URL + "/"
 		dryRunCfg.DevTools.EnableDryRun = true
 
 		dt := devtools.NewDevTools(&dryRunCfg, false, true)
-		err := dt.StartDryRun()
+		urls, err := dt.StartDryRun()
 		if err != nil {
 			t.Errorf("Dry run failed: %v", err)
 		}
+		if len(urls) != 2 {
+			t.Errorf("Expected 2 discovered URLs, got %v", urls)
+		}
 	})
 
 	t.Run("Enhanced Scraper with All Features", func(t *testing.T) {
@@ -371,6 +387,68 @@ func TestFeatureToggling(t *testing.T) {
 	})
 }
 
+// TestProfilerIntegration tests that profiling reports the responses of a real crawl
+func TestProfilerIntegration(t *testing.T) {
+	pages := map[string]string{
+		"/":      `<h1>Home</h1><p>Welcome to the documentation home page.</p><a href="/guide">Guide</a> <a href="/missing">Missing</a>`,
+		"/guide": `<h1>Guide</h1><p>The guide explains every step of the setup.</p>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>Docs</title></head><body><main>" + body + "</main></body></html>"))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		RootURL:      server.URL + "/",
+		OutputFormat: "markdown",
+		OutputType:   "single",
+		OutputDir:    tempDir,
+		MaxDepth:     2,
+		LogFile:      filepath.Join(tempDir, "test.log"),
+	}
+	cfg.SetDefaults()
+	cfg.MinDelay, cfg.MaxDelay = 0, 0 // No delay for tests
+
+	enhancedScraper, err := scraper.NewWithFeatures(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create enhanced scraper: %v", err)
+	}
+
+	dt := devtools.NewDevTools(cfg, false, false)
+	enhancedScraper.SetProfiler(dt.Profiler())
+	dt.StartProfiling()
+	if _, err := enhancedScraper.ScrapeWithFeatures(); err != nil {
+		t.Fatalf("Scraping failed: %v", err)
+	}
+	report := dt.StopProfiling()
+
+	// The home page and the guide; the 404 answer for the missing page is only an error
+	if report.PagesScraped != 2 || len(report.PageTimings) != 2 {
+		t.Errorf("Expected 2 profiled responses, got %d pages and %d timings", report.PagesScraped, len(report.PageTimings))
+	}
+	if report.TotalDataDownloaded == 0 {
+		t.Error("Expected downloaded bytes to be recorded")
+	}
+	if report.ErrorsEncountered != 1 {
+		t.Errorf("Expected 1 profiled error, got %d", report.ErrorsEncountered)
+	}
+	if report.AverageTimePerPage <= 0 {
+		t.Errorf("Expected a positive average time per page, got %v", report.AverageTimePerPage)
+	}
+
+	// The home page and the guide were extracted
+	if len(report.ExtractionTimings) != 2 {
+		t.Errorf("Expected 2 profiled extractions, got %v", report.ExtractionTimings)
+	}
+}
+
 // TestEdgeCases tests edge cases and error handling
 func TestEdgeCases(t *testing.T) {
 	t.Run("Empty Page List", func(t *testing.T) {